@@ -1,23 +1,41 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/config"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/assignment"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/auth"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/bundle"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/category"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/certificate"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/cohort"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/forum"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/gift"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/gradebook"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/invite"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/learningpath"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/material"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/peerreview"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/quiz"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/release"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/review"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/similarity"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/submission"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/subscription"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/team"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/wishlist"
 	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 
@@ -47,15 +65,48 @@ func main() {
 		&schema.Wallet{},
 		&schema.MidtransTransaction{},
 		&schema.User{},
+		&schema.SubscriptionPlan{},
+		&schema.Subscription{},
+		&schema.Category{},
+		&schema.Tag{},
 		&schema.Course{},
+		&schema.Cohort{},
+		&schema.CohortMember{},
 		&schema.Material{},
+		&schema.GradeCategory{},
+		&schema.GradeScaleStep{},
+		&schema.TeamSet{},
+		&schema.Team{},
+		&schema.TeamMember{},
 		&schema.Assignment{},
+		&schema.AssignmentExtension{},
+		&schema.RubricCriterion{},
+		&schema.RubricLevel{},
 		&schema.Submission{},
+		&schema.RubricScore{},
+		&schema.SubmissionMember{},
+		&schema.PeerReview{},
+		&schema.SimilarityReport{},
+		&schema.Quiz{},
+		&schema.QuizQuestion{},
+		&schema.QuizAttempt{},
 		&schema.Attachment{},
 		&schema.Review{},
 		&schema.CourseEnroll{},
 		&schema.ForumDiscussion{},
 		&schema.ForumReply{},
+		&schema.Certificate{},
+		&schema.CoursePrerequisite{},
+		&schema.CourseSale{},
+		&schema.EnrollmentInvite{},
+		&schema.Gift{},
+		&schema.WishlistItem{},
+		&schema.ReleaseNotice{},
+		&schema.Revision{},
+		&schema.LearningPath{},
+		&schema.LearningPathCourse{},
+		&schema.Bundle{},
+		&schema.BundleCourse{},
 	)
 
 	mailDialer := config.NewMailDialer()
@@ -96,37 +147,124 @@ func main() {
 	courseEnrollRepo := courseenroll.NewRepository(db)
 	courseEnrollUseCase := courseenroll.NewUseCase(courseEnrollRepo)
 
+	// Category
+	categoryRepo := category.NewRepository(db)
+	categoryUseCase := category.NewUseCase(categoryRepo)
+	category.NewRestController(engine, categoryUseCase)
+
 	// Course
 	courseRepo := course.NewRepository(db)
 	courseUseCase := course.NewUseCase(courseRepo, walletRepo, *courseEnrollUseCase, userRepo, notificationRepo, mailDialer, uploader)
 	course.NewRestController(engine, courseUseCase, walletUseCase)
 
+	// Bundle
+	bundleRepo := bundle.NewRepository(db, walletRepo)
+	bundleUseCase := bundle.NewUseCase(bundleRepo, courseUseCase, notificationRepo)
+	bundle.NewRestController(engine, bundleUseCase)
+
+	// Learning Path
+	learningPathRepo := learningpath.NewRepository(db, walletRepo)
+	learningPathUseCase := learningpath.NewUseCase(learningPathRepo, courseRepo, courseUseCase, courseEnrollUseCase)
+	learningpath.NewRestController(engine, learningPathUseCase)
+
+	// Subscription
+	subscriptionRepo := subscription.NewRepository(db, walletRepo)
+	subscriptionUseCase := subscription.NewUseCase(subscriptionRepo, notificationRepo)
+	subscription.NewRestController(engine, subscriptionUseCase)
+	go subscriptionUseCase.StartRenewalWorker(context.Background(), time.Hour)
+
+	// Enrollment Invite
+	inviteRepo := invite.NewRepository(db)
+	inviteUseCase := invite.NewUseCase(inviteRepo, courseUseCase, courseEnrollUseCase)
+	invite.NewRestController(engine, inviteUseCase)
+
+	// Gift
+	giftRepo := gift.NewRepository(db, walletRepo)
+	giftUseCase := gift.NewUseCase(giftRepo, courseUseCase, courseEnrollUseCase, userRepo, notificationRepo, mailDialer)
+	gift.NewRestController(engine, giftUseCase)
+
+	// Wishlist
+	wishlistRepo := wishlist.NewRepository(db)
+	wishlistUseCase := wishlist.NewUseCase(wishlistRepo, courseUseCase, courseEnrollUseCase, userRepo, notificationRepo, mailDialer)
+	courseUseCase.PriceWatcher = wishlistUseCase
+	wishlist.NewRestController(engine, wishlistUseCase)
+	go wishlistUseCase.StartAlertWorker(context.Background(), time.Hour)
+
+	// Release
+	releaseRepo := release.NewRepository(db)
+	releaseUseCase := release.NewUseCase(releaseRepo, notificationRepo)
+	go releaseUseCase.StartNotifyWorker(context.Background(), time.Hour)
+
 	// Attachment
 	attachmentRepo := attachment.NewRepository(db)
 	attachmentUseCase := attachment.NewUseCase(attachmentRepo, uploader)
 	attachment.NewRestController(engine, attachmentUseCase)
 
+	// Revision
+	revisionRepo := revision.NewRepository(db)
+	revisionUseCase := revision.NewUseCase(revisionRepo)
+
 	assignmentRepo := assignment.NewRepository(db)
-	assignmentUseCase := assignment.NewUseCase(assignmentRepo, attachmentUseCase)
+	assignmentUseCase := assignment.NewUseCase(assignmentRepo, attachmentUseCase, revisionUseCase, notificationRepo)
 	assignment.NewRestController(engine, assignmentUseCase, courseUseCase)
 
+	// Certificate
+	certificateRepo := certificate.NewRepository(db)
+	certificateUseCase := certificate.NewUseCase(certificateRepo, courseRepo, userRepo, uploader, mailDialer)
+	certificate.NewRestController(engine, certificateUseCase)
+
+	// Similarity
+	similarityRepo := similarity.NewRepository(db)
+	similarityUseCase := similarity.NewUseCase(similarityRepo, assignmentRepo, courseRepo, attachmentUseCase)
+	similarity.NewRestController(engine, similarityUseCase)
+
 	// Submission
 	submissionRepo := submission.NewRepository(db)
 	submissionUseCase := submission.NewUseCase(submissionRepo, assignmentRepo, *attachmentUseCase, courseRepo,
-		courseEnrollRepo, userRepo, notificationRepo, mailDialer)
+		courseEnrollUseCase, userRepo, notificationRepo, mailDialer)
+	submissionUseCase.CertificateUc = certificateUseCase
+	submissionUseCase.SimilarityUc = similarityUseCase
 	submission.NewRestController(engine, submissionUseCase)
 
+	// Peer Review
+	peerReviewRepo := peerreview.NewRepository(db)
+	peerReviewUseCase := peerreview.NewUseCase(peerReviewRepo, assignmentRepo, courseRepo, submissionUseCase,
+		attachmentUseCase, notificationRepo)
+	peerreview.NewRestController(engine, peerReviewUseCase)
+	go peerReviewUseCase.StartAssignWorker(context.Background(), time.Hour)
+
 	materialRepo := material.NewRepository(db)
-	materialUsecase := material.NewUseCase(materialRepo, attachmentUseCase)
+	materialUsecase := material.NewUseCase(materialRepo, attachmentUseCase, revisionUseCase)
 	material.NewRestController(engine, materialUsecase, courseUseCase)
 
 	reviewRepo := review.NewRepository(db)
 	reviewUseCase := review.NewUseCase(reviewRepo, courseRepo, courseEnrollUseCase)
 	review.NewRestController(engine, reviewUseCase)
 
+	// Quiz
+	quizRepo := quiz.NewRepository(db)
+	quizUseCase := quiz.NewUseCase(quizRepo, courseUseCase, courseEnrollUseCase)
+	quizUseCase.CertificateUc = certificateUseCase
+	quiz.NewRestController(engine, quizUseCase)
+
+	// Gradebook
+	gradebookRepo := gradebook.NewRepository(db)
+	gradebookUseCase := gradebook.NewUseCase(gradebookRepo, courseUseCase, courseEnrollUseCase)
+	gradebook.NewRestController(engine, gradebookUseCase)
+
+	// Team
+	teamRepo := team.NewRepository(db)
+	teamUseCase := team.NewUseCase(teamRepo, courseUseCase, courseEnrollUseCase)
+	team.NewRestController(engine, teamUseCase)
+
+	// Cohort
+	cohortRepo := cohort.NewRepository(db)
+	cohortUseCase := cohort.NewUseCase(cohortRepo, courseUseCase, assignmentRepo, courseEnrollUseCase, notificationRepo)
+	cohort.NewRestController(engine, cohortUseCase)
+
 	// Forum
 	forumRepo := forum.NewRepository(db)
-	forumUseCase := forum.NewUseCase(forumRepo, courseEnrollUseCase, courseRepo)
+	forumUseCase := forum.NewUseCase(forumRepo, courseEnrollUseCase, courseRepo, cohortUseCase)
 	forum.NewRestController(engine, forumUseCase)
 
 	if err := engine.Run(":" + config.Env.ApiPort); err != nil {
@@ -1,9 +1,11 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/midtrans/midtrans-go"
@@ -43,6 +45,9 @@ type environmentVariables struct {
 
 	MidtransServerKey   string
 	MidtransEnvironment midtrans.EnvironmentType
+
+	BaseCurrency  string
+	ExchangeRates map[string]float64
 }
 
 var Env *environmentVariables
@@ -110,5 +115,38 @@ func LoadEnv() {
 	//	env.MidtransEnvironment = midtrans.Production
 	//}
 
+	env.BaseCurrency = strings.ToUpper(os.Getenv("BASE_CURRENCY"))
+	if env.BaseCurrency == "" {
+		env.BaseCurrency = "IDR"
+	}
+	env.ExchangeRates, err = parseExchangeRates(os.Getenv("EXCHANGE_RATES"))
+	if err != nil {
+		log.Fatal("Fail to parse EXCHANGE_RATES: ", err)
+	}
+
 	Env = env
 }
+
+// parseExchangeRates reads a list like "USD=16250,EUR=17600" where every rate is the value of one unit of
+// that currency in the base currency
+func parseExchangeRates(raw string) (map[string]float64, error) {
+	rates := make(map[string]float64)
+	if raw == "" {
+		return rates, nil
+	}
+
+	for _, pair := range strings.Split(raw, ",") {
+		code, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid pair %q", pair)
+		}
+
+		rate, err := strconv.ParseFloat(value, 64)
+		if err != nil || rate <= 0 {
+			return nil, fmt.Errorf("invalid rate for %s", code)
+		}
+		rates[strings.ToUpper(code)] = rate
+	}
+
+	return rates, nil
+}
@@ -69,7 +69,8 @@ func migratePostgresqlTables(db *gorm.DB, migrations ...any) error {
 		DO $$ BEGIN
 			CREATE TYPE user_role AS ENUM (
 				'student',
-				'instructor'
+				'instructor',
+				'admin'
 			);
 		EXCEPTION
 			WHEN duplicate_object THEN null;
@@ -78,6 +79,10 @@ func migratePostgresqlTables(db *gorm.DB, migrations ...any) error {
 		return err
 	}
 
+	if err := db.Exec(`ALTER TYPE user_role ADD VALUE IF NOT EXISTS 'admin'`).Error; err != nil {
+		return err
+	}
+
 	if err := db.Exec(`
 		DO $$ BEGIN
 			CREATE TYPE course_difficulty AS ENUM (
@@ -122,28 +127,153 @@ func migratePostgresqlTables(db *gorm.DB, migrations ...any) error {
 	}
 
 	if err := db.Exec(`
-        DO $$ BEGIN
-            CREATE TYPE course_category AS ENUM (
-                'Web Development',
-                'Game Development',
-                'Cloud Computing',
-                'Data Science & Analytics',
-                'Programming Languages',
-                'Cybersecurity',
-                'Mobile App Development',
-                'Database Management',
-                'Software Development',
-                'DevOps & Automation',
-                'Networking',
-                'AI & Machine Learning',
-                'Internet of Things (IoT)',
-                'Blockchain & Cryptocurrency',
-                'Augmented Reality (AR) & Virtual Reality (VR)'
-            );
-        EXCEPTION
-            WHEN duplicate_object THEN null;
-        END $$;
-    `).Error; err != nil {
+		DO $$ BEGIN
+			CREATE TYPE prerequisite_policy AS ENUM (
+				'none',
+				'warn',
+				'block'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE subscription_status AS ENUM (
+				'active',
+				'past_due',
+				'canceled'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE cohort_member_status AS ENUM (
+				'enrolled',
+				'waitlisted'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE gift_status AS ENUM (
+				'pending',
+				'redeemed',
+				'refunded'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE material_type AS ENUM (
+				'attachment',
+				'text',
+				'video',
+				'embed'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE revision_item_type AS ENUM (
+				'material',
+				'assignment'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE question_type AS ENUM (
+				'multiple_choice',
+				'multi_select',
+				'true_false',
+				'numeric',
+				'short_answer'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE late_policy AS ENUM (
+				'hard',
+				'soft',
+				'penalty'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE grading_policy AS ENUM (
+				'latest',
+				'highest',
+				'average'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	if err := db.Exec(`
+		DO $$ BEGIN
+			CREATE TYPE submission_status AS ENUM (
+				'submitted',
+				'in_review',
+				'graded',
+				'returned'
+			);
+		EXCEPTION
+			WHEN duplicate_object THEN null;
+		END $$;
+	`).Error; err != nil {
+		return err
+	}
+
+	// views are rebuilt after the tables so AutoMigrate is free to alter the columns they read
+	if err := db.Exec(`DROP VIEW IF EXISTS assignment_grades`).Error; err != nil {
+		return err
+	}
+
+	if err := migrateCourseCurrency(db); err != nil {
 		return err
 	}
 
@@ -153,5 +283,107 @@ func migratePostgresqlTables(db *gorm.DB, migrations ...any) error {
 		return err
 	}
 
+	if err := migrateCourseCategories(db); err != nil {
+		return err
+	}
+
+	if err := migrateSubmissionStatus(db); err != nil {
+		return err
+	}
+
+	if err := createAssignmentGradesView(db); err != nil {
+		return err
+	}
+
 	return nil
 }
+
+// createAssignmentGradesView defines assignment_grades, the grade of each student for each assignment they
+// submitted to. It applies the grading policy of the assignment to the graded attempts, released_grade only
+// looks at the attempts released to the student. A team submission counts for each of its members with their
+// adjustment added to the grade of the team. Queries that need the grade of an assignment read it from here
+// instead of the submissions
+func createAssignmentGradesView(db *gorm.DB) error {
+	return db.Exec(`
+		CREATE VIEW assignment_grades AS
+		SELECT s.assignment_id, s.user_id, a.course_id,
+			COUNT(*) AS attempts,
+			CASE a.grading_policy
+				WHEN 'highest' THEN MAX(s.grade)
+				WHEN 'average' THEN ROUND(AVG(s.grade), 1)
+				ELSE (ARRAY_AGG(s.grade ORDER BY s.attempt DESC) FILTER (WHERE s.grade IS NOT NULL))[1]
+			END AS grade,
+			CASE a.grading_policy
+				WHEN 'highest' THEN MAX(s.grade) FILTER (WHERE s.released_at IS NOT NULL)
+				WHEN 'average' THEN ROUND(AVG(s.grade) FILTER (WHERE s.released_at IS NOT NULL), 1)
+				ELSE (ARRAY_AGG(s.grade ORDER BY s.attempt DESC) FILTER (WHERE s.grade IS NOT NULL AND s.released_at IS NOT NULL))[1]
+			END AS released_grade
+		FROM (
+			SELECT s.assignment_id, s.user_id, s.attempt, s.grade, s.released_at
+			FROM submissions s
+			WHERE s.deleted_at IS NULL
+				AND NOT EXISTS (SELECT 1 FROM submission_members m WHERE m.submission_id = s.id)
+			UNION ALL
+			SELECT s.assignment_id, m.user_id, s.attempt, LEAST(GREATEST(s.grade + m.adjustment, 0), 100), s.released_at
+			FROM submissions s
+			JOIN submission_members m ON m.submission_id = s.id
+			WHERE s.deleted_at IS NULL
+		) s
+		JOIN assignments a ON a.id = s.assignment_id AND a.deleted_at IS NULL
+		GROUP BY s.assignment_id, s.user_id, a.course_id, a.grading_policy
+	`).Error
+}
+
+// migrateSubmissionStatus moves submissions from before the status existed onto it. Their grade defaulted to
+// zero, so a zero is taken as not graded yet and any other grade as graded and released when it was last updated
+func migrateSubmissionStatus(db *gorm.DB) error {
+	return db.Exec(`
+		UPDATE submissions SET status = 'graded', graded_at = updated_at, released_at = updated_at
+		WHERE status = 'submitted' AND graded_at IS NULL AND grade > 0;
+
+		UPDATE submissions SET grade = NULL
+		WHERE status = 'submitted' AND graded_at IS NULL AND grade = 0;
+	`).Error
+}
+
+// migrateCourseCurrency gives the courses stored without a currency the base currency before AutoMigrate makes
+// it required. The column has no default as the base currency is configured by BASE_CURRENCY
+func migrateCourseCurrency(db *gorm.DB) error {
+	if !db.Migrator().HasTable("courses") {
+		return nil
+	}
+
+	if err := db.Exec(`ALTER TABLE courses ADD COLUMN IF NOT EXISTS currency char(3)`).Error; err != nil {
+		return err
+	}
+	if err := db.Exec(`ALTER TABLE courses ALTER COLUMN currency DROP DEFAULT`).Error; err != nil {
+		return err
+	}
+	return db.Exec(`UPDATE courses SET currency = ? WHERE currency IS NULL`, Env.BaseCurrency).Error
+}
+
+// migrateCourseCategories moves databases created before categories became a table off the old course_category enum,
+// every enum value becomes a category row and courses are pointed at it before the enum column is dropped
+func migrateCourseCategories(db *gorm.DB) error {
+	return db.Exec(`
+		DO $$ BEGIN
+			IF EXISTS (
+				SELECT 1 FROM information_schema.columns
+				WHERE table_name = 'courses' AND column_name = 'category'
+			) THEN
+				INSERT INTO categories (id, name, slug, created_at, updated_at)
+				SELECT gen_random_uuid(), v::text, trim(both '-' from regexp_replace(lower(v::text), '[^a-z0-9]+', '-', 'g')), now(), now()
+				FROM unnest(enum_range(NULL::course_category)) AS v
+				ON CONFLICT (slug) DO NOTHING;
+
+				UPDATE courses SET category_id = categories.id
+				FROM categories
+				WHERE courses.category_id IS NULL AND categories.name = courses.category::text;
+
+				ALTER TABLE courses DROP COLUMN category;
+			END IF;
+
+			DROP TYPE IF EXISTS course_category;
+		END $$;
+	`).Error
+}
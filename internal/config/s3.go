@@ -1,10 +1,13 @@
 package config
 
 import (
+	"bytes"
 	"fmt"
 
 	"mime/multipart"
 	"net/url"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/session"
@@ -13,6 +16,8 @@ import (
 
 type FileUploader interface {
 	UploadFile(key string, fileHeader *multipart.FileHeader) (string, error)
+	UploadBytes(key string, content []byte, contentType string) (string, error)
+	PresignURL(fileURL string, expiry time.Duration) (string, error)
 }
 type S3FileUploader struct {
 	S3Service *s3.S3
@@ -57,3 +62,43 @@ func (uploader *S3FileUploader) UploadFile(key string, fileHeader *multipart.Fil
 	urlStr := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", Env.AwsBucketName, aws.StringValue(uploader.S3Service.Config.Region), encodedKey)
 	return urlStr, nil
 }
+
+// UploadBytes uploads in-memory content, such as generated documents, to the specified S3 bucket
+func (uploader *S3FileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	_, err := uploader.S3Service.PutObject(&s3.PutObjectInput{
+		Bucket:      aws.String(Env.AwsBucketName),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(content),
+		ContentType: aws.String(contentType),
+	})
+	if err != nil {
+		return "", fmt.Errorf("unable to upload %q to %q: %v", key, Env.AwsBucketName, err)
+	}
+
+	urlStr := fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", Env.AwsBucketName, aws.StringValue(uploader.S3Service.Config.Region), url.PathEscape(key))
+	return urlStr, nil
+}
+
+// PresignURL turns the permanent URL of an uploaded object into a download link that expires,
+// URLs pointing outside the bucket are returned unchanged
+func (uploader *S3FileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	u, err := url.Parse(fileURL)
+	if err != nil {
+		return "", fmt.Errorf("unable to parse %q: %v", fileURL, err)
+	}
+
+	bucketHost := fmt.Sprintf("%s.s3.%s.amazonaws.com", Env.AwsBucketName, aws.StringValue(uploader.S3Service.Config.Region))
+	if u.Host != bucketHost {
+		return fileURL, nil
+	}
+
+	req, _ := uploader.S3Service.GetObjectRequest(&s3.GetObjectInput{
+		Bucket: aws.String(Env.AwsBucketName),
+		Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+	})
+	signedURL, err := req.Presign(expiry)
+	if err != nil {
+		return "", fmt.Errorf("unable to presign %q: %v", fileURL, err)
+	}
+	return signedURL, nil
+}
@@ -0,0 +1,82 @@
+package currency
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+)
+
+var ErrUnsupportedCurrency = errors.New("unsupported currency")
+
+// minorUnits is the number of decimal places each currency amount is stored with, so 1999 USD means 19.99
+var minorUnits = map[string]int{
+	"IDR": 0,
+	"JPY": 0,
+	"KRW": 0,
+	"VND": 0,
+}
+
+func decimals(code string) int {
+	if d, ok := minorUnits[code]; ok {
+		return d
+	}
+	return 2
+}
+
+// rate returns the value of one major unit of the currency in the base currency
+func rate(code string) (float64, bool) {
+	if code == config.Env.BaseCurrency {
+		return 1, true
+	}
+	r, ok := config.Env.ExchangeRates[code]
+	return r, ok
+}
+
+func Base() string {
+	return config.Env.BaseCurrency
+}
+
+func Normalize(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func IsSupported(code string) bool {
+	_, ok := rate(Normalize(code))
+	return ok
+}
+
+// Supported lists the base currency followed by every currency with a configured exchange rate
+func Supported() []string {
+	codes := []string{config.Env.BaseCurrency}
+	for code := range config.Env.ExchangeRates {
+		if code != config.Env.BaseCurrency {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes[1:])
+	return codes
+}
+
+// Convert converts an amount in minor units between two currencies through the base currency
+func Convert(amount int64, from, to string) (int64, error) {
+	from, to = Normalize(from), Normalize(to)
+	if from == to {
+		return amount, nil
+	}
+
+	fromRate, ok := rate(from)
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+	toRate, ok := rate(to)
+	if !ok {
+		return 0, ErrUnsupportedCurrency
+	}
+
+	major := float64(amount) / math.Pow10(decimals(from))
+	converted := major * fromRate / toRate
+	return int64(math.Round(converted * math.Pow10(decimals(to)))), nil
+}
@@ -0,0 +1,71 @@
+package currency
+
+import (
+	"os"
+	"testing"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadEnv() {
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	_ = os.Setenv("BASE_CURRENCY", "IDR")
+	_ = os.Setenv("EXCHANGE_RATES", "USD=16000,EUR=17500,JPY=105")
+	config.LoadEnv()
+}
+
+func TestConvert(t *testing.T) {
+	loadEnv()
+
+	tests := []struct {
+		name     string
+		amount   int64
+		from, to string
+		want     int64
+		err      error
+	}{
+		{name: "same currency", amount: 1999, from: "USD", to: "usd", want: 1999},
+		{name: "cents to base", amount: 1999, from: "USD", to: "IDR", want: 319840},
+		{name: "base to cents", amount: 160000, from: "IDR", to: "USD", want: 1000},
+		{name: "rounds to the nearest cent", amount: 100000, from: "IDR", to: "EUR", want: 571},
+		{name: "between two foreign currencies", amount: 1000, from: "USD", to: "JPY", want: 1524},
+		{name: "zero decimal to cents", amount: 1000, from: "JPY", to: "USD", want: 656},
+		{name: "codes are normalized", amount: 100, from: " usd ", to: "idr", want: 16000},
+		{name: "unsupported source", amount: 100, from: "GBP", to: "IDR", err: ErrUnsupportedCurrency},
+		{name: "unsupported target", amount: 100, from: "IDR", to: "GBP", err: ErrUnsupportedCurrency},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Convert(tt.amount, tt.from, tt.to)
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestIsSupported(t *testing.T) {
+	loadEnv()
+
+	assert.True(t, IsSupported("IDR"))
+	assert.True(t, IsSupported("usd"))
+	assert.False(t, IsSupported("GBP"))
+	assert.False(t, IsSupported(""))
+}
+
+func TestSupported(t *testing.T) {
+	loadEnv()
+
+	assert.Equal(t, []string{"IDR", "EUR", "JPY", "USD"}, Supported())
+}
+
+func TestNormalize(t *testing.T) {
+	assert.Equal(t, "USD", Normalize(" usd\n"))
+	assert.Equal(t, "", Normalize("  "))
+}
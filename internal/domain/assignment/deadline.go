@@ -0,0 +1,56 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"math"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DueFor returns the deadline of the assignment for one student: their extension when they have one,
+// the cohort deadline inside a cohort and Due otherwise. Nil means the assignment has no deadline
+func DueFor(ctx context.Context, repo Repository, a *schema.Assignment, userID uuid.UUID) (*time.Time, error) {
+	if a.Due == nil && a.DueOffsetDays == nil {
+		return nil, nil
+	}
+
+	extension, err := repo.GetExtension(ctx, a.ID, userID)
+	if err == nil {
+		return &extension.Due, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if a.DueOffsetDays != nil {
+		cohortStart, err := repo.GetCohortStart(ctx, a.CourseID, userID)
+		if err != nil {
+			return nil, err
+		}
+		if cohortStart != nil {
+			return DueInCohort(a, *cohortStart), nil
+		}
+	}
+	return a.Due, nil
+}
+
+// DaysLate counts the started days between the deadline and at, 0 when at is on time
+func DaysLate(due *time.Time, at time.Time) int {
+	if due == nil || !at.After(*due) {
+		return 0
+	}
+	return int(math.Ceil(at.Sub(*due).Hours() / 24))
+}
+
+// LatePenalty is the part of grade taken off a submission daysLate days late, it never takes more than the grade
+func LatePenalty(a *schema.Assignment, grade float64, daysLate int) float64 {
+	if a.LatePolicy != schema.LatePolicyPenalty || a.LatePenaltyPercent == nil || daysLate <= 0 {
+		return 0
+	}
+	percent := math.Min(*a.LatePenaltyPercent*float64(daysLate), 100)
+	return math.Round(grade*percent/10) / 10
+}
@@ -4,20 +4,51 @@ import (
 	"mime/multipart"
 	"time"
 
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 )
 
 type CreateAssignmentRequest struct {
-	CourseID    string     `json:"course_id"`
-	Title       string     `json:"title"`
-	Description string     `json:"description"`
-	Due         *time.Time `json:"due,omitempty"`
+	CourseID           string               `json:"course_id"`
+	Title              string               `json:"title"`
+	Description        string               `json:"description"`
+	Due                *time.Time           `json:"due,omitempty"`
+	DueOffsetDays      *int                 `json:"due_offset_days,omitempty" binding:"omitempty,min=0"`
+	LatePolicy         schema.LatePolicy    `json:"late_policy,omitempty" binding:"omitempty,oneof=hard soft penalty"`
+	LatePenaltyPercent *float64             `json:"late_penalty_percent,omitempty" binding:"omitempty,gt=0,max=100"`
+	MaxAttempts        *int                 `json:"max_attempts,omitempty" binding:"omitempty,min=1,max=100"`
+	GradingPolicy      schema.GradingPolicy `json:"grading_policy,omitempty" binding:"omitempty,oneof=latest highest average"`
+	CategoryID         *string              `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	Weight             *float64             `json:"weight,omitempty" binding:"omitempty,min=0,max=1000"`
+	HoldGrades         *bool                `json:"hold_grades,omitempty"`
+	PeerReviewCount    *int                 `json:"peer_review_count,omitempty" binding:"omitempty,min=0,max=10"`
+	TeamSetID          *string              `json:"team_set_id,omitempty" binding:"omitempty,uuid"`
+	ReleaseAt          *time.Time           `json:"release_at,omitempty"`
+	ReleaseAfterDays   *int                 `json:"release_after_days,omitempty" binding:"omitempty,min=0,max=3650"`
 }
 
 type UpdateAssignmentRequest struct {
-	Title       *string    `json:"title,omitempty"`
-	Description *string    `json:"description,omitempty"`
-	Due         *time.Time `json:"due,omitempty"`
+	Title              *string              `json:"title,omitempty"`
+	Description        *string              `json:"description,omitempty"`
+	Due                *time.Time           `json:"due,omitempty"`
+	DueOffsetDays      *int                 `json:"due_offset_days,omitempty"`
+	LatePolicy         schema.LatePolicy    `json:"late_policy,omitempty" binding:"omitempty,oneof=hard soft penalty"`
+	LatePenaltyPercent *float64             `json:"late_penalty_percent,omitempty" binding:"omitempty,gt=0,max=100"`
+	MaxAttempts        *int                 `json:"max_attempts,omitempty" binding:"omitempty,min=1,max=100"`
+	GradingPolicy      schema.GradingPolicy `json:"grading_policy,omitempty" binding:"omitempty,oneof=latest highest average"`
+	CategoryID         *string              `json:"category_id,omitempty" binding:"omitempty,uuid"`
+	Weight             *float64             `json:"weight,omitempty" binding:"omitempty,min=0,max=1000"`
+	HoldGrades         *bool                `json:"hold_grades,omitempty"`
+	PeerReviewCount    *int                 `json:"peer_review_count,omitempty" binding:"omitempty,min=0,max=10"`
+	TeamSetID          *string              `json:"team_set_id,omitempty" binding:"omitempty,uuid"`
+	ReleaseAt          *time.Time           `json:"release_at,omitempty"`
+	ReleaseAfterDays   *int                 `json:"release_after_days,omitempty" binding:"omitempty,min=0,max=3650"`
+	// ClearRelease drops the current schedule before applying the release fields, releasing the assignment now
+	ClearRelease bool `json:"clear_release"`
+	// ClearCategory takes the assignment out of its grade category
+	ClearCategory bool `json:"clear_category"`
+	// ClearTeamSet turns a group assignment back into individual work
+	ClearTeamSet bool `json:"clear_team_set"`
 }
 
 type AssignmentResponse struct {
@@ -30,6 +61,29 @@ type AssignmentResponse struct {
 	UpdatedAt   time.Time `json:"updated_at"`
 }
 
+// GrantExtensionRequest sets the personal deadline of a student, granting again replaces the previous one
+type GrantExtensionRequest struct {
+	Due    time.Time `json:"due" binding:"required"`
+	Reason string    `json:"reason" binding:"max=500"`
+}
+
+// SetRubricRequest replaces the rubric of an assignment, an empty list of criteria removes it
+type SetRubricRequest struct {
+	Criteria []RubricCriterionRequest `json:"criteria" binding:"max=50,dive"`
+}
+
+type RubricCriterionRequest struct {
+	Title       string               `json:"title" binding:"required,max=255"`
+	Description string               `json:"description" binding:"max=1000"`
+	Levels      []RubricLevelRequest `json:"levels" binding:"required,min=1,max=10,dive"`
+}
+
+type RubricLevelRequest struct {
+	Title       string  `json:"title" binding:"required,max=255"`
+	Description string  `json:"description" binding:"max=1000"`
+	Points      float64 `json:"points" binding:"min=0,max=1000"`
+}
+
 type AttachmentInput struct {
 	File        *multipart.FileHeader `form:"file" binding:"required"`
 	Description string                `form:"description"`
@@ -42,4 +42,44 @@ var (
 	ErrEditConflict = apierror.NewApiErrorBuilder().
 			WithHttpStatus(http.StatusConflict).
 			WithMessage("EDIT_CONFLICT")
+
+	ErrAssignmentLocked = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("ASSIGNMENT_LOCKED")
+
+	ErrAssignmentNotReleased = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusForbidden).
+					WithMessage("ASSIGNMENT_NOT_RELEASED")
+
+	ErrInvalidLatePolicy = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_LATE_POLICY")
+
+	ErrExtensionNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("EXTENSION_NOT_FOUND")
+
+	ErrStudentNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("STUDENT_NOT_FOUND")
+
+	ErrCategoryNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("GRADE_CATEGORY_NOT_FOUND")
+
+	ErrInvalidRubric = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_RUBRIC")
+
+	ErrInvalidPeerReview = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_PEER_REVIEW")
+
+	ErrTeamSetNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("TEAM_SET_NOT_FOUND")
+
+	ErrInvalidGroupWork = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_GROUP_WORK")
 )
@@ -0,0 +1,85 @@
+package assignment
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// DueFor returns the deadline of the assignment for the student, see the package function DueFor
+func (uc *UseCase) DueFor(ctx context.Context, a *schema.Assignment, userID uuid.UUID) (*time.Time, error) {
+	return DueFor(ctx, uc.repo, a, userID)
+}
+
+// GrantExtension gives the student a personal deadline for the assignment and lets them know
+func (uc *UseCase) GrantExtension(ctx context.Context, id, userID uuid.UUID, req GrantExtensionRequest) (*schema.AssignmentExtension, error) {
+	a, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAssignmentNotFound.Build()
+	}
+
+	grantedBy, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	extension := &schema.AssignmentExtension{
+		AssignmentID: a.ID,
+		UserID:       userID,
+		Due:          req.Due,
+		Reason:       req.Reason,
+		GrantedBy:    grantedBy,
+	}
+	if err := uc.repo.SaveExtension(ctx, extension); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23503" {
+			return nil, ErrStudentNotFound.Build()
+		}
+		log.Println("Error saving assignment extension: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	notificationID, err := uuid.NewV7()
+	if err == nil {
+		notif := schema.Notification{
+			ID:     notificationID,
+			UserID: userID,
+			Title:  "Deadline extended",
+			Detail: fmt.Sprintf("You can now hand in %s until %s", a.Title, req.Due.Format(time.RFC1123)),
+		}
+		if err := uc.notificationRepo.Create(&notif); err != nil {
+			log.Println("Error creating notification: ", err)
+		}
+	}
+
+	return extension, nil
+}
+
+// RevokeExtension puts the student back on the regular deadline
+func (uc *UseCase) RevokeExtension(ctx context.Context, id, userID uuid.UUID) error {
+	if err := uc.repo.DeleteExtension(ctx, id, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrExtensionNotFound.Build()
+		}
+		log.Println("Error deleting assignment extension: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+func (uc *UseCase) GetExtensions(ctx context.Context, id uuid.UUID) ([]schema.AssignmentExtension, error) {
+	extensions, err := uc.repo.GetExtensions(ctx, id)
+	if err != nil {
+		log.Println("Error getting assignment extensions: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return extensions, nil
+}
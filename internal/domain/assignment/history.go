@@ -0,0 +1,188 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// snapshot is the part of an assignment kept in its history
+type snapshot struct {
+	Title              string               `json:"title"`
+	Description        string               `json:"description"`
+	Due                *time.Time           `json:"due"`
+	DueOffsetDays      *int                 `json:"due_offset_days"`
+	LatePolicy         schema.LatePolicy    `json:"late_policy"`
+	LatePenaltyPercent *float64             `json:"late_penalty_percent"`
+	MaxAttempts        int                  `json:"max_attempts"`
+	GradingPolicy      schema.GradingPolicy `json:"grading_policy"`
+	ReleaseAt          *time.Time           `json:"release_at"`
+	ReleaseAfterDays   *int                 `json:"release_after_days"`
+	TeamSetID          *uuid.UUID           `json:"team_set_id"`
+	Attachments        []schema.Attachment  `json:"attachments"`
+}
+
+func snapshotOf(a *schema.Assignment) snapshot {
+	attachments := make([]schema.Attachment, len(a.Attachments))
+	copy(attachments, a.Attachments)
+
+	return snapshot{
+		Title:              a.Title,
+		Description:        a.Description,
+		Due:                a.Due,
+		DueOffsetDays:      a.DueOffsetDays,
+		LatePolicy:         a.LatePolicy,
+		LatePenaltyPercent: a.LatePenaltyPercent,
+		MaxAttempts:        a.MaxAttempts,
+		GradingPolicy:      a.GradingPolicy,
+		ReleaseAt:          a.ReleaseAt,
+		ReleaseAfterDays:   a.ReleaseAfterDays,
+		TeamSetID:          a.TeamSetID,
+		Attachments:        attachments,
+	}
+}
+
+func (s *snapshot) applyTo(a *schema.Assignment) {
+	a.Title = s.Title
+	a.Description = s.Description
+	a.Due = s.Due
+	a.DueOffsetDays = s.DueOffsetDays
+	a.LatePenaltyPercent = s.LatePenaltyPercent
+	// revisions from before these settings existed fall back to the defaults
+	a.LatePolicy, a.MaxAttempts, a.GradingPolicy = schema.LatePolicySoft, 1, schema.GradingPolicyLatest
+	if s.LatePolicy != "" {
+		a.LatePolicy = s.LatePolicy
+	}
+	if s.MaxAttempts > 0 {
+		a.MaxAttempts = s.MaxAttempts
+	}
+	if s.GradingPolicy != "" {
+		a.GradingPolicy = s.GradingPolicy
+	}
+	a.ReleaseAt = s.ReleaseAt
+	a.ReleaseAfterDays = s.ReleaseAfterDays
+	a.Attachments = s.Attachments
+	// the team set goes through changeTeamSet, it cannot change once students handed in
+}
+
+// teamSetRequest is the update moving an assignment to the team set of the snapshot, revisions from before
+// group work existed are individual work
+func (s *snapshot) teamSetRequest() UpdateAssignmentRequest {
+	if s.TeamSetID == nil {
+		return UpdateAssignmentRequest{ClearTeamSet: true}
+	}
+	teamSetID := s.TeamSetID.String()
+	return UpdateAssignmentRequest{TeamSetID: &teamSetID}
+}
+
+// record stores the edit in the history of the assignment and tells the students who already
+// submitted when the description they worked from changed
+func (uc *UseCase) record(ctx context.Context, a *schema.Assignment, before *snapshot, restoredFrom *int) error {
+	var from any
+	if before != nil {
+		from = before
+	}
+	if _, err := uc.revisionUseCase.Record(ctx, schema.RevisionAssignment, a.ID, from, snapshotOf(a), restoredFrom); err != nil {
+		return err
+	}
+
+	if before != nil && before.Description != a.Description {
+		uc.notifySubmitters(ctx, a)
+	}
+	return nil
+}
+
+func (uc *UseCase) notifySubmitters(ctx context.Context, a *schema.Assignment) {
+	userIDs, err := uc.repo.GetSubmitterIDs(ctx, a.ID)
+	if err != nil {
+		log.Println("Error getting assignment submitters: ", err)
+		return
+	}
+
+	for _, userID := range userIDs {
+		notificationID, err := uuid.NewV7()
+		if err != nil {
+			return
+		}
+
+		notif := schema.Notification{
+			ID:     notificationID,
+			UserID: userID,
+			Title:  "An assignment you submitted was updated",
+			Detail: fmt.Sprintf("The description of %s changed after you submitted, check whether your work still fits", a.Title),
+		}
+		if err := uc.notificationRepo.Create(&notif); err != nil {
+			log.Println("Error creating notification: ", err)
+		}
+	}
+}
+
+func (uc *UseCase) GetHistory(ctx context.Context, id uuid.UUID) ([]schema.Revision, error) {
+	return uc.revisionUseCase.GetHistory(ctx, schema.RevisionAssignment, id)
+}
+
+func (uc *UseCase) GetRevision(ctx context.Context, id uuid.UUID, version int) (*schema.Revision, error) {
+	return uc.revisionUseCase.GetVersion(ctx, schema.RevisionAssignment, id, version)
+}
+
+// RestoreRevision brings the assignment back to an older version, attachments removed since then are
+// linked again and the ones added since are removed. The restored settings are checked like an update
+// would check them. The restore is stored as a new version
+func (uc *UseCase) RestoreRevision(ctx context.Context, id uuid.UUID, version int) (*schema.Revision, error) {
+	a, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrAssignmentNotFound.Build()
+	}
+
+	rev, err := uc.revisionUseCase.GetVersion(ctx, schema.RevisionAssignment, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored snapshot
+	if err := revision.Decode(rev.Snapshot, &restored); err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	before := snapshotOf(a)
+	kept := make(map[uuid.UUID]bool)
+	for _, att := range restored.Attachments {
+		kept[att.ID] = true
+	}
+
+	restored.applyTo(a)
+	if err := uc.changeTeamSet(ctx, a, restored.teamSetRequest()); err != nil {
+		return nil, err
+	}
+	if err := validateLatePolicy(a); err != nil {
+		return nil, err
+	}
+	if err := validatePeerReview(a); err != nil {
+		return nil, err
+	}
+	if err := uc.repo.Update(ctx, a); err != nil {
+		return nil, err
+	}
+	for _, att := range before.Attachments {
+		if !kept[att.ID] {
+			if err := uc.attachmentUseCase.DeleteAttachment(ctx, att.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	restoredRev, err := uc.revisionUseCase.Record(ctx, schema.RevisionAssignment, id, before, snapshotOf(a), &version)
+	if err != nil {
+		return nil, err
+	}
+	if before.Description != a.Description {
+		uc.notifySubmitters(ctx, a)
+	}
+	return restoredRev, nil
+}
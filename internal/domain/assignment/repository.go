@@ -2,10 +2,12 @@ package assignment
 
 import (
 	"context"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -14,6 +16,16 @@ type Repository interface {
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*schema.Assignment, error)
 	GetByCourseID(ctx context.Context, courseId uuid.UUID) ([]*schema.Assignment, error)
+	GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error)
+	SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error
+	DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error
+	GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error)
+	GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error)
+	GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error)
+	CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error)
+	TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error)
+	GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error)
+	ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error
 }
 
 type repository struct {
@@ -47,3 +59,117 @@ func (r *repository) GetByCourseID(ctx context.Context, courseId uuid.UUID) ([]*
 	result := r.db.Preload("Attachments").Where("course_id = ?", courseId).Find(&assignments)
 	return assignments, result.Error
 }
+
+// GetSubmitterIDs returns the students who have a submission for the assignment
+func (r *repository) GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Model(&schema.Submission{}).
+		Distinct("user_id").
+		Where("assignment_id = ?", id).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// SaveExtension grants the extension, a student has at most one per assignment so a new one replaces it
+func (r *repository) SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error {
+	return r.db.WithContext(ctx).
+		Omit("User", "Assignment").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "assignment_id"}, {Name: "user_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"due", "reason", "granted_by", "updated_at"}),
+		}).
+		Create(extension).Error
+}
+
+func (r *repository) DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error {
+	res := r.db.WithContext(ctx).
+		Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		Delete(&schema.AssignmentExtension{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error) {
+	var extension schema.AssignmentExtension
+	err := r.db.WithContext(ctx).
+		Where("assignment_id = ? AND user_id = ?", assignmentID, userID).
+		First(&extension).Error
+	if err != nil {
+		return nil, err
+	}
+	return &extension, nil
+}
+
+func (r *repository) GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error) {
+	var extensions []schema.AssignmentExtension
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("assignment_id = ?", assignmentID).
+		Order("due").
+		Find(&extensions).Error
+	return extensions, err
+}
+
+// GetCohortStart returns the start of the cohort the user holds a seat in for the course, nil for self-paced students
+func (r *repository) GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error) {
+	var startsAt []time.Time
+	err := r.db.WithContext(ctx).Model(&schema.Cohort{}).
+		Joins("JOIN cohort_members ON cohort_members.cohort_id = cohorts.id").
+		Where("cohorts.course_id = ? AND cohort_members.user_id = ? AND cohort_members.status = ?",
+			courseID, userID, schema.CohortMemberEnrolled).
+		Limit(1).
+		Pluck("cohorts.starts_at", &startsAt).Error
+	if err != nil || len(startsAt) == 0 {
+		return nil, err
+	}
+	return &startsAt[0], nil
+}
+
+// CategoryExists reports whether the grade category belongs to the course
+func (r *repository) CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.GradeCategory{}).
+		Where("id = ? AND course_id = ?", categoryID, courseID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// TeamSetExists reports whether the team set belongs to the course
+func (r *repository) TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.TeamSet{}).
+		Where("id = ? AND course_id = ?", teamSetID, courseID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetRubric returns the criteria of the assignment in order, each with its levels from the lowest points up
+func (r *repository) GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error) {
+	var criteria []schema.RubricCriterion
+	err := r.db.WithContext(ctx).
+		Preload("Levels", func(db *gorm.DB) *gorm.DB {
+			return db.Order("points, title")
+		}).
+		Where("assignment_id = ?", assignmentID).
+		Order("position").
+		Find(&criteria).Error
+	return criteria, err
+}
+
+// ReplaceRubric swaps the whole rubric of the assignment, scores already given keep their copy of the old one
+func (r *repository) ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", assignmentID).Delete(&schema.RubricCriterion{}).Error; err != nil {
+			return err
+		}
+		if len(criteria) == 0 {
+			return nil
+		}
+		return tx.Omit("Assignment").Create(&criteria).Error
+	})
+}
@@ -2,6 +2,7 @@ package assignment
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
@@ -22,11 +23,19 @@ func NewRestController(r *gin.Engine, uc *UseCase, cuc *course.UseCase) {
 	assignmentGroup := r.Group("/v1/assignments")
 	{
 		assignmentGroup.POST("", middleware.Authenticate(), middleware.RequireRole("instructor"), c.createAssignment)
-		assignmentGroup.GET("/:id", c.getAssignmentByID)
+		assignmentGroup.GET("/:id", middleware.Authenticate(), c.getAssignmentByID)
 		assignmentGroup.PUT("/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.updateAssignment)
 		assignmentGroup.POST("/addAttachment/:assignmentId", middleware.Authenticate(), middleware.RequireRole("instructor"), c.addAttachment)
 		assignmentGroup.DELETE("/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.deleteAssignment)
 		assignmentGroup.GET("/course/:courseId", middleware.Authenticate(), c.getAssignmentsByCourse)
+		assignmentGroup.GET("/:id/revisions", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getHistory)
+		assignmentGroup.GET("/:id/revisions/:version", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getRevision)
+		assignmentGroup.POST("/:id/revisions/:version/restore", middleware.Authenticate(), middleware.RequireRole("instructor"), c.restoreRevision)
+		assignmentGroup.GET("/:id/extensions", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getExtensions)
+		assignmentGroup.PUT("/:id/extensions/:userId", middleware.Authenticate(), middleware.RequireRole("instructor"), c.grantExtension)
+		assignmentGroup.DELETE("/:id/extensions/:userId", middleware.Authenticate(), middleware.RequireRole("instructor"), c.revokeExtension)
+		assignmentGroup.GET("/:id/rubric", middleware.Authenticate(), c.getRubric)
+		assignmentGroup.PUT("/:id/rubric", middleware.Authenticate(), middleware.RequireRole("instructor"), c.setRubric)
 	}
 }
 
@@ -61,7 +70,7 @@ func (c *RestController) createAssignment(ctx *gin.Context) {
 	}
 
 	if err := c.useCase.CreateAssignment(ctx, req, courseID); err != nil {
-		response.NewRestResponse(http.StatusInternalServerError, "Failed to create assignment: "+err.Error(), nil).Send(ctx)
+		response.NewRestResponse(apierror.GetHttpStatus(err), "Failed to create assignment: "+err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
 	response.NewRestResponse(http.StatusCreated, "Assignment created successfully", nil).Send(ctx)
@@ -107,6 +116,46 @@ func (c *RestController) getAssignmentByID(ctx *gin.Context) {
 		response.NewRestResponse(http.StatusInternalServerError, "Failed to fetch assignment: "+err.Error(), nil).Send(ctx)
 		return
 	}
+
+	courseData, err := c.courseUseCase.GetByID(ctx, assignment.CourseID)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	viewer, err := c.courseUseCase.GetViewer(ctx, &courseData)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	if !viewer.CanOpen(false) {
+		err := ErrAssignmentLocked.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	if availableAt := viewer.AvailableAt(&assignment.ReleaseSchedule); availableAt != nil {
+		err := ErrAssignmentNotReleased.WithPayload(map[string]any{"available_at": availableAt}).Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	c.courseUseCase.OpenAssignment(assignment)
+
+	// Students see their own deadline, including an extension or the deadline of their cohort
+	if !viewer.IsStaff {
+		userID, err := uuid.Parse(ctx.GetString("user.id"))
+		if err != nil {
+			err = apierror.ErrTokenInvalid.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+			return
+		}
+		due, err := c.useCase.DueFor(ctx, assignment, userID)
+		if err != nil {
+			response.NewRestResponse(http.StatusInternalServerError, "Failed to fetch deadline: "+err.Error(), nil).Send(ctx)
+			return
+		}
+		assignment.Due = due
+	}
+
 	response.NewRestResponse(http.StatusOK, "Assignment retrieved successfully", assignment).Send(ctx)
 }
 
@@ -130,7 +179,7 @@ func (c *RestController) updateAssignment(ctx *gin.Context) {
 	}
 
 	if err := c.useCase.UpdateAssignment(ctx, id, req); err != nil {
-		response.NewRestResponse(http.StatusInternalServerError, "Failed to update assignment: "+err.Error(), nil).Send(ctx)
+		response.NewRestResponse(apierror.GetHttpStatus(err), "Failed to update assignment: "+err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
 	response.NewRestResponse(http.StatusOK, "Assignment updated successfully", nil).Send(ctx)
@@ -163,7 +212,13 @@ func (c *RestController) getAssignmentsByCourse(ctx *gin.Context) {
 		return
 	}
 
-	_, err = c.courseUseCase.GetByID(ctx, courseId)
+	courseData, err := c.courseUseCase.GetByID(ctx, courseId)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	viewer, err := c.courseUseCase.GetViewer(ctx, &courseData)
 	if err != nil {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
 		return
@@ -174,9 +229,236 @@ func (c *RestController) getAssignmentsByCourse(ctx *gin.Context) {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
 		return
 	}
+
+	// Outsiders and students waiting for a release still see what the course asks for, but not the attached files
+	for _, assignment := range assignments {
+		c.courseUseCase.ShowAssignment(viewer, assignment)
+	}
 	response.NewRestResponse(http.StatusOK, "Assignments retrieved successfully", assignments).Send(ctx)
 }
 
+func (c *RestController) getHistory(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	revisions, err := c.useCase.GetHistory(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Assignment history retrieved successfully", revisions).Send(ctx)
+}
+
+func (c *RestController) getRevision(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid version", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rev, err := c.useCase.GetRevision(ctx, id, version)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Assignment revision retrieved successfully", rev).Send(ctx)
+}
+
+func (c *RestController) restoreRevision(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid version", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rev, err := c.useCase.RestoreRevision(ctx, id, version)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Assignment restored successfully", rev).Send(ctx)
+}
+
+func (c *RestController) getExtensions(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	extensions, err := c.useCase.GetExtensions(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Extensions retrieved successfully", extensions).Send(ctx)
+}
+
+func (c *RestController) grantExtension(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid user ID", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	var req GrantExtensionRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid extension data: "+err.Error(), nil).Send(ctx)
+		return
+	}
+
+	extension, err := c.useCase.GrantExtension(ctx, id, userID, req)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Extension granted successfully", extension).Send(ctx)
+}
+
+func (c *RestController) revokeExtension(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	userID, err := uuid.Parse(ctx.Param("userId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid user ID", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	if err := c.useCase.RevokeExtension(ctx, id, userID); err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Extension revoked successfully", nil).Send(ctx)
+}
+
+// getRubric shows the rubric to everyone who can open the assignment, so students know how they are graded
+func (c *RestController) getRubric(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	assignment, err := c.useCase.GetAssignmentByID(ctx, id)
+	if err != nil {
+		err = ErrAssignmentNotFound.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	courseData, err := c.courseUseCase.GetByID(ctx, assignment.CourseID)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	viewer, err := c.courseUseCase.GetViewer(ctx, &courseData)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	if !viewer.CanOpen(false) {
+		err := ErrAssignmentLocked.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rubric, err := c.useCase.GetRubric(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Rubric retrieved successfully", rubric).Send(ctx)
+}
+
+func (c *RestController) setRubric(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyAssignmentOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	var req SetRubricRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid rubric data: "+err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rubric, err := c.useCase.SetRubric(ctx, id, req)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Rubric saved successfully", rubric).Send(ctx)
+}
+
 func (c *RestController) verifyAssignmentOwnership(ctx *gin.Context, assignmentId uuid.UUID) error {
 
 	ass, err := c.useCase.GetAssignmentByID(ctx, assignmentId)
@@ -0,0 +1,71 @@
+package assignment
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+func (uc *UseCase) GetRubric(ctx context.Context, id uuid.UUID) ([]schema.RubricCriterion, error) {
+	criteria, err := uc.repo.GetRubric(ctx, id)
+	if err != nil {
+		log.Println("Error getting rubric: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return criteria, nil
+}
+
+// SetRubric replaces the rubric of the assignment. Every criterion needs a level worth points,
+// otherwise it could not count toward the grade
+func (uc *UseCase) SetRubric(ctx context.Context, id uuid.UUID, req SetRubricRequest) ([]schema.RubricCriterion, error) {
+	if _, err := uc.repo.GetByID(ctx, id); err != nil {
+		return nil, ErrAssignmentNotFound.Build()
+	}
+
+	criteria := make([]schema.RubricCriterion, 0, len(req.Criteria))
+	for i, c := range req.Criteria {
+		criterionID, err := uuid.NewV7()
+		if err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		criterion := schema.RubricCriterion{
+			ID:           criterionID,
+			AssignmentID: id,
+			Title:        c.Title,
+			Description:  c.Description,
+			Position:     i,
+		}
+		maxPoints := 0.0
+		for _, l := range c.Levels {
+			levelID, err := uuid.NewV7()
+			if err != nil {
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			criterion.Levels = append(criterion.Levels, schema.RubricLevel{
+				ID:          levelID,
+				CriterionID: criterionID,
+				Title:       l.Title,
+				Description: l.Description,
+				Points:      l.Points,
+			})
+			maxPoints = max(maxPoints, l.Points)
+		}
+		if maxPoints == 0 {
+			return nil, ErrInvalidRubric.WithPayload(map[string]any{
+				"reason": fmt.Sprintf("criterion %q has no level worth points", c.Title),
+			}).Build()
+		}
+		criteria = append(criteria, criterion)
+	}
+
+	if err := uc.repo.ReplaceRubric(ctx, id, criteria); err != nil {
+		log.Println("Error replacing rubric: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return uc.GetRubric(ctx, id)
+}
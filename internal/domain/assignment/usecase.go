@@ -2,9 +2,12 @@ package assignment
 
 import (
 	"context"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 )
@@ -12,10 +15,83 @@ import (
 type UseCase struct {
 	repo              Repository
 	attachmentUseCase *attachment.UseCase // Add this line
+	revisionUseCase   *revision.UseCase
+	notificationRepo  notification.IRepository
 }
 
-func NewUseCase(repo Repository, attachmentUseCase *attachment.UseCase) *UseCase {
-	return &UseCase{repo: repo, attachmentUseCase: attachmentUseCase}
+func NewUseCase(repo Repository, attachmentUseCase *attachment.UseCase, revisionUseCase *revision.UseCase,
+	notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{repo: repo, attachmentUseCase: attachmentUseCase, revisionUseCase: revisionUseCase,
+		notificationRepo: notificationRepo}
+}
+
+// DueInCohort returns the deadline of the assignment for a cohort starting at cohortStart
+func DueInCohort(a *schema.Assignment, cohortStart time.Time) *time.Time {
+	if a.DueOffsetDays == nil {
+		return a.Due
+	}
+	due := cohortStart.AddDate(0, 0, *a.DueOffsetDays)
+	return &due
+}
+
+// validateLatePolicy checks that a penalty policy says how much it takes off
+func validateLatePolicy(a *schema.Assignment) error {
+	if a.LatePolicy == schema.LatePolicyPenalty && a.LatePenaltyPercent == nil {
+		return ErrInvalidLatePolicy.WithPayload(map[string]any{
+			"reason": "late_penalty_percent is required for the penalty policy",
+		}).Build()
+	}
+	return nil
+}
+
+// validatePeerReview makes sure peer reviewed assignments have one due date for everyone, reviews are
+// handed out when it passes. Reviews go to single students, so group assignments are not peer reviewed
+func validatePeerReview(a *schema.Assignment) error {
+	if a.PeerReviewCount > 0 && (a.Due == nil || a.DueOffsetDays != nil) {
+		return ErrInvalidPeerReview.WithPayload(map[string]any{
+			"reason": "peer review needs a due date without due_offset_days",
+		}).Build()
+	}
+	if a.PeerReviewCount > 0 && a.TeamSetID != nil {
+		return ErrInvalidPeerReview.WithPayload(map[string]any{
+			"reason": "group assignments cannot be peer reviewed",
+		}).Build()
+	}
+	return nil
+}
+
+// setCategory moves the assignment into a grade category of its course
+func (uc *UseCase) setCategory(ctx context.Context, a *schema.Assignment, categoryID string) error {
+	id, err := uuid.Parse(categoryID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+	exists, err := uc.repo.CategoryExists(ctx, a.CourseID, id)
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+	if !exists {
+		return ErrCategoryNotFound.Build()
+	}
+	a.CategoryID = &id
+	return nil
+}
+
+// setTeamSet makes the assignment group work handed in by the teams of a team set of its course
+func (uc *UseCase) setTeamSet(ctx context.Context, a *schema.Assignment, teamSetID string) error {
+	id, err := uuid.Parse(teamSetID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+	exists, err := uc.repo.TeamSetExists(ctx, a.CourseID, id)
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+	if !exists {
+		return ErrTeamSetNotFound.Build()
+	}
+	a.TeamSetID = &id
+	return nil
 }
 
 func (uc *UseCase) CreateAssignment(ctx context.Context, req CreateAssignmentRequest, courseId uuid.UUID) error {
@@ -25,13 +101,60 @@ func (uc *UseCase) CreateAssignment(ctx context.Context, req CreateAssignmentReq
 		return apierror.ErrInternalServer.Build()
 	}
 	assignment := &schema.Assignment{
-		ID:          id,
-		CourseID:    courseId,
-		Title:       req.Title,
-		Description: req.Description,
-		Due:         req.Due,
+		ID:                 id,
+		CourseID:           courseId,
+		Title:              req.Title,
+		Description:        req.Description,
+		Due:                req.Due,
+		DueOffsetDays:      req.DueOffsetDays,
+		LatePolicy:         schema.LatePolicySoft,
+		LatePenaltyPercent: req.LatePenaltyPercent,
+		MaxAttempts:        1,
+		GradingPolicy:      schema.GradingPolicyLatest,
+		Weight:             1,
+		ReleaseSchedule: schema.ReleaseSchedule{
+			ReleaseAt:        req.ReleaseAt,
+			ReleaseAfterDays: req.ReleaseAfterDays,
+		},
+	}
+	if req.LatePolicy != "" {
+		assignment.LatePolicy = req.LatePolicy
+	}
+	if req.MaxAttempts != nil {
+		assignment.MaxAttempts = *req.MaxAttempts
+	}
+	if req.GradingPolicy != "" {
+		assignment.GradingPolicy = req.GradingPolicy
+	}
+	if req.CategoryID != nil {
+		if err := uc.setCategory(ctx, assignment, *req.CategoryID); err != nil {
+			return err
+		}
+	}
+	if req.Weight != nil {
+		assignment.Weight = *req.Weight
+	}
+	if req.HoldGrades != nil {
+		assignment.HoldGrades = *req.HoldGrades
+	}
+	if req.PeerReviewCount != nil {
+		assignment.PeerReviewCount = *req.PeerReviewCount
+	}
+	if req.TeamSetID != nil {
+		if err := uc.setTeamSet(ctx, assignment, *req.TeamSetID); err != nil {
+			return err
+		}
+	}
+	if err := validateLatePolicy(assignment); err != nil {
+		return err
+	}
+	if err := validatePeerReview(assignment); err != nil {
+		return err
+	}
+	if err := uc.repo.Create(ctx, assignment); err != nil {
+		return err
 	}
-	return uc.repo.Create(ctx, assignment)
+	return uc.record(ctx, assignment, nil, nil)
 }
 
 func (uc *UseCase) UpdateAssignment(ctx context.Context, id uuid.UUID, req UpdateAssignmentRequest) error {
@@ -39,6 +162,7 @@ func (uc *UseCase) UpdateAssignment(ctx context.Context, id uuid.UUID, req Updat
 	if err != nil {
 		return err
 	}
+	before := snapshotOf(assignment)
 
 	if req.Title != nil {
 		assignment.Title = *req.Title
@@ -49,8 +173,97 @@ func (uc *UseCase) UpdateAssignment(ctx context.Context, id uuid.UUID, req Updat
 	if req.Due != nil {
 		assignment.Due = req.Due
 	}
+	if req.DueOffsetDays != nil {
+		// A negative offset clears it so the assignment falls back to Due
+		if *req.DueOffsetDays < 0 {
+			assignment.DueOffsetDays = nil
+		} else {
+			assignment.DueOffsetDays = req.DueOffsetDays
+		}
+	}
+	if req.LatePolicy != "" {
+		assignment.LatePolicy = req.LatePolicy
+	}
+	if req.LatePenaltyPercent != nil {
+		assignment.LatePenaltyPercent = req.LatePenaltyPercent
+	}
+	// lowering the limit keeps the attempts already handed in, it only stops new ones
+	if req.MaxAttempts != nil {
+		assignment.MaxAttempts = *req.MaxAttempts
+	}
+	if req.GradingPolicy != "" {
+		assignment.GradingPolicy = req.GradingPolicy
+	}
+	if req.ClearCategory {
+		assignment.CategoryID = nil
+	}
+	if req.CategoryID != nil {
+		if err := uc.setCategory(ctx, assignment, *req.CategoryID); err != nil {
+			return err
+		}
+	}
+	if req.Weight != nil {
+		assignment.Weight = *req.Weight
+	}
+	if req.HoldGrades != nil {
+		assignment.HoldGrades = *req.HoldGrades
+	}
+	if req.PeerReviewCount != nil {
+		assignment.PeerReviewCount = *req.PeerReviewCount
+	}
+	if req.ClearTeamSet || req.TeamSetID != nil {
+		if err := uc.changeTeamSet(ctx, assignment, req); err != nil {
+			return err
+		}
+	}
+	if err := validateLatePolicy(assignment); err != nil {
+		return err
+	}
+	if err := validatePeerReview(assignment); err != nil {
+		return err
+	}
+	if req.ClearRelease {
+		assignment.ReleaseSchedule = schema.ReleaseSchedule{}
+	}
+	if req.ReleaseAt != nil {
+		assignment.ReleaseAt = req.ReleaseAt
+	}
+	if req.ReleaseAfterDays != nil {
+		assignment.ReleaseAfterDays = req.ReleaseAfterDays
+	}
 
-	return uc.repo.Update(ctx, assignment)
+	if err := uc.repo.Update(ctx, assignment); err != nil {
+		return err
+	}
+	return uc.record(ctx, assignment, &before, nil)
+}
+
+// changeTeamSet switches the assignment between individual and group work or to another team set. It cannot
+// change once students handed in, their submissions would no longer match how the assignment is graded
+func (uc *UseCase) changeTeamSet(ctx context.Context, a *schema.Assignment, req UpdateAssignmentRequest) error {
+	previous := a.TeamSetID
+	if req.ClearTeamSet {
+		a.TeamSetID = nil
+	}
+	if req.TeamSetID != nil {
+		if err := uc.setTeamSet(ctx, a, *req.TeamSetID); err != nil {
+			return err
+		}
+	}
+	if previous == a.TeamSetID || (previous != nil && a.TeamSetID != nil && *previous == *a.TeamSetID) {
+		return nil
+	}
+
+	submitters, err := uc.repo.GetSubmitterIDs(ctx, a.ID)
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+	if len(submitters) > 0 {
+		return ErrInvalidGroupWork.WithPayload(map[string]any{
+			"reason": "the team set cannot change once students handed in",
+		}).Build()
+	}
+	return nil
 }
 
 func (uc *UseCase) DeleteAssignment(ctx context.Context, id uuid.UUID) error {
@@ -70,6 +283,7 @@ func (uc *UseCase) AddAttachment(ctx context.Context, id uuid.UUID, req Attachme
 	if err != nil {
 		return ErrAssignmentNotFound.Build()
 	}
+	before := snapshotOf(ass)
 
 	if req.File != nil {
 
@@ -80,5 +294,8 @@ func (uc *UseCase) AddAttachment(ctx context.Context, id uuid.UUID, req Attachme
 		ass.Attachments = append(ass.Attachments, attachment)
 	}
 
-	return uc.repo.Update(ctx, ass)
+	if err := uc.repo.Update(ctx, ass); err != nil {
+		return err
+	}
+	return uc.record(ctx, ass, &before, nil)
 }
@@ -2,10 +2,16 @@ package attachment
 
 import (
 	"context"
+	"errors"
+	"io"
 	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -45,6 +51,16 @@ func (m *MockFileUploader) UploadFile(key string, fileHeader *multipart.FileHead
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	args := m.Called(key, content, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	args := m.Called(fileURL, expiry)
+	return args.String(0), args.Error(1)
+}
+
 type AttachmentUseCaseTestSuite struct {
 	suite.Suite
 	attachmentRepo    *MockRepository
@@ -144,12 +160,16 @@ func (suite *AttachmentUseCaseTestSuite) TestGetAttachmentByID_Success() {
 		Description: "A test file",
 	}
 
+	signedURL := "http://example.com/file.pdf?X-Amz-Signature=abc"
+
 	suite.attachmentRepo.On("GetByID", ctx, id).Return(expectedAttachment, nil)
+	suite.uploader.On("PresignURL", "http://example.com/file.pdf", mock.AnythingOfType("time.Duration")).Return(signedURL, nil)
 
 	attachment, err := suite.attachmentUseCase.GetAttachmentByID(ctx, id)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), expectedAttachment, attachment)
+	assert.Equal(suite.T(), signedURL, attachment.URL)
 	suite.attachmentRepo.AssertExpectations(suite.T())
 }
 
@@ -179,15 +199,54 @@ func (suite *AttachmentUseCaseTestSuite) TestUpdateAttachment_Success() {
 
 	suite.attachmentRepo.On("GetByID", ctx, id).Return(originalAttachment, nil)
 	suite.attachmentRepo.On("Update", ctx, updatedAttachment).Return(nil)
+	suite.uploader.On("PresignURL", expectedURL, mock.AnythingOfType("time.Duration")).Return(expectedURL+"?X-Amz-Signature=abc", nil)
 
 	attachment, err := suite.attachmentUseCase.UpdateAttachment(ctx, id, req)
 
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), updatedAttachment.Description, attachment.Description)
-	assert.Equal(suite.T(), updatedAttachment.URL, attachment.URL)
+	assert.Equal(suite.T(), updatedAttachment.URL+"?X-Amz-Signature=abc", attachment.URL)
 	suite.attachmentRepo.AssertExpectations(suite.T())
 }
 
+func (suite *AttachmentUseCaseTestSuite) TestSignURLs_HidesUnsignableURL() {
+	attachments := []schema.Attachment{
+		{ID: uuid.New(), URL: "http://example.com/a.pdf"},
+		{ID: uuid.New(), URL: "http://example.com/b.pdf"},
+	}
+
+	suite.uploader.On("PresignURL", "http://example.com/a.pdf", mock.AnythingOfType("time.Duration")).Return("http://example.com/a.pdf?sig", nil)
+	suite.uploader.On("PresignURL", "http://example.com/b.pdf", mock.AnythingOfType("time.Duration")).Return("", errors.New("presign failed"))
+
+	suite.attachmentUseCase.SignURLs(attachments)
+
+	assert.Equal(suite.T(), "http://example.com/a.pdf?sig", attachments[0].URL)
+	assert.Empty(suite.T(), attachments[1].URL)
+}
+
+func (suite *AttachmentUseCaseTestSuite) TestOpen_DownloadsThroughSignedURL() {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("sig") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		_, _ = w.Write([]byte("report"))
+	}))
+	defer server.Close()
+
+	suite.uploader.On("PresignURL", "http://example.com/report.pdf", mock.AnythingOfType("time.Duration")).Return(server.URL+"/report.pdf?sig=1", nil)
+	suite.uploader.On("PresignURL", "http://example.com/gone.pdf", mock.AnythingOfType("time.Duration")).Return(server.URL+"/gone.pdf", nil)
+
+	file, err := suite.attachmentUseCase.Open(context.Background(), "http://example.com/report.pdf")
+	assert.NoError(suite.T(), err)
+	content, _ := io.ReadAll(file)
+	file.Close()
+	assert.Equal(suite.T(), "report", string(content))
+
+	_, err = suite.attachmentUseCase.Open(context.Background(), "http://example.com/gone.pdf")
+	assert.Error(suite.T(), err)
+}
+
 func (suite *AttachmentUseCaseTestSuite) TestDeleteAttachment_Success() {
 	ctx := context.Background()
 	id := uuid.New()
@@ -200,6 +259,18 @@ func (suite *AttachmentUseCaseTestSuite) TestDeleteAttachment_Success() {
 	suite.attachmentRepo.AssertExpectations(suite.T())
 }
 
+// Attachments are only handed out signed inside the material, assignment or submission they belong to,
+// where access to the course has been checked
+func (suite *AttachmentUseCaseTestSuite) TestRoutes_NoDirectDownload() {
+	gin.SetMode(gin.TestMode)
+	engine := gin.New()
+	NewRestController(engine, suite.attachmentUseCase)
+
+	for _, route := range engine.Routes() {
+		assert.NotEqual(suite.T(), http.MethodGet, route.Method, route.Path)
+	}
+}
+
 func TestAttachmentUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(AttachmentUseCaseTestSuite))
 }
@@ -20,27 +20,11 @@ func NewRestController(r *gin.Engine, uc *UseCase) {
 	attachmentsGroup := r.Group("/v1/attachments")
 	{
 		attachmentsGroup.PUT("/:id", middleware.Authenticate(), c.update)
-		attachmentsGroup.GET("/:id", middleware.Authenticate(), c.get)
 		attachmentsGroup.DELETE("/:id", middleware.Authenticate(), c.delete)
 
 	}
 }
 
-func (c *RestController) get(ctx *gin.Context) {
-	id, err := uuid.Parse(ctx.Param("id"))
-	if err != nil {
-		err = apierror.ErrInvalidParamId.Build()
-		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), err.Error()).Send(ctx)
-		return
-	}
-	attachment, err := c.useCase.GetAttachmentByID(ctx, id)
-	if err != nil {
-		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
-		return
-	}
-	response.NewRestResponse(http.StatusOK, "Retrieve attachment successfully", attachment).Send(ctx)
-}
-
 func (c *RestController) update(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
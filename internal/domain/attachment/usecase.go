@@ -4,8 +4,12 @@ package attachment
 
 import (
 	"context"
-
+	"fmt"
+	"io"
+	"log"
 	"mime/multipart"
+	"net/http"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/config"
@@ -14,6 +18,9 @@ import (
 	"github.com/google/uuid"
 )
 
+// downloadURLExpiry is how long a signed attachment link stays valid
+const downloadURLExpiry = 15 * time.Minute
+
 type UseCase struct {
 	repo     Repository
 	uploader config.FileUploader
@@ -124,7 +131,57 @@ func (auc *UseCase) CreateSubmissionAttachment(ctx context.Context, fileHeader *
 }
 
 func (uc *UseCase) GetAttachmentByID(ctx context.Context, id uuid.UUID) (*schema.Attachment, error) {
-	return uc.repo.GetByID(ctx, id)
+	attachment, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	attachment.URL = SignURL(uc.uploader, attachment.URL)
+	return attachment, nil
+}
+
+// SignURLs replaces the stored attachment URLs with short-lived download links
+func (uc *UseCase) SignURLs(attachments []schema.Attachment) {
+	SignURLs(uc.uploader, attachments)
+}
+
+// SignURL returns a short-lived download link for a stored attachment URL, or an empty string when the
+// link cannot be signed rather than exposing the permanent one
+func SignURL(uploader config.FileUploader, fileURL string) string {
+	signedURL, err := uploader.PresignURL(fileURL, downloadURLExpiry)
+	if err != nil {
+		log.Println("Error signing attachment URL: ", err)
+		return ""
+	}
+	return signedURL
+}
+
+func SignURLs(uploader config.FileUploader, attachments []schema.Attachment) {
+	for i := range attachments {
+		attachments[i].URL = SignURL(uploader, attachments[i].URL)
+	}
+}
+
+// Open downloads a stored attachment through a short-lived link, the caller closes the returned reader
+func (uc *UseCase) Open(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	signedURL, err := uc.uploader.PresignURL(fileURL, downloadURLExpiry)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, signedURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("unable to download %q: %s", fileURL, res.Status)
+	}
+	return res.Body, nil
 }
 
 func (uc *UseCase) UpdateAttachment(ctx context.Context, id uuid.UUID, req AttachmentUpdateRequest) (*schema.Attachment, error) {
@@ -158,6 +215,7 @@ func (uc *UseCase) UpdateAttachment(ctx context.Context, id uuid.UUID, req Attac
 		return nil, err
 	}
 
+	attachment.URL = SignURL(uc.uploader, attachment.URL)
 	return attachment, nil
 }
 
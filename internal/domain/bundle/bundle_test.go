@@ -0,0 +1,394 @@
+package bundle
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, bundle *schema.Bundle) error {
+	args := m.Called(ctx, bundle)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAll(ctx context.Context, page, limit int) ([]schema.Bundle, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]schema.Bundle), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Bundle, error) {
+	args := m.Called(ctx, id)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Bundle), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, bundle *schema.Bundle) error {
+	args := m.Called(ctx, bundle)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error {
+	args := m.Called(ctx, buyerID, sellerID, amount, enrolls)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type BundleUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	enrollRepo *MockEnrollRepository
+	notifRepo  *MockNotificationRepository
+	useCase    *UseCase
+}
+
+func (suite *BundleUseCaseTestSuite) SetupTest() {
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	_ = os.Setenv("BASE_CURRENCY", "IDR")
+	_ = os.Setenv("EXCHANGE_RATES", "USD=16000")
+	config.LoadEnv()
+
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.notifRepo = new(MockNotificationRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, suite.notifRepo)
+}
+
+func (suite *BundleUseCaseTestSuite) newBundle(courses ...schema.Course) *schema.Bundle {
+	bundle := &schema.Bundle{ID: uuid.New(), InstructorID: uuid.New(), Price: 300000}
+	for _, c := range courses {
+		bundle.Courses = append(bundle.Courses, schema.BundleCourse{BundleID: bundle.ID, CourseID: c.ID, Course: c})
+	}
+	return bundle
+}
+
+func (suite *BundleUseCaseTestSuite) TestBuy_SkipsOwnedCourses() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	ctx = context.WithValue(ctx, "user.name", "John Doe")
+	owned := schema.Course{ID: uuid.New(), Price: 100000}
+	missing := schema.Course{ID: uuid.New(), Price: 200000}
+	bundle := suite.newBundle(owned, missing)
+
+	suite.repo.On("GetByID", ctx, bundle.ID).Return(bundle, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, mock.Anything, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, owned.ID).Return(true, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, missing.ID).Return(false, nil)
+	suite.repo.On("Purchase", ctx, userID, bundle.InstructorID, int64(200000), mock.MatchedBy(func(enrolls []schema.CourseEnroll) bool {
+		return len(enrolls) == 1 && enrolls[0].CourseID == missing.ID
+	})).Return(nil)
+	suite.notifRepo.On("Create", mock.Anything).Return(nil)
+
+	res, err := suite.useCase.Buy(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(200000), res.Price)
+	assert.Equal(suite.T(), []uuid.UUID{owned.ID}, res.OwnedCourseIDs)
+	assert.Equal(suite.T(), []uuid.UUID{missing.ID}, res.EnrollCourseIDs)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *BundleUseCaseTestSuite) TestBuy_AllCoursesOwned() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	first := schema.Course{ID: uuid.New(), Price: 100000}
+	second := schema.Course{ID: uuid.New(), Price: 200000}
+	bundle := suite.newBundle(first, second)
+
+	suite.repo.On("GetByID", ctx, bundle.ID).Return(bundle, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, mock.Anything, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, mock.Anything).Return(true, nil)
+
+	res, err := suite.useCase.Buy(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrAllCoursesOwned.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Purchase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BundleUseCaseTestSuite) TestBuy_PrerequisitesNotMet() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	blocked := schema.Course{ID: uuid.New(), Price: 100000, PrerequisitePolicy: schema.PrerequisiteBlock}
+	prerequisiteID := uuid.New()
+	bundle := suite.newBundle(blocked)
+
+	suite.repo.On("GetByID", ctx, bundle.ID).Return(bundle, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, mock.Anything, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, blocked.ID).Return(false, nil)
+	suite.courseRepo.On("GetByID", ctx, blocked.ID).Return(blocked, nil)
+	suite.courseRepo.On("GetPrerequisites", ctx, blocked.ID).Return([]schema.Course{{ID: prerequisiteID}}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, prerequisiteID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+
+	res, err := suite.useCase.Buy(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), course.ErrPrerequisitesNotMet.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Purchase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *BundleUseCaseTestSuite) TestBuy_DeletedCourse() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	// A soft deleted course preloads as an empty course
+	bundle := suite.newBundle(schema.Course{ID: uuid.New(), Price: 100000}, schema.Course{})
+
+	suite.repo.On("GetByID", ctx, bundle.ID).Return(bundle, nil)
+
+	res, err := suite.useCase.Buy(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), course.ErrPackageCourseUnavailable.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Purchase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+// mixedBundle holds a course priced in dollars and a course on sale, worth 160000 and 80000 in the base currency
+func (suite *BundleUseCaseTestSuite) mixedBundle(ctx context.Context) (*schema.Bundle, schema.Course, schema.Course) {
+	dollars := schema.Course{ID: uuid.New(), Price: 1000, Currency: "USD"}
+	onSale := schema.Course{ID: uuid.New(), Price: 200000, Currency: "IDR"}
+	bundle := suite.newBundle(dollars, onSale)
+
+	suite.repo.On("GetByID", ctx, bundle.ID).Return(bundle, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{dollars.ID, onSale.ID}, mock.Anything).Return([]schema.CourseSale{
+		{CourseID: onSale.ID, SalePrice: 80000, EndsAt: time.Now().Add(time.Hour)},
+	}, nil)
+	return bundle, dollars, onSale
+}
+
+func (suite *BundleUseCaseTestSuite) TestGetByID_OriginalPriceInBaseCurrency() {
+	ctx := context.Background()
+	bundle, _, _ := suite.mixedBundle(ctx)
+
+	res, err := suite.useCase.GetByID(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(240000), res.OriginalPrice)
+}
+
+func (suite *BundleUseCaseTestSuite) TestQuote_ProratesByCurrentBasePrices() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	bundle, dollars, onSale := suite.mixedBundle(ctx)
+
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, dollars.ID).Return(true, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, onSale.ID).Return(false, nil)
+
+	res, err := suite.useCase.Quote(ctx, &BundleIDRequest{ID: bundle.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	// the course on sale is a third of what the courses are worth today
+	assert.Equal(suite.T(), int64(100000), res.Price)
+}
+
+func TestBundleUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(BundleUseCaseTestSuite))
+}
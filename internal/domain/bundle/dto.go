@@ -0,0 +1,44 @@
+package bundle
+
+import (
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type CreateBundleRequest struct {
+	Title       string      `json:"title" binding:"required,max=100"`
+	Description string      `json:"description" binding:"max=1000"`
+	Price       int64       `json:"price" binding:"gte=0"`
+	CourseIDs   []uuid.UUID `json:"course_ids" binding:"required,min=2,max=30"`
+}
+
+type UpdateBundleRequest struct {
+	ID          string      `uri:"id" binding:"required,uuid"`
+	Title       string      `json:"title" binding:"max=100"`
+	Description string      `json:"description" binding:"max=1000"`
+	Price       *int64      `json:"price" binding:"omitempty,gte=0"`
+	CourseIDs   []uuid.UUID `json:"course_ids" binding:"omitempty,min=2,max=30"`
+}
+
+type GetBundlesRequest struct {
+	Page  int `form:"page" binding:"required,min=1"`
+	Limit int `form:"limit" binding:"required,min=1,max=30"`
+}
+
+type BundleIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// BundleResponse is the bundle with OriginalPrice, what its courses sell for on their own right now converted
+// to the base currency the bundle is priced in
+type BundleResponse struct {
+	schema.Bundle
+	OriginalPrice int64 `json:"original_price"`
+}
+
+type BundleQuoteResponse struct {
+	BundleID        uuid.UUID   `json:"bundle_id"`
+	Price           int64       `json:"price"`
+	OwnedCourseIDs  []uuid.UUID `json:"owned_course_ids"`
+	EnrollCourseIDs []uuid.UUID `json:"enroll_course_ids"`
+}
@@ -0,0 +1,21 @@
+package bundle
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrBundleNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("BUNDLE_NOT_FOUND")
+
+	ErrInvalidBundleCourse = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_BUNDLE_COURSE")
+
+	ErrAllCoursesOwned = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("ALL_COURSES_ALREADY_ENROLLED")
+)
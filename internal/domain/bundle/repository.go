@@ -0,0 +1,100 @@
+package bundle
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, bundle *schema.Bundle) error
+	GetAll(ctx context.Context, page, limit int) ([]schema.Bundle, int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Bundle, error)
+	Update(ctx context.Context, bundle *schema.Bundle) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error
+}
+
+type repository struct {
+	db         *gorm.DB
+	walletRepo wallet.IRepository
+}
+
+func NewRepository(db *gorm.DB, walletRepo wallet.IRepository) Repository {
+	return &repository{db: db, walletRepo: walletRepo}
+}
+
+func (r *repository) Create(ctx context.Context, bundle *schema.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Courses").Create(bundle).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("Course").Create(&bundle.Courses).Error
+	})
+}
+
+func (r *repository) GetAll(ctx context.Context, page, limit int) ([]schema.Bundle, int64, error) {
+	var bundles []schema.Bundle
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&schema.Bundle{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.db.WithContext(ctx).
+		Preload("Courses.Course").
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&bundles).Error
+
+	return bundles, total, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Bundle, error) {
+	var bundle schema.Bundle
+	if err := r.db.WithContext(ctx).Preload("Courses.Course").First(&bundle, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &bundle, nil
+}
+
+func (r *repository) Update(ctx context.Context, bundle *schema.Bundle) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Courses").Save(bundle).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("bundle_id = ?", bundle.ID).Delete(&schema.BundleCourse{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Omit("Course").Create(&bundle.Courses).Error
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.Bundle{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purchase charges the buyer once and enrolls them in the given courses atomically
+func (r *repository) Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.walletRepo.TransferByUserID(tx, buyerID, sellerID, amount); err != nil {
+			return err
+		}
+
+		return tx.Create(&enrolls).Error
+	})
+}
@@ -0,0 +1,189 @@
+package bundle
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	bundleGroup := engine.Group("/v1/courses/bundles")
+	{
+		bundleGroup.GET("", controller.GetAll())
+		bundleGroup.GET("/:id", controller.GetByID())
+		bundleGroup.POST("",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("instructor"),
+			controller.Create(),
+		)
+		bundleGroup.PATCH("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.Update(),
+		)
+		bundleGroup.DELETE("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.Delete(),
+		)
+		bundleGroup.GET("/:id/quote",
+			middleware.Authenticate(),
+			middleware.RequireRole("student"),
+			controller.Quote(),
+		)
+		bundleGroup.POST("/:id/buy",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("student"),
+			controller.Buy(),
+		)
+	}
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CreateBundleRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_BUNDLE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req GetBundlesRequest
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetAll(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_BUNDLES_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req BundleIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByID(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_BUNDLE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateBundleRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Update(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_BUNDLE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req BundleIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_BUNDLE_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) Quote() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req BundleIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Quote(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_BUNDLE_QUOTE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Buy() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req BundleIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Buy(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "BUY_BUNDLE_SUCCESS", res).Send(ctx)
+	}
+}
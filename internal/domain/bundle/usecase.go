@@ -0,0 +1,325 @@
+package bundle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo             Repository
+	courseUc         *course.UseCase
+	notificationRepo notification.IRepository
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{repo: repo, courseUc: courseUc, notificationRepo: notificationRepo}
+}
+
+// toResponses adds to every bundle what its courses sell for on their own right now, in the base currency the
+// bundle price is in
+func (uc *UseCase) toResponses(ctx context.Context, bundles []schema.Bundle) ([]BundleResponse, error) {
+	var all []schema.Course
+	for i := range bundles {
+		all = append(all, courses(&bundles[i])...)
+	}
+
+	prices, err := uc.courseUc.BasePrices(ctx, all)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]BundleResponse, len(bundles))
+	next := 0
+	for i := range bundles {
+		res[i] = BundleResponse{Bundle: bundles[i]}
+		for range bundles[i].Courses {
+			res[i].OriginalPrice += prices[next]
+			next++
+		}
+	}
+	return res, nil
+}
+
+func (uc *UseCase) toResponse(ctx context.Context, bundle *schema.Bundle) (*BundleResponse, error) {
+	res, err := uc.toResponses(ctx, []schema.Bundle{*bundle})
+	if err != nil {
+		return nil, err
+	}
+	return &res[0], nil
+}
+
+func courses(bundle *schema.Bundle) []schema.Course {
+	res := make([]schema.Course, len(bundle.Courses))
+	for i, bundleCourse := range bundle.Courses {
+		res[i] = bundleCourse.Course
+	}
+	return res
+}
+
+// buildCourses validates that every course exists and belongs to the instructor selling the bundle
+func (uc *UseCase) buildCourses(ctx context.Context, bundleID, instructorID uuid.UUID, courseIDs []uuid.UUID) ([]schema.BundleCourse, error) {
+	found, err := uc.courseUc.PackageCourses(ctx, courseIDs, &instructorID)
+	if err != nil {
+		var courseErr *course.PackageCourseError
+		if errors.As(err, &courseErr) {
+			return nil, ErrInvalidBundleCourse.WithPayload(map[string]any{
+				"course_id": courseErr.CourseID,
+				"reason":    courseErr.Reason,
+			}).Build()
+		}
+		return nil, err
+	}
+
+	courses := make([]schema.BundleCourse, len(found))
+	for i, courseObj := range found {
+		courses[i] = schema.BundleCourse{BundleID: bundleID, CourseID: courseObj.ID, Course: courseObj}
+	}
+	return courses, nil
+}
+
+func (uc *UseCase) getBundle(ctx context.Context, idStr string) (*schema.Bundle, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	bundle, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrBundleNotFound.Build()
+		}
+		log.Println("Error getting bundle: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return bundle, nil
+}
+
+func (uc *UseCase) getOwnedBundle(ctx context.Context, idStr string) (*schema.Bundle, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	bundle, err := uc.getBundle(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+
+	if bundle.InstructorID != userID {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+
+	return bundle, nil
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateBundleRequest) (*BundleResponse, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	courses, err := uc.buildCourses(ctx, id, userID, req.CourseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	bundle := &schema.Bundle{
+		ID:           id,
+		Title:        req.Title,
+		Description:  req.Description,
+		InstructorID: userID,
+		Price:        req.Price,
+		Courses:      courses,
+	}
+
+	if err := uc.repo.Create(ctx, bundle); err != nil {
+		log.Println("Error creating bundle: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return uc.toResponse(ctx, bundle)
+}
+
+func (uc *UseCase) GetAll(ctx context.Context, req *GetBundlesRequest) (*pagination.GetResourcePaginatedResponse, error) {
+	bundles, total, err := uc.repo.GetAll(ctx, req.Page, req.Limit)
+	if err != nil {
+		log.Println("Error getting bundles: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res, err := uc.toResponses(ctx, bundles)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pagination.GetResourcePaginatedResponse{
+		Data:       res,
+		Pagination: pagination.NewPagination(int(total), req.Page, req.Limit),
+	}, nil
+}
+
+func (uc *UseCase) GetByID(ctx context.Context, req *BundleIDRequest) (*BundleResponse, error) {
+	bundle, err := uc.getBundle(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return uc.toResponse(ctx, bundle)
+}
+
+func (uc *UseCase) Update(ctx context.Context, req *UpdateBundleRequest) (*BundleResponse, error) {
+	bundle, err := uc.getOwnedBundle(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != "" {
+		bundle.Title = req.Title
+	}
+	if req.Description != "" {
+		bundle.Description = req.Description
+	}
+	if req.Price != nil {
+		bundle.Price = *req.Price
+	}
+
+	courseIDs := req.CourseIDs
+	if len(courseIDs) == 0 {
+		for _, bundleCourse := range bundle.Courses {
+			courseIDs = append(courseIDs, bundleCourse.CourseID)
+		}
+	}
+
+	bundle.Courses, err = uc.buildCourses(ctx, bundle.ID, bundle.InstructorID, courseIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, bundle); err != nil {
+		log.Println("Error updating bundle: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return uc.toResponse(ctx, bundle)
+}
+
+func (uc *UseCase) Delete(ctx context.Context, req *BundleIDRequest) error {
+	bundle, err := uc.getOwnedBundle(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, bundle.ID); err != nil {
+		log.Println("Error deleting bundle: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+func (uc *UseCase) quote(ctx context.Context, bundle *schema.Bundle, userID uuid.UUID) (*course.PackageQuote, *BundleQuoteResponse, error) {
+	quote, err := uc.courseUc.QuotePackage(ctx, userID, bundle.Price, courses(bundle))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return quote, &BundleQuoteResponse{
+		BundleID:        bundle.ID,
+		Price:           quote.Price,
+		OwnedCourseIDs:  quote.OwnedCourseIDs,
+		EnrollCourseIDs: quote.EnrollCourseIDs,
+	}, nil
+}
+
+// Quote returns what the student would pay for the bundle, courses they already own are skipped
+func (uc *UseCase) Quote(ctx context.Context, req *BundleIDRequest) (*BundleQuoteResponse, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	bundle, err := uc.getBundle(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, res, err := uc.quote(ctx, bundle, userID)
+	return res, err
+}
+
+func (uc *UseCase) Buy(ctx context.Context, req *BundleIDRequest) (*BundleQuoteResponse, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	bundle, err := uc.getBundle(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	quote, res, err := uc.quote(ctx, bundle, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(res.EnrollCourseIDs) == 0 {
+		return nil, ErrAllCoursesOwned.Build()
+	}
+
+	if err := uc.courseUc.CheckPackagePrerequisites(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	enrolls, err := quote.Enrollments()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Purchase(ctx, userID, bundle.InstructorID, res.Price, enrolls); err != nil {
+		var apiErr *apierror.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		log.Println("Error purchasing bundle: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	userName := ctx.Value("user.name").(string)
+
+	// Create in-app notification
+	go func() {
+		notificationID, err := uuid.NewV7()
+		if err != nil {
+			return
+		}
+		notif := schema.Notification{
+			ID:     notificationID,
+			UserID: bundle.InstructorID,
+			Title:  "You have a new student!",
+			Detail: fmt.Sprintf("Bundle %s has been purchased by %s", bundle.Title, userName),
+		}
+
+		if err := uc.notificationRepo.Create(&notif); err != nil {
+			log.Println("Error creating notification: ", err)
+		}
+	}()
+
+	return res, nil
+}
@@ -0,0 +1,148 @@
+package category
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, category *schema.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAll(ctx context.Context) ([]schema.Category, error) {
+	args := m.Called(ctx)
+	return args.Get(0).([]schema.Category), args.Error(1)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Category, error) {
+	args := m.Called(ctx, id)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetBySlug(ctx context.Context, slug string) (*schema.Category, error) {
+	args := m.Called(ctx, slug)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Category), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, category *schema.Category) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetTags(ctx context.Context, search string, limit int) ([]TagResponse, error) {
+	args := m.Called(ctx, search, limit)
+	return args.Get(0).([]TagResponse), args.Error(1)
+}
+
+type CategoryUseCaseTestSuite struct {
+	suite.Suite
+	repo    *MockRepository
+	useCase *UseCase
+}
+
+func (suite *CategoryUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.useCase = NewUseCase(suite.repo)
+}
+
+func (suite *CategoryUseCaseTestSuite) TestGetTree() {
+	ctx := context.Background()
+	root := schema.Category{ID: uuid.New(), Name: "Development"}
+	child := schema.Category{ID: uuid.New(), Name: "Web Development", ParentID: &root.ID}
+	grandchild := schema.Category{ID: uuid.New(), Name: "Frontend", ParentID: &child.ID}
+	other := schema.Category{ID: uuid.New(), Name: "Design"}
+
+	suite.repo.On("GetAll", ctx).Return([]schema.Category{root, child, grandchild, other}, nil)
+
+	tree, err := suite.useCase.GetTree(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), tree, 2)
+	assert.Equal(suite.T(), child.ID, tree[0].Children[0].ID)
+	assert.Equal(suite.T(), grandchild.ID, tree[0].Children[0].Children[0].ID)
+	assert.Empty(suite.T(), tree[1].Children)
+}
+
+func (suite *CategoryUseCaseTestSuite) TestCreate_GeneratesSlug() {
+	ctx := context.Background()
+
+	suite.repo.On("Create", ctx, mock.AnythingOfType("*schema.Category")).Return(nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateCategoryRequest{Name: "AI & Machine Learning"})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "ai-machine-learning", res.Slug)
+}
+
+func (suite *CategoryUseCaseTestSuite) TestUpdate_ParentCycle() {
+	ctx := context.Background()
+	root := &schema.Category{ID: uuid.New(), Name: "Development", Slug: "development"}
+	child := &schema.Category{ID: uuid.New(), Name: "Web Development", Slug: "web-development", ParentID: &root.ID}
+
+	suite.repo.On("GetByID", ctx, root.ID).Return(root, nil)
+	suite.repo.On("GetByID", ctx, child.ID).Return(child, nil)
+
+	res, err := suite.useCase.Update(ctx, &UpdateCategoryRequest{ID: root.ID.String(), ParentID: &child.ID})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInvalidParent.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *CategoryUseCaseTestSuite) TestUpdate_ParentNotFound() {
+	ctx := context.Background()
+	category := &schema.Category{ID: uuid.New(), Name: "Web Development", Slug: "web-development"}
+	missing := uuid.New()
+
+	suite.repo.On("GetByID", ctx, category.ID).Return(category, nil)
+	suite.repo.On("GetByID", ctx, missing).Return(nil, gorm.ErrRecordNotFound)
+
+	res, err := suite.useCase.Update(ctx, &UpdateCategoryRequest{ID: category.ID.String(), ParentID: &missing})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInvalidParent.Build().Error(), err.Error())
+}
+
+func (suite *CategoryUseCaseTestSuite) TestDelete_HasChildren() {
+	ctx := context.Background()
+	id := uuid.New()
+
+	suite.repo.On("CountChildren", ctx, id).Return(int64(2), nil)
+
+	err := suite.useCase.Delete(ctx, &CategoryIDRequest{ID: id.String()})
+
+	assert.Equal(suite.T(), ErrCategoryHasChildren.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+}
+
+func TestCategoryUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CategoryUseCaseTestSuite))
+}
@@ -0,0 +1,34 @@
+package category
+
+import (
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type CreateCategoryRequest struct {
+	Name     string     `json:"name" binding:"required,max=100"`
+	Slug     string     `json:"slug" binding:"omitempty,max=120"`
+	ParentID *uuid.UUID `json:"parent_id"`
+}
+
+type UpdateCategoryRequest struct {
+	ID           string     `uri:"id" binding:"required,uuid"`
+	Name         string     `json:"name" binding:"max=100"`
+	Slug         string     `json:"slug" binding:"max=120"`
+	ParentID     *uuid.UUID `json:"parent_id"`
+	RemoveParent bool       `json:"remove_parent"`
+}
+
+type CategoryIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type GetTagsRequest struct {
+	Search string `form:"search" binding:"max=50"`
+	Limit  int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type TagResponse struct {
+	schema.Tag
+	CourseCount int64 `json:"course_count"`
+}
@@ -0,0 +1,29 @@
+package category
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrCategoryNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("CATEGORY_NOT_FOUND")
+
+	ErrSlugAlreadyUsed = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("CATEGORY_SLUG_ALREADY_USED")
+
+	ErrInvalidSlug = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("INVALID_CATEGORY_SLUG")
+
+	ErrInvalidParent = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_PARENT_CATEGORY")
+
+	ErrCategoryHasChildren = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("CATEGORY_HAS_SUBCATEGORIES")
+)
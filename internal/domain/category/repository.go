@@ -0,0 +1,96 @@
+package category
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, category *schema.Category) error
+	GetAll(ctx context.Context) ([]schema.Category, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Category, error)
+	GetBySlug(ctx context.Context, slug string) (*schema.Category, error)
+	Update(ctx context.Context, category *schema.Category) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	CountChildren(ctx context.Context, id uuid.UUID) (int64, error)
+	GetTags(ctx context.Context, search string, limit int) ([]TagResponse, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, category *schema.Category) error {
+	return r.db.WithContext(ctx).Create(category).Error
+}
+
+func (r *repository) GetAll(ctx context.Context) ([]schema.Category, error) {
+	var categories []schema.Category
+	err := r.db.WithContext(ctx).Order("name").Find(&categories).Error
+	return categories, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Category, error) {
+	var category schema.Category
+	if err := r.db.WithContext(ctx).First(&category, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) GetBySlug(ctx context.Context, slug string) (*schema.Category, error) {
+	var category schema.Category
+	err := r.db.WithContext(ctx).
+		Preload("Children", func(db *gorm.DB) *gorm.DB {
+			return db.Order("name")
+		}).
+		First(&category, "slug = ?", slug).Error
+	if err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) Update(ctx context.Context, category *schema.Category) error {
+	return r.db.WithContext(ctx).Omit("Children").Save(category).Error
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.Category{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) CountChildren(ctx context.Context, id uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Category{}).Where("parent_id = ?", id).Count(&count).Error
+	return count, err
+}
+
+func (r *repository) GetTags(ctx context.Context, search string, limit int) ([]TagResponse, error) {
+	var tags []TagResponse
+
+	query := r.db.WithContext(ctx).Model(&schema.Tag{}).
+		Select("tags.*, COUNT(course_tags.course_id) AS course_count").
+		Joins("LEFT JOIN course_tags ON course_tags.tag_id = tags.id").
+		Group("tags.id")
+
+	if search != "" {
+		query = query.Where("tags.name ILIKE ?", "%"+search+"%")
+	}
+
+	err := query.Order("course_count DESC").Order("tags.name").Limit(limit).Scan(&tags).Error
+	return tags, err
+}
@@ -0,0 +1,146 @@
+package category
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	categoryGroup := engine.Group("/v1/categories")
+	{
+		categoryGroup.GET("", controller.GetTree())
+		categoryGroup.GET("/:slug", controller.GetBySlug())
+		categoryGroup.POST("",
+			middleware.Authenticate(),
+			middleware.RequireRole("admin"),
+			controller.Create(),
+		)
+		categoryGroup.PATCH("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("admin"),
+			controller.Update(),
+		)
+		categoryGroup.DELETE("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("admin"),
+			controller.Delete(),
+		)
+	}
+
+	engine.GET("/v1/tags", controller.GetTags())
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CreateCategoryRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_CATEGORY_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetTree() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetTree(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_CATEGORIES_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetBySlug() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetBySlug(ctx, ctx.Param("slug"))
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_CATEGORY_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateCategoryRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Update(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_CATEGORY_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CategoryIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_CATEGORY_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) GetTags() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req GetTagsRequest
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetTags(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_TAGS_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,231 @@
+package category
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/slug"
+	"github.com/google/uuid"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo Repository
+}
+
+func NewUseCase(repo Repository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// buildTree nests every category under its parent and returns the root categories
+func buildTree(categories []schema.Category) []schema.Category {
+	children := make(map[uuid.UUID][]schema.Category)
+	var roots []schema.Category
+
+	for _, category := range categories {
+		if category.ParentID == nil {
+			roots = append(roots, category)
+		} else {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		}
+	}
+
+	var attach func(nodes []schema.Category) []schema.Category
+	attach = func(nodes []schema.Category) []schema.Category {
+		for i := range nodes {
+			nodes[i].Children = attach(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	return attach(roots)
+}
+
+func (uc *UseCase) getByID(ctx context.Context, id uuid.UUID) (*schema.Category, error) {
+	category, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound.Build()
+		}
+		log.Println("Error getting category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return category, nil
+}
+
+// validateParent makes sure the parent exists and is not the category itself or one of its descendants
+func (uc *UseCase) validateParent(ctx context.Context, categoryID, parentID uuid.UUID) error {
+	for current := &parentID; current != nil; {
+		if *current == categoryID {
+			return ErrInvalidParent.WithPayload(map[string]any{
+				"parent_id": parentID,
+				"reason":    "category cannot be nested under itself",
+			}).Build()
+		}
+
+		parent, err := uc.repo.GetByID(ctx, *current)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidParent.WithPayload(map[string]any{
+					"parent_id": parentID,
+					"reason":    "parent category not found",
+				}).Build()
+			}
+			log.Println("Error getting category: ", err)
+			return apierror.ErrInternalServer.Build()
+		}
+		current = parent.ParentID
+	}
+
+	return nil
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateCategoryRequest) (*schema.Category, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	categorySlug := slug.Make(req.Slug)
+	if req.Slug == "" {
+		categorySlug = slug.Make(req.Name)
+	}
+	if categorySlug == "" {
+		return nil, ErrInvalidSlug.Build()
+	}
+
+	if req.ParentID != nil {
+		if err := uc.validateParent(ctx, id, *req.ParentID); err != nil {
+			return nil, err
+		}
+	}
+
+	category := &schema.Category{
+		ID:       id,
+		Name:     req.Name,
+		Slug:     categorySlug,
+		ParentID: req.ParentID,
+	}
+
+	if err := uc.repo.Create(ctx, category); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrSlugAlreadyUsed.Build()
+		}
+		log.Println("Error creating category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return category, nil
+}
+
+func (uc *UseCase) GetTree(ctx context.Context) ([]schema.Category, error) {
+	categories, err := uc.repo.GetAll(ctx)
+	if err != nil {
+		log.Println("Error getting categories: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return buildTree(categories), nil
+}
+
+func (uc *UseCase) GetBySlug(ctx context.Context, categorySlug string) (*schema.Category, error) {
+	category, err := uc.repo.GetBySlug(ctx, categorySlug)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound.Build()
+		}
+		log.Println("Error getting category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return category, nil
+}
+
+func (uc *UseCase) Update(ctx context.Context, req *UpdateCategoryRequest) (*schema.Category, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	category, err := uc.getByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != "" {
+		category.Name = req.Name
+	}
+	if req.Slug != "" {
+		category.Slug = slug.Make(req.Slug)
+		if category.Slug == "" {
+			return nil, ErrInvalidSlug.Build()
+		}
+	}
+	if req.RemoveParent {
+		category.ParentID = nil
+	} else if req.ParentID != nil {
+		if err := uc.validateParent(ctx, category.ID, *req.ParentID); err != nil {
+			return nil, err
+		}
+		category.ParentID = req.ParentID
+	}
+
+	if err := uc.repo.Update(ctx, category); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrSlugAlreadyUsed.Build()
+		}
+		log.Println("Error updating category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return category, nil
+}
+
+// Delete removes a category without subcategories, its courses become uncategorized
+func (uc *UseCase) Delete(ctx context.Context, req *CategoryIDRequest) error {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+
+	children, err := uc.repo.CountChildren(ctx, id)
+	if err != nil {
+		log.Println("Error counting subcategories: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	if children > 0 {
+		return ErrCategoryHasChildren.Build()
+	}
+
+	if err := uc.repo.Delete(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound.Build()
+		}
+		log.Println("Error deleting category: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+func (uc *UseCase) GetTags(ctx context.Context, req *GetTagsRequest) ([]TagResponse, error) {
+	limit := req.Limit
+	if limit == 0 {
+		limit = 50
+	}
+
+	tags, err := uc.repo.GetTags(ctx, req.Search, limit)
+	if err != nil {
+		log.Println("Error getting tags: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return tags, nil
+}
@@ -0,0 +1,395 @@
+package certificate
+
+import (
+	"context"
+	"errors"
+	"mime/multipart"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/gomail.v2"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, certificate *schema.Certificate) error {
+	args := m.Called(ctx, certificate)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Certificate, error) {
+	args := m.Called(ctx, id)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Certificate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetByUserAndCourse(ctx context.Context, userID, courseID uuid.UUID) (*schema.Certificate, error) {
+	args := m.Called(ctx, userID, courseID)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Certificate), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Certificate, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Certificate), args.Error(1)
+}
+
+func (m *MockRepository) GetAverageGrade(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *schema.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(id uuid.UUID) (*schema.User, error) {
+	args := m.Called(id)
+	user, ok := args.Get(0).(*schema.User)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(email string) (*schema.User, error) {
+	args := m.Called(email)
+	user, ok := args.Get(0).(*schema.User)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) Update(user *schema.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateByEmail(email string, user *schema.User) error {
+	args := m.Called(email, user)
+	return args.Error(0)
+}
+
+type MockFileUploader struct {
+	mock.Mock
+}
+
+func (m *MockFileUploader) UploadFile(key string, fileHeader *multipart.FileHeader) (string, error) {
+	args := m.Called(key, fileHeader)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	args := m.Called(key, content, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	args := m.Called(fileURL, expiry)
+	return args.String(0), args.Error(1)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) DialAndSend(msgs ...*gomail.Message) error {
+	args := m.Called(msgs)
+	return args.Error(0)
+}
+
+type CertificateUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	userRepo   *MockUserRepository
+	uploader   *MockFileUploader
+	mailer     *MockMailer
+	useCase    *UseCase
+}
+
+func (suite *CertificateUseCaseTestSuite) SetupTest() {
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.userRepo = new(MockUserRepository)
+	suite.uploader = new(MockFileUploader)
+	suite.mailer = new(MockMailer)
+	suite.useCase = NewUseCase(suite.repo, suite.courseRepo, suite.userRepo, suite.uploader, suite.mailer)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_AlreadyIssued() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+	existing := &schema.Certificate{ID: uuid.New(), UserID: userID, CourseID: courseID}
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(existing, nil)
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), existing, cert)
+	suite.courseRepo.AssertNotCalled(suite.T(), "GetUserCourseProgress", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_NotCompleted() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID}, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, courseID, userID).Return(50.0, nil)
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.Nil(suite.T(), cert)
+	assert.Equal(suite.T(), ErrCourseNotCompleted.Build().Error(), err.Error())
+	suite.uploader.AssertNotCalled(suite.T(), "UploadBytes", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_MinimumGradeNotMet() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+	minGrade := 75.0
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, CertificateMinGrade: &minGrade}, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, courseID, userID).Return(100.0, nil)
+	suite.repo.On("GetAverageGrade", ctx, courseID, userID).Return(60.0, nil)
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.Nil(suite.T(), cert)
+	assert.Equal(suite.T(), ErrMinimumGradeNotMet.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_Success() {
+	ctx := context.Background()
+	userID, courseID, instructorID := uuid.New(), uuid.New(), uuid.New()
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, Title: "Go Basics", InstructorID: instructorID}, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, courseID, userID).Return(100.0, nil)
+	suite.repo.On("GetAverageGrade", ctx, courseID, userID).Return(88.5, nil)
+	suite.userRepo.On("GetByID", userID).Return(&schema.User{ID: userID, Name: "John Doe", Email: "john@example.com"}, nil)
+	suite.userRepo.On("GetByID", instructorID).Return(&schema.User{ID: instructorID, Name: "Jane Instructor"}, nil)
+	suite.uploader.On("UploadBytes", mock.AnythingOfType("string"), mock.Anything, "application/pdf").
+		Return("http://example.com/certificate.pdf", nil)
+	suite.repo.On("Create", ctx, mock.MatchedBy(func(c *schema.Certificate) bool {
+		return c.URL == "http://example.com/certificate.pdf"
+	})).Return(nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "John Doe", cert.StudentName)
+	assert.Equal(suite.T(), "Go Basics", cert.CourseTitle)
+	assert.Equal(suite.T(), "Jane Instructor", cert.InstructorName)
+	assert.Equal(suite.T(), 88.5, cert.AverageGrade)
+	assert.Equal(suite.T(), "http://example.com/certificate.pdf", cert.URL)
+
+	pdf := suite.uploader.Calls[0].Arguments.Get(1).([]byte)
+	assert.Contains(suite.T(), string(pdf), "%PDF-1.4")
+	assert.Contains(suite.T(), string(pdf), cert.ID.String())
+	suite.repo.AssertExpectations(suite.T())
+}
+
+// eligibleFor sets up a student who completed the course and is about to get their certificate
+func (suite *CertificateUseCaseTestSuite) eligibleFor(ctx context.Context, userID, courseID uuid.UUID) {
+	instructorID := uuid.New()
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, Title: "Go Basics", InstructorID: instructorID}, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, courseID, userID).Return(100.0, nil)
+	suite.repo.On("GetAverageGrade", ctx, courseID, userID).Return(88.5, nil)
+	suite.userRepo.On("GetByID", userID).Return(&schema.User{ID: userID, Name: "John Doe"}, nil)
+	suite.userRepo.On("GetByID", instructorID).Return(&schema.User{ID: instructorID, Name: "Jane Instructor"}, nil)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_IssuedConcurrently() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+	existing := &schema.Certificate{ID: uuid.New(), UserID: userID, CourseID: courseID, URL: "http://example.com/certificate.pdf"}
+	suite.eligibleFor(ctx, userID, courseID)
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound).Once()
+	suite.uploader.On("UploadBytes", mock.AnythingOfType("string"), mock.Anything, "application/pdf").
+		Return("http://example.com/other.pdf", nil)
+	suite.repo.On("Create", ctx, mock.AnythingOfType("*schema.Certificate")).Return(&pgconn.PgError{Code: "23505"})
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(existing, nil).Once()
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), existing, cert)
+	suite.mailer.AssertNotCalled(suite.T(), "DialAndSend", mock.Anything)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestIssueIfEligible_UploadFails() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+	suite.eligibleFor(ctx, userID, courseID)
+
+	suite.repo.On("GetByUserAndCourse", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.uploader.On("UploadBytes", mock.AnythingOfType("string"), mock.Anything, "application/pdf").
+		Return("", errors.New("s3 unavailable"))
+
+	cert, err := suite.useCase.IssueIfEligible(ctx, userID, courseID)
+
+	assert.Nil(suite.T(), cert)
+	assert.Equal(suite.T(), 500, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+	suite.mailer.AssertNotCalled(suite.T(), "DialAndSend", mock.Anything)
+}
+
+func (suite *CertificateUseCaseTestSuite) TestVerify_NotFound() {
+	ctx := context.Background()
+	id := uuid.New()
+
+	suite.repo.On("GetByID", ctx, id).Return(nil, gorm.ErrRecordNotFound)
+
+	res, err := suite.useCase.Verify(ctx, id.String())
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrCertificateNotFound.Build().Error(), err.Error())
+	assert.Equal(suite.T(), 404, apierror.GetHttpStatus(err))
+}
+
+func TestCertificateUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CertificateUseCaseTestSuite))
+}
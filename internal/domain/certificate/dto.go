@@ -0,0 +1,17 @@
+package certificate
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type VerifyCertificateResponse struct {
+	Valid          bool      `json:"valid"`
+	CertificateID  uuid.UUID `json:"certificate_id"`
+	StudentName    string    `json:"student_name"`
+	CourseTitle    string    `json:"course_title"`
+	InstructorName string    `json:"instructor_name"`
+	AverageGrade   float64   `json:"average_grade"`
+	IssuedAt       time.Time `json:"issued_at"`
+}
@@ -0,0 +1,21 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrCertificateNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("CERTIFICATE_NOT_FOUND")
+
+	ErrCourseNotCompleted = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COURSE_NOT_COMPLETED")
+
+	ErrMinimumGradeNotMet = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("MINIMUM_GRADE_NOT_MET")
+)
@@ -0,0 +1,42 @@
+package certificate
+
+import (
+	"fmt"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/pdfgen"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+)
+
+// renderCertificate draws a landscape A4 certificate, the verification URL printed on it lets anyone confirm authenticity
+func renderCertificate(cert *schema.Certificate, verifyURL string) []byte {
+	doc := pdfgen.NewDocument(pdfgen.A4Height, pdfgen.A4Width)
+	w, h := doc.Width(), doc.Height()
+
+	doc.SetColor(0, 0.467, 0.714)
+	doc.Rect(20, 20, w-40, h-40, 4)
+	doc.Rect(30, 30, w-60, h-60, 1)
+
+	doc.CenteredText(h-120, pdfgen.HelveticaBold, 36, "Certificate of Completion")
+
+	doc.SetColor(0.2, 0.2, 0.2)
+	doc.CenteredText(h-175, pdfgen.Helvetica, 16, "This is to certify that")
+	doc.CenteredText(h-225, pdfgen.HelveticaBold, 30, cert.StudentName)
+	doc.Line(w/2-200, h-237, w/2+200, h-237, 0.75)
+	doc.CenteredText(h-275, pdfgen.Helvetica, 16, "has successfully completed the course")
+	doc.CenteredText(h-315, pdfgen.HelveticaBold, 22, cert.CourseTitle)
+	doc.CenteredText(h-350, pdfgen.Helvetica, 14, fmt.Sprintf("with an average grade of %.1f", cert.AverageGrade))
+
+	doc.Text(90, 130, pdfgen.HelveticaBold, 14, cert.InstructorName)
+	doc.Line(90, 122, 290, 122, 0.75)
+	doc.Text(90, 105, pdfgen.Helvetica, 12, "Instructor")
+
+	doc.Text(w-290, 130, pdfgen.HelveticaBold, 14, cert.IssuedAt.Format("January 2, 2006"))
+	doc.Line(w-290, 122, w-90, 122, 0.75)
+	doc.Text(w-290, 105, pdfgen.Helvetica, 12, "Date Issued")
+
+	doc.SetColor(0.45, 0.45, 0.45)
+	doc.CenteredText(60, pdfgen.Helvetica, 9, "Certificate ID: "+cert.ID.String())
+	doc.CenteredText(46, pdfgen.Helvetica, 9, "Verify at "+verifyURL)
+
+	return doc.Bytes()
+}
@@ -0,0 +1,74 @@
+package certificate
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, certificate *schema.Certificate) error
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Certificate, error)
+	GetByUserAndCourse(ctx context.Context, userID, courseID uuid.UUID) (*schema.Certificate, error)
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Certificate, error)
+	GetAverageGrade(ctx context.Context, courseID, userID uuid.UUID) (float64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, certificate *schema.Certificate) error {
+	return r.db.WithContext(ctx).Create(certificate).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Certificate, error) {
+	var certificate schema.Certificate
+	if err := r.db.WithContext(ctx).First(&certificate, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+func (r *repository) GetByUserAndCourse(ctx context.Context, userID, courseID uuid.UUID) (*schema.Certificate, error) {
+	var certificate schema.Certificate
+	if err := r.db.WithContext(ctx).Where("user_id = ? AND course_id = ?", userID, courseID).
+		First(&certificate).Error; err != nil {
+		return nil, err
+	}
+	return &certificate, nil
+}
+
+func (r *repository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Certificate, error) {
+	var certificates []schema.Certificate
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("issued_at DESC").Find(&certificates).Error
+	return certificates, err
+}
+
+// GetAverageGrade averages the grades of the user for the assignments of the course together with their best
+// score of each quiz they submitted. An assignment whose grade is not released yet counts as zero
+func (r *repository) GetAverageGrade(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	var average float64
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COALESCE(AVG(grade), 0) FROM (
+			SELECT COALESCE(assignment_grades.released_grade, 0) AS grade
+			FROM assignment_grades
+			WHERE assignment_grades.course_id = @course AND assignment_grades.user_id = @user
+			UNION ALL
+			SELECT MAX(quiz_attempts.score) AS grade
+			FROM quiz_attempts
+			INNER JOIN quizzes ON quizzes.id = quiz_attempts.quiz_id
+			WHERE quizzes.course_id = @course AND quizzes.deleted_at IS NULL
+				AND quiz_attempts.user_id = @user AND quiz_attempts.submitted_at IS NOT NULL
+			GROUP BY quiz_attempts.quiz_id
+		) AS grades`,
+		map[string]any{"course": courseID, "user": userID}).
+		Scan(&average).Error
+	return average, err
+}
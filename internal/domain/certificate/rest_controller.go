@@ -0,0 +1,73 @@
+package certificate
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	certificateGroup := engine.Group("/v1/certificates")
+	{
+		certificateGroup.GET("/my", middleware.Authenticate(), controller.GetMy())
+		certificateGroup.POST("/courses/:courseId",
+			middleware.Authenticate(),
+			middleware.RequireRole("student"),
+			controller.Claim(),
+		)
+		certificateGroup.GET("/:id/verify", controller.Verify())
+	}
+}
+
+func (c *RestController) GetMy() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		certificates, err := c.uc.GetMy(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_CERTIFICATES_SUCCESS", certificates).Send(ctx)
+	}
+}
+
+func (c *RestController) Claim() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		courseID, err := uuid.Parse(ctx.Param("courseId"))
+		if err != nil {
+			err2 := apierror.ErrInvalidParamId.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), nil).Send(ctx)
+			return
+		}
+
+		certificate, err := c.uc.Claim(ctx, courseID)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "CLAIM_CERTIFICATE_SUCCESS", certificate).Send(ctx)
+	}
+}
+
+func (c *RestController) Verify() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.Verify(ctx, ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "VERIFY_CERTIFICATE_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,204 @@
+package certificate
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"io"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
+	"github.com/Stefanuswilfrid/course-backend/internal/mailer"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gopkg.in/gomail.v2"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo       Repository
+	courseRepo course.Repository
+	userRepo   user.IRepository
+	uploader   config.FileUploader
+	mailDialer config.IMailer
+}
+
+func NewUseCase(repo Repository, courseRepo course.Repository, userRepo user.IRepository,
+	uploader config.FileUploader, mailDialer config.IMailer) *UseCase {
+	return &UseCase{repo: repo, courseRepo: courseRepo, userRepo: userRepo, uploader: uploader, mailDialer: mailDialer}
+}
+
+//go:embed certificate_issued_student_email_template.html
+var certificateIssuedStudentEmailTemplate string
+
+// IssueIfEligible returns the student's certificate for the course, generating it the first time
+// the student reaches full progress and the course minimum grade (if any).
+func (uc *UseCase) IssueIfEligible(ctx context.Context, userID, courseID uuid.UUID) (*schema.Certificate, error) {
+	existing, err := uc.repo.GetByUserAndCourse(ctx, userID, courseID)
+	if err == nil {
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("Error getting certificate: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	courseObj, err := uc.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, course.ErrCourseNotFound.Build()
+	}
+
+	progress, err := uc.courseRepo.GetUserCourseProgress(ctx, courseID, userID)
+	if err != nil {
+		log.Println("Error getting course progress: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if progress < 100 {
+		return nil, ErrCourseNotCompleted.WithPayload(map[string]any{"progress": progress}).Build()
+	}
+
+	averageGrade, err := uc.repo.GetAverageGrade(ctx, courseID, userID)
+	if err != nil {
+		log.Println("Error getting average grade: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if courseObj.CertificateMinGrade != nil && averageGrade < *courseObj.CertificateMinGrade {
+		return nil, ErrMinimumGradeNotMet.WithPayload(map[string]any{
+			"minimum_grade": *courseObj.CertificateMinGrade,
+			"average_grade": averageGrade,
+		}).Build()
+	}
+
+	student, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		log.Println("Error getting student: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	instructor, err := uc.userRepo.GetByID(courseObj.InstructorID)
+	if err != nil {
+		log.Println("Error getting instructor: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	cert := &schema.Certificate{
+		ID:             id,
+		UserID:         userID,
+		CourseID:       courseID,
+		StudentName:    student.Name,
+		CourseTitle:    courseObj.Title,
+		InstructorName: instructor.Name,
+		AverageGrade:   averageGrade,
+		IssuedAt:       time.Now(),
+	}
+
+	verifyURL := config.Env.FrontendUrl + "/certificates/" + id.String() + "/verify"
+	pdf := renderCertificate(cert, verifyURL)
+
+	// The PDF is uploaded before the certificate is stored, so a stored certificate always has its file
+	cert.URL, err = uc.uploader.UploadBytes("certificates/"+id.String()+".pdf", pdf, "application/pdf")
+	if err != nil {
+		log.Println("Error uploading certificate: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	if err := uc.repo.Create(ctx, cert); err != nil {
+		// Another request issued the certificate meanwhile, the student keeps that one
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			existing, err := uc.repo.GetByUserAndCourse(ctx, userID, courseID)
+			if err != nil {
+				log.Println("Error getting certificate: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			return existing, nil
+		}
+		log.Println("Error creating certificate: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	// Send certificate to student
+	go func() {
+		emailData := map[string]any{
+			"student_name": student.Name,
+			"course_title": courseObj.Title,
+			"verify_link":  verifyURL,
+		}
+
+		mail, err := mailer.GenerateMail(student.Email, "Your Certificate of Completion", certificateIssuedStudentEmailTemplate, emailData)
+		if err != nil {
+			log.Println("Error generating email: ", err)
+			return
+		}
+		mail.Attach("certificate.pdf", gomail.SetCopyFunc(func(w io.Writer) error {
+			_, err := w.Write(pdf)
+			return err
+		}))
+
+		if err = uc.mailDialer.DialAndSend(mail); err != nil {
+			log.Println("Error sending email: ", err)
+		}
+	}()
+
+	return cert, nil
+}
+
+func (uc *UseCase) Claim(ctx context.Context, courseID uuid.UUID) (*schema.Certificate, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	return uc.IssueIfEligible(ctx, userID, courseID)
+}
+
+func (uc *UseCase) GetMy(ctx context.Context) ([]schema.Certificate, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	certificates, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Println("Error getting certificates: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return certificates, nil
+}
+
+func (uc *UseCase) Verify(ctx context.Context, idStr string) (*VerifyCertificateResponse, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	cert, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCertificateNotFound.Build()
+		}
+		log.Println("Error getting certificate: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return &VerifyCertificateResponse{
+		Valid:          true,
+		CertificateID:  cert.ID,
+		StudentName:    cert.StudentName,
+		CourseTitle:    cert.CourseTitle,
+		InstructorName: cert.InstructorName,
+		AverageGrade:   cert.AverageGrade,
+		IssuedAt:       cert.IssuedAt,
+	}, nil
+}
@@ -0,0 +1,555 @@
+package cohort
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, cohort *schema.Cohort) error {
+	args := m.Called(ctx, cohort)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Update(ctx context.Context, cohort *schema.Cohort) ([]schema.CohortMember, error) {
+	args := m.Called(ctx, cohort)
+	return args.Get(0).([]schema.CohortMember), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, courseID, id uuid.UUID) error {
+	args := m.Called(ctx, courseID, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Cohort, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Cohort), args.Error(1)
+}
+
+func (m *MockRepository) GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.Cohort, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Cohort), args.Error(1)
+}
+
+func (m *MockRepository) CountMembers(ctx context.Context, cohortID uuid.UUID, status schema.CohortMemberStatus) (int64, error) {
+	args := m.Called(ctx, cohortID, status)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetMembers(ctx context.Context, cohortID uuid.UUID) ([]schema.CohortMember, error) {
+	args := m.Called(ctx, cohortID)
+	return args.Get(0).([]schema.CohortMember), args.Error(1)
+}
+
+func (m *MockRepository) GetMembershipInCourse(ctx context.Context, courseID, userID uuid.UUID) (*schema.CohortMember, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CohortMember), args.Error(1)
+}
+
+func (m *MockRepository) IsMember(ctx context.Context, cohortID, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, cohortID, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) Join(ctx context.Context, member *schema.CohortMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Leave(ctx context.Context, cohortID, userID uuid.UUID) ([]schema.CohortMember, error) {
+	args := m.Called(ctx, cohortID, userID)
+	return args.Get(0).([]schema.CohortMember), args.Error(1)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockAssignmentRepository struct {
+	mock.Mock
+}
+
+func (m *MockAssignmentRepository) Create(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) Update(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Assignment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*schema.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]*schema.Assignment, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]*schema.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error {
+	args := m.Called(ctx, extension)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepository) GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, teamSetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error) {
+	args := m.Called(ctx, assignmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schema.RubricCriterion), args.Error(1)
+}
+
+func (m *MockAssignmentRepository) ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error {
+	args := m.Called(ctx, assignmentID, criteria)
+	return args.Error(0)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type CohortUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	courseRepo       *MockCourseRepository
+	assignmentRepo   *MockAssignmentRepository
+	enrollRepo       *MockEnrollRepository
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *CohortUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.assignmentRepo = new(MockAssignmentRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, suite.assignmentRepo, enrollUc, suite.notificationRepo)
+}
+
+func (suite *CohortUseCaseTestSuite) TestCreate_EndsBeforeStart() {
+	instructorID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", instructorID.String())
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: instructorID}
+	startsAt := time.Now().AddDate(0, 0, 7)
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateCohortRequest{
+		CourseID: courseObj.ID.String(),
+		Name:     "Batch 1",
+		StartsAt: startsAt,
+		EndsAt:   startsAt.AddDate(0, 0, -1),
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInvalidCohort.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *CohortUseCaseTestSuite) TestCreateController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	instructorID := uuid.New()
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: instructorID}
+
+	suite.courseRepo.On("GetByID", mock.Anything, courseObj.ID).Return(courseObj, nil)
+	suite.repo.On("Create", mock.Anything, mock.AnythingOfType("*schema.Cohort")).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPost, "/v1/courses/"+courseObj.ID.String()+"/cohorts",
+		`{"name": "Batch 1", "starts_at": "2030-01-06T09:00:00Z", "ends_at": "2030-03-31T17:00:00Z", "capacity": 30}`)
+	rec := testutil.Serve("/v1/courses/:id/cohorts", controller.Create(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	cohort := suite.repo.Calls[0].Arguments.Get(1).(*schema.Cohort)
+	assert.Equal(suite.T(), courseObj.ID, cohort.CourseID)
+	assert.Equal(suite.T(), "Batch 1", cohort.Name)
+	assert.Equal(suite.T(), 30, *cohort.Capacity)
+}
+
+func (suite *CohortUseCaseTestSuite) TestUpdateController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	instructorID := uuid.New()
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), Name: "Batch 1",
+		StartsAt: time.Now().AddDate(0, 0, 7), EndsAt: time.Now().AddDate(0, 3, 0), Capacity: testutil.Ptr(30)}
+
+	suite.repo.On("GetByID", mock.Anything, cohort.ID).Return(cohort, nil)
+	suite.courseRepo.On("GetByID", mock.Anything, cohort.CourseID).Return(schema.Course{ID: cohort.CourseID, InstructorID: instructorID}, nil)
+	suite.repo.On("Update", mock.Anything, cohort).Return([]schema.CohortMember{}, nil)
+
+	req := testutil.JSONRequest(http.MethodPatch, "/v1/cohorts/"+cohort.ID.String(), `{"name": "Batch 1 (evening)", "capacity": 0}`)
+	rec := testutil.Serve("/v1/cohorts/:id", controller.Update(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(suite.T(), "Batch 1 (evening)", cohort.Name)
+	assert.Nil(suite.T(), cohort.Capacity)
+}
+
+func (suite *CohortUseCaseTestSuite) TestJoin_NotEnrolledInCourse() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), StartsAt: time.Now().AddDate(0, 0, 7)}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, cohort.CourseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+
+	member, err := suite.useCase.Join(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.Nil(suite.T(), member)
+	assert.Equal(suite.T(), courseenroll.ErrNotEnrolled.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Join", mock.Anything, mock.Anything)
+}
+
+func (suite *CohortUseCaseTestSuite) TestJoin_AlreadyStarted() {
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), StartsAt: time.Now().Add(-time.Hour)}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+
+	member, err := suite.useCase.Join(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.Nil(suite.T(), member)
+	assert.Equal(suite.T(), ErrCohortStarted.Build().Error(), err.Error())
+}
+
+func (suite *CohortUseCaseTestSuite) TestJoin_AlreadyInAnotherCohort() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), StartsAt: time.Now().AddDate(0, 0, 7)}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, cohort.CourseID).Return(&schema.CourseEnroll{}, nil)
+	suite.repo.On("GetMembershipInCourse", ctx, cohort.CourseID, userID).Return(&schema.CohortMember{CohortID: uuid.New()}, nil)
+
+	member, err := suite.useCase.Join(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.Nil(suite.T(), member)
+	assert.Equal(suite.T(), ErrAlreadyInCohort.Build().Error(), err.Error())
+}
+
+func (suite *CohortUseCaseTestSuite) TestJoin_Waitlisted() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), StartsAt: time.Now().AddDate(0, 0, 7)}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, cohort.CourseID).Return(&schema.CourseEnroll{}, nil)
+	suite.repo.On("GetMembershipInCourse", ctx, cohort.CourseID, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("Join", ctx, mock.AnythingOfType("*schema.CohortMember")).Run(func(args mock.Arguments) {
+		args.Get(1).(*schema.CohortMember).Status = schema.CohortMemberWaitlisted
+	}).Return(nil)
+
+	member, err := suite.useCase.Join(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), schema.CohortMemberWaitlisted, member.Status)
+	assert.Equal(suite.T(), userID, member.UserID)
+}
+
+func (suite *CohortUseCaseTestSuite) TestLeave_NotifiesPromotedMember() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), Name: "Batch 1"}
+	promoted := schema.CohortMember{ID: uuid.New(), CohortID: cohort.ID, UserID: uuid.New(), Status: schema.CohortMemberEnrolled}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.repo.On("Leave", ctx, cohort.ID, userID).Return([]schema.CohortMember{promoted}, nil)
+	suite.courseRepo.On("GetByID", ctx, cohort.CourseID).Return(schema.Course{ID: cohort.CourseID, Title: "Go Fundamentals"}, nil)
+	suite.notificationRepo.On("Create", mock.MatchedBy(func(n *schema.Notification) bool {
+		return n.UserID == promoted.UserID
+	})).Return(nil)
+
+	err := suite.useCase.Leave(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	suite.notificationRepo.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *CohortUseCaseTestSuite) TestGetAssignments_ResolvesRelativeDue() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	startsAt := time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC)
+	fixedDue := time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC)
+	offset := 14
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New(), StartsAt: startsAt}
+	relative := &schema.Assignment{ID: uuid.New(), DueOffsetDays: &offset, Due: &fixedDue}
+	absolute := &schema.Assignment{ID: uuid.New(), Due: &fixedDue}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.courseRepo.On("GetByID", ctx, cohort.CourseID).Return(schema.Course{ID: cohort.CourseID, InstructorID: uuid.New()}, nil)
+	suite.repo.On("IsMember", ctx, cohort.ID, userID).Return(true, nil)
+	suite.assignmentRepo.On("GetByCourseID", ctx, cohort.CourseID).Return([]*schema.Assignment{relative, absolute}, nil)
+
+	res, err := suite.useCase.GetAssignments(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), startsAt.AddDate(0, 0, 14), *res[0].Due)
+	assert.Equal(suite.T(), fixedDue, *res[1].Due)
+}
+
+func (suite *CohortUseCaseTestSuite) TestGetAssignments_NotMember() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	cohort := &schema.Cohort{ID: uuid.New(), CourseID: uuid.New()}
+
+	suite.repo.On("GetByID", ctx, cohort.ID).Return(cohort, nil)
+	suite.courseRepo.On("GetByID", ctx, cohort.CourseID).Return(schema.Course{ID: cohort.CourseID, InstructorID: uuid.New()}, nil)
+	suite.repo.On("IsMember", ctx, cohort.ID, userID).Return(false, nil)
+
+	res, err := suite.useCase.GetAssignments(ctx, &CohortIDRequest{ID: cohort.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrNotInCohort.Build().Error(), err.Error())
+	suite.assignmentRepo.AssertNotCalled(suite.T(), "GetByCourseID", mock.Anything, mock.Anything)
+}
+
+func TestCohortUseCase(t *testing.T) {
+	suite.Run(t, new(CohortUseCaseTestSuite))
+}
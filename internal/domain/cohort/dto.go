@@ -0,0 +1,43 @@
+package cohort
+
+import (
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+)
+
+type CourseIDRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+}
+
+type CohortIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type CreateCohortRequest struct {
+	CourseID string    `uri:"id" binding:"required,uuid"`
+	Name     string    `json:"name" binding:"required,max=100"`
+	StartsAt time.Time `json:"starts_at" binding:"required"`
+	EndsAt   time.Time `json:"ends_at" binding:"required"`
+	Capacity *int      `json:"capacity" binding:"omitempty,min=1"`
+}
+
+type UpdateCohortRequest struct {
+	ID       string     `uri:"id" binding:"required,uuid"`
+	Name     *string    `json:"name" binding:"omitempty,max=100"`
+	StartsAt *time.Time `json:"starts_at"`
+	EndsAt   *time.Time `json:"ends_at"`
+	Capacity *int       `json:"capacity" binding:"omitempty,min=0"`
+}
+
+type DeleteCohortRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+	CohortID string `uri:"cohortId" binding:"required,uuid"`
+}
+
+type CohortResponse struct {
+	schema.Cohort
+	EnrolledCount int64 `json:"enrolled_count"`
+	WaitlistCount int64 `json:"waitlist_count"`
+	SeatsLeft     *int  `json:"seats_left"`
+}
@@ -0,0 +1,29 @@
+package cohort
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrCohortNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("COHORT_NOT_FOUND")
+
+	ErrInvalidCohort = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_COHORT")
+
+	ErrCohortStarted = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COHORT_ALREADY_STARTED")
+
+	ErrAlreadyInCohort = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("ALREADY_IN_COHORT")
+
+	ErrNotInCohort = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusForbidden).
+			WithMessage("NOT_IN_COHORT")
+)
@@ -0,0 +1,214 @@
+package cohort
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Create(ctx context.Context, cohort *schema.Cohort) error
+	Update(ctx context.Context, cohort *schema.Cohort) ([]schema.CohortMember, error)
+	Delete(ctx context.Context, courseID, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Cohort, error)
+	GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.Cohort, error)
+	CountMembers(ctx context.Context, cohortID uuid.UUID, status schema.CohortMemberStatus) (int64, error)
+	GetMembers(ctx context.Context, cohortID uuid.UUID) ([]schema.CohortMember, error)
+	GetMembershipInCourse(ctx context.Context, courseID, userID uuid.UUID) (*schema.CohortMember, error)
+	IsMember(ctx context.Context, cohortID, userID uuid.UUID) (bool, error)
+	Join(ctx context.Context, member *schema.CohortMember) error
+	Leave(ctx context.Context, cohortID, userID uuid.UUID) ([]schema.CohortMember, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, cohort *schema.Cohort) error {
+	return r.db.WithContext(ctx).Create(cohort).Error
+}
+
+// Update saves the cohort and fills any seats a larger capacity opened up, the promoted members are returned
+func (r *repository) Update(ctx context.Context, cohort *schema.Cohort) ([]schema.CohortMember, error) {
+	var promoted []schema.CohortMember
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Course").Save(cohort).Error; err != nil {
+			return err
+		}
+
+		var err error
+		promoted, err = promoteWaitlisted(tx, cohort.ID)
+		return err
+	})
+	return promoted, err
+}
+
+func (r *repository) Delete(ctx context.Context, courseID, id uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND course_id = ?", id, courseID).Delete(&schema.Cohort{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Cohort, error) {
+	var cohort schema.Cohort
+	if err := r.db.WithContext(ctx).First(&cohort, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &cohort, nil
+}
+
+func (r *repository) GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.Cohort, error) {
+	var cohorts []schema.Cohort
+	err := r.db.WithContext(ctx).
+		Where("course_id = ?", courseID).
+		Order("starts_at").
+		Find(&cohorts).Error
+	return cohorts, err
+}
+
+func (r *repository) CountMembers(ctx context.Context, cohortID uuid.UUID, status schema.CohortMemberStatus) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&schema.CohortMember{}).
+		Where("cohort_id = ? AND status = ?", cohortID, status).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *repository) GetMembers(ctx context.Context, cohortID uuid.UUID) ([]schema.CohortMember, error) {
+	var members []schema.CohortMember
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("cohort_id = ?", cohortID).
+		Order("status, created_at").
+		Find(&members).Error
+	return members, err
+}
+
+// GetMembershipInCourse returns the seat or waitlist spot the user holds in any cohort of the course
+func (r *repository) GetMembershipInCourse(ctx context.Context, courseID, userID uuid.UUID) (*schema.CohortMember, error) {
+	var member schema.CohortMember
+	err := r.db.WithContext(ctx).
+		Joins("JOIN cohorts ON cohorts.id = cohort_members.cohort_id AND cohorts.deleted_at IS NULL").
+		Where("cohorts.course_id = ? AND cohort_members.user_id = ?", courseID, userID).
+		First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+	return &member, nil
+}
+
+// IsMember reports whether the user holds a seat in the cohort, waitlisted users are not members yet
+func (r *repository) IsMember(ctx context.Context, cohortID, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&schema.CohortMember{}).
+		Where("cohort_id = ? AND user_id = ? AND status = ?", cohortID, userID, schema.CohortMemberEnrolled).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// lockCohort locks the cohort row so seat counting and assignment are serialized per cohort
+func lockCohort(tx *gorm.DB, cohortID uuid.UUID) (*schema.Cohort, error) {
+	var cohort schema.Cohort
+	if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&cohort, "id = ?", cohortID).Error; err != nil {
+		return nil, err
+	}
+	return &cohort, nil
+}
+
+func countEnrolled(tx *gorm.DB, cohortID uuid.UUID) (int64, error) {
+	var count int64
+	err := tx.Model(&schema.CohortMember{}).
+		Where("cohort_id = ? AND status = ?", cohortID, schema.CohortMemberEnrolled).
+		Count(&count).Error
+	return count, err
+}
+
+// promoteWaitlisted moves waitlisted members into free seats in the order they joined
+func promoteWaitlisted(tx *gorm.DB, cohortID uuid.UUID) ([]schema.CohortMember, error) {
+	cohort, err := lockCohort(tx, cohortID)
+	if err != nil {
+		return nil, err
+	}
+
+	query := tx.Where("cohort_id = ? AND status = ?", cohortID, schema.CohortMemberWaitlisted).Order("created_at")
+	if cohort.Capacity != nil {
+		enrolled, err := countEnrolled(tx, cohortID)
+		if err != nil {
+			return nil, err
+		}
+		free := int64(*cohort.Capacity) - enrolled
+		if free <= 0 {
+			return nil, nil
+		}
+		query = query.Limit(int(free))
+	}
+
+	var promoted []schema.CohortMember
+	if err := query.Find(&promoted).Error; err != nil {
+		return nil, err
+	}
+	for i := range promoted {
+		promoted[i].Status = schema.CohortMemberEnrolled
+		if err := tx.Model(&promoted[i]).Update("status", schema.CohortMemberEnrolled).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	return promoted, nil
+}
+
+// Join takes a seat when one is free and puts the member on the waitlist otherwise, member.Status is set accordingly
+func (r *repository) Join(ctx context.Context, member *schema.CohortMember) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		cohort, err := lockCohort(tx, member.CohortID)
+		if err != nil {
+			return err
+		}
+
+		member.Status = schema.CohortMemberEnrolled
+		if cohort.Capacity != nil {
+			enrolled, err := countEnrolled(tx, cohort.ID)
+			if err != nil {
+				return err
+			}
+			if enrolled >= int64(*cohort.Capacity) {
+				member.Status = schema.CohortMemberWaitlisted
+			}
+		}
+
+		return tx.Omit("User", "Cohort").Create(member).Error
+	})
+}
+
+// Leave gives up the user's seat or waitlist spot and returns the members promoted into the freed seat
+func (r *repository) Leave(ctx context.Context, cohortID, userID uuid.UUID) ([]schema.CohortMember, error) {
+	var promoted []schema.CohortMember
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Where("cohort_id = ? AND user_id = ?", cohortID, userID).Delete(&schema.CohortMember{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		var err error
+		promoted, err = promoteWaitlisted(tx, cohortID)
+		return err
+	})
+	return promoted, err
+}
@@ -0,0 +1,218 @@
+package cohort
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	courseCohortGroup := engine.Group("/v1/courses/:id/cohorts")
+	{
+		courseCohortGroup.GET("", controller.GetByCourse())
+		courseCohortGroup.POST("", middleware.Authenticate(), controller.Create())
+		courseCohortGroup.DELETE("/:cohortId", middleware.Authenticate(), controller.Delete())
+	}
+
+	cohortGroup := engine.Group("/v1/cohorts")
+	{
+		cohortGroup.GET("/:id", controller.GetByID())
+		cohortGroup.PATCH("/:id", middleware.Authenticate(), controller.Update())
+		cohortGroup.GET("/:id/members", middleware.Authenticate(), controller.GetMembers())
+		cohortGroup.GET("/:id/assignments", middleware.Authenticate(), controller.GetAssignments())
+		cohortGroup.POST("/:id/join",
+			middleware.Authenticate(),
+			middleware.RequireRole("student"),
+			controller.Join(),
+		)
+		cohortGroup.POST("/:id/leave",
+			middleware.Authenticate(),
+			middleware.RequireRole("student"),
+			controller.Leave(),
+		)
+	}
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := CreateCohortRequest{CourseID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_COHORT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByCourse() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByCourse(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_COHORTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req DeleteCohortRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_COHORT_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CohortIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByID(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_COHORT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateCohortRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Update(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_COHORT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetMembers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CohortIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetMembers(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_COHORT_MEMBERS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetAssignments() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CohortIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetAssignments(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_COHORT_ASSIGNMENTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Join() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CohortIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Join(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "JOIN_COHORT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Leave() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CohortIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Leave(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "LEAVE_COHORT_SUCCESS", nil).Send(ctx)
+	}
+}
@@ -0,0 +1,424 @@
+package cohort
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/assignment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo             Repository
+	courseUc         *course.UseCase
+	assignmentRepo   assignment.Repository
+	enrollUc         *courseenroll.UseCase
+	notificationRepo notification.IRepository
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, assignmentRepo assignment.Repository,
+	enrollUc *courseenroll.UseCase, notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{
+		repo:             repo,
+		courseUc:         courseUc,
+		assignmentRepo:   assignmentRepo,
+		enrollUc:         enrollUc,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func (uc *UseCase) getCohort(ctx context.Context, idStr string) (*schema.Cohort, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	cohort, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCohortNotFound.Build()
+		}
+		log.Println("Error getting cohort: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return cohort, nil
+}
+
+func validateSchedule(startsAt, endsAt time.Time) error {
+	if !endsAt.After(startsAt) {
+		return ErrInvalidCohort.WithPayload(map[string]any{
+			"reason": "ends_at must be after starts_at",
+		}).Build()
+	}
+	return nil
+}
+
+func (uc *UseCase) notifyPromoted(ctx context.Context, cohort *schema.Cohort, promoted []schema.CohortMember) {
+	if len(promoted) == 0 {
+		return
+	}
+
+	courseObj, err := uc.courseUc.GetCourse(ctx, cohort.CourseID)
+	if err != nil {
+		return
+	}
+
+	for _, member := range promoted {
+		notificationID, err := uuid.NewV7()
+		if err != nil {
+			continue
+		}
+
+		notif := schema.Notification{
+			ID:     notificationID,
+			UserID: member.UserID,
+			Title:  "You got a seat",
+			Detail: fmt.Sprintf("A seat opened up in %s of %s and you have been moved off the waitlist", cohort.Name, courseObj.Title),
+		}
+		if err := uc.notificationRepo.Create(&notif); err != nil {
+			log.Println("Error creating notification: ", err)
+		}
+	}
+}
+
+func (uc *UseCase) toResponse(ctx context.Context, cohort schema.Cohort) (*CohortResponse, error) {
+	enrolled, err := uc.repo.CountMembers(ctx, cohort.ID, schema.CohortMemberEnrolled)
+	if err != nil {
+		return nil, err
+	}
+	waitlisted, err := uc.repo.CountMembers(ctx, cohort.ID, schema.CohortMemberWaitlisted)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &CohortResponse{Cohort: cohort, EnrolledCount: enrolled, WaitlistCount: waitlisted}
+	if cohort.Capacity != nil {
+		left := max(*cohort.Capacity-int(enrolled), 0)
+		res.SeatsLeft = &left
+	}
+	return res, nil
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateCohortRequest) (*schema.Cohort, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	courseObj, err := uc.courseUc.GetManagedCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validateSchedule(req.StartsAt, req.EndsAt); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	cohort := &schema.Cohort{
+		ID:       id,
+		CourseID: courseObj.ID,
+		Name:     req.Name,
+		StartsAt: req.StartsAt,
+		EndsAt:   req.EndsAt,
+		Capacity: req.Capacity,
+	}
+
+	if err := uc.repo.Create(ctx, cohort); err != nil {
+		log.Println("Error creating cohort: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return cohort, nil
+}
+
+// Update edits a cohort, raising or removing the capacity moves waitlisted students into the new seats
+func (uc *UseCase) Update(ctx context.Context, req *UpdateCohortRequest) (*schema.Cohort, error) {
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.courseUc.GetManagedCourse(ctx, cohort.CourseID); err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		cohort.Name = *req.Name
+	}
+	if req.StartsAt != nil {
+		cohort.StartsAt = *req.StartsAt
+	}
+	if req.EndsAt != nil {
+		cohort.EndsAt = *req.EndsAt
+	}
+	if req.Capacity != nil {
+		// 0 removes the seat limit
+		if *req.Capacity == 0 {
+			cohort.Capacity = nil
+		} else {
+			cohort.Capacity = req.Capacity
+		}
+	}
+
+	if err := validateSchedule(cohort.StartsAt, cohort.EndsAt); err != nil {
+		return nil, err
+	}
+
+	promoted, err := uc.repo.Update(ctx, cohort)
+	if err != nil {
+		log.Println("Error updating cohort: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	uc.notifyPromoted(ctx, cohort, promoted)
+
+	return cohort, nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, req *DeleteCohortRequest) error {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+
+	if _, err := uc.courseUc.GetManagedCourse(ctx, courseID); err != nil {
+		return err
+	}
+
+	cohortID, err := uuid.Parse(req.CohortID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+
+	if err := uc.repo.Delete(ctx, courseID, cohortID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCohortNotFound.Build()
+		}
+		log.Println("Error deleting cohort: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+func (uc *UseCase) GetByCourse(ctx context.Context, req *CourseIDRequest) ([]CohortResponse, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	if _, err := uc.courseUc.GetCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	cohorts, err := uc.repo.GetByCourseID(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting cohorts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := make([]CohortResponse, len(cohorts))
+	for i, cohort := range cohorts {
+		item, err := uc.toResponse(ctx, cohort)
+		if err != nil {
+			log.Println("Error counting cohort members: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		res[i] = *item
+	}
+
+	return res, nil
+}
+
+func (uc *UseCase) GetByID(ctx context.Context, req *CohortIDRequest) (*CohortResponse, error) {
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := uc.toResponse(ctx, *cohort)
+	if err != nil {
+		log.Println("Error counting cohort members: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return res, nil
+}
+
+func (uc *UseCase) GetMembers(ctx context.Context, req *CohortIDRequest) ([]schema.CohortMember, error) {
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.courseUc.GetManagedCourse(ctx, cohort.CourseID); err != nil {
+		return nil, err
+	}
+
+	members, err := uc.repo.GetMembers(ctx, cohort.ID)
+	if err != nil {
+		log.Println("Error getting cohort members: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return members, nil
+}
+
+// Join puts a student with access to the course into the cohort, or on its waitlist when it is full.
+// A student takes part in one cohort of a course and can only join before it starts
+func (uc *UseCase) Join(ctx context.Context, req *CohortIDRequest) (*schema.CohortMember, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !cohort.StartsAt.After(time.Now()) {
+		return nil, ErrCohortStarted.Build()
+	}
+
+	ok, err := uc.enrollUc.CheckEnrollment(ctx, userID, cohort.CourseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if !ok {
+		return nil, courseenroll.ErrNotEnrolled.Build()
+	}
+
+	if _, err := uc.repo.GetMembershipInCourse(ctx, cohort.CourseID, userID); err == nil {
+		return nil, ErrAlreadyInCohort.Build()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("Error getting cohort membership: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	member := &schema.CohortMember{
+		ID:       id,
+		CohortID: cohort.ID,
+		UserID:   userID,
+	}
+
+	if err := uc.repo.Join(ctx, member); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrAlreadyInCohort.Build()
+		}
+		log.Println("Error joining cohort: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return member, nil
+}
+
+// Leave frees the student's seat, the next student on the waitlist takes it
+func (uc *UseCase) Leave(ctx context.Context, req *CohortIDRequest) error {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return apierror.ErrTokenInvalid.Build()
+	}
+
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	promoted, err := uc.repo.Leave(ctx, cohort.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotInCohort.Build()
+		}
+		log.Println("Error leaving cohort: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	uc.notifyPromoted(ctx, cohort, promoted)
+
+	return nil
+}
+
+// GetAssignments lists the course assignments with deadlines resolved against the cohort start
+func (uc *UseCase) GetAssignments(ctx context.Context, req *CohortIDRequest) ([]*schema.Assignment, error) {
+	cohort, err := uc.getCohort(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.CheckMember(ctx, cohort); err != nil {
+		return nil, err
+	}
+
+	assignments, err := uc.assignmentRepo.GetByCourseID(ctx, cohort.CourseID)
+	if err != nil {
+		log.Println("Error getting assignments: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	for _, a := range assignments {
+		a.Due = assignment.DueInCohort(a, cohort.StartsAt)
+	}
+
+	return assignments, nil
+}
+
+// CheckMember allows the course managers and the students holding a seat in the cohort
+func (uc *UseCase) CheckMember(ctx context.Context, cohort *schema.Cohort) error {
+	courseObj, err := uc.courseUc.GetCourse(ctx, cohort.CourseID)
+	if err != nil {
+		return err
+	}
+	if course.IsManager(ctx, courseObj) {
+		return nil
+	}
+
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return apierror.ErrTokenInvalid.Build()
+	}
+
+	ok, err := uc.repo.IsMember(ctx, cohort.ID, userID)
+	if err != nil {
+		log.Println("Error checking cohort membership: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	if !ok {
+		return ErrNotInCohort.Build()
+	}
+
+	return nil
+}
+
+// CheckAccess verifies the cohort belongs to the course and the current user may take part in it
+func (uc *UseCase) CheckAccess(ctx context.Context, courseID, cohortID uuid.UUID) error {
+	cohort, err := uc.getCohort(ctx, cohortID.String())
+	if err != nil {
+		return err
+	}
+	if cohort.CourseID != courseID {
+		return ErrCohortNotFound.Build()
+	}
+
+	return uc.CheckMember(ctx, cohort)
+}
@@ -0,0 +1,193 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Viewer is what the current user may see of a course. Members open every released lesson while others
+// only open the preview ones, staff open lessons before their release too. Drip schedules count from
+// EnrolledAt, which is the moment of viewing for visitors so they see how long after enrolling a lesson opens
+type Viewer struct {
+	IsMember   bool
+	IsStaff    bool
+	EnrolledAt time.Time
+}
+
+func visitor() *Viewer {
+	return &Viewer{EnrolledAt: time.Now()}
+}
+
+// GetViewer works out the access of the current user to the course: its instructor and admins are staff,
+// students with an enrollment or a subscription are members. Anonymous users are visitors
+func (uc *UseCase) GetViewer(ctx context.Context, course *schema.Course) (*Viewer, error) {
+	userIDStr, ok := ctx.Value("user.id").(string)
+	if !ok {
+		return visitor(), nil
+	}
+
+	if IsManager(ctx, course) {
+		return &Viewer{IsMember: true, IsStaff: true, EnrolledAt: time.Now()}, nil
+	}
+
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	isMember, err := uc.courseEnrollUseCase.CheckEnrollment(ctx, userID, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !isMember {
+		return visitor(), nil
+	}
+
+	enrolledAt, err := uc.courseEnrollUseCase.GetAccessStart(ctx, userID, course.ID)
+	if err != nil {
+		return nil, err
+	}
+	viewer := &Viewer{IsMember: true, EnrolledAt: time.Now()}
+	if enrolledAt != nil {
+		viewer.EnrolledAt = *enrolledAt
+	}
+	return viewer, nil
+}
+
+// IsManager reports whether the current user is the course instructor or an admin
+func IsManager(ctx context.Context, course *schema.Course) bool {
+	role, _ := ctx.Value("user.role").(string)
+	if role == string(schema.RoleAdmin) {
+		return true
+	}
+
+	userIDStr, _ := ctx.Value("user.id").(string)
+	return userIDStr != "" && course.InstructorID.String() == userIDStr
+}
+
+// GetCourse looks up a course, a missing course is reported as ErrCourseNotFound
+func (uc *UseCase) GetCourse(ctx context.Context, courseID uuid.UUID) (*schema.Course, error) {
+	course, err := uc.courseRepo.GetByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCourseNotFound.Build()
+		}
+		log.Println("Error getting course: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return &course, nil
+}
+
+// GetManagedCourse returns the course when the current user is its instructor or an admin
+func (uc *UseCase) GetManagedCourse(ctx context.Context, courseID uuid.UUID) (*schema.Course, error) {
+	course, err := uc.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	if !IsManager(ctx, course) {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	return course, nil
+}
+
+// CanOpen reports whether the viewer has access to an item, preview lessons are open to everyone
+func (v *Viewer) CanOpen(isPreview bool) bool {
+	return v.IsMember || isPreview
+}
+
+// AvailableAt returns when a scheduled item opens for the viewer, nil when it already has
+func (v *Viewer) AvailableAt(schedule *schema.ReleaseSchedule) *time.Time {
+	if v.IsStaff {
+		return nil
+	}
+
+	releaseAt := schedule.ReleaseTime(v.EnrolledAt)
+	if releaseAt == nil || !releaseAt.After(time.Now()) {
+		return nil
+	}
+	return releaseAt
+}
+
+// OpenMaterial swaps the stored links of a material the viewer may open for short-lived download links
+func (uc *UseCase) OpenMaterial(mat *schema.Material) {
+	attachment.SignURLs(uc.uploader, mat.Attachments)
+	if video, ok := mat.Content.(*schema.VideoContent); ok {
+		video.URL = attachment.SignURL(uc.uploader, video.URL)
+	}
+}
+
+// OpenAssignment swaps the stored attachment links of an assignment for short-lived download links
+func (uc *UseCase) OpenAssignment(assignment *schema.Assignment) {
+	attachment.SignURLs(uc.uploader, assignment.Attachments)
+}
+
+// LockMaterial leaves only what the course outline shows of a lesson
+func LockMaterial(mat *schema.Material) {
+	mat.Content = nil
+	mat.Attachments = []schema.Attachment{}
+	mat.Locked = true
+}
+
+// LockAssignment leaves only what the course outline shows of an assignment
+func LockAssignment(assignment *schema.Assignment) {
+	assignment.Attachments = []schema.Attachment{}
+	assignment.Locked = true
+}
+
+// ShowMaterial opens the material for the viewer or locks it, scheduled lessons the viewer
+// has access to are locked until their release and marked as upcoming
+func (uc *UseCase) ShowMaterial(viewer *Viewer, mat *schema.Material) {
+	if !viewer.CanOpen(mat.IsPreview) {
+		LockMaterial(mat)
+		return
+	}
+	if availableAt := viewer.AvailableAt(&mat.ReleaseSchedule); availableAt != nil {
+		LockMaterial(mat)
+		mat.AvailableAt = availableAt
+		return
+	}
+	uc.OpenMaterial(mat)
+}
+
+// ShowAssignment opens the assignment for the viewer or locks it the same way as ShowMaterial
+func (uc *UseCase) ShowAssignment(viewer *Viewer, assignment *schema.Assignment) {
+	if !viewer.CanOpen(false) {
+		LockAssignment(assignment)
+		return
+	}
+	if availableAt := viewer.AvailableAt(&assignment.ReleaseSchedule); availableAt != nil {
+		LockAssignment(assignment)
+		assignment.AvailableAt = availableAt
+		return
+	}
+	uc.OpenAssignment(assignment)
+}
+
+// ApplyContentAccess prepares the materials and assignments of a course for the current viewer,
+// course members get everything released so far while others get the outline with only the preview lessons open
+func (uc *UseCase) ApplyContentAccess(ctx context.Context, course *schema.Course) error {
+	viewer, err := uc.GetViewer(ctx, course)
+	if err != nil {
+		return err
+	}
+
+	uc.applyContentAccess(course, viewer)
+	return nil
+}
+
+func (uc *UseCase) applyContentAccess(course *schema.Course, viewer *Viewer) {
+	for i := range course.Materials {
+		uc.ShowMaterial(viewer, &course.Materials[i])
+	}
+	for i := range course.Assignments {
+		uc.ShowAssignment(viewer, &course.Assignments[i])
+	}
+}
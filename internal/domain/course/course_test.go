@@ -0,0 +1,482 @@
+package course
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) DynamicFilterCourses(ctx context.Context, filter CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+func TestProratePackage(t *testing.T) {
+	prices := []int64{100000, 200000, 200000}
+	free := []int64{0, 0, 0, 0}
+
+	tests := []struct {
+		name   string
+		prices []int64
+		owned  []bool
+		want   int64
+	}{
+		{name: "nothing owned", prices: prices, owned: []bool{false, false, false}, want: 300000},
+		{name: "cheap course owned", prices: prices, owned: []bool{true, false, false}, want: 240000},
+		{name: "two courses owned", prices: prices, owned: []bool{true, true, false}, want: 120000},
+		{name: "all owned", prices: prices, owned: []bool{true, true, true}, want: 0},
+		{name: "free courses weighted equally", prices: free, owned: []bool{true, false, false, false}, want: 225000},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, proratePackage(300000, tt.prices, tt.owned))
+		})
+	}
+}
+
+type CourseUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	enrollRepo       *MockEnrollRepository
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *CourseUseCaseTestSuite) SetupTest() {
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	_ = os.Setenv("BASE_CURRENCY", "IDR")
+	_ = os.Setenv("EXCHANGE_RATES", "USD=16000")
+	config.LoadEnv()
+
+	suite.repo = new(MockRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	suite.useCase = NewUseCase(suite.repo, nil, *enrollUc, nil, suite.notificationRepo, nil, nil)
+}
+
+func (suite *CourseUseCaseTestSuite) TestCreateAndEnrollFree_ZeroPrice() {
+	controller := &RestController{uc: suite.useCase}
+	instructorID, studentID := uuid.New(), uuid.New()
+
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	_ = form.WriteField("title", "Free Intro")
+	_ = form.WriteField("price", "0")
+	_ = form.WriteField("difficulty", "beginner")
+	_ = form.WriteField("prerequisite_policy", "none")
+	_ = form.Close()
+
+	var created schema.Course
+	suite.repo.On("Create", mock.Anything, mock.AnythingOfType("*schema.Course")).Return(nil).Run(func(args mock.Arguments) {
+		created = *args.Get(1).(*schema.Course)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/courses", &body)
+	req.Header.Set("Content-Type", form.FormDataContentType())
+	rec := testutil.Serve("/v1/courses", controller.Create(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	assert.Equal(suite.T(), int64(0), created.Price)
+	assert.Equal(suite.T(), instructorID, created.InstructorID)
+
+	suite.repo.On("GetByID", mock.Anything, created.ID).Return(created, nil)
+	suite.repo.On("GetActiveSales", mock.Anything, []uuid.UUID{created.ID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", mock.Anything, studentID, created.ID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", mock.Anything, studentID).Return(false, nil)
+	suite.enrollRepo.On("Create", mock.Anything, mock.AnythingOfType("*schema.CourseEnroll")).Return(nil)
+	suite.notificationRepo.On("Create", mock.Anything).Return(nil).Maybe()
+
+	req = httptest.NewRequest(http.MethodPost, "/v1/courses/"+created.ID.String()+"/enroll", nil)
+	rec = testutil.Serve("/v1/courses/:id/enroll", controller.EnrollFree(), studentID, schema.RoleStudent, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	enroll := suite.enrollRepo.Calls[len(suite.enrollRepo.Calls)-1].Arguments.Get(1).(*schema.CourseEnroll)
+	assert.Equal(suite.T(), studentID, enroll.UserID)
+	assert.Equal(suite.T(), created.ID, enroll.CourseID)
+}
+
+func (suite *CourseUseCaseTestSuite) sale(courseID uuid.UUID, price int64) schema.CourseSale {
+	now := time.Now()
+	return schema.CourseSale{
+		ID:        uuid.New(),
+		CourseID:  courseID,
+		SalePrice: price,
+		StartsAt:  now.Add(-time.Hour),
+		EndsAt:    now.Add(time.Hour),
+	}
+}
+
+func (suite *CourseUseCaseTestSuite) TestApplyPricing_CheapestSaleWins() {
+	ctx := context.Background()
+	onSale := schema.Course{ID: uuid.New(), Price: 200000, Currency: "IDR"}
+	regular := schema.Course{ID: uuid.New(), Price: 100000}
+	courses := []schema.Course{onSale, regular}
+
+	sales := []schema.CourseSale{
+		suite.sale(onSale.ID, 150000),
+		suite.sale(onSale.ID, 120000),
+		suite.sale(regular.ID, 150000), // above the course price, ignored
+	}
+	suite.repo.On("GetActiveSales", ctx, []uuid.UUID{onSale.ID, regular.ID}, mock.Anything).Return(sales, nil)
+
+	err := suite.useCase.ApplyPricing(ctx, courses, "")
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), courses[0].Pricing.OnSale)
+	assert.Equal(suite.T(), int64(120000), courses[0].Pricing.Price)
+	assert.Equal(suite.T(), int64(200000), courses[0].Pricing.OriginalPrice)
+	assert.Equal(suite.T(), sales[1].EndsAt, *courses[0].Pricing.SaleEndsAt)
+	assert.False(suite.T(), courses[1].Pricing.OnSale)
+	assert.Equal(suite.T(), int64(100000), courses[1].Pricing.Price)
+	assert.Equal(suite.T(), "IDR", courses[1].Currency)
+	assert.Nil(suite.T(), courses[0].Pricing.Display)
+}
+
+func (suite *CourseUseCaseTestSuite) TestApplyPricing_DisplayCurrency() {
+	ctx := context.Background()
+	courses := []schema.Course{{ID: uuid.New(), Price: 320000, Currency: "IDR"}}
+
+	suite.repo.On("GetActiveSales", ctx, []uuid.UUID{courses[0].ID}, mock.Anything).
+		Return([]schema.CourseSale{suite.sale(courses[0].ID, 160000)}, nil)
+
+	err := suite.useCase.ApplyPricing(ctx, courses, "usd")
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &schema.DisplayPrice{Currency: "USD", OriginalPrice: 2000, Price: 1000}, courses[0].Pricing.Display)
+}
+
+func (suite *CourseUseCaseTestSuite) TestApplyPricing_UnsupportedDisplayCurrency() {
+	courses := []schema.Course{{ID: uuid.New(), Price: 320000}}
+
+	err := suite.useCase.ApplyPricing(context.Background(), courses, "GBP")
+
+	assert.Equal(suite.T(), http.StatusBadRequest, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "GetActiveSales", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CourseUseCaseTestSuite) TestChargeAmount_ConvertsSalePriceToBase() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Price: 2000, Currency: "USD"}
+
+	suite.repo.On("GetActiveSales", ctx, []uuid.UUID{courseObj.ID}, mock.Anything).
+		Return([]schema.CourseSale{suite.sale(courseObj.ID, 1500)}, nil)
+
+	amount, err := suite.useCase.ChargeAmount(ctx, &courseObj)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(240000), amount)
+	assert.True(suite.T(), courseObj.Pricing.OnSale)
+}
+
+func (suite *CourseUseCaseTestSuite) TestCreateSale_Validation() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Price: 100000}
+	now := time.Now()
+
+	tests := []struct {
+		name string
+		req  CreateSaleRequest
+	}{
+		{name: "ends before it starts", req: CreateSaleRequest{SalePrice: 50000, StartsAt: now.Add(time.Hour), EndsAt: now}},
+		{name: "already ended", req: CreateSaleRequest{SalePrice: 50000, StartsAt: now.Add(-2 * time.Hour), EndsAt: now.Add(-time.Hour)}},
+		{name: "not a discount", req: CreateSaleRequest{SalePrice: 100000, StartsAt: now, EndsAt: now.Add(time.Hour)}},
+	}
+
+	suite.repo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			sale, err := suite.useCase.CreateSale(ctx, courseObj.ID, tt.req)
+
+			assert.Nil(suite.T(), sale)
+			assert.Equal(suite.T(), http.StatusBadRequest, apierror.GetHttpStatus(err))
+		})
+	}
+	suite.repo.AssertNotCalled(suite.T(), "HasOverlappingSale", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+	suite.repo.AssertNotCalled(suite.T(), "CreateSale", mock.Anything, mock.Anything)
+}
+
+func (suite *CourseUseCaseTestSuite) TestCreateSale_Overlap() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Price: 100000}
+	req := CreateSaleRequest{SalePrice: 50000, StartsAt: time.Now().Add(time.Hour), EndsAt: time.Now().Add(2 * time.Hour)}
+
+	suite.repo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.repo.On("HasOverlappingSale", ctx, courseObj.ID, req.StartsAt, req.EndsAt).Return(true, nil)
+
+	sale, err := suite.useCase.CreateSale(ctx, courseObj.ID, req)
+
+	assert.Nil(suite.T(), sale)
+	assert.Equal(suite.T(), ErrSaleOverlap.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "CreateSale", mock.Anything, mock.Anything)
+}
+
+func (suite *CourseUseCaseTestSuite) TestCreateSale_Success() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Price: 100000}
+	req := CreateSaleRequest{SalePrice: 50000, StartsAt: time.Now().Add(time.Hour), EndsAt: time.Now().Add(2 * time.Hour)}
+
+	suite.repo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.repo.On("HasOverlappingSale", ctx, courseObj.ID, req.StartsAt, req.EndsAt).Return(false, nil)
+	suite.repo.On("CreateSale", ctx, mock.AnythingOfType("*schema.CourseSale")).Return(nil)
+
+	sale, err := suite.useCase.CreateSale(ctx, courseObj.ID, req)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), courseObj.ID, sale.CourseID)
+	assert.Equal(suite.T(), int64(50000), sale.SalePrice)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *CourseUseCaseTestSuite) TestExportRoster_CSV() {
+	ctx := context.Background()
+	courseID := uuid.New()
+	enrolledAt := time.Date(2030, 1, 6, 9, 0, 0, 0, time.UTC)
+	expiresAt := enrolledAt.AddDate(0, 3, 0)
+	entries := []courseenroll.RosterEntry{
+		{UserID: uuid.New(), Name: "Ann, the first", Email: "ann@example.com", EnrolledAt: enrolledAt,
+			ExpiresAt: &expiresAt, Progress: 62.5, AverageGrade: testutil.Ptr(88.25), LastActivityAt: enrolledAt.AddDate(0, 0, 3)},
+		{UserID: uuid.New(), Name: "Anne", Email: "anne@example.com", EnrolledAt: enrolledAt, LastActivityAt: enrolledAt},
+		{UserID: uuid.New(), Name: `=HYPERLINK("http://evil.example","Ann")`, Email: "@ann@example.com", EnrolledAt: enrolledAt, LastActivityAt: enrolledAt},
+	}
+
+	suite.enrollRepo.On("GetRoster", ctx, courseID, "ann", 1, 0).Return(entries, int64(3), nil)
+
+	data, err := suite.useCase.ExportRoster(ctx, courseID, "ann")
+
+	assert.NoError(suite.T(), err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), [][]string{
+		rosterCSVHeader,
+		{entries[0].UserID.String(), "Ann, the first", "ann@example.com", "2030-01-06T09:00:00Z", "2030-04-06T09:00:00Z",
+			"62.5", "88.2", "2030-01-09T09:00:00Z"},
+		{entries[1].UserID.String(), "Anne", "anne@example.com", "2030-01-06T09:00:00Z", "", "0.0", "", "2030-01-06T09:00:00Z"},
+		{entries[2].UserID.String(), `'=HYPERLINK("http://evil.example","Ann")`, "'@ann@example.com", "2030-01-06T09:00:00Z", "", "0.0", "",
+			"2030-01-06T09:00:00Z"},
+	}, records)
+}
+
+func (suite *CourseUseCaseTestSuite) TestGetRosterController_OwnerOnly() {
+	controller := &RestController{uc: suite.useCase}
+	ownerID := uuid.New()
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: ownerID}
+
+	suite.repo.On("GetByID", mock.Anything, courseObj.ID).Return(courseObj, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/courses/"+courseObj.ID.String()+"/roster?page=1&limit=10", nil)
+	rec := testutil.Serve("/v1/courses/:id/roster", controller.GetRoster(), uuid.New(), schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusUnauthorized, rec.Code, rec.Body.String())
+	assert.Contains(suite.T(), rec.Body.String(), ErrNotOwnerAccess.Build().Error())
+	suite.enrollRepo.AssertNotCalled(suite.T(), "GetRoster", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+
+	suite.enrollRepo.On("GetRoster", mock.Anything, courseObj.ID, "", 1, 10).Return([]courseenroll.RosterEntry{}, int64(0), nil)
+
+	req = httptest.NewRequest(http.MethodGet, "/v1/courses/"+courseObj.ID.String()+"/roster?page=1&limit=10", nil)
+	rec = testutil.Serve("/v1/courses/:id/roster", controller.GetRoster(), ownerID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+}
+
+func TestCourseUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CourseUseCaseTestSuite))
+}
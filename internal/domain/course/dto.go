@@ -2,29 +2,41 @@ package course
 
 import (
 	"mime/multipart"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
 )
 
 type CreateCourseRequest struct {
-	Title       string                  `form:"title" binding:"required"`
-	Description string                  `form:"description"`
-	Price       int64                   `form:"price" binding:"required,gte=0"`
-	Image       *multipart.FileHeader   `form:"image"`
-	Syllabus    *multipart.FileHeader   `form:"syllabus"`
-	Difficulty  schema.CourseDifficulty `form:"difficulty" binding:"required,oneof=beginner intermediate advanced expert"`
-	Category    schema.CourseCategory   `form:"category" binding:"required,oneof='Web Development' 'Game Development' 'Cloud Computing' 'Data Science & Analytics' 'Programming Languages' 'Cybersecurity' 'Mobile App Development' 'Database Management' 'Software Development' 'DevOps & Automation' 'Networking' 'AI & Machine Learning' 'Internet of Things (IoT)' 'Blockchain & Cryptocurrency' 'Augmented Reality (AR) & Virtual Reality (VR)'"`
+	Title               string                    `form:"title" binding:"required"`
+	Description         string                    `form:"description"`
+	Price               int64                     `form:"price" binding:"gte=0"`
+	Image               *multipart.FileHeader     `form:"image"`
+	Syllabus            *multipart.FileHeader     `form:"syllabus"`
+	Difficulty          schema.CourseDifficulty   `form:"difficulty" binding:"required,oneof=beginner intermediate advanced expert"`
+	CertificateMinGrade *float64                  `form:"certificate_min_grade" binding:"omitempty,min=0,max=100"`
+	PrerequisitePolicy  schema.PrerequisitePolicy `form:"prerequisite_policy" binding:"omitempty,oneof=none warn block"`
+	CategoryID          string                    `form:"category_id" binding:"omitempty,uuid"`
+	Tags                []string                  `form:"tags" binding:"omitempty,max=10,dive,min=1,max=50"`
+	Currency            string                    `form:"currency" binding:"omitempty,len=3"`
+	AccessDays          *int                      `form:"access_days" binding:"omitempty,min=1,max=3650"`
 }
 
 type UpdateCourseRequest struct {
-	Title       *string                  `form:"title,omitempty"`
-	Description *string                  `form:"description,omitempty"`
-	Price       *int64                   `form:"price,omitempty" binding:"omitempty,gte=0"`
-	Image       *multipart.FileHeader    `form:"image,omitempty"`    // Handled separately, not through direct JSON binding
-	Syllabus    *multipart.FileHeader    `form:"syllabus,omitempty"` // Handled separately
-	Difficulty  *schema.CourseDifficulty `form:"difficulty,omitempty" binding:"omitempty,oneof=beginner intermediate advanced expert"`
-	Category    *schema.CourseCategory   `form:"category" binding:"required,oneof='Web Development' 'Game Development' 'Cloud Computing' 'Data Science & Analytics' 'Programming Languages' 'Cybersecurity' 'Mobile App Development' 'Database Management' 'Software Development' 'DevOps & Automation' 'Networking' 'AI & Machine Learning' 'Internet of Things (IoT)' 'Blockchain & Cryptocurrency' 'Augmented Reality (AR) & Virtual Reality (VR)'"`
+	Title               *string                    `form:"title,omitempty"`
+	Description         *string                    `form:"description,omitempty"`
+	Price               *int64                     `form:"price,omitempty" binding:"omitempty,gte=0"`
+	Image               *multipart.FileHeader      `form:"image,omitempty"`    // Handled separately, not through direct JSON binding
+	Syllabus            *multipart.FileHeader      `form:"syllabus,omitempty"` // Handled separately
+	Difficulty          *schema.CourseDifficulty   `form:"difficulty,omitempty" binding:"omitempty,oneof=beginner intermediate advanced expert"`
+	CertificateMinGrade *float64                   `form:"certificate_min_grade,omitempty" binding:"omitempty,min=0,max=100"`
+	PrerequisitePolicy  *schema.PrerequisitePolicy `form:"prerequisite_policy,omitempty" binding:"omitempty,oneof=none warn block"`
+	CategoryID          *string                    `form:"category_id,omitempty" binding:"omitempty,uuid"`
+	Tags                []string                   `form:"tags,omitempty" binding:"omitempty,max=10,dive,min=1,max=50"`
+	Currency            *string                    `form:"currency,omitempty" binding:"omitempty,len=3"`
+	AccessDays          *int                       `form:"access_days,omitempty" binding:"omitempty,min=0,max=3650"` // 0 switches back to lifetime access
 }
 
 type CoursesPaginatedResponse struct {
@@ -33,8 +45,9 @@ type CoursesPaginatedResponse struct {
 }
 
 type PaginationRequest struct {
-	Page  int `form:"page" binding:"required,min=1"`
-	Limit int `form:"limit" binding:"required,min=1,max=30"`
+	Page     int    `form:"page" binding:"required,min=1"`
+	Limit    int    `form:"limit" binding:"required,min=1,max=30"`
+	Currency string `form:"currency" binding:"omitempty,len=3"`
 }
 
 type CourseProgress struct {
@@ -47,16 +60,78 @@ type CourseProgressResponse struct {
 }
 
 type SearchPaginationRequest struct {
-	Title string `form:"title" binding:"required"`
-	Page  int    `form:"page" binding:"required,min=1"`
-	Limit int    `form:"limit" binding:"required,min=1,max=30"`
+	Title    string `form:"title" binding:"required"`
+	Page     int    `form:"page" binding:"required,min=1"`
+	Limit    int    `form:"limit" binding:"required,min=1,max=30"`
+	Currency string `form:"currency" binding:"omitempty,len=3"`
 }
 
 type FilterCoursesRequest struct {
 	Rating     *float32 `form:"rating" binding:"omitempty,min=0,max=5"`
-	Category   *string  `form:"category" binding:"omitempty,oneof='Web Development' 'Game Development' 'Cloud Computing' 'Data Science & Analytics' 'Programming Languages' 'Cybersecurity' 'Mobile App Development' 'Database Management' 'Software Development' 'DevOps & Automation' 'Networking' 'AI & Machine Learning' 'Internet of Things (IoT)' 'Blockchain & Cryptocurrency' 'Augmented Reality (AR) & Virtual Reality (VR)'"`
+	Category   *string  `form:"category" binding:"omitempty,max=120"`
+	Tags       []string `form:"tags" binding:"omitempty,max=10"`
 	Difficulty *string  `form:"difficulty" binding:"omitempty,oneof=beginner intermediate advanced expert"`
 	Sort       *string  `form:"sort" binding:"omitempty,oneof=highest lowest"`
 	Page       int      `form:"page" binding:"required,min=1"`
 	Limit      int      `form:"limit" binding:"required,min=1,max=50"`
+	Currency   string   `form:"currency" binding:"omitempty,len=3"`
+}
+
+type SetPrerequisitesRequest struct {
+	CourseIDs []uuid.UUID `json:"course_ids" binding:"max=10"`
+}
+
+type PrerequisiteStatus struct {
+	CourseID  uuid.UUID `json:"course_id"`
+	Title     string    `json:"title"`
+	Enrolled  bool      `json:"enrolled"`
+	Progress  float64   `json:"progress"`
+	Completed bool      `json:"completed"`
+}
+
+type BuyCourseResponse struct {
+	MissingPrerequisites []PrerequisiteStatus `json:"missing_prerequisites"`
+	PricePaid            int64                `json:"price_paid"`
+	Currency             string               `json:"currency"`
+	ExpiresAt            *time.Time           `json:"expires_at"`
+}
+
+type CreateSaleRequest struct {
+	SalePrice int64     `json:"sale_price" binding:"gte=0"`
+	StartsAt  time.Time `json:"starts_at" binding:"required"`
+	EndsAt    time.Time `json:"ends_at" binding:"required"`
+}
+
+type CurrenciesResponse struct {
+	Base      string   `json:"base"`
+	Supported []string `json:"supported"`
+}
+
+type GetRosterRequest struct {
+	Search string `form:"search" binding:"max=100"`
+	Page   int    `form:"page" binding:"required,min=1"`
+	Limit  int    `form:"limit" binding:"required,min=1,max=100"`
+}
+
+type ExportRosterRequest struct {
+	Search string `form:"search" binding:"max=100"`
+}
+
+type ManualEnrollRequest struct {
+	Emails []string `json:"emails" binding:"required,min=1,max=100,dive,email"`
+}
+
+type ManualEnrollStatus string
+
+const (
+	ManualEnrollEnrolled        ManualEnrollStatus = "enrolled"
+	ManualEnrollAlreadyEnrolled ManualEnrollStatus = "already_enrolled"
+	ManualEnrollUserNotFound    ManualEnrollStatus = "user_not_found"
+	ManualEnrollNotStudent      ManualEnrollStatus = "not_a_student"
+)
+
+type ManualEnrollResult struct {
+	Email     string             `json:"email"`
+	Status    ManualEnrollStatus `json:"status"`
+	ExpiresAt *time.Time         `json:"expires_at,omitempty"`
 }
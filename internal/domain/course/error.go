@@ -46,4 +46,40 @@ var (
 	ErrAlreadyEnrolled = apierror.NewApiErrorBuilder().
 				WithHttpStatus(http.StatusBadRequest).
 				WithMessage("COURSE_ALREADY_ENROLLED")
+
+	ErrPrerequisitesNotMet = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("PREREQUISITES_NOT_MET")
+
+	ErrInvalidPrerequisite = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_PREREQUISITE")
+
+	ErrCategoryNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("CATEGORY_NOT_FOUND")
+
+	ErrUnsupportedCurrency = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("UNSUPPORTED_CURRENCY")
+
+	ErrInvalidSale = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("INVALID_SALE")
+
+	ErrSaleOverlap = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusConflict).
+			WithMessage("SALE_OVERLAPS_EXISTING_SALE")
+
+	ErrSaleNotFound = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusNotFound).
+			WithMessage("SALE_NOT_FOUND")
+
+	ErrCourseNotFree = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COURSE_IS_NOT_FREE")
+
+	ErrPackageCourseUnavailable = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusConflict).
+					WithMessage("PACKAGE_COURSE_UNAVAILABLE")
 )
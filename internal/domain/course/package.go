@@ -0,0 +1,175 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// A package is a set of courses sold together for a single price in the base currency, course bundles and
+// priced learning paths are packages
+
+// PackageCourseError tells why a course cannot be part of a package
+type PackageCourseError struct {
+	CourseID uuid.UUID
+	Reason   string
+	NotOwned bool
+}
+
+func (e *PackageCourseError) Error() string {
+	return e.Reason
+}
+
+// PackageQuote is what a student pays for a package, courses they already own are left out of the enrollment
+// and the price shrinks by their share
+type PackageQuote struct {
+	Price           int64
+	OwnedCourseIDs  []uuid.UUID
+	EnrollCourseIDs []uuid.UUID
+	userID          uuid.UUID
+	courses         []schema.Course
+}
+
+// PackageCourses looks up the courses of a package in the order given. When sellerID is set every course has to be
+// taught by the seller so the whole payment goes to a single instructor wallet. Courses that cannot be part of
+// the package are reported with a *PackageCourseError
+func (uc *UseCase) PackageCourses(ctx context.Context, courseIDs []uuid.UUID, sellerID *uuid.UUID) ([]schema.Course, error) {
+	seen := make(map[uuid.UUID]bool)
+	courses := make([]schema.Course, 0, len(courseIDs))
+
+	for _, courseID := range courseIDs {
+		if seen[courseID] {
+			return nil, &PackageCourseError{CourseID: courseID, Reason: "course is listed more than once"}
+		}
+		seen[courseID] = true
+
+		courseObj, err := uc.courseRepo.GetByID(ctx, courseID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, &PackageCourseError{CourseID: courseID, Reason: "course not found"}
+			}
+			log.Println("Error getting course: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		if sellerID != nil && courseObj.InstructorID != *sellerID {
+			return nil, &PackageCourseError{CourseID: courseID, Reason: "course is not yours", NotOwned: true}
+		}
+
+		courses = append(courses, courseObj)
+	}
+
+	return courses, nil
+}
+
+// QuotePackage prices the package for the student, the courses they already bought are skipped. Courses are
+// weighed by what they sell for on their own right now, so courses priced in other currencies or on sale count
+// for what they are worth today. A course deleted after it was packaged comes back empty, such a package cannot be
+// sold until the course is taken out of it
+func (uc *UseCase) QuotePackage(ctx context.Context, userID uuid.UUID, price int64, courses []schema.Course) (*PackageQuote, error) {
+	for _, c := range courses {
+		if c.ID == uuid.Nil {
+			return nil, ErrPackageCourseUnavailable.Build()
+		}
+	}
+
+	quote := &PackageQuote{
+		OwnedCourseIDs:  []uuid.UUID{},
+		EnrollCourseIDs: []uuid.UUID{},
+		userID:          userID,
+		courses:         courses,
+	}
+
+	prices, err := uc.BasePrices(ctx, courses)
+	if err != nil {
+		return nil, err
+	}
+
+	owned := make([]bool, len(courses))
+	for i, c := range courses {
+		purchased, err := uc.courseEnrollUseCase.HasPurchased(ctx, userID, c.ID)
+		if err != nil {
+			log.Println("Error checking enrollment: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		if purchased {
+			owned[i] = true
+			quote.OwnedCourseIDs = append(quote.OwnedCourseIDs, c.ID)
+		} else {
+			quote.EnrollCourseIDs = append(quote.EnrollCourseIDs, c.ID)
+		}
+	}
+
+	quote.Price = proratePackage(price, prices, owned)
+	return quote, nil
+}
+
+// CheckPackagePrerequisites runs the prerequisite check of a direct purchase on every course the quoted package
+// enrolls the student in, rejecting the package when one of them blocks on a prerequisite they have not completed
+func (uc *UseCase) CheckPackagePrerequisites(ctx context.Context, quote *PackageQuote) error {
+	for _, c := range quote.courses {
+		if c.PrerequisitePolicy != schema.PrerequisiteBlock || !slices.Contains(quote.EnrollCourseIDs, c.ID) {
+			continue
+		}
+
+		if _, err := uc.checkPrerequisites(ctx, &c, quote.userID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Enrollments are the enrollments buying the quoted package creates, one for every course the student does not own
+func (q *PackageQuote) Enrollments() ([]schema.CourseEnroll, error) {
+	now := time.Now()
+	enrolls := make([]schema.CourseEnroll, 0, len(q.EnrollCourseIDs))
+	for _, c := range q.courses {
+		if !slices.Contains(q.EnrollCourseIDs, c.ID) {
+			continue
+		}
+
+		enrollID, err := uuid.NewV7()
+		if err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		enrolls = append(enrolls, schema.CourseEnroll{
+			ID:        enrollID,
+			UserID:    q.userID,
+			CourseID:  c.ID,
+			ExpiresAt: courseenroll.AccessExpiry(c.AccessDays, now),
+			CreatedAt: now,
+		})
+	}
+	return enrolls, nil
+}
+
+// proratePackage scales the package price by the share of the course prices the buyer does not own yet,
+// courses are weighted equally when they are all free
+func proratePackage(price int64, prices []int64, owned []bool) int64 {
+	var total, remaining, remainingCount int64
+	for i, p := range prices {
+		total += p
+		if !owned[i] {
+			remaining += p
+			remainingCount++
+		}
+	}
+
+	if remainingCount == int64(len(prices)) {
+		return price
+	}
+	if total == 0 {
+		return price * remainingCount / int64(len(prices))
+	}
+
+	return (price*remaining + total/2) / total
+}
@@ -0,0 +1,245 @@
+package course
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/currency"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// PriceWatcher is told about courses whose price may have just dropped
+type PriceWatcher interface {
+	CheckPriceDrops(ctx context.Context, courseIDs []uuid.UUID) (int, error)
+}
+
+// watchPrice hands the course to the PriceWatcher in the background, sales starting later are picked up
+// by the watcher on its own schedule
+func (uc *UseCase) watchPrice(courseID uuid.UUID) {
+	if uc.PriceWatcher == nil {
+		return
+	}
+
+	go func() {
+		if _, err := uc.PriceWatcher.CheckPriceDrops(context.Background(), []uuid.UUID{courseID}); err != nil {
+			log.Println("Error checking price drops: ", err)
+		}
+	}()
+}
+
+// resolveCurrency normalizes a course currency, an empty code means the base currency
+func resolveCurrency(code string) (string, error) {
+	if code == "" {
+		return currency.Base(), nil
+	}
+
+	code = currency.Normalize(code)
+	if !currency.IsSupported(code) {
+		return "", ErrUnsupportedCurrency.WithPayload(map[string]any{
+			"currency":  code,
+			"supported": currency.Supported(),
+		}).Build()
+	}
+	return code, nil
+}
+
+// ApplyPricing fills the computed pricing of every course with the sale running right now,
+// and converts the prices to displayCurrency when one is given
+func (uc *UseCase) ApplyPricing(ctx context.Context, courses []schema.Course, displayCurrency string) error {
+	if displayCurrency != "" {
+		var err error
+		if displayCurrency, err = resolveCurrency(displayCurrency); err != nil {
+			return err
+		}
+	}
+
+	ids := make([]uuid.UUID, len(courses))
+	for i, course := range courses {
+		ids[i] = course.ID
+	}
+
+	sales, err := uc.courseRepo.GetActiveSales(ctx, ids, time.Now())
+	if err != nil {
+		log.Println("Error getting active sales: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	// When sales overlap despite the validation, the cheapest one wins
+	activeSales := make(map[uuid.UUID]schema.CourseSale)
+	for _, sale := range sales {
+		if current, ok := activeSales[sale.CourseID]; !ok || sale.SalePrice < current.SalePrice {
+			activeSales[sale.CourseID] = sale
+		}
+	}
+
+	for i := range courses {
+		course := &courses[i]
+		if course.Currency == "" {
+			course.Currency = currency.Base()
+		}
+
+		pricing := &schema.CoursePricing{
+			Currency:      course.Currency,
+			OriginalPrice: course.Price,
+			Price:         course.Price,
+		}
+		if sale, ok := activeSales[course.ID]; ok && sale.SalePrice < course.Price {
+			pricing.Price = sale.SalePrice
+			pricing.OnSale = true
+			pricing.SaleEndsAt = &sale.EndsAt
+		}
+
+		if displayCurrency != "" && displayCurrency != course.Currency {
+			originalPrice, err := currency.Convert(pricing.OriginalPrice, course.Currency, displayCurrency)
+			if err != nil {
+				log.Println("Error converting course price: ", err)
+				return apierror.ErrInternalServer.Build()
+			}
+			price, err := currency.Convert(pricing.Price, course.Currency, displayCurrency)
+			if err != nil {
+				log.Println("Error converting course price: ", err)
+				return apierror.ErrInternalServer.Build()
+			}
+			pricing.Display = &schema.DisplayPrice{
+				Currency:      displayCurrency,
+				OriginalPrice: originalPrice,
+				Price:         price,
+			}
+		}
+
+		course.Pricing = pricing
+	}
+
+	return nil
+}
+
+// ChargeAmount returns the current price of a course converted to the base currency the wallets are kept in
+func (uc *UseCase) ChargeAmount(ctx context.Context, course *schema.Course) (int64, error) {
+	courses := []schema.Course{*course}
+	if err := uc.ApplyPricing(ctx, courses, ""); err != nil {
+		return 0, err
+	}
+	*course = courses[0]
+
+	return basePrice(course.Pricing)
+}
+
+// BasePrices returns what each course sells for on its own right now, sales included, converted to the base
+// currency the wallets are kept in
+func (uc *UseCase) BasePrices(ctx context.Context, courses []schema.Course) ([]int64, error) {
+	priced := slices.Clone(courses)
+	if err := uc.ApplyPricing(ctx, priced, ""); err != nil {
+		return nil, err
+	}
+
+	prices := make([]int64, len(priced))
+	for i := range priced {
+		amount, err := basePrice(priced[i].Pricing)
+		if err != nil {
+			return nil, err
+		}
+		prices[i] = amount
+	}
+	return prices, nil
+}
+
+func basePrice(pricing *schema.CoursePricing) (int64, error) {
+	amount, err := currency.Convert(pricing.Price, pricing.Currency, currency.Base())
+	if err != nil {
+		log.Println("Error converting course price: ", err)
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	return amount, nil
+}
+
+func (uc *UseCase) GetCurrencies() CurrenciesResponse {
+	return CurrenciesResponse{Base: currency.Base(), Supported: currency.Supported()}
+}
+
+func (uc *UseCase) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	if _, err := uc.GetByID(ctx, courseID); err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	sales, err := uc.courseRepo.GetSales(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting sales: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return sales, nil
+}
+
+func (uc *UseCase) CreateSale(ctx context.Context, courseID uuid.UUID, req CreateSaleRequest) (*schema.CourseSale, error) {
+	course, err := uc.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	if !req.EndsAt.After(req.StartsAt) {
+		return nil, ErrInvalidSale.WithPayload(map[string]any{
+			"reason": "ends_at must be after starts_at",
+		}).Build()
+	}
+	if !req.EndsAt.After(time.Now()) {
+		return nil, ErrInvalidSale.WithPayload(map[string]any{
+			"reason": "sale has already ended",
+		}).Build()
+	}
+	if req.SalePrice >= course.Price {
+		return nil, ErrInvalidSale.WithPayload(map[string]any{
+			"reason": "sale_price must be lower than the course price",
+			"price":  course.Price,
+		}).Build()
+	}
+
+	overlap, err := uc.courseRepo.HasOverlappingSale(ctx, courseID, req.StartsAt, req.EndsAt)
+	if err != nil {
+		log.Println("Error checking overlapping sales: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if overlap {
+		return nil, ErrSaleOverlap.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		log.Println("Error generating UUID: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	sale := &schema.CourseSale{
+		ID:        id,
+		CourseID:  courseID,
+		SalePrice: req.SalePrice,
+		StartsAt:  req.StartsAt,
+		EndsAt:    req.EndsAt,
+	}
+	if err := uc.courseRepo.CreateSale(ctx, sale); err != nil {
+		log.Println("Error creating sale: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	if !sale.StartsAt.After(time.Now()) {
+		uc.watchPrice(courseID)
+	}
+
+	return sale, nil
+}
+
+func (uc *UseCase) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	if err := uc.courseRepo.DeleteSale(ctx, courseID, saleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSaleNotFound.Build()
+		}
+		log.Println("Error deleting sale: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
@@ -2,12 +2,12 @@ package course
 
 import (
 	"context"
-
-	"strconv"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
@@ -21,7 +21,26 @@ type Repository interface {
 	FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error)
 	GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error)
 	SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error)
-	DynamicFilterCourses(ctx context.Context, filterType, filterValue, sort string, page, limit int) ([]schema.Course, int, error)
+	DynamicFilterCourses(ctx context.Context, filter CourseFilter, page, limit int) ([]schema.Course, int, error)
+	GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error)
+	SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error
+	CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error)
+	SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error)
+	ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error
+	CreateSale(ctx context.Context, sale *schema.CourseSale) error
+	GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error)
+	DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error
+	HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error)
+	GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error)
+}
+
+// CourseFilter holds the optional criteria for DynamicFilterCourses, zero values are ignored
+type CourseFilter struct {
+	CategorySlug string   // matches the category and all of its subcategories
+	Tags         []string // tag slugs, a course matches when it has any of them
+	Difficulty   string
+	Rating       *float32
+	Sort         string
 }
 
 type repository struct {
@@ -34,7 +53,7 @@ func NewRepository(db *gorm.DB) Repository {
 
 func (r *repository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
 	var courses []schema.Course
-	result := r.db.Preload("Materials.Attachments").Preload("Assignments.Attachments").Offset((page - 1) * pageSize).Limit(pageSize).Find(&courses)
+	result := r.db.Preload("Materials.Attachments").Preload("Assignments.Attachments").Preload("Category").Preload("Tags").Offset((page - 1) * pageSize).Limit(pageSize).Find(&courses)
 	if result.Error != nil {
 		return nil, 0, result.Error
 	}
@@ -66,23 +85,36 @@ func (r *repository) FindByPopularity(ctx context.Context, page, pageSize int) (
 	return courses, int(totalRecords), nil
 }
 
+// GetUserCourseProgress is the share of assignments and quizzes of the course the user completed,
+// a quiz counts once the user submitted an attempt
 func (r *repository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
-	var totalAssignments, completedAssignments int64
+	var totalAssignments, completedAssignments, totalQuizzes, completedQuizzes int64
 
 	if err := r.db.Model(&schema.Assignment{}).Where("course_id = ?", courseID).Count(&totalAssignments).Error; err != nil {
 		return 0, err
 	}
 
-	if err := r.db.Model(&schema.Assignment{}).
-		Joins("inner join submissions on submissions.assignment_id = assignments.id").
-		Where("assignments.course_id = ? AND submissions.user_id = ? AND submissions.deleted_at IS NULL", courseID, userID).
+	if err := r.db.Table("assignment_grades").
+		Where("course_id = ? AND user_id = ?", courseID, userID).
 		Count(&completedAssignments).Error; err != nil {
 		return 0, err
 	}
 
+	if err := r.db.Model(&schema.Quiz{}).Where("course_id = ?", courseID).Count(&totalQuizzes).Error; err != nil {
+		return 0, err
+	}
+
+	if err := r.db.Model(&schema.Quiz{}).
+		Where("quizzes.course_id = ?", courseID).
+		Where("EXISTS (SELECT 1 FROM quiz_attempts WHERE quiz_attempts.quiz_id = quizzes.id "+
+			"AND quiz_attempts.user_id = ? AND quiz_attempts.submitted_at IS NOT NULL)", userID).
+		Count(&completedQuizzes).Error; err != nil {
+		return 0, err
+	}
+
 	var progress float64
-	if totalAssignments > 0 {
-		progress = (float64(completedAssignments) / float64(totalAssignments)) * 100
+	if total := totalAssignments + totalQuizzes; total > 0 {
+		progress = (float64(completedAssignments+completedQuizzes) / float64(total)) * 100
 	}
 
 	return progress, nil
@@ -101,7 +133,7 @@ func (r *repository) FindByInstructorID(ctx context.Context, instructorID uuid.U
 
 func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
 	var course schema.Course
-	if err := r.db.Preload("Materials.Attachments").First(&course, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Materials.Attachments").Preload("Category").Preload("Tags").First(&course, "id = ?", id).Error; err != nil {
 		return schema.Course{}, err
 	}
 	return course, nil
@@ -120,7 +152,8 @@ func (r *repository) Create(ctx context.Context, course *schema.Course) error {
 }
 
 func (r *repository) Update(ctx context.Context, course *schema.Course) error {
-	return r.db.WithContext(ctx).Save(course).Error
+	// Category and tags are changed through their ids and ReplaceTags, not through the loaded associations
+	return r.db.WithContext(ctx).Omit("Category", "Tags").Save(course).Error
 }
 
 func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
@@ -138,26 +171,38 @@ func (r *repository) SearchByTitle(ctx context.Context, title string, page, page
 	return courses, int(totalRecords), nil
 }
 
-func (r *repository) DynamicFilterCourses(ctx context.Context, filterType, filterValue, sort string, page, limit int) ([]schema.Course, int, error) {
+func (r *repository) DynamicFilterCourses(ctx context.Context, filter CourseFilter, page, limit int) ([]schema.Course, int, error) {
 	var courses []schema.Course
 	var total int64
 
-	query := r.db.Model(&schema.Course{})
+	query := r.db.WithContext(ctx).Model(&schema.Course{})
 
-	switch filterType {
-	case "category":
-		query = query.Where("category = ?", filterValue)
-	case "difficulty":
-		query = query.Where("difficulty = ?", filterValue)
-	case "rating":
-		rating, _ := strconv.ParseFloat(filterValue, 32)
-		upperBound := rating + 1.0
-		query = query.Where("rating >= ? AND rating < ?", rating, upperBound)
+	if filter.CategorySlug != "" {
+		query = query.Where("category_id IN (?)", r.db.Raw(`
+			WITH RECURSIVE tree AS (
+				SELECT id FROM categories WHERE slug = ?
+				UNION ALL
+				SELECT categories.id FROM categories JOIN tree ON categories.parent_id = tree.id
+			)
+			SELECT id FROM tree`, filter.CategorySlug))
+	}
+	if len(filter.Tags) > 0 {
+		query = query.Where("id IN (?)", r.db.Table("course_tags").
+			Select("course_tags.course_id").
+			Joins("JOIN tags ON tags.id = course_tags.tag_id").
+			Where("tags.slug IN ?", filter.Tags))
+	}
+	if filter.Difficulty != "" {
+		query = query.Where("difficulty = ?", filter.Difficulty)
+	}
+	if filter.Rating != nil {
+		upperBound := *filter.Rating + 1.0
+		query = query.Where("rating >= ? AND rating < ?", *filter.Rating, upperBound)
 	}
 
-	if sort == "highest" {
+	if filter.Sort == "highest" {
 		query = query.Order("rating DESC")
-	} else if sort == "lowest" {
+	} else if filter.Sort == "lowest" {
 		query = query.Order("rating ASC")
 	}
 
@@ -165,7 +210,7 @@ func (r *repository) DynamicFilterCourses(ctx context.Context, filterType, filte
 		return nil, 0, err
 	}
 
-	query = query.Offset((page - 1) * limit).Limit(limit)
+	query = query.Preload("Category").Preload("Tags").Offset((page - 1) * limit).Limit(limit)
 
 	if err := query.Find(&courses).Error; err != nil {
 		return nil, 0, err
@@ -173,3 +218,103 @@ func (r *repository) DynamicFilterCourses(ctx context.Context, filterType, filte
 
 	return courses, int(total), nil
 }
+
+func (r *repository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	var courses []schema.Course
+	err := r.db.WithContext(ctx).
+		Joins("JOIN course_prerequisites ON course_prerequisites.prerequisite_id = courses.id").
+		Where("course_prerequisites.course_id = ?", courseID).
+		Order("course_prerequisites.created_at").
+		Find(&courses).Error
+	return courses, err
+}
+
+func (r *repository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseID).Delete(&schema.CoursePrerequisite{}).Error; err != nil {
+			return err
+		}
+
+		if len(prerequisiteIDs) == 0 {
+			return nil
+		}
+
+		prerequisites := make([]schema.CoursePrerequisite, len(prerequisiteIDs))
+		for i, id := range prerequisiteIDs {
+			prerequisites[i] = schema.CoursePrerequisite{CourseID: courseID, PrerequisiteID: id}
+		}
+		return tx.Create(&prerequisites).Error
+	})
+}
+
+func (r *repository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Category{}).Where("id = ?", categoryID).Count(&count).Error
+	return count > 0, err
+}
+
+// SaveTags inserts the tags that do not exist yet and returns the stored rows for every given slug
+func (r *repository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	if len(tags) == 0 {
+		return []schema.Tag{}, nil
+	}
+
+	if err := r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+		return nil, err
+	}
+
+	slugs := make([]string, len(tags))
+	for i, tag := range tags {
+		slugs[i] = tag.Slug
+	}
+
+	var stored []schema.Tag
+	err := r.db.WithContext(ctx).Where("slug IN ?", slugs).Find(&stored).Error
+	return stored, err
+}
+
+func (r *repository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	return r.db.WithContext(ctx).Model(&schema.Course{ID: courseID}).Association("Tags").Replace(tags)
+}
+
+func (r *repository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	return r.db.WithContext(ctx).Create(sale).Error
+}
+
+func (r *repository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	var sales []schema.CourseSale
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("starts_at").Find(&sales).Error
+	return sales, err
+}
+
+func (r *repository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Where("course_id = ?", courseID).Delete(&schema.CourseSale{}, "id = ?", saleID)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.CourseSale{}).
+		Where("course_id = ? AND starts_at < ? AND ends_at > ?", courseID, endsAt, startsAt).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetActiveSales returns the sales running at the given time for any of the courses
+func (r *repository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	var sales []schema.CourseSale
+	if len(courseIDs) == 0 {
+		return sales, nil
+	}
+
+	err := r.db.WithContext(ctx).
+		Where("course_id IN ? AND starts_at <= ? AND ends_at > ?", courseIDs, at, at).
+		Find(&sales).Error
+	return sales, err
+}
@@ -7,6 +7,7 @@ import (
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
 	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
 	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -23,7 +24,7 @@ func NewRestController(router *gin.Engine, uc *UseCase, wuc *wallet.UseCase) {
 	courseGroup := router.Group("/v1/courses")
 	{
 		courseGroup.GET("", controller.GetAll())
-		courseGroup.GET("/:id", controller.GetByID())
+		courseGroup.GET("/:id", middleware.OptionalAuthenticate(), controller.GetByID())
 		courseGroup.POST("",
 			middleware.Authenticate(),
 			middleware.RequireEmailVerified(),
@@ -45,6 +46,30 @@ func NewRestController(router *gin.Engine, uc *UseCase, wuc *wallet.UseCase) {
 		courseGroup.GET("/progress/:courseId", middleware.Authenticate(), middleware.RequireEmailVerified(), controller.GetStudentProgress())
 		courseGroup.GET("/search", controller.SearchCourses())
 		courseGroup.GET("/filter", controller.FilterCourse())
+		courseGroup.GET("/:id/prerequisites", controller.GetPrerequisites())
+		courseGroup.GET("/:id/prerequisites/status", middleware.Authenticate(), controller.GetPrerequisiteStatus())
+		courseGroup.PUT("/:id/prerequisites",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.SetPrerequisites(),
+		)
+		courseGroup.GET("/:id/access", middleware.Authenticate(), controller.GetAccess())
+		courseGroup.POST("/:id/enroll", middleware.Authenticate(), middleware.RequireRole("student"), controller.EnrollFree())
+		courseGroup.POST("/:id/enrollments", middleware.Authenticate(), controller.EnrollByEmails())
+		courseGroup.GET("/:id/roster", middleware.Authenticate(), controller.GetRoster())
+		courseGroup.GET("/:id/roster/export", middleware.Authenticate(), controller.ExportRoster())
+		courseGroup.GET("/currencies", controller.GetCurrencies())
+		courseGroup.GET("/:id/sales", controller.GetSales())
+		courseGroup.POST("/:id/sales",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.CreateSale(),
+		)
+		courseGroup.DELETE("/:id/sales/:saleId",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.DeleteSale(),
+		)
 	}
 
 }
@@ -61,6 +86,10 @@ func (c *RestController) GetAll() gin.HandlerFunc {
 			response.NewRestResponse(http.StatusInternalServerError, "Failed to retrieve courses", nil).Send(ctx)
 			return
 		}
+		if err := c.uc.ApplyPricing(ctx, result.Courses, req.Currency); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
 		response.NewRestResponse(http.StatusOK, "Courses retrieved successfully", result).Send(ctx)
 	}
 }
@@ -77,6 +106,10 @@ func (c *RestController) GetByPopularity() gin.HandlerFunc {
 			response.NewRestResponse(http.StatusInternalServerError, "Failed to retrieve courses", nil).Send(ctx)
 			return
 		}
+		if err := c.uc.ApplyPricing(ctx, result.Courses, req.Currency); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
 		response.NewRestResponse(http.StatusOK, "Courses retrieved successfully", result).Send(ctx)
 	}
 }
@@ -94,6 +127,10 @@ func (c *RestController) SearchCourses() gin.HandlerFunc {
 			response.NewRestResponse(http.StatusInternalServerError, "Failed to search courses", err.Error()).Send(ctx)
 			return
 		}
+		if err := c.uc.ApplyPricing(ctx, result.Courses, req.Currency); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
 
 		response.NewRestResponse(http.StatusOK, "Courses found", result).Send(ctx)
 	}
@@ -119,6 +156,10 @@ func (c *RestController) GetInstructorCourse() gin.HandlerFunc {
 			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 			return
 		}
+		if err := c.uc.ApplyPricing(ctx, result.Courses, req.Currency); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
 
 		if len(result.Courses) == 0 {
 			response.NewRestResponse(http.StatusOK, "No courses found for this instructor", nil).Send(ctx)
@@ -137,6 +178,12 @@ func (c *RestController) GetCourseEnrollments() gin.HandlerFunc {
 			return
 		}
 
+		err = c.checkCourseOwnership(ctx, courseID)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
 		users, err := c.uc.GetEnrollmentsByCourse(ctx, courseID)
 		if err != nil {
 			response.NewRestResponse(http.StatusInternalServerError, "Failed to retrieve enrollments", err.Error()).Send(ctx)
@@ -189,13 +236,13 @@ func (c *RestController) BuyCourse() gin.HandlerFunc {
 			return
 		}
 
-		err = c.uc.BuyCourse(ctx, id, studentID.(string))
+		res, err := c.uc.BuyCourse(ctx, id, studentID.(string))
 		if err != nil {
 			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 			return
 		}
 
-		response.NewRestResponse(http.StatusOK, "Buy Course successfully", nil).Send(ctx)
+		response.NewRestResponse(http.StatusOK, "Buy Course successfully", res).Send(ctx)
 	}
 }
 
@@ -212,7 +259,18 @@ func (c *RestController) GetByID() gin.HandlerFunc {
 			response.NewRestResponse(http.StatusInternalServerError, err.Error(), nil).Send(ctx)
 			return
 		}
-		response.NewRestResponse(http.StatusOK, "Course retrieved successfully", course).Send(ctx)
+
+		if err := c.uc.ApplyContentAccess(ctx, &course); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		courses := []schema.Course{course}
+		if err := c.uc.ApplyPricing(ctx, courses, ctx.Query("currency")); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+		response.NewRestResponse(http.StatusOK, "Course retrieved successfully", courses[0]).Send(ctx)
 	}
 }
 
@@ -326,6 +384,18 @@ func (c *RestController) checkCourseOwnership(ctx *gin.Context, courseID uuid.UU
 	return nil
 }
 
+// checkCourseManager allows admins and the course owner
+func (c *RestController) checkCourseManager(ctx *gin.Context, courseID uuid.UUID) error {
+	if ctx.GetString("user.role") == string(schema.RoleAdmin) {
+		_, err := c.uc.GetByID(ctx, courseID)
+		if err != nil {
+			return ErrCourseNotFound.Build()
+		}
+		return nil
+	}
+	return c.checkCourseOwnership(ctx, courseID)
+}
+
 func (c *RestController) GetStudentProgress() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
 
@@ -364,7 +434,301 @@ func (c *RestController) FilterCourse() gin.HandlerFunc {
 			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 			return
 		}
+		if err := c.uc.ApplyPricing(ctx, result.Courses, req.Currency); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
 
 		response.NewRestResponse(http.StatusOK, "Course retrieve successfully", result).Send(ctx)
 	}
 }
+
+func (c *RestController) GetPrerequisites() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		prerequisites, err := c.uc.GetPrerequisites(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Prerequisites retrieved successfully", prerequisites).Send(ctx)
+	}
+}
+
+func (c *RestController) GetPrerequisiteStatus() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		userID, err := uuid.Parse(ctx.GetString("user.id"))
+		if err != nil {
+			err2 := apierror.ErrTokenInvalid.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), nil).Send(ctx)
+			return
+		}
+
+		statuses, err := c.uc.GetPrerequisiteStatus(ctx, id, userID)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Prerequisite status retrieved successfully", statuses).Send(ctx)
+	}
+}
+
+func (c *RestController) SetPrerequisites() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		var req SetPrerequisitesRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid prerequisite data: "+err.Error(), nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseOwnership(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		prerequisites, err := c.uc.SetPrerequisites(ctx, id, req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Prerequisites updated successfully", prerequisites).Send(ctx)
+	}
+}
+
+func (c *RestController) GetCurrencies() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		response.NewRestResponse(http.StatusOK, "Currencies retrieved successfully", c.uc.GetCurrencies()).Send(ctx)
+	}
+}
+
+func (c *RestController) GetSales() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		sales, err := c.uc.GetSales(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Sales retrieved successfully", sales).Send(ctx)
+	}
+}
+
+func (c *RestController) CreateSale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		var req CreateSaleRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid sale data: "+err.Error(), nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseOwnership(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		sale, err := c.uc.CreateSale(ctx, id, req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "Sale created successfully", sale).Send(ctx)
+	}
+}
+
+func (c *RestController) DeleteSale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		saleID, err := uuid.Parse(ctx.Param("saleId"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid sale ID", nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseOwnership(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		if err := c.uc.DeleteSale(ctx, id, saleID); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Sale deleted successfully", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) EnrollFree() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.EnrollFree(ctx, id, ctx.GetString("user.id"))
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Enrolled successfully", res).Send(ctx)
+	}
+}
+
+func (c *RestController) EnrollByEmails() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		var req ManualEnrollRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid enrollment data: "+err.Error(), nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseManager(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		results, err := c.uc.EnrollByEmails(ctx, id, req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Students enrolled successfully", results).Send(ctx)
+	}
+}
+
+func (c *RestController) GetAccess() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		userID, err := uuid.Parse(ctx.GetString("user.id"))
+		if err != nil {
+			err2 := apierror.ErrTokenInvalid.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), nil).Send(ctx)
+			return
+		}
+
+		access, err := c.uc.GetAccess(ctx, id, userID)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Course access retrieved successfully", access).Send(ctx)
+	}
+}
+
+func (c *RestController) GetRoster() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		var req GetRosterRequest
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid roster query: "+err.Error(), nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseOwnership(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		roster, err := c.uc.GetRoster(ctx, id, req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "Roster retrieved successfully", roster).Send(ctx)
+	}
+}
+
+func (c *RestController) ExportRoster() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		id, err := uuid.Parse(ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid ID", nil).Send(ctx)
+			return
+		}
+
+		var req ExportRosterRequest
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			response.NewRestResponse(http.StatusBadRequest, "Invalid roster query: "+err.Error(), nil).Send(ctx)
+			return
+		}
+
+		err = c.checkCourseOwnership(ctx, id)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		data, err := c.uc.ExportRoster(ctx, id, req.Search)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		ctx.Header("Content-Disposition", `attachment; filename="roster-`+id.String()+`.csv"`)
+		ctx.Data(http.StatusOK, "text/csv", data)
+	}
+}
@@ -0,0 +1,80 @@
+package course
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
+	"github.com/Stefanuswilfrid/course-backend/internal/sanitize"
+	"github.com/google/uuid"
+)
+
+var rosterCSVHeader = []string{
+	"user_id", "name", "email", "enrolled_at", "expires_at", "progress", "average_grade", "last_activity_at",
+}
+
+func (uc *UseCase) GetRoster(ctx context.Context, courseID uuid.UUID, req GetRosterRequest) (*pagination.GetResourcePaginatedResponse, error) {
+	entries, total, err := uc.courseEnrollUseCase.GetRoster(ctx, courseID, req.Search, req.Page, req.Limit)
+	if err != nil {
+		log.Println("Error getting roster: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return &pagination.GetResourcePaginatedResponse{
+		Data:       entries,
+		Pagination: pagination.NewPagination(int(total), req.Page, req.Limit),
+	}, nil
+}
+
+// ExportRoster renders every enrolled student matching the search as CSV
+func (uc *UseCase) ExportRoster(ctx context.Context, courseID uuid.UUID, search string) ([]byte, error) {
+	entries, _, err := uc.courseEnrollUseCase.GetRoster(ctx, courseID, search, 1, 0)
+	if err != nil {
+		log.Println("Error getting roster: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(rosterCSVHeader); err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	for _, e := range entries {
+		expiresAt := ""
+		if e.ExpiresAt != nil {
+			expiresAt = e.ExpiresAt.Format(time.RFC3339)
+		}
+		averageGrade := ""
+		if e.AverageGrade != nil {
+			averageGrade = strconv.FormatFloat(*e.AverageGrade, 'f', 1, 64)
+		}
+
+		record := []string{
+			e.UserID.String(),
+			sanitize.CSVCell(e.Name),
+			sanitize.CSVCell(e.Email),
+			e.EnrolledAt.Format(time.RFC3339),
+			expiresAt,
+			strconv.FormatFloat(e.Progress, 'f', 1, 64),
+			averageGrade,
+			e.LastActivityAt.Format(time.RFC3339),
+		}
+		if err := w.Write(record); err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Println("Error writing roster csv: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return buf.Bytes(), nil
+}
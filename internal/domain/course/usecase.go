@@ -3,13 +3,16 @@ package course
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log"
 	"mime/multipart"
 	"slices"
+	"strings"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/currency"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
@@ -18,7 +21,9 @@ import (
 	"github.com/Stefanuswilfrid/course-backend/internal/mailer"
 	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/slug"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type UseCase struct {
@@ -29,6 +34,7 @@ type UseCase struct {
 	notificationRepo    notification.IRepository
 	mailDialer          config.IMailer
 	uploader            config.FileUploader
+	PriceWatcher        PriceWatcher
 }
 
 func NewUseCase(courseRepo Repository, walletRepo wallet.IRepository, ceUseCase courseenroll.UseCase,
@@ -42,6 +48,10 @@ func (uc *UseCase) GetAll(ctx context.Context, page, pageSize int) (CoursesPagin
 	if err != nil {
 		return CoursesPaginatedResponse{}, err
 	}
+	// Listings are public, they only carry the course outline
+	for i := range courses {
+		uc.applyContentAccess(&courses[i], visitor())
+	}
 	pag := pagination.NewPagination(total, page, pageSize)
 	return CoursesPaginatedResponse{
 		Courses:    courses,
@@ -54,6 +64,10 @@ func (uc *UseCase) GetCourseByPopularity(ctx context.Context, page, pageSize int
 	if err != nil {
 		return CoursesPaginatedResponse{}, err
 	}
+	// Listings are public, they only carry the course outline
+	for i := range courses {
+		uc.applyContentAccess(&courses[i], visitor())
+	}
 	pag := pagination.NewPagination(total, page, pageSize)
 	return CoursesPaginatedResponse{
 		Courses:    courses,
@@ -148,19 +162,96 @@ func (uc *UseCase) Create(ctx context.Context, req CreateCourseRequest, imageFil
 		}
 	}
 
+	categoryID, err := uc.resolveCategory(ctx, req.CategoryID)
+	if err != nil {
+		return err
+	}
+
+	courseCurrency, err := resolveCurrency(req.Currency)
+	if err != nil {
+		return err
+	}
+
 	course := schema.Course{
-		Title:        req.Title,
-		Description:  req.Description,
-		Price:        req.Price,
-		ImageURL:     imageUrl,
-		SyllabusURL:  syllabusUrl,
-		InstructorID: uuidInstructorID,
-		Difficulty:   req.Difficulty,
-		ID:           id,
-		Category:     req.Category,
-	}
-
-	return uc.courseRepo.Create(ctx, &course)
+		Title:               req.Title,
+		Description:         req.Description,
+		Price:               req.Price,
+		Currency:            courseCurrency,
+		ImageURL:            imageUrl,
+		SyllabusURL:         syllabusUrl,
+		InstructorID:        uuidInstructorID,
+		Difficulty:          req.Difficulty,
+		ID:                  id,
+		CategoryID:          categoryID,
+		CertificateMinGrade: req.CertificateMinGrade,
+		PrerequisitePolicy:  req.PrerequisitePolicy,
+		AccessDays:          req.AccessDays,
+	}
+
+	if err := uc.courseRepo.Create(ctx, &course); err != nil {
+		return err
+	}
+
+	return uc.applyTags(ctx, course.ID, req.Tags)
+}
+
+// resolveCategory parses an optional category id and makes sure the category exists, an empty id means no category
+func (uc *UseCase) resolveCategory(ctx context.Context, categoryIDStr string) (*uuid.UUID, error) {
+	if categoryIDStr == "" {
+		return nil, nil
+	}
+
+	categoryID, err := uuid.Parse(categoryIDStr)
+	if err != nil {
+		return nil, ErrCategoryNotFound.Build()
+	}
+
+	exists, err := uc.courseRepo.CategoryExists(ctx, categoryID)
+	if err != nil {
+		log.Println("Error checking category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if !exists {
+		return nil, ErrCategoryNotFound.Build()
+	}
+
+	return &categoryID, nil
+}
+
+// applyTags replaces the course tags, unknown tags are created on the fly
+func (uc *UseCase) applyTags(ctx context.Context, courseID uuid.UUID, names []string) error {
+	if names == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	tags := make([]schema.Tag, 0, len(names))
+	for _, name := range names {
+		tagSlug := slug.Make(name)
+		if tagSlug == "" || seen[tagSlug] {
+			continue
+		}
+		seen[tagSlug] = true
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return apierror.ErrInternalServer.Build()
+		}
+		tags = append(tags, schema.Tag{ID: id, Name: strings.TrimSpace(name), Slug: tagSlug})
+	}
+
+	stored, err := uc.courseRepo.SaveTags(ctx, tags)
+	if err != nil {
+		log.Println("Error saving tags: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	if err := uc.courseRepo.ReplaceTags(ctx, courseID, stored); err != nil {
+		log.Println("Error replacing course tags: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
 }
 
 func (uc *UseCase) Update(ctx context.Context, req UpdateCourseRequest, id uuid.UUID, imageFile, syllabusFile *multipart.FileHeader) (schema.Course, error) {
@@ -176,14 +267,37 @@ func (uc *UseCase) Update(ctx context.Context, req UpdateCourseRequest, id uuid.
 	if req.Description != nil {
 		course.Description = *req.Description
 	}
+	oldPrice, oldCurrency := course.Price, course.Currency
 	if req.Price != nil {
 		course.Price = *req.Price
 	}
 	if req.Difficulty != nil {
 		course.Difficulty = *req.Difficulty
 	}
-	if req.Category != nil {
-		course.Category = *req.Category
+	if req.CategoryID != nil {
+		course.CategoryID, err = uc.resolveCategory(ctx, *req.CategoryID)
+		if err != nil {
+			return schema.Course{}, err
+		}
+		course.Category = nil
+	}
+	if req.CertificateMinGrade != nil {
+		course.CertificateMinGrade = req.CertificateMinGrade
+	}
+	if req.PrerequisitePolicy != nil {
+		course.PrerequisitePolicy = *req.PrerequisitePolicy
+	}
+	if req.AccessDays != nil {
+		course.AccessDays = req.AccessDays
+		if *req.AccessDays == 0 {
+			course.AccessDays = nil
+		}
+	}
+	if req.Currency != nil {
+		course.Currency, err = resolveCurrency(*req.Currency)
+		if err != nil {
+			return schema.Course{}, err
+		}
 	}
 
 	// Handle image update if file is provided
@@ -247,7 +361,15 @@ func (uc *UseCase) Update(ctx context.Context, req UpdateCourseRequest, id uuid.
 		return schema.Course{}, fmt.Errorf("failed to update course: %v", err)
 	}
 
-	return course, nil
+	if err := uc.applyTags(ctx, course.ID, req.Tags); err != nil {
+		return schema.Course{}, err
+	}
+
+	if course.Price < oldPrice || course.Currency != oldCurrency {
+		uc.watchPrice(course.ID)
+	}
+
+	return uc.courseRepo.GetByID(ctx, course.ID)
 }
 
 func (uc *UseCase) SearchCoursesByTitle(ctx context.Context, title string, page, pageSize int) (CoursesPaginatedResponse, error) {
@@ -265,40 +387,56 @@ func (uc *UseCase) SearchCoursesByTitle(ctx context.Context, title string, page,
 //go:embed buy_course_instructor_email_template.html
 var buyCourseInstructorEmailTemplate string
 
-func (uc *UseCase) BuyCourse(ctx context.Context, courseId uuid.UUID, studentId string) error {
+func (uc *UseCase) BuyCourse(ctx context.Context, courseId uuid.UUID, studentId string) (*BuyCourseResponse, error) {
 
 	course, err := uc.GetByID(ctx, courseId)
 	if err != nil {
-		return ErrCourseNotFound.Build()
+		return nil, ErrCourseNotFound.Build()
 	}
 
 	studentUUID, err := uuid.Parse(studentId)
 	if err != nil {
 
-		return apierror.ErrInternalServer.Build()
+		return nil, apierror.ErrInternalServer.Build()
 	}
 
-	enrolled, err := uc.courseEnrollUseCase.CheckEnrollment(ctx, studentUUID, courseId)
+	access, err := uc.courseEnrollUseCase.GetAccess(ctx, studentUUID, courseId)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if enrolled {
-		return ErrAlreadyEnrolled.Build()
+	// A purchase with an expiry can be renewed, subscribers may still buy the course to keep it
+	renewal := access.Source == courseenroll.AccessPurchase
+	if renewal && access.ExpiresAt == nil {
+		return nil, ErrAlreadyEnrolled.Build()
 	}
 
-	err = uc.walletRepo.TransferByUserID(nil, studentUUID, course.InstructorID, course.Price)
+	res := &BuyCourseResponse{MissingPrerequisites: []PrerequisiteStatus{}}
+	if !renewal {
+		res, err = uc.checkPrerequisites(ctx, &course, studentUUID)
+		if err != nil {
+			return nil, err
+		}
+	}
 
+	amount, err := uc.ChargeAmount(ctx, &course)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	err = uc.courseEnrollUseCase.EnrollStudent(ctx, studentUUID, course.ID)
+	err = uc.walletRepo.TransferByUserID(nil, studentUUID, course.InstructorID, amount)
 
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	enroll, err := uc.courseEnrollUseCase.EnrollStudent(ctx, studentUUID, &course)
+
+	if err != nil {
+		return nil, err
+	}
+	res.ExpiresAt = enroll.ExpiresAt
+
 	userName := ctx.Value("user.name").(string)
 	userEmail := ctx.Value("user.email").(string)
 
@@ -346,7 +484,307 @@ func (uc *UseCase) BuyCourse(ctx context.Context, courseId uuid.UUID, studentId
 		}
 	}()
 
-	return nil
+	res.PricePaid = amount
+	res.Currency = currency.Base()
+	return res, nil
+}
+
+// checkPrerequisites lists the prerequisites the student is missing and rejects the enrollment
+// when the course blocks on them
+func (uc *UseCase) checkPrerequisites(ctx context.Context, course *schema.Course, studentID uuid.UUID) (*BuyCourseResponse, error) {
+	res := &BuyCourseResponse{MissingPrerequisites: []PrerequisiteStatus{}}
+	if course.PrerequisitePolicy == schema.PrerequisiteNone {
+		return res, nil
+	}
+
+	statuses, err := uc.GetPrerequisiteStatus(ctx, course.ID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, status := range statuses {
+		if !status.Completed {
+			res.MissingPrerequisites = append(res.MissingPrerequisites, status)
+		}
+	}
+
+	if len(res.MissingPrerequisites) > 0 && course.PrerequisitePolicy == schema.PrerequisiteBlock {
+		return nil, ErrPrerequisitesNotMet.WithPayload(res).Build()
+	}
+
+	return res, nil
+}
+
+// EnrollFree enrolls a student in a course that currently costs nothing, without touching the wallets
+func (uc *UseCase) EnrollFree(ctx context.Context, courseId uuid.UUID, studentId string) (*BuyCourseResponse, error) {
+	course, err := uc.GetByID(ctx, courseId)
+	if err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	studentUUID, err := uuid.Parse(studentId)
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	amount, err := uc.ChargeAmount(ctx, &course)
+	if err != nil {
+		return nil, err
+	}
+	if amount > 0 {
+		return nil, ErrCourseNotFree.WithPayload(course.Pricing).Build()
+	}
+
+	enrolled, err := uc.courseEnrollUseCase.CheckEnrollment(ctx, studentUUID, courseId)
+	if err != nil {
+		return nil, err
+	}
+	if enrolled {
+		return nil, ErrAlreadyEnrolled.Build()
+	}
+
+	res, err := uc.checkPrerequisites(ctx, &course, studentUUID)
+	if err != nil {
+		return nil, err
+	}
+
+	enroll, err := uc.courseEnrollUseCase.EnrollStudent(ctx, studentUUID, &course)
+	if err != nil {
+		return nil, err
+	}
+	res.ExpiresAt = enroll.ExpiresAt
+
+	userName := ctx.Value("user.name").(string)
+	go func() {
+		notificationID, err := uuid.NewV7()
+		if err != nil {
+			return
+		}
+		notif := schema.Notification{
+			ID:     notificationID,
+			UserID: course.InstructorID,
+			Title:  "You have a new student!",
+			Detail: fmt.Sprintf("%s has enrolled in %s", userName, course.Title),
+		}
+
+		if err := uc.notificationRepo.Create(&notif); err != nil {
+			log.Println("Error creating notification: ", err)
+		}
+	}()
+
+	res.Currency = currency.Base()
+	return res, nil
+}
+
+// EnrollByEmails enrolls the students with the given emails without payment, every email gets its own result
+// so one unknown address does not fail the whole list
+func (uc *UseCase) EnrollByEmails(ctx context.Context, courseID uuid.UUID, req ManualEnrollRequest) ([]ManualEnrollResult, error) {
+	course, err := uc.GetByID(ctx, courseID)
+	if err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	seen := make(map[string]bool)
+	results := make([]ManualEnrollResult, 0, len(req.Emails))
+	for _, email := range req.Emails {
+		email = strings.TrimSpace(email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+
+		result := ManualEnrollResult{Email: email}
+
+		student, err := uc.userRepo.GetByEmail(email)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Println("Error getting user by email: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			result.Status = ManualEnrollUserNotFound
+			results = append(results, result)
+			continue
+		}
+
+		if student.Role != schema.RoleStudent {
+			result.Status = ManualEnrollNotStudent
+			results = append(results, result)
+			continue
+		}
+
+		purchased, err := uc.courseEnrollUseCase.HasPurchased(ctx, student.ID, course.ID)
+		if err != nil {
+			log.Println("Error checking enrollment: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		if purchased {
+			result.Status = ManualEnrollAlreadyEnrolled
+			results = append(results, result)
+			continue
+		}
+
+		enroll, err := uc.courseEnrollUseCase.EnrollStudent(ctx, student.ID, &course)
+		if err != nil {
+			log.Println("Error enrolling student: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		result.Status = ManualEnrollEnrolled
+		result.ExpiresAt = enroll.ExpiresAt
+		results = append(results, result)
+
+		go func(studentID uuid.UUID) {
+			notificationID, err := uuid.NewV7()
+			if err != nil {
+				return
+			}
+			notif := schema.Notification{
+				ID:     notificationID,
+				UserID: studentID,
+				Title:  "You have been enrolled in a course",
+				Detail: fmt.Sprintf("You now have access to %s", course.Title),
+			}
+
+			if err := uc.notificationRepo.Create(&notif); err != nil {
+				log.Println("Error creating notification: ", err)
+			}
+		}(student.ID)
+	}
+
+	return results, nil
+}
+
+func (uc *UseCase) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	if _, err := uc.GetByID(ctx, courseID); err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	prerequisites, err := uc.courseRepo.GetPrerequisites(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting prerequisites: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return prerequisites, nil
+}
+
+func (uc *UseCase) SetPrerequisites(ctx context.Context, courseID uuid.UUID, req SetPrerequisitesRequest) ([]schema.Course, error) {
+	seen := make(map[uuid.UUID]bool)
+	prerequisiteIDs := make([]uuid.UUID, 0, len(req.CourseIDs))
+
+	for _, id := range req.CourseIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+
+		if id == courseID {
+			return nil, ErrInvalidPrerequisite.WithPayload(map[string]any{
+				"course_id": id,
+				"reason":    "a course cannot be its own prerequisite",
+			}).Build()
+		}
+
+		if _, err := uc.GetByID(ctx, id); err != nil {
+			return nil, ErrInvalidPrerequisite.WithPayload(map[string]any{
+				"course_id": id,
+				"reason":    "course not found",
+			}).Build()
+		}
+
+		// Reject the prerequisite if it already (transitively) requires this course
+		cyclic, err := uc.requires(ctx, id, courseID, make(map[uuid.UUID]bool))
+		if err != nil {
+			log.Println("Error checking prerequisite cycle: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		if cyclic {
+			return nil, ErrInvalidPrerequisite.WithPayload(map[string]any{
+				"course_id": id,
+				"reason":    "prerequisite would create a cycle",
+			}).Build()
+		}
+
+		prerequisiteIDs = append(prerequisiteIDs, id)
+	}
+
+	if err := uc.courseRepo.SetPrerequisites(ctx, courseID, prerequisiteIDs); err != nil {
+		log.Println("Error setting prerequisites: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return uc.GetPrerequisites(ctx, courseID)
+}
+
+// requires reports whether courseID depends on target through its prerequisite chain
+func (uc *UseCase) requires(ctx context.Context, courseID, target uuid.UUID, visited map[uuid.UUID]bool) (bool, error) {
+	if visited[courseID] {
+		return false, nil
+	}
+	visited[courseID] = true
+
+	prerequisites, err := uc.courseRepo.GetPrerequisites(ctx, courseID)
+	if err != nil {
+		return false, err
+	}
+
+	for _, prerequisite := range prerequisites {
+		if prerequisite.ID == target {
+			return true, nil
+		}
+		found, err := uc.requires(ctx, prerequisite.ID, target, visited)
+		if err != nil || found {
+			return found, err
+		}
+	}
+
+	return false, nil
+}
+
+// GetPrerequisiteStatus lists the course prerequisites and whether the user has completed each of them,
+// a prerequisite counts as completed once the user is enrolled and has reached full progress
+func (uc *UseCase) GetPrerequisiteStatus(ctx context.Context, courseID, userID uuid.UUID) ([]PrerequisiteStatus, error) {
+	prerequisites, err := uc.GetPrerequisites(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]PrerequisiteStatus, 0, len(prerequisites))
+	for _, prerequisite := range prerequisites {
+		status := PrerequisiteStatus{CourseID: prerequisite.ID, Title: prerequisite.Title}
+
+		status.Enrolled, err = uc.courseEnrollUseCase.CheckEnrollment(ctx, userID, prerequisite.ID)
+		if err != nil {
+			log.Println("Error checking enrollment: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		if status.Enrolled {
+			status.Progress, err = uc.courseRepo.GetUserCourseProgress(ctx, prerequisite.ID, userID)
+			if err != nil {
+				log.Println("Error getting course progress: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			status.Completed = status.Progress >= 100
+		}
+
+		statuses = append(statuses, status)
+	}
+
+	return statuses, nil
+}
+
+func (uc *UseCase) GetAccess(ctx context.Context, courseID uuid.UUID, userID uuid.UUID) (*courseenroll.Access, error) {
+	if _, err := uc.GetByID(ctx, courseID); err != nil {
+		return nil, ErrCourseNotFound.Build()
+	}
+
+	access, err := uc.courseEnrollUseCase.GetAccess(ctx, userID, courseID)
+	if err != nil {
+		log.Println("Error getting course access: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return access, nil
 }
 
 func (uc *UseCase) GetEnrollmentsByCourse(ctx context.Context, id uuid.UUID) ([]schema.User, error) {
@@ -407,23 +845,25 @@ func (uc *UseCase) GetUserCourseProgress(ctx context.Context, courseId uuid.UUID
 }
 
 func (uc *UseCase) FilterCourses(ctx context.Context, req FilterCoursesRequest) (*CoursesPaginatedResponse, error) {
-	var filterType, filterValue, sort string
+	filter := CourseFilter{Rating: req.Rating}
 
 	if req.Category != nil {
-		filterType = "category"
-		filterValue = string(*req.Category)
-	} else if req.Difficulty != nil {
-		filterType = "difficulty"
-		filterValue = string(*req.Difficulty)
-	} else if req.Rating != nil {
-		filterType = "rating"
-		filterValue = fmt.Sprintf("%.1f", *req.Rating)
-	} else if req.Sort != nil {
-		sort = string(*req.Sort)
+		filter.CategorySlug = slug.Make(*req.Category)
+	}
+	for _, tag := range req.Tags {
+		if tagSlug := slug.Make(tag); tagSlug != "" {
+			filter.Tags = append(filter.Tags, tagSlug)
+		}
+	}
+	if req.Difficulty != nil {
+		filter.Difficulty = *req.Difficulty
+	}
+	if req.Sort != nil {
+		filter.Sort = *req.Sort
 	}
 
 	// Query the repository with the constructed filters and sorting
-	courses, total, err := uc.courseRepo.DynamicFilterCourses(ctx, filterType, filterValue, sort, req.Page, req.Limit)
+	courses, total, err := uc.courseRepo.DynamicFilterCourses(ctx, filter, req.Page, req.Limit)
 	if err != nil {
 		return nil, err
 	}
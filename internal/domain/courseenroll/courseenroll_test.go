@@ -0,0 +1,212 @@
+package courseenroll
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+// sqlRecorder keeps the statements gorm builds, with their arguments inlined
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// dryRunRepository builds the queries of the repository without a database
+func dryRunRepository(t *testing.T) (Repository, *sqlRecorder) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRepository(db), recorder
+}
+
+func TestGetRoster_EscapesSearchWildcards(t *testing.T) {
+	repo, recorder := dryRunRepository(t)
+
+	_, _, _ = repo.GetRoster(context.Background(), uuid.New(), `50%_off\`, 1, 10)
+
+	assert.Len(t, recorder.statements, 2)
+	for _, sql := range recorder.statements {
+		assert.Contains(t, sql, `users.name ILIKE '%50\%\_off\\%' ESCAPE '\'`)
+		assert.Contains(t, sql, `users.email ILIKE '%50\%\_off\\%' ESCAPE '\'`)
+	}
+}
+
+func TestGetRoster_OnlyActiveEnrollmentsOfTheCourse(t *testing.T) {
+	repo, recorder := dryRunRepository(t)
+	courseID := uuid.New()
+
+	_, _, _ = repo.GetRoster(context.Background(), courseID, "", 1, 10)
+
+	count := recorder.statements[0]
+	assert.True(t, strings.HasPrefix(count, "SELECT count(*) FROM (SELECT user_id"))
+	assert.Contains(t, count, `FROM "course_enrolls" WHERE course_id = '`+courseID.String()+`' GROUP BY "user_id"`)
+	assert.Contains(t, count, "HAVING bool_or(expires_at IS NULL OR expires_at > ")
+	assert.NotContains(t, count, "ILIKE")
+}
+
+func TestGetRoster_Pagination(t *testing.T) {
+	tests := []struct {
+		name        string
+		page, limit int
+		want        string
+	}{
+		{name: "second page", page: 2, limit: 10, want: "ORDER BY users.name LIMIT 10 OFFSET 10"},
+		{name: "export without limit", page: 1, limit: 0, want: "ORDER BY users.name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, recorder := dryRunRepository(t)
+
+			_, _, _ = repo.GetRoster(context.Background(), uuid.New(), "", tt.page, tt.limit)
+
+			assert.True(t, strings.HasSuffix(recorder.statements[1], tt.want), recorder.statements[1])
+		})
+	}
+}
+
+type CourseEnrollUseCaseTestSuite struct {
+	suite.Suite
+	repo    *MockRepository
+	useCase *UseCase
+}
+
+func (suite *CourseEnrollUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.useCase = NewUseCase(suite.repo)
+}
+
+func (suite *CourseEnrollUseCaseTestSuite) TestCheckEnrollment_ActiveEnrollment() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+
+	suite.repo.On("GetActiveEnrollment", ctx, userID, courseID).Return(&schema.CourseEnroll{UserID: userID, CourseID: courseID}, nil)
+
+	enrolled, err := suite.useCase.CheckEnrollment(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), enrolled)
+	suite.repo.AssertNotCalled(suite.T(), "IsEnrolled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *CourseEnrollUseCaseTestSuite) TestCheckEnrollment_ExpiredWithoutSubscription() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+
+	suite.repo.On("GetActiveEnrollment", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+
+	enrolled, err := suite.useCase.CheckEnrollment(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), enrolled)
+}
+
+func (suite *CourseEnrollUseCaseTestSuite) TestCheckEnrollment_Subscription() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+
+	suite.repo.On("GetActiveEnrollment", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("HasActiveSubscription", ctx, userID).Return(true, nil)
+
+	enrolled, err := suite.useCase.CheckEnrollment(ctx, userID, courseID)
+	access, _ := suite.useCase.GetAccess(ctx, userID, courseID)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), enrolled)
+	assert.Equal(suite.T(), AccessSubscription, access.Source)
+}
+
+func (suite *CourseEnrollUseCaseTestSuite) TestCheckEnrollment_RepositoryError() {
+	ctx := context.Background()
+	userID, courseID := uuid.New(), uuid.New()
+	dbErr := errors.New("connection reset")
+
+	suite.repo.On("GetActiveEnrollment", ctx, userID, courseID).Return(nil, dbErr)
+
+	enrolled, err := suite.useCase.CheckEnrollment(ctx, userID, courseID)
+
+	assert.ErrorIs(suite.T(), err, dbErr)
+	assert.False(suite.T(), enrolled)
+}
+
+func TestCourseEnrollUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(CourseEnrollUseCaseTestSuite))
+}
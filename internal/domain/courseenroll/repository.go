@@ -2,6 +2,8 @@ package courseenroll
 
 import (
 	"context"
+	"strings"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
@@ -13,6 +15,10 @@ type Repository interface {
 	GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error)
 	GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error)
 	IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error)
+	GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error)
+	HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error)
+	GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error)
+	GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]RosterEntry, int64, error)
 }
 
 type repository struct {
@@ -23,31 +29,167 @@ func NewRepository(db *gorm.DB) Repository {
 	return &repository{db: db}
 }
 
+// likeEscaper escapes the LIKE wildcards so a search matches them literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// activeEnrollment matches enrollments that have not expired yet
+func activeEnrollment(db *gorm.DB) *gorm.DB {
+	return db.Where("course_enrolls.expires_at IS NULL OR course_enrolls.expires_at > ?", time.Now())
+}
+
 func (r *repository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
 	return r.db.WithContext(ctx).Create(enroll).Error
 }
 
 func (r *repository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
 	var users []schema.User
-	err := r.db.Joins("JOIN course_enrolls ON course_enrolls.user_id = users.id").
+	err := r.db.Distinct("users.*").
+		Joins("JOIN course_enrolls ON course_enrolls.user_id = users.id").
 		Where("course_enrolls.course_id = ?", courseID).
+		Scopes(activeEnrollment).
 		Find(&users).Error
 	return users, err
 }
 
 func (r *repository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
 	var courses []schema.Course
-	err := r.db.Joins("JOIN course_enrolls ON course_enrolls.course_id = courses.id").
+	err := r.db.Distinct("courses.*").
+		Joins("JOIN course_enrolls ON course_enrolls.course_id = courses.id").
 		Where("course_enrolls.user_id = ?", userID).
+		Scopes(activeEnrollment).
 		Find(&courses).Error
 	return courses, err
 }
 
 func (r *repository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
 	var count int64
-	err := r.db.Model(&schema.CourseEnroll{}).Where("user_id = ? AND course_id = ?", userID, courseID).Count(&count).Error
+	err := r.db.Model(&schema.CourseEnroll{}).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Scopes(activeEnrollment).
+		Count(&count).Error
 	if err != nil {
 		return false, err
 	}
 	return count > 0, nil
 }
+
+// GetActiveEnrollment returns the enrollment that gives the longest access, lifetime enrollments first
+func (r *repository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	var enroll schema.CourseEnroll
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Scopes(activeEnrollment).
+		Order("expires_at DESC NULLS FIRST").
+		First(&enroll).Error
+	if err != nil {
+		return nil, err
+	}
+	return &enroll, nil
+}
+
+func (r *repository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Subscription{}).
+		Where("user_id = ? AND status = ? AND current_period_end > ?", userID, schema.SubscriptionActive, time.Now()).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// GetAccessStart returns when the user first enrolled in the course, renewals keep the original date.
+// Subscribers who never enrolled count from the start of their subscription, nil means neither applies
+func (r *repository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	var enrolledAt *time.Time
+	err := r.db.WithContext(ctx).Model(&schema.CourseEnroll{}).
+		Select("MIN(created_at)").
+		Where("user_id = ? AND course_id = ?", userID, courseID).
+		Scan(&enrolledAt).Error
+	if err != nil || enrolledAt != nil {
+		return enrolledAt, err
+	}
+
+	var subscribedAt *time.Time
+	err = r.db.WithContext(ctx).Model(&schema.Subscription{}).
+		Select("MIN(created_at)").
+		Where("user_id = ? AND status = ? AND current_period_end > ?", userID, schema.SubscriptionActive, time.Now()).
+		Scan(&subscribedAt).Error
+	return subscribedAt, err
+}
+
+// GetRoster lists the students with an active enrollment in the course together with their activity in it,
+// a limit of 0 returns every student
+func (r *repository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]RosterEntry, int64, error) {
+	enrollments := r.db.WithContext(ctx).Model(&schema.CourseEnroll{}).
+		Select(`user_id,
+			MIN(created_at) AS enrolled_at,
+			CASE WHEN bool_or(expires_at IS NULL) THEN NULL ELSE MAX(expires_at) END AS expires_at`).
+		Where("course_id = ?", courseID).
+		Group("user_id").
+		Having("bool_or(expires_at IS NULL OR expires_at > ?)", time.Now())
+
+	roster := func() *gorm.DB {
+		query := r.db.WithContext(ctx).
+			Table("(?) AS e", enrollments).
+			Joins("JOIN users ON users.id = e.user_id AND users.deleted_at IS NULL")
+		if search != "" {
+			pattern := "%" + likeEscaper.Replace(search) + "%"
+			query = query.Where(`users.name ILIKE ? ESCAPE '\' OR users.email ILIKE ? ESCAPE '\'`, pattern, pattern)
+		}
+		return query
+	}
+
+	var total int64
+	if err := roster().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	query := roster().
+		Select(`users.id AS user_id, users.name, users.email, e.enrolled_at, e.expires_at,
+			COALESCE(100.0 * ((
+				SELECT COUNT(*) FROM assignment_grades ag
+				WHERE ag.course_id = @course AND ag.user_id = users.id
+			) + (
+				SELECT COUNT(DISTINCT qa.quiz_id) FROM quiz_attempts qa
+				JOIN quizzes q ON q.id = qa.quiz_id AND q.deleted_at IS NULL
+				WHERE q.course_id = @course AND qa.user_id = users.id AND qa.submitted_at IS NOT NULL
+			)) / NULLIF((
+				SELECT COUNT(*) FROM assignments a WHERE a.course_id = @course AND a.deleted_at IS NULL
+			) + (
+				SELECT COUNT(*) FROM quizzes q WHERE q.course_id = @course AND q.deleted_at IS NULL
+			), 0), 0) AS progress,
+			(
+				SELECT AVG(g.grade) FROM (
+					SELECT ag.grade FROM assignment_grades ag
+					WHERE ag.course_id = @course AND ag.user_id = users.id
+					UNION ALL
+					SELECT MAX(qa.score) FROM quiz_attempts qa
+					JOIN quizzes q ON q.id = qa.quiz_id AND q.deleted_at IS NULL
+					WHERE q.course_id = @course AND qa.user_id = users.id AND qa.submitted_at IS NOT NULL
+					GROUP BY qa.quiz_id
+				) AS g
+			) AS average_grade,
+			GREATEST(e.enrolled_at, (
+				SELECT MAX(s.updated_at) FROM submissions s
+				JOIN assignments a ON a.id = s.assignment_id
+				WHERE a.course_id = @course AND s.user_id = users.id
+			), (
+				SELECT MAX(COALESCE(qa.submitted_at, qa.started_at)) FROM quiz_attempts qa
+				JOIN quizzes q ON q.id = qa.quiz_id
+				WHERE q.course_id = @course AND qa.user_id = users.id
+			), (
+				SELECT MAX(d.updated_at) FROM forum_discussions d WHERE d.course_id = @course AND d.user_id = users.id
+			), (
+				SELECT MAX(fr.updated_at) FROM forum_replies fr WHERE fr.course_id = @course AND fr.user_id = users.id
+			)) AS last_activity_at`,
+			map[string]any{"course": courseID}).
+		Order("users.name")
+	if limit > 0 {
+		query = query.Offset((page - 1) * limit).Limit(limit)
+	}
+
+	var entries []RosterEntry
+	if err := query.Scan(&entries).Error; err != nil {
+		return nil, 0, err
+	}
+
+	return entries, total, nil
+}
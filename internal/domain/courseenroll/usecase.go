@@ -2,13 +2,41 @@ package courseenroll
 
 import (
 	"context"
+	"errors"
 	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
+type AccessSource string
+
+const (
+	AccessPurchase     AccessSource = "purchase"
+	AccessSubscription AccessSource = "subscription"
+)
+
+// Access describes why a user can open a course, ExpiresAt is nil for lifetime or subscription access
+type Access struct {
+	HasAccess bool         `json:"has_access"`
+	Source    AccessSource `json:"source,omitempty"`
+	ExpiresAt *time.Time   `json:"expires_at,omitempty"`
+}
+
+// RosterEntry is one enrolled student as seen by the course owner, AverageGrade is nil until a submission is graded
+type RosterEntry struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	Name           string     `json:"name"`
+	Email          string     `json:"email"`
+	EnrolledAt     time.Time  `json:"enrolled_at"`
+	ExpiresAt      *time.Time `json:"expires_at"`
+	Progress       float64    `json:"progress"`
+	AverageGrade   *float64   `json:"average_grade"`
+	LastActivityAt time.Time  `json:"last_activity_at"`
+}
+
 type UseCase struct {
 	repo Repository
 }
@@ -17,28 +45,98 @@ func NewUseCase(repo Repository) *UseCase {
 	return &UseCase{repo: repo}
 }
 
-func (uc *UseCase) EnrollStudent(ctx context.Context, userID, courseID uuid.UUID) error {
+// AccessExpiry returns when an enrollment starting at from ends, nil access days means lifetime access
+func AccessExpiry(accessDays *int, from time.Time) *time.Time {
+	if accessDays == nil {
+		return nil
+	}
+	expiresAt := from.AddDate(0, 0, *accessDays)
+	return &expiresAt
+}
+
+// EnrollStudent gives the student access to the course, renewing a time-limited course
+// extends the access from the end of the current enrollment
+func (uc *UseCase) EnrollStudent(ctx context.Context, userID uuid.UUID, course *schema.Course) (*schema.CourseEnroll, error) {
 	id, err := uuid.NewV7()
 	if err != nil {
-		return apierror.ErrInternalServer.Build()
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	from := now
+	if course.AccessDays != nil {
+		current, err := uc.repo.GetActiveEnrollment(ctx, userID, course.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if current != nil && current.ExpiresAt != nil {
+			from = *current.ExpiresAt
+		}
 	}
-	enroll := schema.CourseEnroll{
+
+	enroll := &schema.CourseEnroll{
 		ID:        id,
 		UserID:    userID,
-		CourseID:  courseID,
-		CreatedAt: time.Now(),
+		CourseID:  course.ID,
+		ExpiresAt: AccessExpiry(course.AccessDays, from),
+		CreatedAt: now,
 	}
-	return uc.repo.Create(ctx, &enroll)
+	if err := uc.repo.Create(ctx, enroll); err != nil {
+		return nil, err
+	}
+	return enroll, nil
 }
 
 func (uc *UseCase) GetEnrollmentsByCourse(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
 	return uc.repo.GetUsersByCourseID(ctx, courseID)
 }
 
+func (uc *UseCase) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]RosterEntry, int64, error) {
+	return uc.repo.GetRoster(ctx, courseID, search, page, limit)
+}
+
 func (uc *UseCase) GetEnrollmentsByUser(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
 	return uc.repo.GetCoursesByUserID(ctx, userID)
 }
 
-func (uc *UseCase) CheckEnrollment(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+// HasPurchased reports whether the user owns an unexpired enrollment, subscriptions are not considered
+func (uc *UseCase) HasPurchased(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
 	return uc.repo.IsEnrolled(ctx, userID, courseID)
 }
+
+// GetAccess decides whether the user can open the course, through an unexpired enrollment
+// or an active all-access subscription
+func (uc *UseCase) GetAccess(ctx context.Context, userID, courseID uuid.UUID) (*Access, error) {
+	enroll, err := uc.repo.GetActiveEnrollment(ctx, userID, courseID)
+	if err == nil {
+		return &Access{HasAccess: true, Source: AccessPurchase, ExpiresAt: enroll.ExpiresAt}, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	subscribed, err := uc.repo.HasActiveSubscription(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if subscribed {
+		return &Access{HasAccess: true, Source: AccessSubscription}, nil
+	}
+
+	return &Access{HasAccess: false}, nil
+}
+
+// CheckEnrollment is the single place deciding whether a user may access a course
+func (uc *UseCase) CheckEnrollment(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	access, err := uc.GetAccess(ctx, userID, courseID)
+	if err != nil {
+		return false, err
+	}
+
+	return access.HasAccess, nil
+}
+
+// GetAccessStart returns the moment drip schedules of the course count from for the user
+func (uc *UseCase) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	return uc.repo.GetAccessStart(ctx, userID, courseID)
+}
@@ -3,13 +3,15 @@ package forum
 import "github.com/google/uuid"
 
 type CreateForumDiscussionRequest struct {
-	CourseID uuid.UUID `json:"course_id" binding:"required,uuid"`
-	Title    string    `json:"title" binding:"required,max=150"`
-	Content  string    `json:"content" binding:"required,max=30000"`
+	CourseID uuid.UUID  `json:"course_id" binding:"required,uuid"`
+	CohortID *uuid.UUID `json:"cohort_id"`
+	Title    string     `json:"title" binding:"required,max=150"`
+	Content  string     `json:"content" binding:"required,max=30000"`
 }
 
 type GetForumDiscussionsRequest struct {
 	CourseID string `form:"course_id" binding:"required,uuid"`
+	CohortID string `form:"cohort_id" binding:"omitempty,uuid"`
 	Page     int    `form:"page" binding:"required"`
 	Limit    int    `form:"limit" binding:"required,max=30"`
 }
@@ -9,7 +9,7 @@ import (
 type IRepository interface {
 	CreateDiscussion(discussion *schema.ForumDiscussion) error
 	GetDiscussionByID(id uuid.UUID) (*schema.ForumDiscussion, error)
-	GetDiscussionsByCourseID(courseID uuid.UUID, page int, limit int) ([]*schema.ForumDiscussion, int64, error)
+	GetDiscussionsByCourseID(courseID uuid.UUID, cohortID *uuid.UUID, page int, limit int) ([]*schema.ForumDiscussion, int64, error)
 	UpdateDiscussion(discussion *schema.ForumDiscussion) error
 	DeleteDiscussion(id uuid.UUID) error
 
@@ -38,11 +38,16 @@ func (r *repository) GetDiscussionByID(id uuid.UUID) (*schema.ForumDiscussion, e
 	return &discussion, err
 }
 
-func (r *repository) GetDiscussionsByCourseID(courseID uuid.UUID, page int, limit int) ([]*schema.ForumDiscussion, int64, error) {
+func (r *repository) GetDiscussionsByCourseID(courseID uuid.UUID, cohortID *uuid.UUID, page int, limit int) ([]*schema.ForumDiscussion, int64, error) {
 	var discussions []*schema.ForumDiscussion
 	var total int64
 
 	tx := r.db.Model(&schema.ForumDiscussion{}).Where("course_id = ?", courseID)
+	if cohortID != nil {
+		tx = tx.Where("cohort_id = ?", *cohortID)
+	} else {
+		tx = tx.Where("cohort_id IS NULL")
+	}
 
 	tx.Count(&total)
 
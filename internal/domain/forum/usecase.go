@@ -6,6 +6,7 @@ import (
 	"log"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/cohort"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
 	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
@@ -19,10 +20,11 @@ type UseCase struct {
 	repo       IRepository
 	enrollUc   *courseenroll.UseCase
 	courseRepo course.Repository
+	cohortUc   *cohort.UseCase
 }
 
-func NewUseCase(repo IRepository, enrollUc *courseenroll.UseCase, courseRepo course.Repository) *UseCase {
-	return &UseCase{repo: repo, enrollUc: enrollUc, courseRepo: courseRepo}
+func NewUseCase(repo IRepository, enrollUc *courseenroll.UseCase, courseRepo course.Repository, cohortUc *cohort.UseCase) *UseCase {
+	return &UseCase{repo: repo, enrollUc: enrollUc, courseRepo: courseRepo, cohortUc: cohortUc}
 }
 
 func (uc *UseCase) isPermitted(ctx context.Context, userRole string, userID, courseID uuid.UUID) (bool, error) {
@@ -53,6 +55,23 @@ func (uc *UseCase) isPermitted(ctx context.Context, userRole string, userID, cou
 	return true, nil
 }
 
+// canSeeDiscussion checks course access and, for a cohort discussion, cohort membership
+func (uc *UseCase) canSeeDiscussion(ctx context.Context, userID uuid.UUID, discussion *schema.ForumDiscussion) error {
+	ok, err := uc.isPermitted(ctx, ctx.Value("user.role").(string), userID, discussion.CourseID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return courseenroll.ErrNotEnrolled.Build()
+	}
+
+	if discussion.CohortID != nil {
+		return uc.cohortUc.CheckAccess(ctx, discussion.CourseID, *discussion.CohortID)
+	}
+
+	return nil
+}
+
 func (uc *UseCase) CreateDiscussion(ctx context.Context, req *CreateForumDiscussionRequest) error {
 	userID, err := uuid.Parse(ctx.Value("user.id").(string))
 	if err != nil {
@@ -69,6 +88,12 @@ func (uc *UseCase) CreateDiscussion(ctx context.Context, req *CreateForumDiscuss
 		return courseenroll.ErrNotEnrolled.Build()
 	}
 
+	if req.CohortID != nil {
+		if err := uc.cohortUc.CheckAccess(ctx, req.CourseID, *req.CohortID); err != nil {
+			return err
+		}
+	}
+
 	discussionID, err := uuid.NewV7()
 	if err != nil {
 		return apierror.ErrInternalServer.Build()
@@ -78,6 +103,7 @@ func (uc *UseCase) CreateDiscussion(ctx context.Context, req *CreateForumDiscuss
 		ID:       discussionID,
 		UserID:   userID,
 		CourseID: req.CourseID,
+		CohortID: req.CohortID,
 		Title:    req.Title,
 		Content:  req.Content,
 	}
@@ -110,13 +136,9 @@ func (uc *UseCase) GetDiscussionByID(ctx context.Context, idStr string) (*schema
 		return nil, apierror.ErrInternalServer.Build()
 	}
 
-	ok, err := uc.isPermitted(ctx, ctx.Value("user.role").(string), userID, discussion.CourseID)
-	if err != nil {
+	if err := uc.canSeeDiscussion(ctx, userID, discussion); err != nil {
 		return nil, err
 	}
-	if !ok {
-		return nil, courseenroll.ErrNotEnrolled.Build()
-	}
 
 	return discussion, nil
 }
@@ -140,7 +162,19 @@ func (uc *UseCase) GetDiscussionsByCourseID(ctx context.Context, req *GetForumDi
 		return nil, courseenroll.ErrNotEnrolled.Build()
 	}
 
-	discussions, total, err := uc.repo.GetDiscussionsByCourseID(courseID, req.Page, req.Limit)
+	var cohortID *uuid.UUID
+	if req.CohortID != "" {
+		id, err := uuid.Parse(req.CohortID)
+		if err != nil {
+			return nil, apierror.ErrValidation.Build()
+		}
+		if err := uc.cohortUc.CheckAccess(ctx, courseID, id); err != nil {
+			return nil, err
+		}
+		cohortID = &id
+	}
+
+	discussions, total, err := uc.repo.GetDiscussionsByCourseID(courseID, cohortID, req.Page, req.Limit)
 	if err != nil {
 		log.Println("Error getting discussions: ", err)
 		return nil, apierror.ErrInternalServer.Build()
@@ -239,13 +273,9 @@ func (uc *UseCase) CreateReply(ctx context.Context, req *CreateForumReplyRequest
 		return apierror.ErrInternalServer.Build()
 	}
 
-	ok, err := uc.isPermitted(ctx, ctx.Value("user.role").(string), userID, discussion.CourseID)
-	if err != nil {
+	if err := uc.canSeeDiscussion(ctx, userID, discussion); err != nil {
 		return err
 	}
-	if !ok {
-		return courseenroll.ErrNotEnrolled.Build()
-	}
 
 	replyID, err := uuid.NewV7()
 	if err != nil {
@@ -288,12 +318,17 @@ func (uc *UseCase) GetReplyByID(ctx context.Context, idStr string) (*schema.Foru
 		return nil, apierror.ErrInternalServer.Build()
 	}
 
-	ok, err := uc.isPermitted(ctx, ctx.Value("user.role").(string), userID, reply.CourseID)
+	discussion, err := uc.repo.GetDiscussionByID(reply.ForumDiscussionID)
 	if err != nil {
-		return nil, err
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDiscussionNotFound.Build()
+		}
+		log.Println("Error getting discussion: ", err)
+		return nil, apierror.ErrInternalServer.Build()
 	}
-	if !ok {
-		return nil, courseenroll.ErrNotEnrolled.Build()
+
+	if err := uc.canSeeDiscussion(ctx, userID, discussion); err != nil {
+		return nil, err
 	}
 
 	return reply, nil
@@ -319,13 +354,9 @@ func (uc *UseCase) GetRepliesByDiscussionID(ctx context.Context, req *GetForumRe
 		return nil, apierror.ErrInternalServer.Build()
 	}
 
-	ok, err := uc.isPermitted(ctx, ctx.Value("user.role").(string), userID, discussion.CourseID)
-	if err != nil {
+	if err := uc.canSeeDiscussion(ctx, userID, discussion); err != nil {
 		return nil, err
 	}
-	if !ok {
-		return nil, courseenroll.ErrNotEnrolled.Build()
-	}
 
 	replies, total, err := uc.repo.GetRepliesByDiscussionID(discussionID, req.Page, req.Limit)
 	if err != nil {
@@ -0,0 +1,32 @@
+package gift
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type SendGiftRequest struct {
+	CourseID       string `json:"course_id" binding:"required,uuid"`
+	RecipientEmail string `json:"recipient_email" binding:"required,email,max=320"`
+	Message        string `json:"message" binding:"max=500"`
+}
+
+type CodeRequest struct {
+	Code string `uri:"code" binding:"required,alphanum,max=16"`
+}
+
+type RefundRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type GiftPreviewResponse struct {
+	Code        string    `json:"code"`
+	CourseID    uuid.UUID `json:"course_id"`
+	CourseTitle string    `json:"course_title"`
+	CourseImage string    `json:"course_image_url"`
+	SenderName  string    `json:"sender_name"`
+	Message     string    `json:"message"`
+	Redeemable  bool      `json:"redeemable"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
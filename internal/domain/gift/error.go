@@ -0,0 +1,33 @@
+package gift
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrGiftNotFound = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusNotFound).
+			WithMessage("GIFT_NOT_FOUND")
+
+	ErrGiftUnavailable = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusGone).
+				WithMessage("GIFT_EXPIRED_OR_REDEEMED")
+
+	ErrGiftNotRefundable = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("GIFT_NOT_REFUNDABLE")
+
+	ErrGiftNotForYou = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("GIFT_NOT_FOR_YOU")
+
+	ErrInvalidGift = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("INVALID_GIFT")
+
+	ErrAlreadyEnrolled = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COURSE_ALREADY_ENROLLED")
+)
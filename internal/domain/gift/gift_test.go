@@ -0,0 +1,391 @@
+package gift
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Purchase(ctx context.Context, gift *schema.Gift) error {
+	args := m.Called(ctx, gift)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByCode(ctx context.Context, code string) (*schema.Gift, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Gift), args.Error(1)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Gift, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Gift), args.Error(1)
+}
+
+func (m *MockRepository) GetBySenderID(ctx context.Context, senderID uuid.UUID) ([]schema.Gift, error) {
+	args := m.Called(ctx, senderID)
+	return args.Get(0).([]schema.Gift), args.Error(1)
+}
+
+func (m *MockRepository) Redeem(ctx context.Context, gift *schema.Gift, instructorID uuid.UUID, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, gift, instructorID, enroll)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Refund(ctx context.Context, gift *schema.Gift) error {
+	args := m.Called(ctx, gift)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type GiftUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	courseRepo       *MockCourseRepository
+	enrollRepo       *MockEnrollRepository
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *GiftUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, suite.notificationRepo, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, enrollUc, nil, suite.notificationRepo, nil)
+}
+
+func (suite *GiftUseCaseTestSuite) userCtx(userID uuid.UUID, email string) context.Context {
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	ctx = context.WithValue(ctx, "user.email", email)
+	return context.WithValue(ctx, "user.name", "Budi")
+}
+
+func (suite *GiftUseCaseTestSuite) pendingGift() *schema.Gift {
+	return &schema.Gift{
+		ID:             uuid.New(),
+		Code:           "ABCDEFGH2345",
+		CourseID:       uuid.New(),
+		SenderID:       uuid.New(),
+		RecipientEmail: "friend@example.com",
+		Amount:         150000,
+		Status:         schema.GiftPending,
+		ExpiresAt:      time.Now().Add(24 * time.Hour),
+		Course:         schema.Course{Title: "Go Fundamentals", InstructorID: uuid.New()},
+	}
+}
+
+func (suite *GiftUseCaseTestSuite) TestSend_ToSelf() {
+	ctx := suite.userCtx(uuid.New(), "me@example.com")
+
+	gift, err := suite.useCase.Send(ctx, &SendGiftRequest{CourseID: uuid.New().String(), RecipientEmail: "Me@Example.com"})
+
+	assert.Nil(suite.T(), gift)
+	assert.Equal(suite.T(), ErrInvalidGift.Build().Error(), err.Error())
+}
+
+func (suite *GiftUseCaseTestSuite) TestRedeem_Success() {
+	userID := uuid.New()
+	ctx := suite.userCtx(userID, "Friend@Example.com")
+	gift := suite.pendingGift()
+
+	suite.repo.On("GetByCode", ctx, gift.Code).Return(gift, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, gift.CourseID).Return(false, nil)
+	suite.repo.On("Redeem", ctx, gift, gift.Course.InstructorID, mock.AnythingOfType("*schema.CourseEnroll")).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil).Maybe()
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: gift.Code})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, enroll.UserID)
+	assert.Equal(suite.T(), gift.CourseID, enroll.CourseID)
+}
+
+func (suite *GiftUseCaseTestSuite) TestRedeem_WrongRecipient() {
+	ctx := suite.userCtx(uuid.New(), "someone@example.com")
+	gift := suite.pendingGift()
+
+	suite.repo.On("GetByCode", ctx, gift.Code).Return(gift, nil)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: gift.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrGiftNotForYou.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Redeem", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *GiftUseCaseTestSuite) TestRedeem_Expired() {
+	ctx := suite.userCtx(uuid.New(), "friend@example.com")
+	gift := suite.pendingGift()
+	gift.ExpiresAt = time.Now().Add(-time.Hour)
+
+	suite.repo.On("GetByCode", ctx, gift.Code).Return(gift, nil)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: gift.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrGiftUnavailable.Build().Error(), err.Error())
+}
+
+func (suite *GiftUseCaseTestSuite) TestRedeem_RedeemedConcurrently() {
+	userID := uuid.New()
+	ctx := suite.userCtx(userID, "friend@example.com")
+	gift := suite.pendingGift()
+
+	suite.repo.On("GetByCode", ctx, gift.Code).Return(gift, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, gift.CourseID).Return(false, nil)
+	suite.repo.On("Redeem", ctx, gift, gift.Course.InstructorID, mock.AnythingOfType("*schema.CourseEnroll")).Return(gorm.ErrRecordNotFound)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: gift.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrGiftUnavailable.Build().Error(), err.Error())
+}
+
+func (suite *GiftUseCaseTestSuite) TestRefund_NotExpiredYet() {
+	gift := suite.pendingGift()
+	ctx := suite.userCtx(gift.SenderID, "me@example.com")
+
+	suite.repo.On("GetByID", ctx, gift.ID).Return(gift, nil)
+
+	res, err := suite.useCase.Refund(ctx, &RefundRequest{ID: gift.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrGiftNotRefundable.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Refund", mock.Anything, mock.Anything)
+}
+
+func (suite *GiftUseCaseTestSuite) TestRefund_Expired() {
+	gift := suite.pendingGift()
+	gift.ExpiresAt = time.Now().Add(-time.Hour)
+	ctx := suite.userCtx(gift.SenderID, "me@example.com")
+
+	suite.repo.On("GetByID", ctx, gift.ID).Return(gift, nil)
+	suite.repo.On("Refund", ctx, gift).Return(nil)
+
+	res, err := suite.useCase.Refund(ctx, &RefundRequest{ID: gift.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), schema.GiftRefunded, res.Status)
+	assert.NotNil(suite.T(), res.RefundedAt)
+}
+
+func (suite *GiftUseCaseTestSuite) TestRefund_NotSender() {
+	gift := suite.pendingGift()
+	gift.ExpiresAt = time.Now().Add(-time.Hour)
+	ctx := suite.userCtx(uuid.New(), "other@example.com")
+
+	suite.repo.On("GetByID", ctx, gift.ID).Return(gift, nil)
+
+	res, err := suite.useCase.Refund(ctx, &RefundRequest{ID: gift.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Error(suite.T(), err)
+	suite.repo.AssertNotCalled(suite.T(), "Refund", mock.Anything, mock.Anything)
+}
+
+func TestGiftUseCase(t *testing.T) {
+	suite.Run(t, new(GiftUseCaseTestSuite))
+}
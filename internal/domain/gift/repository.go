@@ -0,0 +1,114 @@
+package gift
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Purchase(ctx context.Context, gift *schema.Gift) error
+	GetByCode(ctx context.Context, code string) (*schema.Gift, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Gift, error)
+	GetBySenderID(ctx context.Context, senderID uuid.UUID) ([]schema.Gift, error)
+	Redeem(ctx context.Context, gift *schema.Gift, instructorID uuid.UUID, enroll *schema.CourseEnroll) error
+	Refund(ctx context.Context, gift *schema.Gift) error
+}
+
+type repository struct {
+	db         *gorm.DB
+	walletRepo wallet.IRepository
+}
+
+func NewRepository(db *gorm.DB, walletRepo wallet.IRepository) Repository {
+	return &repository{db: db, walletRepo: walletRepo}
+}
+
+// Purchase takes the gift amount from the sender wallet into escrow and stores the gift atomically
+func (r *repository) Purchase(ctx context.Context, gift *schema.Gift) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.walletRepo.DebitByUserID(tx, gift.SenderID, gift.Amount); err != nil {
+			return err
+		}
+
+		return tx.Omit("Course").Create(gift).Error
+	})
+}
+
+func (r *repository) GetByCode(ctx context.Context, code string) (*schema.Gift, error) {
+	var gift schema.Gift
+	if err := r.db.WithContext(ctx).Preload("Course").First(&gift, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &gift, nil
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Gift, error) {
+	var gift schema.Gift
+	if err := r.db.WithContext(ctx).Preload("Course").First(&gift, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &gift, nil
+}
+
+func (r *repository) GetBySenderID(ctx context.Context, senderID uuid.UUID) ([]schema.Gift, error) {
+	var gifts []schema.Gift
+	err := r.db.WithContext(ctx).
+		Preload("Course").
+		Where("sender_id = ?", senderID).
+		Order("created_at DESC").
+		Find(&gifts).Error
+	return gifts, err
+}
+
+// Redeem marks a pending, unexpired gift as redeemed, releases the escrow to the instructor and
+// enrolls the recipient in one transaction. gorm.ErrRecordNotFound means the gift is no longer redeemable
+func (r *repository) Redeem(ctx context.Context, gift *schema.Gift, instructorID uuid.UUID, enroll *schema.CourseEnroll) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&schema.Gift{}).
+			Where("id = ? AND status = ? AND expires_at > ?", gift.ID, schema.GiftPending, time.Now()).
+			Updates(map[string]any{
+				"status":      schema.GiftRedeemed,
+				"redeemed_by": enroll.UserID,
+				"redeemed_at": enroll.CreatedAt,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		if err := r.walletRepo.CreditByUserID(tx, instructorID, gift.Amount); err != nil {
+			return err
+		}
+
+		return tx.Create(enroll).Error
+	})
+}
+
+// Refund returns the escrow of an expired, unredeemed gift to the sender.
+// gorm.ErrRecordNotFound means the gift is not refundable
+func (r *repository) Refund(ctx context.Context, gift *schema.Gift) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		res := tx.Model(&schema.Gift{}).
+			Where("id = ? AND status = ? AND expires_at <= ?", gift.ID, schema.GiftPending, now).
+			Updates(map[string]any{
+				"status":      schema.GiftRefunded,
+				"refunded_at": now,
+			})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return r.walletRepo.CreditByUserID(tx, gift.SenderID, gift.Amount)
+	})
+}
@@ -0,0 +1,125 @@
+package gift
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	giftGroup := engine.Group("/v1/gifts")
+	{
+		giftGroup.POST("",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("student"),
+			controller.Send(),
+		)
+		giftGroup.GET("/sent", middleware.Authenticate(), controller.GetSent())
+		giftGroup.POST("/sent/:id/refund", middleware.Authenticate(), controller.Refund())
+		giftGroup.GET("/:code", controller.Preview())
+		giftGroup.POST("/:code/redeem",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("student"),
+			controller.Redeem(),
+		)
+	}
+}
+
+func (c *RestController) Send() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SendGiftRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Send(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "SEND_GIFT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetSent() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetSent(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_SENT_GIFTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Refund() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req RefundRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Refund(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "REFUND_GIFT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Preview() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CodeRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Preview(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_GIFT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Redeem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CodeRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Redeem(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "REDEEM_GIFT_SUCCESS", res).Send(ctx)
+	}
+}
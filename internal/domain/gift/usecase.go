@@ -0,0 +1,337 @@
+package gift
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
+	"github.com/Stefanuswilfrid/course-backend/internal/mailer"
+	"github.com/Stefanuswilfrid/course-backend/internal/redeemcode"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const (
+	codeLength = 12
+	// giftValidity is how long the recipient has to redeem a gift before the sender can take the money back
+	giftValidity = 90 * 24 * time.Hour
+)
+
+//go:embed gift_received_email_template.html
+var giftReceivedEmailTemplate string
+
+type UseCase struct {
+	repo             Repository
+	courseUc         *course.UseCase
+	enrollUc         *courseenroll.UseCase
+	userRepo         user.IRepository
+	notificationRepo notification.IRepository
+	mailDialer       config.IMailer
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase, userRepo user.IRepository,
+	notificationRepo notification.IRepository, mailDialer config.IMailer) *UseCase {
+	return &UseCase{
+		repo:             repo,
+		courseUc:         courseUc,
+		enrollUc:         enrollUc,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		mailDialer:       mailDialer,
+	}
+}
+
+// redeemable reports whether the gift can still be redeemed
+func redeemable(gift *schema.Gift) bool {
+	return gift.Status == schema.GiftPending && gift.ExpiresAt.After(time.Now())
+}
+
+func (uc *UseCase) getByCode(ctx context.Context, code string) (*schema.Gift, error) {
+	gift, err := uc.repo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftNotFound.Build()
+		}
+		log.Println("Error getting gift: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return gift, nil
+}
+
+func (uc *UseCase) notify(userID uuid.UUID, title, detail string) {
+	notificationID, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+
+	notif := schema.Notification{
+		ID:     notificationID,
+		UserID: userID,
+		Title:  title,
+		Detail: detail,
+	}
+	if err := uc.notificationRepo.Create(&notif); err != nil {
+		log.Println("Error creating notification: ", err)
+	}
+}
+
+func (uc *UseCase) sendGiftEmail(gift *schema.Gift, senderName string) {
+	emailData := map[string]any{
+		"sender_name":  senderName,
+		"course_title": gift.Course.Title,
+		"message":      gift.Message,
+		"code":         gift.Code,
+		"redeem_url":   config.Env.FrontendUrl + "/gifts/" + gift.Code,
+		"expires_at":   gift.ExpiresAt.Format("2 January 2006"),
+	}
+
+	mail, err := mailer.GenerateMail(gift.RecipientEmail, "You received a course gift!", giftReceivedEmailTemplate, emailData)
+	if err != nil {
+		log.Println("Error generating email: ", err)
+		return
+	}
+
+	if err = uc.mailDialer.DialAndSend(mail); err != nil {
+		log.Println("Error sending email: ", err)
+	}
+}
+
+// Send buys the course for the recipient at the current price, the money stays in escrow until the gift
+// is redeemed and the code is emailed to the recipient
+func (uc *UseCase) Send(ctx context.Context, req *SendGiftRequest) (*schema.Gift, error) {
+	senderID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	if strings.EqualFold(req.RecipientEmail, ctx.Value("user.email").(string)) {
+		return nil, ErrInvalidGift.WithPayload(map[string]any{
+			"reason": "you cannot send a gift to yourself",
+		}).Build()
+	}
+
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	courseObj, err := uc.courseUc.GetByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrCourseNotFound.Build()
+		}
+		log.Println("Error getting course: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	amount, err := uc.courseUc.ChargeAmount(ctx, &courseObj)
+	if err != nil {
+		return nil, err
+	}
+	if amount == 0 {
+		return nil, ErrInvalidGift.WithPayload(map[string]any{
+			"reason": "free courses cannot be gifted",
+		}).Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	gift := &schema.Gift{
+		ID:             id,
+		CourseID:       courseObj.ID,
+		SenderID:       senderID,
+		RecipientEmail: req.RecipientEmail,
+		Message:        req.Message,
+		Amount:         amount,
+		Status:         schema.GiftPending,
+		ExpiresAt:      time.Now().Add(giftValidity),
+	}
+
+	// Retry on the rare code collision
+	for attempt := 0; ; attempt++ {
+		gift.Code, err = redeemcode.Generate(codeLength)
+		if err != nil {
+			log.Println("Error generating gift code: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		err = uc.repo.Purchase(ctx, gift)
+		if err == nil {
+			break
+		}
+
+		var apiErr *apierror.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" || attempt == 2 {
+			log.Println("Error purchasing gift: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+	gift.Course = courseObj
+
+	senderName := ctx.Value("user.name").(string)
+	go uc.sendGiftEmail(gift, senderName)
+
+	return gift, nil
+}
+
+func (uc *UseCase) GetSent(ctx context.Context) ([]schema.Gift, error) {
+	senderID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	gifts, err := uc.repo.GetBySenderID(ctx, senderID)
+	if err != nil {
+		log.Println("Error getting gifts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return gifts, nil
+}
+
+// Preview shows what a gift code contains so the frontend can render the gift link
+func (uc *UseCase) Preview(ctx context.Context, req *CodeRequest) (*GiftPreviewResponse, error) {
+	gift, err := uc.getByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &GiftPreviewResponse{
+		Code:        gift.Code,
+		CourseID:    gift.CourseID,
+		CourseTitle: gift.Course.Title,
+		CourseImage: gift.Course.ImageURL,
+		Message:     gift.Message,
+		Redeemable:  redeemable(gift),
+		ExpiresAt:   gift.ExpiresAt,
+	}
+
+	sender, err := uc.userRepo.GetByID(gift.SenderID)
+	if err == nil {
+		res.SenderName = sender.Name
+	}
+
+	return res, nil
+}
+
+// Redeem enrolls the recipient and pays the instructor out of the escrow. Only the account registered
+// with the recipient email can redeem, a gift for a course the recipient already owns stays refundable
+func (uc *UseCase) Redeem(ctx context.Context, req *CodeRequest) (*schema.CourseEnroll, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	gift, err := uc.getByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	if !redeemable(gift) {
+		return nil, ErrGiftUnavailable.Build()
+	}
+	if !strings.EqualFold(gift.RecipientEmail, ctx.Value("user.email").(string)) {
+		return nil, ErrGiftNotForYou.Build()
+	}
+
+	purchased, err := uc.enrollUc.HasPurchased(ctx, userID, gift.CourseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if purchased {
+		return nil, ErrAlreadyEnrolled.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	enroll := &schema.CourseEnroll{
+		ID:        id,
+		UserID:    userID,
+		CourseID:  gift.CourseID,
+		ExpiresAt: courseenroll.AccessExpiry(gift.Course.AccessDays, now),
+		CreatedAt: now,
+	}
+
+	if err := uc.repo.Redeem(ctx, gift, gift.Course.InstructorID, enroll); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftUnavailable.Build()
+		}
+		log.Println("Error redeeming gift: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	recipientName := ctx.Value("user.name").(string)
+	go uc.notify(gift.SenderID, "Your gift was redeemed",
+		fmt.Sprintf("%s redeemed your gift of %s", recipientName, gift.Course.Title))
+	go uc.notify(gift.Course.InstructorID, "You have a new student!",
+		fmt.Sprintf("%s has been gifted to %s", gift.Course.Title, recipientName))
+
+	return enroll, nil
+}
+
+// Refund gives the sender their money back once the gift has expired without being redeemed
+func (uc *UseCase) Refund(ctx context.Context, req *RefundRequest) (*schema.Gift, error) {
+	senderID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	gift, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftNotFound.Build()
+		}
+		log.Println("Error getting gift: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	if gift.SenderID != senderID {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	if gift.Status != schema.GiftPending || gift.ExpiresAt.After(time.Now()) {
+		return nil, ErrGiftNotRefundable.Build()
+	}
+
+	if err := uc.repo.Refund(ctx, gift); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGiftNotRefundable.Build()
+		}
+		log.Println("Error refunding gift: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	gift.Status = schema.GiftRefunded
+	gift.RefundedAt = &now
+
+	return gift, nil
+}
@@ -0,0 +1,199 @@
+package gradebook
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/sanitize"
+	"github.com/google/uuid"
+)
+
+// assignmentColumn matches the header of an assignment column, the title is followed by the assignment ID
+// in brackets so the export can be edited offline and imported again
+var assignmentColumn = regexp.MustCompile(`\[([0-9a-fA-F-]{36})\]\s*$`)
+
+func formatGrade(grade *float64) string {
+	if grade == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*grade, 'f', 1, 64)
+}
+
+// Export renders the gradebook of the course as CSV, one row per student
+func (uc *UseCase) Export(ctx context.Context, req *CourseIDRequest) ([]byte, error) {
+	gradebook, err := uc.Get(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	header := []string{"user_id", "name", "email"}
+	for _, a := range gradebook.Assignments {
+		header = append(header, sanitize.CSVCell(fmt.Sprintf("%s [%s]", a.Title, a.ID)))
+	}
+	header = append(header, "final", "letter")
+
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	if err := w.Write(header); err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	for _, s := range gradebook.Students {
+		record := []string{s.UserID.String(), sanitize.CSVCell(s.Name), sanitize.CSVCell(s.Email)}
+		for _, a := range gradebook.Assignments {
+			record = append(record, formatGrade(s.Grades[a.ID]))
+		}
+		record = append(record, formatGrade(s.Final), sanitize.CSVCell(s.Letter))
+		if err := w.Write(record); err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+
+	w.Flush()
+	if err := w.Error(); err != nil {
+		log.Println("Error writing gradebook csv: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return buf.Bytes(), nil
+}
+
+// Import reads grades from a CSV laid out like the export. Only the user_id and assignment columns are read,
+// empty cells and grades that did not change are skipped. Nothing is written unless every cell is valid
+func (uc *UseCase) Import(ctx context.Context, req *CourseIDRequest, file io.Reader) (*ImportResponse, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	r := csv.NewReader(file)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+	records, err := r.ReadAll()
+	if err != nil || len(records) == 0 {
+		return nil, ErrInvalidImport.WithPayload(map[string]any{"reason": "the file is not a CSV with a header row"}).Build()
+	}
+
+	assignments, err := uc.repo.GetAssignments(ctx, courseObj.ID)
+	if err != nil {
+		log.Println("Error getting gradebook assignments: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	inCourse := make(map[uuid.UUID]bool, len(assignments))
+	held := make(map[uuid.UUID]bool, len(assignments))
+	for _, a := range assignments {
+		inCourse[a.ID] = true
+		held[a.ID] = a.HoldGrades
+	}
+
+	var errs []ImportError
+	header := records[0]
+	userColumn := -1
+	type column struct {
+		index        int
+		assignmentID uuid.UUID
+	}
+	var columns []column
+	for i, h := range header {
+		if strings.EqualFold(strings.TrimSpace(h), "user_id") {
+			userColumn = i
+			continue
+		}
+		match := assignmentColumn.FindStringSubmatch(h)
+		if match == nil {
+			continue
+		}
+		id, err := uuid.Parse(match[1])
+		if err != nil || !inCourse[id] {
+			errs = append(errs, ImportError{Row: 1, Column: h, Reason: "not an assignment of the course"})
+			continue
+		}
+		columns = append(columns, column{i, id})
+	}
+	if userColumn < 0 || len(columns) == 0 {
+		return nil, ErrInvalidImport.WithPayload(map[string]any{
+			"reason": "the header needs a user_id column and at least one assignment column",
+		}).Build()
+	}
+
+	students, err := uc.repo.GetStudents(ctx, courseObj.ID)
+	if err != nil {
+		log.Println("Error getting gradebook students: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	isStudent := make(map[uuid.UUID]bool, len(students))
+	for _, s := range students {
+		isStudent[s.ID] = true
+	}
+
+	grades, err := uc.repo.GetGrades(ctx, courseObj.ID, nil, false)
+	if err != nil {
+		log.Println("Error getting grades: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	type cell struct{ assignmentID, userID uuid.UUID }
+	current := make(map[cell]float64, len(grades))
+	for _, g := range grades {
+		current[cell{g.AssignmentID, g.UserID}] = g.Grade
+	}
+
+	var imported []ImportedGrade
+	seen := make(map[uuid.UUID]bool)
+	for i, record := range records[1:] {
+		row := i + 2
+		if userColumn >= len(record) {
+			errs = append(errs, ImportError{Row: row, Column: header[userColumn], Reason: "missing"})
+			continue
+		}
+		userID, err := uuid.Parse(strings.TrimSpace(record[userColumn]))
+		if err != nil || !isStudent[userID] {
+			errs = append(errs, ImportError{Row: row, Column: header[userColumn], Reason: "not a student of the course"})
+			continue
+		}
+		if seen[userID] {
+			errs = append(errs, ImportError{Row: row, Column: header[userColumn], Reason: "the student appears twice"})
+			continue
+		}
+		seen[userID] = true
+
+		for _, c := range columns {
+			if c.index >= len(record) || strings.TrimSpace(record[c.index]) == "" {
+				continue
+			}
+			grade, err := strconv.ParseFloat(strings.TrimSpace(record[c.index]), 64)
+			if err != nil || grade < 0 || grade > 100 {
+				errs = append(errs, ImportError{Row: row, Column: header[c.index], Reason: "the grade must be a number from 0 to 100"})
+				continue
+			}
+
+			grade = roundGrade(grade)
+			if old, ok := current[cell{c.assignmentID, userID}]; ok && old == grade {
+				continue
+			}
+			imported = append(imported, ImportedGrade{
+				AssignmentID: c.assignmentID,
+				UserID:       userID,
+				Grade:        grade,
+				Release:      !held[c.assignmentID],
+			})
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, ErrInvalidImport.WithPayload(map[string]any{"errors": errs}).Build()
+	}
+	if len(imported) > 0 {
+		if err := uc.repo.ImportGrades(ctx, imported); err != nil {
+			log.Println("Error importing grades: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+	return &ImportResponse{Updated: len(imported)}, nil
+}
@@ -0,0 +1,76 @@
+package gradebook
+
+import (
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type CourseIDRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+}
+
+type CategoryIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type CreateCategoryRequest struct {
+	CourseID string  `uri:"id" binding:"required,uuid"`
+	Name     string  `json:"name" binding:"required,max=100"`
+	Weight   float64 `json:"weight" binding:"required,gt=0,max=100"`
+	Position int     `json:"position" binding:"min=0"`
+}
+
+type UpdateCategoryRequest struct {
+	ID       string   `uri:"id" binding:"required,uuid"`
+	Name     *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	Weight   *float64 `json:"weight" binding:"omitempty,gt=0,max=100"`
+	Position *int     `json:"position" binding:"omitempty,min=0"`
+}
+
+// SetScaleRequest replaces the letter grade scale of the course, an empty scale goes back to the default one
+type SetScaleRequest struct {
+	CourseID string           `uri:"id" binding:"required,uuid"`
+	Steps    []ScaleStepInput `json:"steps" binding:"max=20,dive"`
+}
+
+type ScaleStepInput struct {
+	Letter     string  `json:"letter" binding:"required,max=5"`
+	MinPercent float64 `json:"min_percent" binding:"min=0,max=100"`
+}
+
+type AssignmentColumn struct {
+	ID         uuid.UUID  `json:"id"`
+	Title      string     `json:"title"`
+	CategoryID *uuid.UUID `json:"category_id"`
+	Weight     float64    `json:"weight"`
+}
+
+type StudentGrades struct {
+	UserID     uuid.UUID              `json:"user_id"`
+	Name       string                 `json:"name"`
+	Email      string                 `json:"email"`
+	Grades     map[uuid.UUID]*float64 `json:"grades"`
+	Categories map[uuid.UUID]*float64 `json:"categories"`
+	Final      *float64               `json:"final"`
+	Letter     string                 `json:"letter"`
+}
+
+// GradebookResponse is the grade of every student for every assignment of the course, a student viewing
+// their own grades only gets their row
+type GradebookResponse struct {
+	Assignments []AssignmentColumn      `json:"assignments"`
+	Categories  []schema.GradeCategory  `json:"categories"`
+	Scale       []schema.GradeScaleStep `json:"scale"`
+	Students    []StudentGrades         `json:"students"`
+}
+
+type ImportResponse struct {
+	Updated int `json:"updated"`
+}
+
+// ImportError points at a cell of the uploaded CSV that could not be imported, rows count from 1 at the header
+type ImportError struct {
+	Row    int    `json:"row"`
+	Column string `json:"column"`
+	Reason string `json:"reason"`
+}
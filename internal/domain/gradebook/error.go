@@ -0,0 +1,21 @@
+package gradebook
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrCategoryNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("GRADE_CATEGORY_NOT_FOUND")
+
+	ErrInvalidScale = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("INVALID_GRADE_SCALE")
+
+	ErrInvalidImport = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_GRADEBOOK_IMPORT")
+)
@@ -0,0 +1,90 @@
+package gradebook
+
+import (
+	"math"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// defaultScale is used by courses that did not set a letter grade scale of their own
+var defaultScale = []schema.GradeScaleStep{
+	{Letter: "A", MinPercent: 90},
+	{Letter: "B", MinPercent: 80},
+	{Letter: "C", MinPercent: 70},
+	{Letter: "D", MinPercent: 60},
+	{Letter: "F", MinPercent: 0},
+}
+
+// finalGrade weighs the grades of a student into the final grade of the course. Assignments without a grade
+// count as zero. With grade categories every category is the weighted average of its assignments and the
+// categories are weighed against each other, assignments outside a category do not count then. Without
+// categories the assignments are weighed against each other directly. The results are nil when nothing
+// carries weight
+func finalGrade(assignments []schema.Assignment, categories []schema.GradeCategory, grades map[uuid.UUID]*float64) (map[uuid.UUID]*float64, *float64) {
+	if len(categories) == 0 {
+		return map[uuid.UUID]*float64{}, weightedAverage(assignments, grades)
+	}
+
+	categoryGrades := make(map[uuid.UUID]*float64, len(categories))
+	var total, weights float64
+	for _, c := range categories {
+		var members []schema.Assignment
+		for _, a := range assignments {
+			if a.CategoryID != nil && *a.CategoryID == c.ID {
+				members = append(members, a)
+			}
+		}
+
+		grade := weightedAverage(members, grades)
+		categoryGrades[c.ID] = grade
+		if grade == nil {
+			continue
+		}
+		total += c.Weight * *grade
+		weights += c.Weight
+	}
+
+	if weights == 0 {
+		return categoryGrades, nil
+	}
+	final := roundGrade(total / weights)
+	return categoryGrades, &final
+}
+
+func weightedAverage(assignments []schema.Assignment, grades map[uuid.UUID]*float64) *float64 {
+	var total, weights float64
+	for _, a := range assignments {
+		if a.Weight <= 0 {
+			continue
+		}
+		if grade := grades[a.ID]; grade != nil {
+			total += a.Weight * *grade
+		}
+		weights += a.Weight
+	}
+
+	if weights == 0 {
+		return nil
+	}
+	average := roundGrade(total / weights)
+	return &average
+}
+
+// letterFor picks the letter of the highest step of the scale the grade reaches, the scale is ordered
+// from the highest step down
+func letterFor(scale []schema.GradeScaleStep, grade *float64) string {
+	if grade == nil {
+		return ""
+	}
+	for _, step := range scale {
+		if *grade >= step.MinPercent {
+			return step.Letter
+		}
+	}
+	return ""
+}
+
+func roundGrade(grade float64) float64 {
+	return math.Round(grade*10) / 10
+}
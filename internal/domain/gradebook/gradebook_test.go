@@ -0,0 +1,542 @@
+package gradebook
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateCategory(ctx context.Context, category *schema.GradeCategory) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateCategory(ctx context.Context, category *schema.GradeCategory) error {
+	args := m.Called(ctx, category)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCategory(ctx context.Context, id uuid.UUID) (*schema.GradeCategory, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.GradeCategory), args.Error(1)
+}
+
+func (m *MockRepository) GetCategories(ctx context.Context, courseID uuid.UUID) ([]schema.GradeCategory, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.GradeCategory), args.Error(1)
+}
+
+func (m *MockRepository) GetScale(ctx context.Context, courseID uuid.UUID) ([]schema.GradeScaleStep, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.GradeScaleStep), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceScale(ctx context.Context, courseID uuid.UUID, steps []schema.GradeScaleStep) error {
+	args := m.Called(ctx, courseID, steps)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAssignments(ctx context.Context, courseID uuid.UUID) ([]schema.Assignment, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Assignment), args.Error(1)
+}
+
+func (m *MockRepository) GetStudents(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockRepository) GetGrades(ctx context.Context, courseID uuid.UUID, userID *uuid.UUID, released bool) ([]Grade, error) {
+	args := m.Called(ctx, courseID, userID, released)
+	return args.Get(0).([]Grade), args.Error(1)
+}
+
+func (m *MockRepository) ImportGrades(ctx context.Context, grades []ImportedGrade) error {
+	args := m.Called(ctx, grades)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type GradebookUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	enrollRepo *MockEnrollRepository
+	useCase    *UseCase
+}
+
+func (suite *GradebookUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, enrollUc)
+}
+
+// instructorOf sets up a course taught by the returned instructor
+func (suite *GradebookUseCaseTestSuite) instructorOf(courseID uuid.UUID) context.Context {
+	instructorID := uuid.New()
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, InstructorID: instructorID}, nil)
+	return ctx
+}
+
+func (suite *GradebookUseCaseTestSuite) TestGet_WeighsCategories() {
+	courseID := uuid.New()
+	ctx := suite.instructorOf(courseID)
+	homework := schema.GradeCategory{ID: uuid.New(), CourseID: courseID, Name: "Homework", Weight: 40}
+	exams := schema.GradeCategory{ID: uuid.New(), CourseID: courseID, Name: "Exams", Weight: 60}
+	hw1 := schema.Assignment{ID: uuid.New(), Title: "HW 1", CategoryID: &homework.ID, Weight: 1}
+	hw2 := schema.Assignment{ID: uuid.New(), Title: "HW 2", CategoryID: &homework.ID, Weight: 1}
+	midterm := schema.Assignment{ID: uuid.New(), Title: "Midterm", CategoryID: &exams.ID, Weight: 1}
+	final := schema.Assignment{ID: uuid.New(), Title: "Final", CategoryID: &exams.ID, Weight: 2}
+	extra := schema.Assignment{ID: uuid.New(), Title: "Uncategorized", Weight: 1}
+	student := schema.User{ID: uuid.New(), Name: "Ann", Email: "ann@example.com"}
+
+	suite.repo.On("GetStudents", ctx, courseID).Return([]schema.User{student}, nil)
+	suite.repo.On("GetAssignments", ctx, courseID).Return([]schema.Assignment{hw1, hw2, midterm, final, extra}, nil)
+	suite.repo.On("GetCategories", ctx, courseID).Return([]schema.GradeCategory{homework, exams}, nil)
+	suite.repo.On("GetScale", ctx, courseID).Return([]schema.GradeScaleStep{}, nil)
+	suite.repo.On("GetGrades", ctx, courseID, (*uuid.UUID)(nil), false).Return([]Grade{
+		{AssignmentID: hw1.ID, UserID: student.ID, Grade: 100},
+		{AssignmentID: midterm.ID, UserID: student.ID, Grade: 70},
+		{AssignmentID: final.ID, UserID: student.ID, Grade: 85},
+		{AssignmentID: extra.ID, UserID: student.ID, Grade: 0},
+	}, nil)
+
+	res, err := suite.useCase.Get(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), res.Students, 1)
+	row := res.Students[0]
+	assert.Nil(suite.T(), row.Grades[hw2.ID])
+	// homework: the missing HW 2 counts as zero, exams: (70 + 2 * 85) / 3
+	assert.Equal(suite.T(), 50.0, *row.Categories[homework.ID])
+	assert.Equal(suite.T(), 80.0, *row.Categories[exams.ID])
+	assert.Equal(suite.T(), 68.0, *row.Final)
+	assert.Equal(suite.T(), "D", row.Letter)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestGet_WithoutCategoriesWeighsAssignments() {
+	courseID := uuid.New()
+	ctx := suite.instructorOf(courseID)
+	quiz := schema.Assignment{ID: uuid.New(), Weight: 1}
+	project := schema.Assignment{ID: uuid.New(), Weight: 3}
+	practice := schema.Assignment{ID: uuid.New(), Weight: 0}
+	student := schema.User{ID: uuid.New()}
+
+	suite.repo.On("GetStudents", ctx, courseID).Return([]schema.User{student}, nil)
+	suite.repo.On("GetAssignments", ctx, courseID).Return([]schema.Assignment{quiz, project, practice}, nil)
+	suite.repo.On("GetCategories", ctx, courseID).Return([]schema.GradeCategory{}, nil)
+	suite.repo.On("GetScale", ctx, courseID).Return([]schema.GradeScaleStep{
+		{Letter: "P", MinPercent: 75},
+		{Letter: "NP", MinPercent: 0},
+	}, nil)
+	suite.repo.On("GetGrades", ctx, courseID, (*uuid.UUID)(nil), false).Return([]Grade{
+		{AssignmentID: quiz.ID, UserID: student.ID, Grade: 60},
+		{AssignmentID: project.ID, UserID: student.ID, Grade: 90},
+		{AssignmentID: practice.ID, UserID: student.ID, Grade: 0},
+	}, nil)
+
+	res, err := suite.useCase.Get(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 82.5, *res.Students[0].Final)
+	assert.Equal(suite.T(), "P", res.Students[0].Letter)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestGet_NotInstructor() {
+	courseID := uuid.New()
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.useCase.Get(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "GetStudents", mock.Anything, mock.Anything)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestGetMine_OnlyOwnRow() {
+	courseID := uuid.New()
+	studentID := uuid.New()
+	ctx := testutil.UserCtx(studentID, schema.RoleStudent)
+	assignment := schema.Assignment{ID: uuid.New(), Weight: 1}
+
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, studentID, courseID).Return(&schema.CourseEnroll{}, nil)
+	suite.repo.On("GetAssignments", ctx, courseID).Return([]schema.Assignment{assignment}, nil)
+	suite.repo.On("GetCategories", ctx, courseID).Return([]schema.GradeCategory{}, nil)
+	suite.repo.On("GetScale", ctx, courseID).Return([]schema.GradeScaleStep{}, nil)
+	suite.repo.On("GetGrades", ctx, courseID, &studentID, true).Return([]Grade{
+		{AssignmentID: assignment.ID, UserID: studentID, Grade: 93},
+	}, nil)
+
+	res, err := suite.useCase.GetMine(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), res.Students, 1)
+	assert.Equal(suite.T(), studentID, res.Students[0].UserID)
+	assert.Equal(suite.T(), "A", res.Students[0].Letter)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestGetMine_NotEnrolled() {
+	courseID := uuid.New()
+	studentID := uuid.New()
+	ctx := testutil.UserCtx(studentID, schema.RoleStudent)
+
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, studentID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, studentID).Return(false, nil)
+
+	_, err := suite.useCase.GetMine(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.Equal(suite.T(), courseenroll.ErrNotEnrolled.Build().Error(), err.Error())
+}
+
+// importFixture sets up a course with one student who has a grade of 70 for its first assignment
+func (suite *GradebookUseCaseTestSuite) importFixture(others ...schema.Assignment) (context.Context, uuid.UUID, schema.Assignment, schema.User) {
+	courseID := uuid.New()
+	ctx := suite.instructorOf(courseID)
+	assignment := schema.Assignment{ID: uuid.New(), Title: "Essay", Weight: 1}
+	student := schema.User{ID: uuid.New(), Name: "Ann"}
+
+	suite.repo.On("GetAssignments", ctx, courseID).Return(append([]schema.Assignment{assignment}, others...), nil)
+	suite.repo.On("GetStudents", ctx, courseID).Return([]schema.User{student}, nil)
+	suite.repo.On("GetGrades", ctx, courseID, (*uuid.UUID)(nil), false).Return([]Grade{
+		{AssignmentID: assignment.ID, UserID: student.ID, Grade: 70},
+	}, nil)
+	return ctx, courseID, assignment, student
+}
+
+func (suite *GradebookUseCaseTestSuite) TestImport_WritesChangedGrades() {
+	other := schema.Assignment{ID: uuid.New(), Title: "Lab", Weight: 1}
+	held := schema.Assignment{ID: uuid.New(), Title: "Exam", Weight: 1, HoldGrades: true}
+	ctx, courseID, assignment, student := suite.importFixture(other, held)
+	suite.repo.On("ImportGrades", ctx, []ImportedGrade{
+		{AssignmentID: other.ID, UserID: student.ID, Grade: 88.5, Release: true},
+		{AssignmentID: held.ID, UserID: student.ID, Grade: 64, Release: false},
+	}).Return(nil)
+
+	csv := fmt.Sprintf("user_id,name,Essay [%s],Lab [%s],Exam [%s],final\n%s,Ann,70.0,88.5,64,74.2\n",
+		assignment.ID, other.ID, held.ID, student.ID)
+	res, err := suite.useCase.Import(ctx, &CourseIDRequest{CourseID: courseID.String()}, bytes.NewBufferString(csv))
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, res.Updated)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *GradebookUseCaseTestSuite) TestExport_EscapesFormulas() {
+	courseID := uuid.New()
+	ctx := suite.instructorOf(courseID)
+	assignment := schema.Assignment{ID: uuid.New(), Title: "=1+1", Weight: 1}
+	student := schema.User{ID: uuid.New(), Name: `=HYPERLINK("http://evil.example","Ann")`, Email: "+ann@example.com"}
+
+	suite.repo.On("GetStudents", ctx, courseID).Return([]schema.User{student}, nil)
+	suite.repo.On("GetAssignments", ctx, courseID).Return([]schema.Assignment{assignment}, nil)
+	suite.repo.On("GetCategories", ctx, courseID).Return([]schema.GradeCategory{}, nil)
+	suite.repo.On("GetScale", ctx, courseID).Return([]schema.GradeScaleStep{{Letter: "-", MinPercent: 0}}, nil)
+	suite.repo.On("GetGrades", ctx, courseID, (*uuid.UUID)(nil), false).Return([]Grade{
+		{AssignmentID: assignment.ID, UserID: student.ID, Grade: 70},
+	}, nil)
+
+	data, err := suite.useCase.Export(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.NoError(suite.T(), err)
+	records, err := csv.NewReader(bytes.NewReader(data)).ReadAll()
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), [][]string{
+		{"user_id", "name", "email", fmt.Sprintf("'=1+1 [%s]", assignment.ID), "final", "letter"},
+		{student.ID.String(), `'=HYPERLINK("http://evil.example","Ann")`, "'+ann@example.com", "70.0", "70.0", "'-"},
+	}, records)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestImport_RejectsInvalidCells() {
+	ctx, courseID, assignment, student := suite.importFixture()
+
+	csv := fmt.Sprintf("user_id,Essay [%s]\n%s,101\n%s,80\n", assignment.ID, student.ID, uuid.New())
+	_, err := suite.useCase.Import(ctx, &CourseIDRequest{CourseID: courseID.String()}, bytes.NewBufferString(csv))
+
+	assert.Equal(suite.T(), http.StatusBadRequest, apierror.GetHttpStatus(err))
+	errs := apierror.GetPayload(err).(map[string]any)["errors"].([]ImportError)
+	assert.Equal(suite.T(), []ImportError{
+		{Row: 2, Column: fmt.Sprintf("Essay [%s]", assignment.ID), Reason: "the grade must be a number from 0 to 100"},
+		{Row: 3, Column: "user_id", Reason: "not a student of the course"},
+	}, errs)
+	suite.repo.AssertNotCalled(suite.T(), "ImportGrades", mock.Anything, mock.Anything)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestSetScale_LowestStepMustStartAtZero() {
+	courseID := uuid.New()
+	ctx := suite.instructorOf(courseID)
+
+	_, err := suite.useCase.SetScale(ctx, &SetScaleRequest{
+		CourseID: courseID.String(),
+		Steps:    []ScaleStepInput{{Letter: "A", MinPercent: 80}, {Letter: "B", MinPercent: 50}},
+	})
+
+	assert.Equal(suite.T(), http.StatusBadRequest, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "ReplaceScale", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestUpdateCategory_OtherInstructor() {
+	courseID := uuid.New()
+	category := &schema.GradeCategory{ID: uuid.New(), CourseID: courseID, Name: "Exams", Weight: 50}
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleInstructor)
+
+	suite.repo.On("GetCategory", ctx, category.ID).Return(category, nil)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.useCase.UpdateCategory(ctx, &UpdateCategoryRequest{ID: category.ID.String(), Weight: testutil.Ptr(70.0)})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "UpdateCategory", mock.Anything, mock.Anything)
+}
+
+// managedCourse sets up a course taught by the returned instructor
+func (suite *GradebookUseCaseTestSuite) managedCourse() (uuid.UUID, uuid.UUID) {
+	instructorID, courseID := uuid.New(), uuid.New()
+	suite.courseRepo.On("GetByID", mock.Anything, courseID).Return(schema.Course{ID: courseID, InstructorID: instructorID}, nil)
+	return courseID, instructorID
+}
+
+func (suite *GradebookUseCaseTestSuite) TestCreateCategoryController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	courseID, instructorID := suite.managedCourse()
+
+	suite.repo.On("CreateCategory", mock.Anything, mock.AnythingOfType("*schema.GradeCategory")).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPost, "/v1/courses/"+courseID.String()+"/gradebook/categories",
+		`{"name": "Homework", "weight": 40, "position": 1}`)
+	rec := testutil.Serve("/v1/courses/:id/gradebook/categories", controller.CreateCategory(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	category := suite.repo.Calls[0].Arguments.Get(1).(*schema.GradeCategory)
+	assert.Equal(suite.T(), courseID, category.CourseID)
+	assert.Equal(suite.T(), 40.0, category.Weight)
+	assert.Equal(suite.T(), 1, category.Position)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestUpdateCategoryController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	courseID, instructorID := suite.managedCourse()
+	category := &schema.GradeCategory{ID: uuid.New(), CourseID: courseID, Name: "Homework", Weight: 40}
+
+	suite.repo.On("GetCategory", mock.Anything, category.ID).Return(category, nil)
+	suite.repo.On("UpdateCategory", mock.Anything, category).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPatch, "/v1/grade-categories/"+category.ID.String(), `{"weight": 25}`)
+	rec := testutil.Serve("/v1/grade-categories/:id", controller.UpdateCategory(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(suite.T(), 25.0, category.Weight)
+	assert.Equal(suite.T(), "Homework", category.Name)
+}
+
+func (suite *GradebookUseCaseTestSuite) TestSetScaleController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	courseID, instructorID := suite.managedCourse()
+	steps := []schema.GradeScaleStep{
+		{CourseID: courseID, Letter: "A", MinPercent: 80},
+		{CourseID: courseID, Letter: "B", MinPercent: 60},
+		{CourseID: courseID, Letter: "C", MinPercent: 0},
+	}
+
+	suite.repo.On("ReplaceScale", mock.Anything, courseID, steps).Return(nil)
+	suite.repo.On("GetScale", mock.Anything, courseID).Return(steps, nil)
+
+	req := testutil.JSONRequest(http.MethodPut, "/v1/courses/"+courseID.String()+"/gradebook/scale",
+		`{"steps": [{"letter": "A", "min_percent": 80}, {"letter": "B", "min_percent": 60}, {"letter": "C", "min_percent": 0}]}`)
+	rec := testutil.Serve("/v1/courses/:id/gradebook/scale", controller.SetScale(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func TestGradebookUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(GradebookUseCaseTestSuite))
+}
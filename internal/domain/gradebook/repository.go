@@ -0,0 +1,221 @@
+package gradebook
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Grade is the grade of a student for an assignment after its grading policy, read from assignment_grades
+type Grade struct {
+	AssignmentID uuid.UUID
+	UserID       uuid.UUID
+	Grade        float64
+}
+
+// ImportedGrade is a grade typed in offline, it is written to the latest attempt of the student and shown to
+// them right away when Release is set
+type ImportedGrade struct {
+	AssignmentID uuid.UUID
+	UserID       uuid.UUID
+	Grade        float64
+	Release      bool
+}
+
+type Repository interface {
+	CreateCategory(ctx context.Context, category *schema.GradeCategory) error
+	UpdateCategory(ctx context.Context, category *schema.GradeCategory) error
+	DeleteCategory(ctx context.Context, id uuid.UUID) error
+	GetCategory(ctx context.Context, id uuid.UUID) (*schema.GradeCategory, error)
+	GetCategories(ctx context.Context, courseID uuid.UUID) ([]schema.GradeCategory, error)
+	GetScale(ctx context.Context, courseID uuid.UUID) ([]schema.GradeScaleStep, error)
+	ReplaceScale(ctx context.Context, courseID uuid.UUID, steps []schema.GradeScaleStep) error
+	GetAssignments(ctx context.Context, courseID uuid.UUID) ([]schema.Assignment, error)
+	GetStudents(ctx context.Context, courseID uuid.UUID) ([]schema.User, error)
+	GetGrades(ctx context.Context, courseID uuid.UUID, userID *uuid.UUID, released bool) ([]Grade, error)
+	ImportGrades(ctx context.Context, grades []ImportedGrade) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateCategory(ctx context.Context, category *schema.GradeCategory) error {
+	return r.db.WithContext(ctx).Omit("Course").Create(category).Error
+}
+
+func (r *repository) UpdateCategory(ctx context.Context, category *schema.GradeCategory) error {
+	return r.db.WithContext(ctx).Omit("Course").Save(category).Error
+}
+
+// DeleteCategory removes the category, its assignments are left without one
+func (r *repository) DeleteCategory(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.GradeCategory{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetCategory(ctx context.Context, id uuid.UUID) (*schema.GradeCategory, error) {
+	var category schema.GradeCategory
+	if err := r.db.WithContext(ctx).First(&category, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &category, nil
+}
+
+func (r *repository) GetCategories(ctx context.Context, courseID uuid.UUID) ([]schema.GradeCategory, error) {
+	var categories []schema.GradeCategory
+	err := r.db.WithContext(ctx).
+		Where("course_id = ?", courseID).
+		Order("position, created_at").
+		Find(&categories).Error
+	return categories, err
+}
+
+// GetScale returns the letter grade scale of the course from the highest step down
+func (r *repository) GetScale(ctx context.Context, courseID uuid.UUID) ([]schema.GradeScaleStep, error) {
+	var steps []schema.GradeScaleStep
+	err := r.db.WithContext(ctx).
+		Where("course_id = ?", courseID).
+		Order("min_percent DESC").
+		Find(&steps).Error
+	return steps, err
+}
+
+func (r *repository) ReplaceScale(ctx context.Context, courseID uuid.UUID, steps []schema.GradeScaleStep) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("course_id = ?", courseID).Delete(&schema.GradeScaleStep{}).Error; err != nil {
+			return err
+		}
+		if len(steps) == 0 {
+			return nil
+		}
+		return tx.Omit("Course").Create(&steps).Error
+	})
+}
+
+func (r *repository) GetAssignments(ctx context.Context, courseID uuid.UUID) ([]schema.Assignment, error) {
+	var assignments []schema.Assignment
+	err := r.db.WithContext(ctx).
+		Select("id", "course_id", "title", "due", "category_id", "weight", "grading_policy", "hold_grades", "created_at").
+		Where("course_id = ?", courseID).
+		Order("created_at").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// GetStudents returns the students enrolled in the course together with everyone who still has a grade in it,
+// ordered by name
+func (r *repository) GetStudents(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	enrolled := r.db.Table("course_enrolls").
+		Select("user_id").
+		Where("course_id = ? AND (expires_at IS NULL OR expires_at > ?)", courseID, time.Now())
+	graded := r.db.Table("assignment_grades").
+		Select("user_id").
+		Where("course_id = ?", courseID)
+
+	var users []schema.User
+	err := r.db.WithContext(ctx).
+		Where("id IN (?) OR id IN (?)", enrolled, graded).
+		Order("name, email").
+		Find(&users).Error
+	return users, err
+}
+
+// GetGrades returns the grades of the course, only those of one student when userID is set and only those
+// released to the students when released is set. Assignments that are not graded yet are left out
+func (r *repository) GetGrades(ctx context.Context, courseID uuid.UUID, userID *uuid.UUID, released bool) ([]Grade, error) {
+	column := "grade"
+	if released {
+		column = "released_grade"
+	}
+
+	var grades []Grade
+	query := r.db.WithContext(ctx).Table("assignment_grades").
+		Select("assignment_id, user_id, "+column+" AS grade").
+		Where("course_id = ? AND "+column+" IS NOT NULL", courseID)
+	if userID != nil {
+		query = query.Where("user_id = ?", *userID)
+	}
+	err := query.Scan(&grades).Error
+	return grades, err
+}
+
+// ImportGrades writes every grade to the latest attempt of the student, students who never handed in get
+// an empty attempt carrying the grade. The grade replaces the late penalty as it is the final one
+func (r *repository) ImportGrades(ctx context.Context, grades []ImportedGrade) error {
+	now := time.Now()
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, g := range grades {
+			var releasedAt *time.Time
+			if g.Release {
+				releasedAt = &now
+			}
+
+			var latest schema.Submission
+			err := tx.Where("assignment_id = ? AND user_id = ?", g.AssignmentID, g.UserID).
+				Order("attempt DESC").
+				Limit(1).
+				Find(&latest).Error
+			if err != nil {
+				return err
+			}
+
+			if latest.ID != uuid.Nil {
+				if latest.ReleasedAt != nil {
+					releasedAt = latest.ReleasedAt
+				}
+				if err := tx.Model(&latest).Updates(map[string]any{
+					"status":       schema.SubmissionGraded,
+					"grade":        g.Grade,
+					"late_penalty": 0,
+					"graded_at":    now,
+					"released_at":  releasedAt,
+				}).Error; err != nil {
+					return err
+				}
+				continue
+			}
+
+			var last int
+			if err := tx.Unscoped().Model(&schema.Submission{}).
+				Select("COALESCE(MAX(attempt), 0)").
+				Where("assignment_id = ? AND user_id = ?", g.AssignmentID, g.UserID).
+				Scan(&last).Error; err != nil {
+				return err
+			}
+
+			id, err := uuid.NewV7()
+			if err != nil {
+				return err
+			}
+			submission := schema.Submission{
+				ID:           id,
+				AssignmentID: g.AssignmentID,
+				UserID:       g.UserID,
+				Attempt:      last + 1,
+				Status:       schema.SubmissionGraded,
+				Grade:        &g.Grade,
+				GradedAt:     &now,
+				ReleasedAt:   releasedAt,
+				SubmittedAt:  now,
+			}
+			if err := tx.Omit("Attachments", "RubricScores").Create(&submission).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,212 @@
+package gradebook
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	gradebookGroup := engine.Group("/v1/courses/:id/gradebook", middleware.Authenticate())
+	{
+		gradebookGroup.GET("", controller.Get())
+		gradebookGroup.GET("/me", middleware.RequireRole("student"), controller.GetMine())
+		gradebookGroup.GET("/export", controller.Export())
+		gradebookGroup.POST("/import", controller.Import())
+		gradebookGroup.POST("/categories", controller.CreateCategory())
+		gradebookGroup.PUT("/scale", controller.SetScale())
+	}
+
+	categoryGroup := engine.Group("/v1/grade-categories", middleware.Authenticate())
+	{
+		categoryGroup.PATCH("/:id", controller.UpdateCategory())
+		categoryGroup.DELETE("/:id", controller.DeleteCategory())
+	}
+}
+
+func (c *RestController) Get() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Get(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_GRADEBOOK_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetMine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetMine(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_GRADEBOOK_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Export() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		data, err := c.uc.Export(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		ctx.Header("Content-Disposition", `attachment; filename="gradebook-`+req.CourseID+`.csv"`)
+		ctx.Data(http.StatusOK, "text/csv", data)
+	}
+}
+
+func (c *RestController) Import() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		fileHeader, err := ctx.FormFile("file")
+		if err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+		file, err := fileHeader.Open()
+		if err != nil {
+			err2 := apierror.ErrInternalServer.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), nil).Send(ctx)
+			return
+		}
+		defer file.Close()
+
+		res, err := c.uc.Import(ctx, &req, file)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "IMPORT_GRADEBOOK_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) CreateCategory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := CreateCategoryRequest{CourseID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.CreateCategory(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_GRADE_CATEGORY_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) UpdateCategory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateCategoryRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.UpdateCategory(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_GRADE_CATEGORY_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) DeleteCategory() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CategoryIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.DeleteCategory(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_GRADE_CATEGORY_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) SetScale() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SetScaleRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.SetScale(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "SET_GRADE_SCALE_SUCCESS", res).Send(ctx)
+	}
+}
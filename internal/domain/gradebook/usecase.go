@@ -0,0 +1,277 @@
+package gradebook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo     Repository
+	courseUc *course.UseCase
+	enrollUc *courseenroll.UseCase
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase) *UseCase {
+	return &UseCase{
+		repo:     repo,
+		courseUc: courseUc,
+		enrollUc: enrollUc,
+	}
+}
+
+func currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return uuid.Nil, apierror.ErrTokenInvalid.Build()
+	}
+	return userID, nil
+}
+
+// getManagedCourse parses the course id of the request and returns the course when the current user manages it
+func (uc *UseCase) getManagedCourse(ctx context.Context, idStr string) (*schema.Course, error) {
+	courseID, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	return uc.courseUc.GetManagedCourse(ctx, courseID)
+}
+
+func (uc *UseCase) getManagedCategory(ctx context.Context, idStr string) (*schema.GradeCategory, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	category, err := uc.repo.GetCategory(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrCategoryNotFound.Build()
+		}
+		log.Println("Error getting grade category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if _, err := uc.getManagedCourse(ctx, category.CourseID.String()); err != nil {
+		return nil, err
+	}
+	return category, nil
+}
+
+func (uc *UseCase) CreateCategory(ctx context.Context, req *CreateCategoryRequest) (*schema.GradeCategory, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	category := &schema.GradeCategory{
+		ID:       id,
+		CourseID: courseObj.ID,
+		Name:     req.Name,
+		Weight:   req.Weight,
+		Position: req.Position,
+	}
+	if err := uc.repo.CreateCategory(ctx, category); err != nil {
+		log.Println("Error creating grade category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return category, nil
+}
+
+func (uc *UseCase) UpdateCategory(ctx context.Context, req *UpdateCategoryRequest) (*schema.GradeCategory, error) {
+	category, err := uc.getManagedCategory(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		category.Name = *req.Name
+	}
+	if req.Weight != nil {
+		category.Weight = *req.Weight
+	}
+	if req.Position != nil {
+		category.Position = *req.Position
+	}
+	if err := uc.repo.UpdateCategory(ctx, category); err != nil {
+		log.Println("Error updating grade category: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return category, nil
+}
+
+func (uc *UseCase) DeleteCategory(ctx context.Context, req *CategoryIDRequest) error {
+	category, err := uc.getManagedCategory(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if err := uc.repo.DeleteCategory(ctx, category.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrCategoryNotFound.Build()
+		}
+		log.Println("Error deleting grade category: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+// SetScale replaces the letter grade scale of the course. The lowest step has to start at zero so every
+// final grade gets a letter
+func (uc *UseCase) SetScale(ctx context.Context, req *SetScaleRequest) ([]schema.GradeScaleStep, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	steps := make([]schema.GradeScaleStep, 0, len(req.Steps))
+	letters := make(map[string]bool, len(req.Steps))
+	minimums := make(map[float64]bool, len(req.Steps))
+	for _, s := range req.Steps {
+		if letters[s.Letter] || minimums[s.MinPercent] {
+			return nil, ErrInvalidScale.WithPayload(map[string]any{
+				"reason": fmt.Sprintf("step %s repeats a letter or a minimum", s.Letter),
+			}).Build()
+		}
+		letters[s.Letter], minimums[s.MinPercent] = true, true
+		steps = append(steps, schema.GradeScaleStep{CourseID: courseObj.ID, Letter: s.Letter, MinPercent: s.MinPercent})
+	}
+	if len(steps) > 0 && !minimums[0] {
+		return nil, ErrInvalidScale.WithPayload(map[string]any{
+			"reason": "the lowest step must start at 0",
+		}).Build()
+	}
+
+	if err := uc.repo.ReplaceScale(ctx, courseObj.ID, steps); err != nil {
+		log.Println("Error replacing grade scale: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return uc.getScale(ctx, courseObj.ID)
+}
+
+func (uc *UseCase) getScale(ctx context.Context, courseID uuid.UUID) ([]schema.GradeScaleStep, error) {
+	scale, err := uc.repo.GetScale(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting grade scale: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if len(scale) == 0 {
+		return defaultScale, nil
+	}
+	return scale, nil
+}
+
+// Get returns the whole gradebook of the course for its instructor
+func (uc *UseCase) Get(ctx context.Context, req *CourseIDRequest) (*GradebookResponse, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := uc.repo.GetStudents(ctx, courseObj.ID)
+	if err != nil {
+		log.Println("Error getting gradebook students: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return uc.build(ctx, courseObj.ID, students, nil)
+}
+
+// GetMine returns the row of the current student in the gradebook of the course
+func (uc *UseCase) GetMine(ctx context.Context, req *CourseIDRequest) (*GradebookResponse, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	enrolled, err := uc.enrollUc.CheckEnrollment(ctx, userID, courseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if !enrolled {
+		return nil, courseenroll.ErrNotEnrolled.Build()
+	}
+
+	name, _ := ctx.Value("user.name").(string)
+	email, _ := ctx.Value("user.email").(string)
+	student := schema.User{ID: userID, Name: name, Email: email}
+	return uc.build(ctx, courseID, []schema.User{student}, &userID)
+}
+
+// build puts together the gradebook of the students, with only their released grades when it is for one student
+func (uc *UseCase) build(ctx context.Context, courseID uuid.UUID, students []schema.User, userID *uuid.UUID) (*GradebookResponse, error) {
+	assignments, err := uc.repo.GetAssignments(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting gradebook assignments: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	categories, err := uc.repo.GetCategories(ctx, courseID)
+	if err != nil {
+		log.Println("Error getting grade categories: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	scale, err := uc.getScale(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	grades, err := uc.repo.GetGrades(ctx, courseID, userID, userID != nil)
+	if err != nil {
+		log.Println("Error getting grades: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	byStudent := make(map[uuid.UUID]map[uuid.UUID]*float64, len(students))
+	for _, g := range grades {
+		if byStudent[g.UserID] == nil {
+			byStudent[g.UserID] = make(map[uuid.UUID]*float64)
+		}
+		grade := g.Grade
+		byStudent[g.UserID][g.AssignmentID] = &grade
+	}
+
+	res := &GradebookResponse{
+		Assignments: make([]AssignmentColumn, 0, len(assignments)),
+		Categories:  categories,
+		Scale:       scale,
+		Students:    make([]StudentGrades, 0, len(students)),
+	}
+	for _, a := range assignments {
+		res.Assignments = append(res.Assignments, AssignmentColumn{
+			ID:         a.ID,
+			Title:      a.Title,
+			CategoryID: a.CategoryID,
+			Weight:     a.Weight,
+		})
+	}
+	for _, s := range students {
+		studentGrades := make(map[uuid.UUID]*float64, len(assignments))
+		for _, a := range assignments {
+			studentGrades[a.ID] = byStudent[s.ID][a.ID]
+		}
+
+		categoryGrades, final := finalGrade(assignments, categories, studentGrades)
+		res.Students = append(res.Students, StudentGrades{
+			UserID:     s.ID,
+			Name:       s.Name,
+			Email:      s.Email,
+			Grades:     studentGrades,
+			Categories: categoryGrades,
+			Final:      final,
+			Letter:     letterFor(scale, final),
+		})
+	}
+	return res, nil
+}
@@ -0,0 +1,41 @@
+package invite
+
+import (
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type CourseIDRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+}
+
+type CreateInviteRequest struct {
+	CourseID  string     `uri:"id" binding:"required,uuid"`
+	MaxUses   *int       `json:"max_uses" binding:"omitempty,min=1,max=10000"`
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+type DeleteInviteRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+	InviteID string `uri:"inviteId" binding:"required,uuid"`
+}
+
+type CodeRequest struct {
+	Code string `uri:"code" binding:"required,alphanum,max=16"`
+}
+
+type InviteResponse struct {
+	schema.EnrollmentInvite
+	URL string `json:"url"`
+}
+
+type InvitePreviewResponse struct {
+	Code          string     `json:"code"`
+	CourseID      uuid.UUID  `json:"course_id"`
+	CourseTitle   string     `json:"course_title"`
+	CourseImage   string     `json:"course_image_url"`
+	ExpiresAt     *time.Time `json:"expires_at"`
+	RemainingUses *int       `json:"remaining_uses"`
+}
@@ -0,0 +1,25 @@
+package invite
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrInviteNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("INVITE_NOT_FOUND")
+
+	ErrInviteUnavailable = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusGone).
+				WithMessage("INVITE_EXPIRED_OR_USED_UP")
+
+	ErrInvalidInvite = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_INVITE")
+
+	ErrAlreadyEnrolled = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COURSE_ALREADY_ENROLLED")
+)
@@ -0,0 +1,207 @@
+package invite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, invite *schema.EnrollmentInvite) error {
+	args := m.Called(ctx, invite)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.EnrollmentInvite, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.EnrollmentInvite), args.Error(1)
+}
+
+func (m *MockRepository) GetByCode(ctx context.Context, code string) (*schema.EnrollmentInvite, error) {
+	args := m.Called(ctx, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.EnrollmentInvite), args.Error(1)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, courseID, id uuid.UUID) error {
+	args := m.Called(ctx, courseID, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Redeem(ctx context.Context, inviteID uuid.UUID, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, inviteID, enroll)
+	return args.Error(0)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type InviteUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	enrollRepo *MockEnrollRepository
+	useCase    *UseCase
+}
+
+func (suite *InviteUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.useCase = NewUseCase(suite.repo, nil, courseenroll.NewUseCase(suite.enrollRepo))
+}
+
+func (suite *InviteUseCaseTestSuite) TestRedeem_Success() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	maxUses := 5
+	invite := &schema.EnrollmentInvite{ID: uuid.New(), CourseID: uuid.New(), Code: "ABCDEFGH23", MaxUses: &maxUses, UsedCount: 4}
+
+	suite.repo.On("GetByCode", ctx, invite.Code).Return(invite, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, invite.CourseID).Return(false, nil)
+	suite.repo.On("Redeem", ctx, invite.ID, mock.AnythingOfType("*schema.CourseEnroll")).Return(nil)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: invite.Code})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, enroll.UserID)
+	assert.Equal(suite.T(), invite.CourseID, enroll.CourseID)
+}
+
+func (suite *InviteUseCaseTestSuite) TestRedeem_Expired() {
+	ctx := context.WithValue(context.Background(), "user.id", uuid.New().String())
+	expiresAt := time.Now().Add(-time.Hour)
+	invite := &schema.EnrollmentInvite{ID: uuid.New(), CourseID: uuid.New(), Code: "ABCDEFGH23", ExpiresAt: &expiresAt}
+
+	suite.repo.On("GetByCode", ctx, invite.Code).Return(invite, nil)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: invite.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrInviteUnavailable.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *InviteUseCaseTestSuite) TestRedeem_LastUseTakenConcurrently() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	invite := &schema.EnrollmentInvite{ID: uuid.New(), CourseID: uuid.New(), Code: "ABCDEFGH23"}
+
+	suite.repo.On("GetByCode", ctx, invite.Code).Return(invite, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, invite.CourseID).Return(false, nil)
+	suite.repo.On("Redeem", ctx, invite.ID, mock.AnythingOfType("*schema.CourseEnroll")).Return(gorm.ErrRecordNotFound)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: invite.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrInviteUnavailable.Build().Error(), err.Error())
+}
+
+func (suite *InviteUseCaseTestSuite) TestRedeem_AlreadyEnrolled() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	invite := &schema.EnrollmentInvite{ID: uuid.New(), CourseID: uuid.New(), Code: "ABCDEFGH23"}
+
+	suite.repo.On("GetByCode", ctx, invite.Code).Return(invite, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, invite.CourseID).Return(true, nil)
+
+	enroll, err := suite.useCase.Redeem(ctx, &CodeRequest{Code: invite.Code})
+
+	assert.Nil(suite.T(), enroll)
+	assert.Equal(suite.T(), ErrAlreadyEnrolled.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Redeem", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *InviteUseCaseTestSuite) TestPreview_RemainingUses() {
+	ctx := context.Background()
+	maxUses := 10
+	invite := &schema.EnrollmentInvite{
+		ID:        uuid.New(),
+		CourseID:  uuid.New(),
+		Code:      "ABCDEFGH23",
+		MaxUses:   &maxUses,
+		UsedCount: 3,
+		Course:    schema.Course{Title: "Go Fundamentals"},
+	}
+
+	suite.repo.On("GetByCode", ctx, invite.Code).Return(invite, nil)
+
+	res, err := suite.useCase.Preview(ctx, &CodeRequest{Code: invite.Code})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "Go Fundamentals", res.CourseTitle)
+	assert.Equal(suite.T(), 7, *res.RemainingUses)
+}
+
+func (suite *InviteUseCaseTestSuite) TestPreview_NotFound() {
+	ctx := context.Background()
+
+	suite.repo.On("GetByCode", ctx, "UNKNOWN234").Return(nil, gorm.ErrRecordNotFound)
+
+	res, err := suite.useCase.Preview(ctx, &CodeRequest{Code: "UNKNOWN234"})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInviteNotFound.Build().Error(), err.Error())
+}
+
+func TestInviteUseCase(t *testing.T) {
+	suite.Run(t, new(InviteUseCaseTestSuite))
+}
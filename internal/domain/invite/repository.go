@@ -0,0 +1,75 @@
+package invite
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, invite *schema.EnrollmentInvite) error
+	GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.EnrollmentInvite, error)
+	GetByCode(ctx context.Context, code string) (*schema.EnrollmentInvite, error)
+	Delete(ctx context.Context, courseID, id uuid.UUID) error
+	Redeem(ctx context.Context, inviteID uuid.UUID, enroll *schema.CourseEnroll) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, invite *schema.EnrollmentInvite) error {
+	return r.db.WithContext(ctx).Omit("Course").Create(invite).Error
+}
+
+func (r *repository) GetByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.EnrollmentInvite, error) {
+	var invites []schema.EnrollmentInvite
+	err := r.db.WithContext(ctx).Where("course_id = ?", courseID).Order("created_at DESC").Find(&invites).Error
+	return invites, err
+}
+
+func (r *repository) GetByCode(ctx context.Context, code string) (*schema.EnrollmentInvite, error) {
+	var invite schema.EnrollmentInvite
+	if err := r.db.WithContext(ctx).Preload("Course").First(&invite, "code = ?", code).Error; err != nil {
+		return nil, err
+	}
+	return &invite, nil
+}
+
+func (r *repository) Delete(ctx context.Context, courseID, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Where("course_id = ?", courseID).Delete(&schema.EnrollmentInvite{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Redeem takes one use of the invite and enrolls the student atomically,
+// it returns gorm.ErrRecordNotFound when the invite is expired or used up
+func (r *repository) Redeem(ctx context.Context, inviteID uuid.UUID, enroll *schema.CourseEnroll) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&schema.EnrollmentInvite{}).
+			Where("id = ?", inviteID).
+			Where("max_uses IS NULL OR used_count < max_uses").
+			Where("expires_at IS NULL OR expires_at > ?", time.Now()).
+			Update("used_count", gorm.Expr("used_count + 1"))
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return tx.Create(enroll).Error
+	})
+}
@@ -0,0 +1,135 @@
+package invite
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	courseInviteGroup := engine.Group("/v1/courses/:id/invites", middleware.Authenticate())
+	{
+		courseInviteGroup.GET("", controller.GetByCourse())
+		courseInviteGroup.POST("", controller.Create())
+		courseInviteGroup.DELETE("/:inviteId", controller.Delete())
+	}
+
+	inviteGroup := engine.Group("/v1/invites")
+	{
+		inviteGroup.GET("/:code", controller.Preview())
+		inviteGroup.POST("/:code/redeem",
+			middleware.Authenticate(),
+			middleware.RequireRole("student"),
+			controller.Redeem(),
+		)
+	}
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CreateInviteRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_INVITE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByCourse() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByCourse(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_INVITES_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req DeleteInviteRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_INVITE_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) Preview() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CodeRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Preview(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_INVITE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Redeem() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CodeRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Redeem(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "REDEEM_INVITE_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,233 @@
+package invite
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/redeemcode"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+const codeLength = 10
+
+type UseCase struct {
+	repo     Repository
+	courseUc *course.UseCase
+	enrollUc *courseenroll.UseCase
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase) *UseCase {
+	return &UseCase{repo: repo, courseUc: courseUc, enrollUc: enrollUc}
+}
+
+func toResponse(invite schema.EnrollmentInvite) InviteResponse {
+	return InviteResponse{
+		EnrollmentInvite: invite,
+		URL:              config.Env.FrontendUrl + "/invites/" + invite.Code,
+	}
+}
+
+// available reports whether the invite can still be redeemed
+func available(invite *schema.EnrollmentInvite) bool {
+	if invite.ExpiresAt != nil && !invite.ExpiresAt.After(time.Now()) {
+		return false
+	}
+	return invite.MaxUses == nil || invite.UsedCount < *invite.MaxUses
+}
+
+// getManagedCourse parses the course id of the request and returns the course when the current user manages it
+func (uc *UseCase) getManagedCourse(ctx context.Context, idStr string) (*schema.Course, error) {
+	courseID, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	return uc.courseUc.GetManagedCourse(ctx, courseID)
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateInviteRequest) (*InviteResponse, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ExpiresAt != nil && !req.ExpiresAt.After(time.Now()) {
+		return nil, ErrInvalidInvite.WithPayload(map[string]any{
+			"reason": "expires_at must be in the future",
+		}).Build()
+	}
+
+	userID, _ := uuid.Parse(ctx.Value("user.id").(string))
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	invite := schema.EnrollmentInvite{
+		ID:        id,
+		CourseID:  courseObj.ID,
+		CreatedBy: userID,
+		MaxUses:   req.MaxUses,
+		ExpiresAt: req.ExpiresAt,
+	}
+
+	// Retry on the rare code collision
+	for attempt := 0; ; attempt++ {
+		invite.Code, err = redeemcode.Generate(codeLength)
+		if err != nil {
+			log.Println("Error generating invite code: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		err = uc.repo.Create(ctx, &invite)
+		if err == nil {
+			break
+		}
+
+		var pgErr *pgconn.PgError
+		if !errors.As(err, &pgErr) || pgErr.Code != "23505" || attempt == 2 {
+			log.Println("Error creating invite: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+
+	res := toResponse(invite)
+	return &res, nil
+}
+
+func (uc *UseCase) GetByCourse(ctx context.Context, req *CourseIDRequest) ([]InviteResponse, error) {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	invites, err := uc.repo.GetByCourseID(ctx, courseObj.ID)
+	if err != nil {
+		log.Println("Error getting invites: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := make([]InviteResponse, len(invites))
+	for i, invite := range invites {
+		res[i] = toResponse(invite)
+	}
+
+	return res, nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, req *DeleteInviteRequest) error {
+	courseObj, err := uc.getManagedCourse(ctx, req.CourseID)
+	if err != nil {
+		return err
+	}
+
+	inviteID, err := uuid.Parse(req.InviteID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+
+	if err := uc.repo.Delete(ctx, courseObj.ID, inviteID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInviteNotFound.Build()
+		}
+		log.Println("Error deleting invite: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+func (uc *UseCase) getByCode(ctx context.Context, code string) (*schema.EnrollmentInvite, error) {
+	invite, err := uc.repo.GetByCode(ctx, code)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteNotFound.Build()
+		}
+		log.Println("Error getting invite: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	if !available(invite) {
+		return nil, ErrInviteUnavailable.Build()
+	}
+
+	return invite, nil
+}
+
+// Preview shows which course an invite leads to so the frontend can render the invite link
+func (uc *UseCase) Preview(ctx context.Context, req *CodeRequest) (*InvitePreviewResponse, error) {
+	invite, err := uc.getByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &InvitePreviewResponse{
+		Code:        invite.Code,
+		CourseID:    invite.CourseID,
+		CourseTitle: invite.Course.Title,
+		CourseImage: invite.Course.ImageURL,
+		ExpiresAt:   invite.ExpiresAt,
+	}
+	if invite.MaxUses != nil {
+		remaining := *invite.MaxUses - invite.UsedCount
+		res.RemainingUses = &remaining
+	}
+
+	return res, nil
+}
+
+// Redeem enrolls the current student for free, an invite is issued by the instructor
+// so the course price and prerequisite policy do not apply
+func (uc *UseCase) Redeem(ctx context.Context, req *CodeRequest) (*schema.CourseEnroll, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	invite, err := uc.getByCode(ctx, req.Code)
+	if err != nil {
+		return nil, err
+	}
+
+	purchased, err := uc.enrollUc.HasPurchased(ctx, userID, invite.CourseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if purchased {
+		return nil, ErrAlreadyEnrolled.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	enroll := &schema.CourseEnroll{
+		ID:        id,
+		UserID:    userID,
+		CourseID:  invite.CourseID,
+		ExpiresAt: courseenroll.AccessExpiry(invite.Course.AccessDays, now),
+		CreatedAt: now,
+	}
+
+	if err := uc.repo.Redeem(ctx, invite.ID, enroll); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInviteUnavailable.Build()
+		}
+		log.Println("Error redeeming invite: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return enroll, nil
+}
@@ -0,0 +1,51 @@
+package learningpath
+
+import "github.com/google/uuid"
+
+type CreateLearningPathRequest struct {
+	Title       string      `json:"title" binding:"required,max=100"`
+	Description string      `json:"description" binding:"max=1000"`
+	Price       *int64      `json:"price" binding:"omitempty,gte=0"`
+	CourseIDs   []uuid.UUID `json:"course_ids" binding:"required,min=1,max=30"`
+}
+
+type UpdateLearningPathRequest struct {
+	ID          string      `uri:"id" binding:"required,uuid"`
+	Title       string      `json:"title" binding:"max=100"`
+	Description string      `json:"description" binding:"max=1000"`
+	Price       *int64      `json:"price" binding:"omitempty,gte=0"`
+	RemovePrice bool        `json:"remove_price"`
+	CourseIDs   []uuid.UUID `json:"course_ids" binding:"max=30"`
+}
+
+type GetLearningPathsRequest struct {
+	Page  int `form:"page" binding:"required,min=1"`
+	Limit int `form:"limit" binding:"required,min=1,max=30"`
+}
+
+type LearningPathIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type CourseProgress struct {
+	CourseID  uuid.UUID `json:"course_id"`
+	Title     string    `json:"title"`
+	Position  int       `json:"position"`
+	Enrolled  bool      `json:"enrolled"`
+	Progress  float64   `json:"progress"`
+	Completed bool      `json:"completed"`
+}
+
+type LearningPathProgressResponse struct {
+	LearningPathID   uuid.UUID        `json:"learning_path_id"`
+	Progress         float64          `json:"progress"`
+	CompletedCourses int              `json:"completed_courses"`
+	TotalCourses     int              `json:"total_courses"`
+	NextCourseID     *uuid.UUID       `json:"next_course_id"`
+	Courses          []CourseProgress `json:"courses"`
+}
+
+type PurchaseLearningPathResponse struct {
+	Price             int64       `json:"price"`
+	EnrolledCourseIDs []uuid.UUID `json:"enrolled_course_ids"`
+}
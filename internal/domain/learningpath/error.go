@@ -0,0 +1,29 @@
+package learningpath
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrLearningPathNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("LEARNING_PATH_NOT_FOUND")
+
+	ErrInvalidPathCourse = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_LEARNING_PATH_COURSE")
+
+	ErrBundleCourseNotOwned = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("BUNDLE_COURSE_NOT_OWNED")
+
+	ErrLearningPathNotForSale = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusBadRequest).
+					WithMessage("LEARNING_PATH_NOT_FOR_SALE")
+
+	ErrAllCoursesOwned = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("ALL_COURSES_ALREADY_ENROLLED")
+)
@@ -0,0 +1,410 @@
+package learningpath
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, path *schema.LearningPath) error {
+	args := m.Called(ctx, path)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAll(ctx context.Context, page, limit int) ([]schema.LearningPath, int64, error) {
+	args := m.Called(ctx, page, limit)
+	return args.Get(0).([]schema.LearningPath), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.LearningPath, error) {
+	args := m.Called(ctx, id)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.LearningPath), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) Update(ctx context.Context, path *schema.LearningPath) error {
+	args := m.Called(ctx, path)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error {
+	args := m.Called(ctx, buyerID, sellerID, amount, enrolls)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type LearningPathUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	enrollRepo *MockEnrollRepository
+	useCase    *UseCase
+}
+
+func (suite *LearningPathUseCaseTestSuite) SetupTest() {
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, suite.courseRepo, courseUc, enrollUc)
+}
+
+func (suite *LearningPathUseCaseTestSuite) newPath(price *int64, courseIDs ...uuid.UUID) *schema.LearningPath {
+	path := &schema.LearningPath{ID: uuid.New(), CreatorID: uuid.New(), Price: price}
+	for i, courseID := range courseIDs {
+		path.Courses = append(path.Courses, schema.LearningPathCourse{
+			LearningPathID: path.ID,
+			CourseID:       courseID,
+			Position:       i + 1,
+			Course:         schema.Course{ID: courseID},
+		})
+	}
+	return path
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestCreate_PricedPathWithForeignCourse() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	ownCourse, foreignCourse := uuid.New(), uuid.New()
+	price := int64(100000)
+
+	suite.courseRepo.On("GetByID", ctx, ownCourse).Return(schema.Course{ID: ownCourse, InstructorID: userID}, nil)
+	suite.courseRepo.On("GetByID", ctx, foreignCourse).Return(schema.Course{ID: foreignCourse, InstructorID: uuid.New()}, nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateLearningPathRequest{
+		Title:     "Backend Engineer",
+		Price:     &price,
+		CourseIDs: []uuid.UUID{ownCourse, foreignCourse},
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrBundleCourseNotOwned.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestCreate_DuplicateCourse() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	courseID := uuid.New()
+
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID}, nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateLearningPathRequest{
+		Title:     "Backend Engineer",
+		CourseIDs: []uuid.UUID{courseID, courseID},
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInvalidPathCourse.Build().Error(), err.Error())
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestGetProgress() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	first, second, third := uuid.New(), uuid.New(), uuid.New()
+	path := suite.newPath(nil, first, second, third)
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, first).Return(&schema.CourseEnroll{}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, second).Return(&schema.CourseEnroll{}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, third).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, first, userID).Return(100.0, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, second, userID).Return(50.0, nil)
+
+	res, err := suite.useCase.GetProgress(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 50.0, res.Progress)
+	assert.Equal(suite.T(), 1, res.CompletedCourses)
+	assert.Equal(suite.T(), 3, res.TotalCourses)
+	assert.Equal(suite.T(), second, *res.NextCourseID)
+	assert.False(suite.T(), res.Courses[2].Enrolled)
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestPurchase_NotForSale() {
+	ctx := context.WithValue(context.Background(), "user.id", uuid.New().String())
+	path := suite.newPath(nil, uuid.New())
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+
+	res, err := suite.useCase.Purchase(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrLearningPathNotForSale.Build().Error(), err.Error())
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestPurchase_EnrollsMissingCourses() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	owned, missing := uuid.New(), uuid.New()
+	price := int64(150000)
+	path := suite.newPath(&price, owned, missing)
+	path.Courses[0].Course.Price = 50000
+	path.Courses[1].Course.Price = 150000
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{owned, missing}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, owned).Return(true, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, missing).Return(false, nil)
+	// the owned course is a quarter of the path's worth, so is the discount
+	suite.repo.On("Purchase", ctx, userID, path.CreatorID, int64(112500), mock.MatchedBy(func(enrolls []schema.CourseEnroll) bool {
+		return len(enrolls) == 1 && enrolls[0].CourseID == missing && enrolls[0].UserID == userID
+	})).Return(nil)
+
+	res, err := suite.useCase.Purchase(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(112500), res.Price)
+	assert.Equal(suite.T(), []uuid.UUID{missing}, res.EnrolledCourseIDs)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestPurchase_PrerequisitesNotMet() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	courseID, prerequisiteID := uuid.New(), uuid.New()
+	price := int64(150000)
+	path := suite.newPath(&price, courseID)
+	path.Courses[0].Course.PrerequisitePolicy = schema.PrerequisiteBlock
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseID).Return(false, nil)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(path.Courses[0].Course, nil)
+	suite.courseRepo.On("GetPrerequisites", ctx, courseID).Return([]schema.Course{{ID: prerequisiteID}}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, prerequisiteID).Return(&schema.CourseEnroll{}, nil)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+	suite.courseRepo.On("GetUserCourseProgress", ctx, prerequisiteID, userID).Return(50.0, nil)
+
+	res, err := suite.useCase.Purchase(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), course.ErrPrerequisitesNotMet.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Purchase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestPurchase_DeletedCourse() {
+	ctx := context.WithValue(context.Background(), "user.id", uuid.New().String())
+	price := int64(150000)
+	path := suite.newPath(&price, uuid.New(), uuid.New())
+	// A soft deleted course preloads as an empty course
+	path.Courses[1].Course = schema.Course{}
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+
+	res, err := suite.useCase.Purchase(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), course.ErrPackageCourseUnavailable.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Purchase", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *LearningPathUseCaseTestSuite) TestPurchase_InsufficientBalance() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	courseID := uuid.New()
+	price := int64(150000)
+	path := suite.newPath(&price, courseID)
+
+	suite.repo.On("GetByID", ctx, path.ID).Return(path, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseID).Return(false, nil)
+	suite.repo.On("Purchase", ctx, userID, path.CreatorID, price, mock.Anything).
+		Return(apierror.ErrInsufficientBalance.Build())
+
+	res, err := suite.useCase.Purchase(ctx, &LearningPathIDRequest{ID: path.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), apierror.ErrInsufficientBalance.Build(), err)
+}
+
+func TestLearningPathUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(LearningPathUseCaseTestSuite))
+}
@@ -0,0 +1,114 @@
+package learningpath
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, path *schema.LearningPath) error
+	GetAll(ctx context.Context, page, limit int) ([]schema.LearningPath, int64, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.LearningPath, error)
+	Update(ctx context.Context, path *schema.LearningPath) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error
+}
+
+type repository struct {
+	db         *gorm.DB
+	walletRepo wallet.IRepository
+}
+
+func NewRepository(db *gorm.DB, walletRepo wallet.IRepository) Repository {
+	return &repository{db: db, walletRepo: walletRepo}
+}
+
+func (r *repository) Create(ctx context.Context, path *schema.LearningPath) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Courses").Create(path).Error; err != nil {
+			return err
+		}
+
+		return r.createCourses(tx, path.Courses)
+	})
+}
+
+func (r *repository) GetAll(ctx context.Context, page, limit int) ([]schema.LearningPath, int64, error) {
+	var paths []schema.LearningPath
+	var total int64
+
+	if err := r.db.WithContext(ctx).Model(&schema.LearningPath{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := r.preloadCourses(r.db.WithContext(ctx)).
+		Order("created_at DESC").
+		Offset((page - 1) * limit).
+		Limit(limit).
+		Find(&paths).Error
+
+	return paths, total, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.LearningPath, error) {
+	var path schema.LearningPath
+	if err := r.preloadCourses(r.db.WithContext(ctx)).First(&path, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &path, nil
+}
+
+func (r *repository) Update(ctx context.Context, path *schema.LearningPath) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Courses").Save(path).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("learning_path_id = ?", path.ID).Delete(&schema.LearningPathCourse{}).Error; err != nil {
+			return err
+		}
+
+		return r.createCourses(tx, path.Courses)
+	})
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.LearningPath{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Purchase charges the buyer and enrolls them in the given courses atomically
+func (r *repository) Purchase(ctx context.Context, buyerID, sellerID uuid.UUID, amount int64, enrolls []schema.CourseEnroll) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.walletRepo.TransferByUserID(tx, buyerID, sellerID, amount); err != nil {
+			return err
+		}
+
+		return tx.Create(&enrolls).Error
+	})
+}
+
+func (r *repository) createCourses(tx *gorm.DB, courses []schema.LearningPathCourse) error {
+	if len(courses) == 0 {
+		return nil
+	}
+	return tx.Omit("Course").Create(&courses).Error
+}
+
+func (r *repository) preloadCourses(tx *gorm.DB) *gorm.DB {
+	return tx.
+		Preload("Courses", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position")
+		}).
+		Preload("Courses.Course")
+}
@@ -0,0 +1,178 @@
+package learningpath
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	pathGroup := engine.Group("/v1/learning-paths")
+	{
+		pathGroup.GET("", controller.GetAll())
+		pathGroup.GET("/:id", controller.GetByID())
+		pathGroup.POST("",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("instructor"),
+			controller.Create(),
+		)
+		pathGroup.PATCH("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.Update(),
+		)
+		pathGroup.DELETE("/:id",
+			middleware.Authenticate(),
+			middleware.RequireRole("instructor"),
+			controller.Delete(),
+		)
+		pathGroup.GET("/:id/progress", middleware.Authenticate(), controller.GetProgress())
+		pathGroup.POST("/:id/purchase",
+			middleware.Authenticate(),
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("student"),
+			controller.Purchase(),
+		)
+	}
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CreateLearningPathRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_LEARNING_PATH_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetAll() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req GetLearningPathsRequest
+		if err := ctx.ShouldBindQuery(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetAll(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_LEARNING_PATHS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetByID(ctx, ctx.Param("id"))
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_LEARNING_PATH_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateLearningPathRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Update(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_LEARNING_PATH_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req LearningPathIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_LEARNING_PATH_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) GetProgress() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req LearningPathIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetProgress(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_LEARNING_PATH_PROGRESS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Purchase() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req LearningPathIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Purchase(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "PURCHASE_LEARNING_PATH_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,316 @@
+package learningpath
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/pagination"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo       Repository
+	courseRepo course.Repository
+	courseUc   *course.UseCase
+	enrollUc   *courseenroll.UseCase
+}
+
+func NewUseCase(repo Repository, courseRepo course.Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase) *UseCase {
+	return &UseCase{repo: repo, courseRepo: courseRepo, courseUc: courseUc, enrollUc: enrollUc}
+}
+
+// buildCourses validates the ordered course list of a path. Paths with a bundle price may only contain
+// the creator's own courses so the whole payment goes to a single instructor wallet.
+func (uc *UseCase) buildCourses(ctx context.Context, pathID, creatorID uuid.UUID, courseIDs []uuid.UUID, priced bool) ([]schema.LearningPathCourse, error) {
+	var sellerID *uuid.UUID
+	if priced {
+		sellerID = &creatorID
+	}
+
+	found, err := uc.courseUc.PackageCourses(ctx, courseIDs, sellerID)
+	if err != nil {
+		var courseErr *course.PackageCourseError
+		if errors.As(err, &courseErr) {
+			if courseErr.NotOwned {
+				return nil, ErrBundleCourseNotOwned.WithPayload(map[string]any{
+					"course_id": courseErr.CourseID,
+				}).Build()
+			}
+			return nil, ErrInvalidPathCourse.WithPayload(map[string]any{
+				"course_id": courseErr.CourseID,
+				"reason":    courseErr.Reason,
+			}).Build()
+		}
+		return nil, err
+	}
+
+	courses := make([]schema.LearningPathCourse, len(found))
+	for i, courseObj := range found {
+		courses[i] = schema.LearningPathCourse{
+			LearningPathID: pathID,
+			CourseID:       courseObj.ID,
+			Position:       i + 1,
+			Course:         courseObj,
+		}
+	}
+	return courses, nil
+}
+
+func (uc *UseCase) getOwnedPath(ctx context.Context, idStr string) (*schema.LearningPath, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	path, err := uc.getPath(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.CreatorID != userID {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+
+	return path, nil
+}
+
+func (uc *UseCase) getPath(ctx context.Context, id uuid.UUID) (*schema.LearningPath, error) {
+	path, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrLearningPathNotFound.Build()
+		}
+		log.Println("Error getting learning path: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return path, nil
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateLearningPathRequest) (*schema.LearningPath, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	courses, err := uc.buildCourses(ctx, id, userID, req.CourseIDs, req.Price != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	path := &schema.LearningPath{
+		ID:          id,
+		Title:       req.Title,
+		Description: req.Description,
+		CreatorID:   userID,
+		Price:       req.Price,
+		Courses:     courses,
+	}
+
+	if err := uc.repo.Create(ctx, path); err != nil {
+		log.Println("Error creating learning path: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return path, nil
+}
+
+func (uc *UseCase) GetAll(ctx context.Context, req *GetLearningPathsRequest) (*pagination.GetResourcePaginatedResponse, error) {
+	paths, total, err := uc.repo.GetAll(ctx, req.Page, req.Limit)
+	if err != nil {
+		log.Println("Error getting learning paths: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return &pagination.GetResourcePaginatedResponse{
+		Data:       paths,
+		Pagination: pagination.NewPagination(int(total), req.Page, req.Limit),
+	}, nil
+}
+
+func (uc *UseCase) GetByID(ctx context.Context, idStr string) (*schema.LearningPath, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	return uc.getPath(ctx, id)
+}
+
+func (uc *UseCase) Update(ctx context.Context, req *UpdateLearningPathRequest) (*schema.LearningPath, error) {
+	path, err := uc.getOwnedPath(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != "" {
+		path.Title = req.Title
+	}
+	if req.Description != "" {
+		path.Description = req.Description
+	}
+	if req.RemovePrice {
+		path.Price = nil
+	} else if req.Price != nil {
+		path.Price = req.Price
+	}
+
+	courseIDs := req.CourseIDs
+	if len(courseIDs) == 0 {
+		for _, pathCourse := range path.Courses {
+			courseIDs = append(courseIDs, pathCourse.CourseID)
+		}
+	}
+
+	path.Courses, err = uc.buildCourses(ctx, path.ID, path.CreatorID, courseIDs, path.Price != nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Update(ctx, path); err != nil {
+		log.Println("Error updating learning path: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return path, nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, req *LearningPathIDRequest) error {
+	path, err := uc.getOwnedPath(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, path.ID); err != nil {
+		log.Println("Error deleting learning path: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+// GetProgress reports the user's progress through each course of the path, overall progress is the
+// average across all courses with courses the user is not enrolled in counting as zero
+func (uc *UseCase) GetProgress(ctx context.Context, req *LearningPathIDRequest) (*LearningPathProgressResponse, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	path, err := uc.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	res := &LearningPathProgressResponse{
+		LearningPathID: path.ID,
+		TotalCourses:   len(path.Courses),
+		Courses:        make([]CourseProgress, 0, len(path.Courses)),
+	}
+
+	var totalProgress float64
+	for _, pathCourse := range path.Courses {
+		progress := CourseProgress{
+			CourseID: pathCourse.CourseID,
+			Title:    pathCourse.Course.Title,
+			Position: pathCourse.Position,
+		}
+
+		progress.Enrolled, err = uc.enrollUc.CheckEnrollment(ctx, userID, pathCourse.CourseID)
+		if err != nil {
+			log.Println("Error checking enrollment: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		if progress.Enrolled {
+			progress.Progress, err = uc.courseRepo.GetUserCourseProgress(ctx, pathCourse.CourseID, userID)
+			if err != nil {
+				log.Println("Error getting course progress: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			progress.Completed = progress.Progress >= 100
+		}
+
+		if progress.Completed {
+			res.CompletedCourses++
+		} else if res.NextCourseID == nil {
+			courseID := pathCourse.CourseID
+			res.NextCourseID = &courseID
+		}
+
+		totalProgress += progress.Progress
+		res.Courses = append(res.Courses, progress)
+	}
+
+	if res.TotalCourses > 0 {
+		res.Progress = totalProgress / float64(res.TotalCourses)
+	}
+
+	return res, nil
+}
+
+// Purchase buys the path bundle, enrolling the student in every course of the path they do not own yet. Like
+// bundles the price is prorated by the share of the courses they already own
+func (uc *UseCase) Purchase(ctx context.Context, req *LearningPathIDRequest) (*PurchaseLearningPathResponse, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	path, err := uc.GetByID(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if path.Price == nil {
+		return nil, ErrLearningPathNotForSale.Build()
+	}
+
+	courses := make([]schema.Course, len(path.Courses))
+	for i, pathCourse := range path.Courses {
+		courses[i] = pathCourse.Course
+	}
+
+	quote, err := uc.courseUc.QuotePackage(ctx, userID, *path.Price, courses)
+	if err != nil {
+		return nil, err
+	}
+	if len(quote.EnrollCourseIDs) == 0 {
+		return nil, ErrAllCoursesOwned.Build()
+	}
+
+	if err := uc.courseUc.CheckPackagePrerequisites(ctx, quote); err != nil {
+		return nil, err
+	}
+
+	enrolls, err := quote.Enrollments()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.Purchase(ctx, userID, path.CreatorID, quote.Price, enrolls); err != nil {
+		var apiErr *apierror.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		log.Println("Error purchasing learning path: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return &PurchaseLearningPathResponse{Price: quote.Price, EnrolledCourseIDs: quote.EnrollCourseIDs}, nil
+}
@@ -0,0 +1,172 @@
+package material
+
+import (
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/sanitize"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+)
+
+// embedProviders is the allow-list of hosts lessons may embed content from, mapped to the provider name
+var embedProviders = map[string]string{
+	"youtube.com":              "youtube",
+	"www.youtube.com":          "youtube",
+	"m.youtube.com":            "youtube",
+	"youtu.be":                 "youtube",
+	"www.youtube-nocookie.com": "youtube",
+	"vimeo.com":                "vimeo",
+	"player.vimeo.com":         "vimeo",
+	"loom.com":                 "loom",
+	"www.loom.com":             "loom",
+	"codepen.io":               "codepen",
+	"codesandbox.io":           "codesandbox",
+	"docs.google.com":          "google_docs",
+	"www.figma.com":            "figma",
+}
+
+var (
+	youtubeIDPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{6,20}$`)
+	vimeoIDPattern   = regexp.MustCompile(`^[0-9]+$`)
+)
+
+// contentInput is the typed content of a material before it is validated
+type contentInput struct {
+	Type            schema.MaterialType
+	BodyFormat      schema.TextFormat
+	Body            string
+	VideoURL        string
+	DurationSeconds int
+	EmbedURL        string
+}
+
+// contentOf returns the stored content of a material, so an update only has to override what changed
+func contentOf(mat *schema.Material) contentInput {
+	in := contentInput{Type: mat.Type, BodyFormat: mat.BodyFormat, Body: mat.Body}
+	switch mat.Type {
+	case schema.MaterialVideo:
+		in.VideoURL = mat.MediaURL
+		if mat.DurationSeconds != nil {
+			in.DurationSeconds = *mat.DurationSeconds
+		}
+	case schema.MaterialEmbed:
+		in.EmbedURL = mat.MediaURL
+	}
+	return in
+}
+
+func invalidContent(reason string) error {
+	return ErrInvalidMaterialContent.WithPayload(map[string]any{"reason": reason}).Build()
+}
+
+// applyContent validates the content for its type and stores it on the material,
+// clearing whatever another type left behind
+func applyContent(mat *schema.Material, in contentInput) error {
+	if in.Type == "" {
+		in.Type = schema.MaterialAttachment
+	}
+
+	mat.Type = in.Type
+	mat.BodyFormat = ""
+	mat.Body = ""
+	mat.MediaURL = ""
+	mat.DurationSeconds = nil
+	mat.EmbedProvider = ""
+
+	switch in.Type {
+	case schema.MaterialText:
+		if strings.TrimSpace(in.Body) == "" {
+			return invalidContent("body is required for text materials")
+		}
+		mat.BodyFormat = in.BodyFormat
+		if mat.BodyFormat == "" {
+			mat.BodyFormat = schema.TextMarkdown
+		}
+		if mat.BodyFormat == schema.TextHTML {
+			mat.Body = sanitize.HTML(in.Body)
+		} else {
+			mat.Body = sanitize.Markdown(in.Body)
+		}
+	case schema.MaterialVideo:
+		u, err := url.Parse(in.VideoURL)
+		if in.VideoURL == "" || err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalidContent("video_url must be an http or https URL")
+		}
+		if in.DurationSeconds <= 0 {
+			return invalidContent("duration_seconds is required for video materials")
+		}
+		mat.MediaURL = u.String()
+		mat.DurationSeconds = &in.DurationSeconds
+	case schema.MaterialEmbed:
+		provider, embedURL, err := resolveEmbed(in.EmbedURL)
+		if err != nil {
+			return err
+		}
+		mat.EmbedProvider = provider
+		mat.MediaURL = embedURL
+	}
+
+	mat.Content = mat.TypedContent()
+	return nil
+}
+
+// resolveEmbed checks the URL against the provider allow-list and turns share links into embeddable ones
+func resolveEmbed(raw string) (provider, embedURL string, err error) {
+	u, err := url.Parse(raw)
+	if raw == "" || err != nil || u.Scheme != "https" || u.Host == "" {
+		return "", "", invalidContent("embed_url must be an https URL")
+	}
+
+	host := strings.ToLower(u.Hostname())
+	provider, ok := embedProviders[host]
+	if !ok {
+		return "", "", ErrEmbedProviderNotAllowed.WithPayload(map[string]any{
+			"host":              host,
+			"allowed_providers": allowedProviders(),
+		}).Build()
+	}
+
+	path := strings.Trim(u.Path, "/")
+	switch provider {
+	case "youtube":
+		id := u.Query().Get("v")
+		if host == "youtu.be" {
+			id = path
+		} else if rest, ok := strings.CutPrefix(path, "embed/"); ok {
+			id = rest
+		} else if rest, ok := strings.CutPrefix(path, "shorts/"); ok {
+			id = rest
+		}
+		if !youtubeIDPattern.MatchString(id) {
+			return "", "", invalidContent("embed_url is not a YouTube video")
+		}
+		return provider, "https://www.youtube.com/embed/" + id, nil
+	case "vimeo":
+		id := strings.TrimPrefix(path, "video/")
+		if !vimeoIDPattern.MatchString(id) {
+			return "", "", invalidContent("embed_url is not a Vimeo video")
+		}
+		return provider, "https://player.vimeo.com/video/" + id, nil
+	case "loom":
+		if rest, ok := strings.CutPrefix(path, "share/"); ok {
+			return provider, "https://www.loom.com/embed/" + rest, nil
+		}
+	}
+
+	u.Host = host
+	u.Fragment = ""
+	return provider, u.String(), nil
+}
+
+func allowedProviders() []string {
+	var providers []string
+	for _, provider := range embedProviders {
+		if !slices.Contains(providers, provider) {
+			providers = append(providers, provider)
+		}
+	}
+	slices.Sort(providers)
+	return providers
+}
@@ -2,6 +2,9 @@ package material
 
 import (
 	"mime/multipart"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 )
 
 type AttachmentInput struct {
@@ -10,12 +13,32 @@ type AttachmentInput struct {
 }
 
 type CreateMaterialRequest struct {
-	CourseID    string `form:"course_id" binding:"required"`
-	Title       string `form:"title" binding:"required"`
-	Description string `form:"description"`
+	CourseID         string              `form:"course_id" binding:"required"`
+	Title            string              `form:"title" binding:"required"`
+	Description      string              `form:"description"`
+	Type             schema.MaterialType `form:"type" binding:"omitempty,oneof=attachment text video embed"`
+	BodyFormat       schema.TextFormat   `form:"body_format" binding:"omitempty,oneof=markdown html"`
+	Body             string              `form:"body" binding:"max=100000"`
+	VideoURL         string              `form:"video_url" binding:"omitempty,url,max=2000"`
+	DurationSeconds  int                 `form:"duration_seconds" binding:"omitempty,min=1,max=86400"`
+	EmbedURL         string              `form:"embed_url" binding:"omitempty,url,max=2000"`
+	IsPreview        bool                `form:"is_preview"`
+	ReleaseAt        *time.Time          `form:"release_at"`
+	ReleaseAfterDays *int                `form:"release_after_days" binding:"omitempty,min=0,max=3650"`
 }
 
 type UpdateMaterialRequest struct {
-	Title       *string `form:"title"`
-	Description *string `form:"description"`
+	Title            *string              `form:"title"`
+	Description      *string              `form:"description"`
+	Type             *schema.MaterialType `form:"type" binding:"omitempty,oneof=attachment text video embed"`
+	BodyFormat       *schema.TextFormat   `form:"body_format" binding:"omitempty,oneof=markdown html"`
+	Body             *string              `form:"body" binding:"omitempty,max=100000"`
+	VideoURL         *string              `form:"video_url" binding:"omitempty,url,max=2000"`
+	DurationSeconds  *int                 `form:"duration_seconds" binding:"omitempty,min=1,max=86400"`
+	EmbedURL         *string              `form:"embed_url" binding:"omitempty,url,max=2000"`
+	IsPreview        *bool                `form:"is_preview"`
+	ReleaseAt        *time.Time           `form:"release_at"`
+	ReleaseAfterDays *int                 `form:"release_after_days" binding:"omitempty,min=0,max=3650"`
+	// ClearRelease drops the current schedule before applying the release fields, releasing the lesson now
+	ClearRelease bool `form:"clear_release"`
 }
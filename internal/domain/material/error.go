@@ -42,4 +42,20 @@ var (
 	ErrEditConflict = apierror.NewApiErrorBuilder().
 			WithHttpStatus(http.StatusConflict).
 			WithMessage("EDIT_CONFLICT")
+
+	ErrInvalidMaterialContent = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusBadRequest).
+					WithMessage("INVALID_MATERIAL_CONTENT")
+
+	ErrEmbedProviderNotAllowed = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusBadRequest).
+					WithMessage("EMBED_PROVIDER_NOT_ALLOWED")
+
+	ErrMaterialLocked = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("MATERIAL_LOCKED")
+
+	ErrMaterialNotReleased = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("MATERIAL_NOT_RELEASED")
 )
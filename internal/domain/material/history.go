@@ -0,0 +1,119 @@
+package material
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// snapshot is the part of a material kept in its history, the body is stored already sanitized
+type snapshot struct {
+	Title            string              `json:"title"`
+	Description      string              `json:"description"`
+	Type             schema.MaterialType `json:"type"`
+	BodyFormat       schema.TextFormat   `json:"body_format"`
+	Body             string              `json:"body"`
+	MediaURL         string              `json:"media_url"`
+	DurationSeconds  *int                `json:"duration_seconds"`
+	EmbedProvider    string              `json:"embed_provider"`
+	IsPreview        bool                `json:"is_preview"`
+	ReleaseAt        *time.Time          `json:"release_at"`
+	ReleaseAfterDays *int                `json:"release_after_days"`
+	Attachments      []schema.Attachment `json:"attachments"`
+}
+
+func snapshotOf(mat *schema.Material) snapshot {
+	attachments := make([]schema.Attachment, len(mat.Attachments))
+	copy(attachments, mat.Attachments)
+
+	return snapshot{
+		Title:            mat.Title,
+		Description:      mat.Description,
+		Type:             mat.Type,
+		BodyFormat:       mat.BodyFormat,
+		Body:             mat.Body,
+		MediaURL:         mat.MediaURL,
+		DurationSeconds:  mat.DurationSeconds,
+		EmbedProvider:    mat.EmbedProvider,
+		IsPreview:        mat.IsPreview,
+		ReleaseAt:        mat.ReleaseAt,
+		ReleaseAfterDays: mat.ReleaseAfterDays,
+		Attachments:      attachments,
+	}
+}
+
+func (s *snapshot) applyTo(mat *schema.Material) {
+	mat.Title = s.Title
+	mat.Description = s.Description
+	mat.Type = s.Type
+	mat.BodyFormat = s.BodyFormat
+	mat.Body = s.Body
+	mat.MediaURL = s.MediaURL
+	mat.DurationSeconds = s.DurationSeconds
+	mat.EmbedProvider = s.EmbedProvider
+	mat.IsPreview = s.IsPreview
+	mat.ReleaseAt = s.ReleaseAt
+	mat.ReleaseAfterDays = s.ReleaseAfterDays
+	mat.Attachments = s.Attachments
+	mat.Content = mat.TypedContent()
+}
+
+func (uc *UseCase) record(ctx context.Context, mat *schema.Material, before *snapshot, restoredFrom *int) error {
+	var from any
+	if before != nil {
+		from = before
+	}
+	_, err := uc.revisionUseCase.Record(ctx, schema.RevisionMaterial, mat.ID, from, snapshotOf(mat), restoredFrom)
+	return err
+}
+
+func (uc *UseCase) GetHistory(ctx context.Context, id uuid.UUID) ([]schema.Revision, error) {
+	return uc.revisionUseCase.GetHistory(ctx, schema.RevisionMaterial, id)
+}
+
+func (uc *UseCase) GetRevision(ctx context.Context, id uuid.UUID, version int) (*schema.Revision, error) {
+	return uc.revisionUseCase.GetVersion(ctx, schema.RevisionMaterial, id, version)
+}
+
+// RestoreRevision brings the material back to an older version, attachments removed since then are
+// linked again and the ones added since are removed. The restore is stored as a new version
+func (uc *UseCase) RestoreRevision(ctx context.Context, id uuid.UUID, version int) (*schema.Revision, error) {
+	mat, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, ErrMaterialNotFound.Build()
+	}
+
+	rev, err := uc.revisionUseCase.GetVersion(ctx, schema.RevisionMaterial, id, version)
+	if err != nil {
+		return nil, err
+	}
+
+	var restored snapshot
+	if err := revision.Decode(rev.Snapshot, &restored); err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	before := snapshotOf(mat)
+	kept := make(map[uuid.UUID]bool)
+	for _, att := range restored.Attachments {
+		kept[att.ID] = true
+	}
+
+	restored.applyTo(mat)
+	if err := uc.repo.Update(ctx, mat); err != nil {
+		return nil, err
+	}
+	for _, att := range before.Attachments {
+		if !kept[att.ID] {
+			if err := uc.attachmentUseCase.DeleteAttachment(ctx, att.ID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return uc.revisionUseCase.Record(ctx, schema.RevisionMaterial, id, before, snapshotOf(mat), &version)
+}
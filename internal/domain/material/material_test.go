@@ -4,14 +4,17 @@ import (
 	"context"
 	"mime/multipart"
 	"testing"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
 )
 
 type MockRepository struct {
@@ -85,12 +88,53 @@ func (m *MockFileUploader) UploadFile(key string, fileHeader *multipart.FileHead
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	args := m.Called(key, content, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	args := m.Called(fileURL, expiry)
+	return args.String(0), args.Error(1)
+}
+
+type MockRevisionRepository struct {
+	mock.Mock
+}
+
+func (m *MockRevisionRepository) Create(ctx context.Context, rev *schema.Revision) error {
+	args := m.Called(ctx, rev)
+	return args.Error(0)
+}
+
+func (m *MockRevisionRepository) GetLatest(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) (*schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepository) GetByItem(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) ([]schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID)
+	return args.Get(0).([]schema.Revision), args.Error(1)
+}
+
+func (m *MockRevisionRepository) GetVersion(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID, version int) (*schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Revision), args.Error(1)
+}
+
 type MaterialUseCaseTestSuite struct {
 	suite.Suite
 	attachmentRepo    *MockAttachmentRepository
 	uploader          *MockFileUploader
 	attachmentUseCase *attachment.UseCase
 	materialRepo      *MockRepository
+	revisionRepo      *MockRevisionRepository
 	materialUseCase   *UseCase
 }
 
@@ -99,7 +143,14 @@ func (suite *MaterialUseCaseTestSuite) SetupTest() {
 	suite.uploader = new(MockFileUploader)
 	suite.materialRepo = new(MockRepository)
 	suite.attachmentUseCase = attachment.NewUseCase(suite.attachmentRepo, suite.uploader)
-	suite.materialUseCase = NewUseCase(suite.materialRepo, suite.attachmentUseCase)
+	suite.revisionRepo = new(MockRevisionRepository)
+	suite.materialUseCase = NewUseCase(suite.materialRepo, suite.attachmentUseCase, revision.NewUseCase(suite.revisionRepo))
+}
+
+// expectFirstRevision lets an edit be stored as the first entries of a history that does not exist yet
+func (suite *MaterialUseCaseTestSuite) expectFirstRevision(ctx context.Context) {
+	suite.revisionRepo.On("GetLatest", ctx, schema.RevisionMaterial, mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	suite.revisionRepo.On("Create", ctx, mock.AnythingOfType("*schema.Revision")).Return(nil)
 }
 
 func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_Success() {
@@ -111,6 +162,7 @@ func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_Success() {
 	}
 
 	suite.materialRepo.On("Create", ctx, mock.Anything).Return(nil)
+	suite.expectFirstRevision(ctx)
 
 	// Call the function under test
 	err := suite.materialUseCase.CreateMaterial(ctx, req)
@@ -120,6 +172,110 @@ func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_Success() {
 	suite.materialRepo.AssertExpectations(suite.T())
 }
 
+func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_TextIsSanitized() {
+	ctx := context.Background()
+	req := CreateMaterialRequest{
+		CourseID:   uuid.New().String(),
+		Title:      "Reactions",
+		Type:       schema.MaterialText,
+		BodyFormat: schema.TextHTML,
+		Body:       `<p onclick="steal()">Safe <b>bold</b></p><script>alert(1)</script><a href="javascript:alert(1)">link</a>`,
+	}
+
+	suite.expectFirstRevision(ctx)
+	suite.materialRepo.On("Create", ctx, mock.AnythingOfType("*schema.Material")).Return(nil).Run(func(args mock.Arguments) {
+		mat := args.Get(1).(*schema.Material)
+		assert.Equal(suite.T(), schema.MaterialText, mat.Type)
+		assert.Equal(suite.T(), `<p>Safe <b>bold</b></p><a rel="nofollow noopener noreferrer">link</a>`, mat.Body)
+	})
+
+	err := suite.materialUseCase.CreateMaterial(ctx, req)
+
+	assert.NoError(suite.T(), err)
+	suite.materialRepo.AssertExpectations(suite.T())
+}
+
+func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_MarkdownLinksAreSanitized() {
+	ctx := context.Background()
+	req := CreateMaterialRequest{
+		CourseID:   uuid.New().String(),
+		Title:      "Reactions",
+		Type:       schema.MaterialText,
+		BodyFormat: schema.TextMarkdown,
+		Body:       "[docs](https://example.com) [click](jav&#x61;script:alert(1)) ![img](javascript:alert(1))",
+	}
+
+	suite.expectFirstRevision(ctx)
+	suite.materialRepo.On("Create", ctx, mock.AnythingOfType("*schema.Material")).Return(nil).Run(func(args mock.Arguments) {
+		mat := args.Get(1).(*schema.Material)
+		assert.Equal(suite.T(), schema.TextMarkdown, mat.BodyFormat)
+		assert.Equal(suite.T(), "[docs](https://example.com) [click](#) ![img](#)", mat.Body)
+	})
+
+	err := suite.materialUseCase.CreateMaterial(ctx, req)
+
+	assert.NoError(suite.T(), err)
+	suite.materialRepo.AssertExpectations(suite.T())
+}
+
+func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_VideoRequiresDuration() {
+	ctx := context.Background()
+	req := CreateMaterialRequest{
+		CourseID: uuid.New().String(),
+		Title:    "Lab walkthrough",
+		Type:     schema.MaterialVideo,
+		VideoURL: "https://cdn.example.com/lab.mp4",
+	}
+
+	err := suite.materialUseCase.CreateMaterial(ctx, req)
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), ErrInvalidMaterialContent.Build().Error(), err.Error())
+	suite.materialRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *MaterialUseCaseTestSuite) TestCreateMaterial_EmbedProviderNotAllowed() {
+	ctx := context.Background()
+	req := CreateMaterialRequest{
+		CourseID: uuid.New().String(),
+		Title:    "External page",
+		Type:     schema.MaterialEmbed,
+		EmbedURL: "https://evil.example.com/frame",
+	}
+
+	err := suite.materialUseCase.CreateMaterial(ctx, req)
+
+	assert.Error(suite.T(), err)
+	assert.Equal(suite.T(), ErrEmbedProviderNotAllowed.Build().Error(), err.Error())
+}
+
+func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_SwitchToEmbed() {
+	ctx := context.Background()
+	materialID := uuid.New()
+	existingMaterial := &schema.Material{
+		ID:         materialID,
+		Title:      "Lesson",
+		Type:       schema.MaterialText,
+		BodyFormat: schema.TextMarkdown,
+		Body:       "# Old lesson",
+	}
+	embedType := schema.MaterialEmbed
+	embedURL := "https://youtu.be/dQw4w9WgXcQ"
+
+	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.expectFirstRevision(ctx)
+
+	err := suite.materialUseCase.UpdateMaterial(ctx, UpdateMaterialRequest{Type: &embedType, EmbedURL: &embedURL}, materialID)
+
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), existingMaterial.Body)
+	assert.Equal(suite.T(), &schema.EmbedContent{
+		Provider: "youtube",
+		URL:      "https://www.youtube.com/embed/dQw4w9WgXcQ",
+	}, existingMaterial.Content)
+}
+
 func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_Success() {
 	ctx := context.Background()
 	materialID := uuid.New()
@@ -138,6 +294,7 @@ func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_Success() {
 
 	// Mocking the repository to return the existing material and handle the update
 	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.expectFirstRevision(ctx)
 	suite.materialRepo.On("Update", ctx, mock.AnythingOfType("*schema.Material")).Return(nil).Run(func(args mock.Arguments) {
 		arg := args.Get(1).(*schema.Material)
 		assert.Equal(suite.T(), updatedTitle, arg.Title)
@@ -152,6 +309,111 @@ func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_Success() {
 	suite.materialRepo.AssertExpectations(suite.T())
 }
 
+func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_MarkAsPreview() {
+	ctx := context.Background()
+	materialID := uuid.New()
+	existingMaterial := &schema.Material{ID: materialID, Title: "Lesson"}
+	isPreview := true
+
+	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.expectFirstRevision(ctx)
+
+	err := suite.materialUseCase.UpdateMaterial(ctx, UpdateMaterialRequest{IsPreview: &isPreview}, materialID)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), existingMaterial.IsPreview)
+	assert.Equal(suite.T(), "Lesson", existingMaterial.Title)
+}
+
+func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_Reschedule() {
+	ctx := context.Background()
+	materialID := uuid.New()
+	releaseAt := time.Now().AddDate(0, 0, 7)
+	existingMaterial := &schema.Material{
+		ID:              materialID,
+		Title:           "Week 2",
+		ReleaseSchedule: schema.ReleaseSchedule{ReleaseAt: &releaseAt},
+	}
+	releaseAfterDays := 14
+
+	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.expectFirstRevision(ctx)
+
+	err := suite.materialUseCase.UpdateMaterial(ctx, UpdateMaterialRequest{
+		ClearRelease:     true,
+		ReleaseAfterDays: &releaseAfterDays,
+	}, materialID)
+
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), existingMaterial.ReleaseAt)
+	assert.Equal(suite.T(), &releaseAfterDays, existingMaterial.ReleaseAfterDays)
+}
+
+func (suite *MaterialUseCaseTestSuite) TestUpdateMaterial_RecordsChanges() {
+	ctx := context.WithValue(context.Background(), "user.id", uuid.New().String())
+	materialID := uuid.New()
+	existingMaterial := &schema.Material{ID: materialID, Title: "Old Title", Type: schema.MaterialAttachment}
+	latest, err := revision.Encode(snapshotOf(existingMaterial))
+	suite.Require().NoError(err)
+	updatedTitle := "New Title"
+
+	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.revisionRepo.On("GetLatest", ctx, schema.RevisionMaterial, materialID).Return(&schema.Revision{
+		Version:  3,
+		Snapshot: latest,
+	}, nil)
+	suite.revisionRepo.On("Create", ctx, mock.MatchedBy(func(rev *schema.Revision) bool {
+		return rev.Version == 4 && rev.EditorID != nil && len(rev.Changes) == 1 &&
+			rev.Changes[0] == schema.FieldChange{Field: "title", From: "Old Title", To: "New Title"}
+	})).Return(nil).Once()
+
+	err = suite.materialUseCase.UpdateMaterial(ctx, UpdateMaterialRequest{Title: &updatedTitle}, materialID)
+
+	assert.NoError(suite.T(), err)
+	suite.revisionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *MaterialUseCaseTestSuite) TestRestoreRevision_BringsBackAttachments() {
+	ctx := context.Background()
+	materialID := uuid.New()
+	oldAttachment := schema.Attachment{ID: uuid.New(), URL: "https://bucket/old.pdf", MaterialID: &materialID}
+	newAttachment := schema.Attachment{ID: uuid.New(), URL: "https://bucket/new.pdf", MaterialID: &materialID}
+	existingMaterial := &schema.Material{
+		ID:          materialID,
+		Title:       "Rewritten",
+		Type:        schema.MaterialAttachment,
+		Attachments: []schema.Attachment{newAttachment},
+	}
+	version1, err := revision.Encode(snapshot{
+		Title:       "Original",
+		Type:        schema.MaterialAttachment,
+		Attachments: []schema.Attachment{oldAttachment},
+	})
+	suite.Require().NoError(err)
+
+	suite.materialRepo.On("GetByID", ctx, materialID).Return(existingMaterial, nil)
+	suite.revisionRepo.On("GetVersion", ctx, schema.RevisionMaterial, materialID, 1).Return(&schema.Revision{
+		Version:  1,
+		Snapshot: version1,
+	}, nil)
+	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.attachmentRepo.On("Delete", ctx, newAttachment.ID).Return(nil)
+	suite.revisionRepo.On("GetLatest", ctx, schema.RevisionMaterial, materialID).Return(&schema.Revision{Version: 2}, nil)
+	suite.revisionRepo.On("Create", ctx, mock.AnythingOfType("*schema.Revision")).Return(nil)
+
+	rev, err := suite.materialUseCase.RestoreRevision(ctx, materialID, 1)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, rev.Version)
+	assert.Equal(suite.T(), 1, *rev.RestoredFrom)
+	assert.Equal(suite.T(), "Original", existingMaterial.Title)
+	assert.Equal(suite.T(), []schema.Attachment{oldAttachment}, existingMaterial.Attachments)
+	suite.attachmentRepo.AssertExpectations(suite.T())
+}
+
 func (suite *MaterialUseCaseTestSuite) TestGetMaterialByID_Success() {
 	ctx := context.Background()
 	materialID := uuid.New()
@@ -228,6 +490,7 @@ func (suite *MaterialUseCaseTestSuite) TestAddAttachment_Success() {
 	suite.uploader.On("UploadFile", mock.AnythingOfType("string"), mock.AnythingOfType("*multipart.FileHeader")).Return(expectedAttachment.URL, nil)
 	suite.attachmentRepo.On("Create", ctx, mock.AnythingOfType("*schema.Attachment")).Return(nil)
 	suite.materialRepo.On("Update", ctx, existingMaterial).Return(nil)
+	suite.expectFirstRevision(ctx)
 
 	err := suite.materialUseCase.AddAttachment(ctx, materialID, req)
 
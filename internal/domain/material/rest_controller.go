@@ -4,11 +4,13 @@ package material
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
 	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
 	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
@@ -24,12 +26,15 @@ func NewRestController(r *gin.Engine, uc *UseCase, cuc *course.UseCase) {
 	materialGroup := r.Group("/v1/materials")
 	{
 		materialGroup.POST("", middleware.Authenticate(), middleware.RequireRole("instructor"), c.create)
-		materialGroup.GET("/:id", c.getByID)
-		materialGroup.GET("/course/:id", c.getMaterialByCourse)
-		materialGroup.GET("", c.getAll)
+		materialGroup.GET("/:id", middleware.OptionalAuthenticate(), c.getByID)
+		materialGroup.GET("/course/:id", middleware.OptionalAuthenticate(), c.getMaterialByCourse)
+		materialGroup.GET("", middleware.Authenticate(), middleware.RequireRole("admin"), c.getAll)
 		materialGroup.PUT("/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.update)
 		materialGroup.DELETE("/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.delete)
 		materialGroup.POST("addAttachment/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.addAttachment)
+		materialGroup.GET("/:id/revisions", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getHistory)
+		materialGroup.GET("/:id/revisions/:version", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getRevision)
+		materialGroup.POST("/:id/revisions/:version/restore", middleware.Authenticate(), middleware.RequireRole("instructor"), c.restoreRevision)
 	}
 
 }
@@ -87,6 +92,13 @@ func (c *RestController) getByID(ctx *gin.Context) {
 		return
 
 	}
+
+	if err := c.verifyMaterialAccess(ctx, mat); err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	c.courseUseCase.OpenMaterial(mat)
+
 	response.NewRestResponse(http.StatusOK, "All Material Retrieve", mat).Send(ctx)
 }
 
@@ -96,6 +108,9 @@ func (c *RestController) getAll(ctx *gin.Context) {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
+	for _, mat := range mats {
+		c.courseUseCase.OpenMaterial(mat)
+	}
 	response.NewRestResponse(http.StatusOK, "All Material Retrieve", mats).Send(ctx)
 }
 
@@ -113,6 +128,11 @@ func (c *RestController) getMaterialByCourse(ctx *gin.Context) {
 		return
 	}
 
+	if err := c.courseUseCase.ApplyContentAccess(ctx, &course); err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+
 	response.NewRestResponse(http.StatusOK, "All Course Material Retrieve", course.Materials).Send(ctx)
 
 }
@@ -194,6 +214,84 @@ func (c *RestController) addAttachment(ctx *gin.Context) {
 	response.NewRestResponse(http.StatusOK, "Add attachment successfully", nil).Send(ctx)
 }
 
+func (c *RestController) getHistory(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		err = apierror.ErrInvalidParamId.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), err.Error()).Send(ctx)
+		return
+	}
+
+	err = c.verifyMaterialOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	revisions, err := c.useCase.GetHistory(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Material history retrieved successfully", revisions).Send(ctx)
+}
+
+func (c *RestController) getRevision(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		err = apierror.ErrInvalidParamId.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), err.Error()).Send(ctx)
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid version", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyMaterialOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rev, err := c.useCase.GetRevision(ctx, id, version)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Material revision retrieved successfully", rev).Send(ctx)
+}
+
+func (c *RestController) restoreRevision(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		err = apierror.ErrInvalidParamId.Build()
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), err.Error()).Send(ctx)
+		return
+	}
+
+	version, err := strconv.Atoi(ctx.Param("version"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid version", nil).Send(ctx)
+		return
+	}
+
+	err = c.verifyMaterialOwnership(ctx, id)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), nil).Send(ctx)
+		return
+	}
+
+	rev, err := c.useCase.RestoreRevision(ctx, id, version)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Material restored successfully", rev).Send(ctx)
+}
+
 func (c *RestController) verifyMaterialOwnership(ctx *gin.Context, materialID uuid.UUID) error {
 
 	mat, err := c.useCase.GetMaterialByID(ctx, materialID)
@@ -215,3 +313,24 @@ func (c *RestController) verifyMaterialOwnership(ctx *gin.Context, materialID uu
 
 	return nil
 }
+
+// verifyMaterialAccess lets everyone open released preview lessons and course members open the other released ones
+func (c *RestController) verifyMaterialAccess(ctx *gin.Context, mat *schema.Material) error {
+	courseData, err := c.courseUseCase.GetByID(ctx, mat.CourseID)
+	if err != nil {
+		return err
+	}
+
+	viewer, err := c.courseUseCase.GetViewer(ctx, &courseData)
+	if err != nil {
+		return err
+	}
+	if !viewer.CanOpen(mat.IsPreview) {
+		return ErrMaterialLocked.Build()
+	}
+	if availableAt := viewer.AvailableAt(&mat.ReleaseSchedule); availableAt != nil {
+		return ErrMaterialNotReleased.WithPayload(map[string]any{"available_at": availableAt}).Build()
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/revision"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 )
@@ -14,10 +15,11 @@ import (
 type UseCase struct {
 	repo              Repository
 	attachmentUseCase *attachment.UseCase // Add this line
+	revisionUseCase   *revision.UseCase
 }
 
-func NewUseCase(repo Repository, attachmentUseCase *attachment.UseCase) *UseCase {
-	return &UseCase{repo: repo, attachmentUseCase: attachmentUseCase}
+func NewUseCase(repo Repository, attachmentUseCase *attachment.UseCase, revisionUseCase *revision.UseCase) *UseCase {
+	return &UseCase{repo: repo, attachmentUseCase: attachmentUseCase, revisionUseCase: revisionUseCase}
 }
 
 func (uc *UseCase) CreateMaterial(ctx context.Context, req CreateMaterialRequest) error {
@@ -39,9 +41,29 @@ func (uc *UseCase) CreateMaterial(ctx context.Context, req CreateMaterialRequest
 		CourseID:    courseId,
 		Title:       req.Title,
 		Description: req.Description,
+		IsPreview:   req.IsPreview,
+		ReleaseSchedule: schema.ReleaseSchedule{
+			ReleaseAt:        req.ReleaseAt,
+			ReleaseAfterDays: req.ReleaseAfterDays,
+		},
 	}
 
-	return uc.repo.Create(ctx, &mat)
+	err = applyContent(&mat, contentInput{
+		Type:            req.Type,
+		BodyFormat:      req.BodyFormat,
+		Body:            req.Body,
+		VideoURL:        req.VideoURL,
+		DurationSeconds: req.DurationSeconds,
+		EmbedURL:        req.EmbedURL,
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Create(ctx, &mat); err != nil {
+		return err
+	}
+	return uc.record(ctx, &mat, nil, nil)
 }
 
 func (uc *UseCase) GetMaterialByID(ctx context.Context, id uuid.UUID) (*schema.Material, error) {
@@ -57,6 +79,7 @@ func (uc *UseCase) UpdateMaterial(ctx context.Context, req UpdateMaterialRequest
 	if err != nil {
 		return ErrMaterialNotFound.Build()
 	}
+	before := snapshotOf(mat)
 
 	// Update the material fields from the request
 	if req.Title != nil {
@@ -65,8 +88,46 @@ func (uc *UseCase) UpdateMaterial(ctx context.Context, req UpdateMaterialRequest
 	if req.Description != nil {
 		mat.Description = *req.Description
 	}
+	if req.IsPreview != nil {
+		mat.IsPreview = *req.IsPreview
+	}
+	if req.ClearRelease {
+		mat.ReleaseSchedule = schema.ReleaseSchedule{}
+	}
+	if req.ReleaseAt != nil {
+		mat.ReleaseAt = req.ReleaseAt
+	}
+	if req.ReleaseAfterDays != nil {
+		mat.ReleaseAfterDays = req.ReleaseAfterDays
+	}
+
+	content := contentOf(mat)
+	if req.Type != nil {
+		content.Type = *req.Type
+	}
+	if req.BodyFormat != nil {
+		content.BodyFormat = *req.BodyFormat
+	}
+	if req.Body != nil {
+		content.Body = *req.Body
+	}
+	if req.VideoURL != nil {
+		content.VideoURL = *req.VideoURL
+	}
+	if req.DurationSeconds != nil {
+		content.DurationSeconds = *req.DurationSeconds
+	}
+	if req.EmbedURL != nil {
+		content.EmbedURL = *req.EmbedURL
+	}
+	if err := applyContent(mat, content); err != nil {
+		return err
+	}
 
-	return uc.repo.Update(ctx, mat)
+	if err := uc.repo.Update(ctx, mat); err != nil {
+		return err
+	}
+	return uc.record(ctx, mat, &before, nil)
 }
 
 func (uc *UseCase) AddAttachment(ctx context.Context, id uuid.UUID, req AttachmentInput) error {
@@ -74,6 +135,7 @@ func (uc *UseCase) AddAttachment(ctx context.Context, id uuid.UUID, req Attachme
 	if err != nil {
 		return ErrMaterialNotFound.Build()
 	}
+	before := snapshotOf(mat)
 
 	if req.File != nil {
 
@@ -85,7 +147,10 @@ func (uc *UseCase) AddAttachment(ctx context.Context, id uuid.UUID, req Attachme
 		mat.Attachments = append(mat.Attachments, attachment)
 	}
 
-	return uc.repo.Update(ctx, mat)
+	if err := uc.repo.Update(ctx, mat); err != nil {
+		return err
+	}
+	return uc.record(ctx, mat, &before, nil)
 }
 
 func (uc *UseCase) DeleteMaterial(ctx context.Context, id uuid.UUID) error {
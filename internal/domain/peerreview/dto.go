@@ -0,0 +1,63 @@
+package peerreview
+
+import (
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/submission"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type AssignmentIDRequest struct {
+	AssignmentID string `uri:"id" binding:"required,uuid"`
+}
+
+// SubmitReviewRequest hands a review in the same way an instructor grades, by rubric or with a plain grade.
+// Feedback is the comment left to the author
+type SubmitReviewRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+	submission.GradeSubmissionRequest
+}
+
+// ReviewTask is a submission the student was asked to review, it never tells who the author is
+type ReviewTask struct {
+	ID              uuid.UUID                `json:"id"`
+	AssignmentID    uuid.UUID                `json:"assignment_id"`
+	AssignmentTitle string                   `json:"assignment_title"`
+	Content         string                   `json:"content"`
+	Attachments     []schema.Attachment      `json:"attachments"`
+	Rubric          []schema.RubricCriterion `json:"rubric"`
+	Grade           *float64                 `json:"grade"`
+	Scores          []schema.PeerReviewScore `json:"scores"`
+	Comment         string                   `json:"comment"`
+	SubmittedAt     *time.Time               `json:"submitted_at"`
+	Open            bool                     `json:"open"`
+}
+
+// ReceivedReview is a review of the work of the student, it never tells who the reviewer is
+type ReceivedReview struct {
+	Grade       *float64                 `json:"grade"`
+	Scores      []schema.PeerReviewScore `json:"scores"`
+	Comment     string                   `json:"comment"`
+	SubmittedAt *time.Time               `json:"submitted_at"`
+}
+
+// SubmissionReviews are the reviews of a submission as the instructor sees them, PeerGrade is the median of
+// the reviews handed in so far
+type SubmissionReviews struct {
+	SubmissionID uuid.UUID               `json:"submission_id"`
+	UserID       uuid.UUID               `json:"user_id"`
+	Attempt      int                     `json:"attempt"`
+	Status       schema.SubmissionStatus `json:"status"`
+	Grade        *float64                `json:"grade"`
+	PeerGrade    *float64                `json:"peer_grade"`
+	Reviews      []schema.PeerReview     `json:"reviews"`
+}
+
+type AssignResponse struct {
+	Reviews int `json:"reviews"`
+}
+
+type FinalizeResponse struct {
+	Graded int `json:"graded"`
+}
@@ -0,0 +1,37 @@
+package peerreview
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrAssignmentNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("ASSIGNMENT_NOT_FOUND")
+
+	ErrReviewNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("PEER_REVIEW_NOT_FOUND")
+
+	ErrPeerReviewDisabled = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("PEER_REVIEW_DISABLED")
+
+	ErrNotDueYet = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("ASSIGNMENT_NOT_DUE_YET")
+
+	ErrAlreadyAssigned = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("PEER_REVIEWS_ALREADY_ASSIGNED")
+
+	ErrNotAssigned = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("PEER_REVIEWS_NOT_ASSIGNED")
+
+	ErrReviewClosed = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("PEER_REVIEW_CLOSED")
+)
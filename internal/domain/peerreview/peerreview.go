@@ -0,0 +1,105 @@
+package peerreview
+
+import (
+	"math"
+	"math/rand"
+	"slices"
+	"sort"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// pairReviewers hands every submission to count enrolled students other than its author. The students with
+// the fewest reviews so far are picked first so the work is spread evenly, ties are broken at random so the
+// pairing does not follow the order the submissions came in. Small courses may leave a submission with fewer
+// reviewers than asked
+func pairReviewers(submissions []schema.Submission, reviewers []uuid.UUID, count int) map[uuid.UUID][]uuid.UUID {
+	pool := slices.Clone(reviewers)
+	rand.Shuffle(len(pool), func(i, j int) {
+		pool[i], pool[j] = pool[j], pool[i]
+	})
+
+	// Authors whose submission is still to be handed out come first among reviewers with the same load,
+	// they will be skipped for their own work and would otherwise end up with fewer reviews than the rest
+	pending := make(map[uuid.UUID]bool, len(submissions))
+	for _, s := range submissions {
+		pending[s.UserID] = true
+	}
+
+	load := make(map[uuid.UUID]int, len(pool))
+	pairs := make(map[uuid.UUID][]uuid.UUID, len(submissions))
+	for _, i := range rand.Perm(len(submissions)) {
+		s := submissions[i]
+		pending[s.UserID] = false
+		sort.SliceStable(pool, func(a, b int) bool {
+			if load[pool[a]] != load[pool[b]] {
+				return load[pool[a]] < load[pool[b]]
+			}
+			return pending[pool[a]] && !pending[pool[b]]
+		})
+
+		picked := make([]uuid.UUID, 0, count)
+		for _, reviewer := range pool {
+			if len(picked) == count {
+				break
+			}
+			if reviewer != s.UserID {
+				picked = append(picked, reviewer)
+			}
+		}
+		for _, reviewer := range picked {
+			load[reviewer]++
+		}
+		pairs[s.ID] = picked
+	}
+	return pairs
+}
+
+// peerGrade is the median of the grades handed in, one unfair reviewer cannot pull it far off. It is rounded
+// to one decimal like the grades of the instructor
+func peerGrade(reviews []schema.PeerReview) *float64 {
+	var grades []float64
+	for _, r := range reviews {
+		if r.SubmittedAt != nil && r.Grade != nil {
+			grades = append(grades, *r.Grade)
+		}
+	}
+	if len(grades) == 0 {
+		return nil
+	}
+
+	sort.Float64s(grades)
+	mid := len(grades) / 2
+	grade := grades[mid]
+	if len(grades)%2 == 0 {
+		grade = (grades[mid-1] + grades[mid]) / 2
+	}
+	grade = math.Round(grade*10) / 10
+	return &grade
+}
+
+// reviewOpen tells whether the submission still takes reviews, it stops once the instructor or the peers
+// graded it or it was returned to the student
+func reviewOpen(s *schema.Submission) bool {
+	return s.Status != schema.SubmissionGraded && s.Status != schema.SubmissionReturned
+}
+
+func peerScores(scores []schema.RubricScore) []schema.PeerReviewScore {
+	if len(scores) == 0 {
+		return nil
+	}
+	res := make([]schema.PeerReviewScore, len(scores))
+	for i, s := range scores {
+		res[i] = schema.PeerReviewScore{
+			CriterionID: s.CriterionID,
+			LevelID:     s.LevelID,
+			Criterion:   s.Criterion,
+			Level:       s.Level,
+			Points:      s.Points,
+			MaxPoints:   s.MaxPoints,
+			Comment:     s.Comment,
+		}
+	}
+	return res
+}
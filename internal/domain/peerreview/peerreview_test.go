@@ -0,0 +1,600 @@
+package peerreview
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/submission"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetDueAssignments(ctx context.Context, now time.Time) ([]schema.Assignment, error) {
+	args := m.Called(ctx, now)
+	return args.Get(0).([]schema.Assignment), args.Error(1)
+}
+
+func (m *MockRepository) GetLatestSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.Submission), args.Error(1)
+}
+
+func (m *MockRepository) GetReviewers(ctx context.Context, courseID uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockRepository) Assign(ctx context.Context, assignmentID uuid.UUID, reviews []schema.PeerReview) (bool, error) {
+	args := m.Called(ctx, assignmentID, reviews)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.PeerReview, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.PeerReview), args.Error(1)
+}
+
+func (m *MockRepository) GetByReviewer(ctx context.Context, reviewerID uuid.UUID) ([]schema.PeerReview, error) {
+	args := m.Called(ctx, reviewerID)
+	return args.Get(0).([]schema.PeerReview), args.Error(1)
+}
+
+func (m *MockRepository) GetBySubmission(ctx context.Context, submissionID uuid.UUID) ([]schema.PeerReview, error) {
+	args := m.Called(ctx, submissionID)
+	return args.Get(0).([]schema.PeerReview), args.Error(1)
+}
+
+func (m *MockRepository) GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.PeerReview, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.PeerReview), args.Error(1)
+}
+
+func (m *MockRepository) GetReceived(ctx context.Context, assignmentID, userID uuid.UUID) ([]schema.PeerReview, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Get(0).([]schema.PeerReview), args.Error(1)
+}
+
+func (m *MockRepository) Save(ctx context.Context, review *schema.PeerReview) error {
+	args := m.Called(ctx, review)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockAssignmentRepo struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockAssignmentRepo) Create(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockAssignmentRepo) Update(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockAssignmentRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockAssignmentRepo) GetByID(ctx context.Context, id uuid.UUID) (*schema.Assignment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*schema.Assignment), args.Error(1)
+}
+
+// GetByCourseID mocks the GetByCourseID method
+func (m *MockAssignmentRepo) GetByCourseID(ctx context.Context, courseId uuid.UUID) ([]*schema.Assignment, error) {
+	args := m.Called(ctx, courseId)
+	return args.Get(0).([]*schema.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error {
+	args := m.Called(ctx, extension)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, teamSetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error) {
+	args := m.Called(ctx, assignmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schema.RubricCriterion), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error {
+	args := m.Called(ctx, assignmentID, criteria)
+	return args.Error(0)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type MockGrader struct {
+	mock.Mock
+}
+
+func (m *MockGrader) ApplyPeerGrades(ctx context.Context, assignmentObj *schema.Assignment, grades map[uuid.UUID]float64) (int, error) {
+	args := m.Called(ctx, assignmentObj, grades)
+	return args.Int(0), args.Error(1)
+}
+
+type MockSigner struct {
+	mock.Mock
+}
+
+func (m *MockSigner) SignURLs(attachments []schema.Attachment) {
+	m.Called(attachments)
+}
+
+type PeerReviewUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	assignmentRepo   *MockAssignmentRepo
+	courseRepo       *MockCourseRepository
+	grader           *MockGrader
+	signer           *MockSigner
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *PeerReviewUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.assignmentRepo = new(MockAssignmentRepo)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.grader = new(MockGrader)
+	suite.signer = new(MockSigner)
+	suite.notificationRepo = new(MockNotificationRepository)
+	suite.notificationRepo.On("Create", mock.Anything).Return(nil).Maybe()
+	suite.useCase = NewUseCase(suite.repo, suite.assignmentRepo, suite.courseRepo, suite.grader, suite.signer,
+		suite.notificationRepo)
+}
+
+// instructorOf sets up the assignment in a course taught by the returned instructor
+func (suite *PeerReviewUseCaseTestSuite) instructorOf(assignmentObj *schema.Assignment) context.Context {
+	instructorID := uuid.New()
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+	suite.assignmentRepo.On("GetByID", ctx, assignmentObj.ID).Return(assignmentObj, nil)
+	suite.courseRepo.On("GetByID", ctx, assignmentObj.CourseID).Return(schema.Course{ID: assignmentObj.CourseID, InstructorID: instructorID}, nil)
+	return ctx
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestPairReviewers_SpreadsReviewsEvenly() {
+	var submissions []schema.Submission
+	var students []uuid.UUID
+	for i := 0; i < 6; i++ {
+		s := schema.Submission{ID: uuid.New(), UserID: uuid.New()}
+		submissions = append(submissions, s)
+		students = append(students, s.UserID)
+	}
+
+	pairs := pairReviewers(submissions, students, 3)
+
+	load := make(map[uuid.UUID]int)
+	for _, s := range submissions {
+		picked := pairs[s.ID]
+		assert.Len(suite.T(), picked, 3)
+		assert.NotContains(suite.T(), picked, s.UserID)
+		seen := make(map[uuid.UUID]bool)
+		for _, reviewer := range picked {
+			assert.False(suite.T(), seen[reviewer], "reviewer picked twice for the same submission")
+			seen[reviewer] = true
+			load[reviewer]++
+		}
+	}
+	for _, student := range students {
+		assert.Equal(suite.T(), 3, load[student])
+	}
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestPairReviewers_SmallCourse() {
+	a := schema.Submission{ID: uuid.New(), UserID: uuid.New()}
+	b := schema.Submission{ID: uuid.New(), UserID: uuid.New()}
+
+	pairs := pairReviewers([]schema.Submission{a, b}, []uuid.UUID{a.UserID, b.UserID}, 3)
+
+	assert.Equal(suite.T(), []uuid.UUID{b.UserID}, pairs[a.ID])
+	assert.Equal(suite.T(), []uuid.UUID{a.UserID}, pairs[b.ID])
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestPeerGrade_Median() {
+	now := time.Now()
+	review := func(grade float64) schema.PeerReview {
+		return schema.PeerReview{Grade: testutil.Ptr(grade), SubmittedAt: &now}
+	}
+
+	assert.Equal(suite.T(), 80.0, *peerGrade([]schema.PeerReview{review(70), review(95), review(80)}))
+	assert.Equal(suite.T(), 77.5, *peerGrade([]schema.PeerReview{review(70), review(85)}))
+	// Reviews that were not handed in do not count
+	assert.Equal(suite.T(), 70.0, *peerGrade([]schema.PeerReview{review(70), {}}))
+	assert.Nil(suite.T(), peerGrade([]schema.PeerReview{{}}))
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestAssign_NotDueYet() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 2, Due: testutil.Ptr(time.Now().Add(time.Hour))}
+	ctx := suite.instructorOf(assignmentObj)
+
+	_, err := suite.useCase.Assign(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.Equal(suite.T(), ErrNotDueYet.Build(), err)
+	suite.repo.AssertNotCalled(suite.T(), "Assign", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestAssign_HandsOutReviews() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 2, Due: testutil.Ptr(time.Now().Add(-time.Hour))}
+	ctx := suite.instructorOf(assignmentObj)
+
+	var submissions []schema.Submission
+	var students []uuid.UUID
+	for i := 0; i < 3; i++ {
+		s := schema.Submission{ID: uuid.New(), AssignmentID: assignmentObj.ID, UserID: uuid.New()}
+		submissions = append(submissions, s)
+		students = append(students, s.UserID)
+	}
+	suite.repo.On("GetLatestSubmissions", ctx, assignmentObj.ID).Return(submissions, nil)
+	suite.repo.On("GetReviewers", ctx, assignmentObj.CourseID).Return(students, nil)
+	suite.repo.On("Assign", ctx, assignmentObj.ID, mock.MatchedBy(func(reviews []schema.PeerReview) bool {
+		return len(reviews) == 6
+	})).Return(true, nil)
+
+	res, err := suite.useCase.Assign(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 6, res.Reviews)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestAssignDue_SkipsAssignmentsHandedOutMeanwhile() {
+	assignmentObj := schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 1, Due: testutil.Ptr(time.Now().Add(-time.Hour))}
+	s := schema.Submission{ID: uuid.New(), AssignmentID: assignmentObj.ID, UserID: uuid.New()}
+	ctx := context.Background()
+
+	suite.repo.On("GetDueAssignments", ctx, mock.Anything).Return([]schema.Assignment{assignmentObj}, nil)
+	suite.repo.On("GetLatestSubmissions", ctx, assignmentObj.ID).Return([]schema.Submission{s}, nil)
+	suite.repo.On("GetReviewers", ctx, assignmentObj.CourseID).Return([]uuid.UUID{s.UserID, uuid.New()}, nil)
+	suite.repo.On("Assign", ctx, assignmentObj.ID, mock.Anything).Return(false, nil)
+
+	n, err := suite.useCase.AssignDue(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+	suite.notificationRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+// reviewFixture sets up a review of a submission without a rubric, handed to the returned reviewer
+func (suite *PeerReviewUseCaseTestSuite) reviewFixture(status schema.SubmissionStatus) (context.Context, *schema.Assignment, *schema.PeerReview) {
+	reviewerID := uuid.New()
+	ctx := testutil.UserCtx(reviewerID, schema.RoleStudent)
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 2}
+	review := &schema.PeerReview{
+		ID:           uuid.New(),
+		AssignmentID: assignmentObj.ID,
+		SubmissionID: uuid.New(),
+		ReviewerID:   reviewerID,
+	}
+	review.Submission = schema.Submission{ID: review.SubmissionID, AssignmentID: assignmentObj.ID, UserID: uuid.New(), Status: status}
+
+	suite.repo.On("GetByID", ctx, review.ID).Return(review, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignmentObj.ID).Return(assignmentObj, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignmentObj.ID).Return([]schema.RubricCriterion{}, nil)
+	return ctx, assignmentObj, review
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestSubmit_NotReviewer() {
+	_, _, review := suite.reviewFixture(schema.SubmissionSubmitted)
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	suite.repo.On("GetByID", ctx, review.ID).Return(review, nil)
+
+	_, err := suite.useCase.Submit(ctx, &SubmitReviewRequest{
+		ID:                     review.ID.String(),
+		GradeSubmissionRequest: submission.GradeSubmissionRequest{Grade: testutil.Ptr(80.0)},
+	})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestSubmit_ClosedOnceGraded() {
+	ctx, _, review := suite.reviewFixture(schema.SubmissionGraded)
+
+	_, err := suite.useCase.Submit(ctx, &SubmitReviewRequest{
+		ID:                     review.ID.String(),
+		GradeSubmissionRequest: submission.GradeSubmissionRequest{Grade: testutil.Ptr(80.0)},
+	})
+
+	assert.Equal(suite.T(), ErrReviewClosed.Build(), err)
+	suite.repo.AssertNotCalled(suite.T(), "Save", mock.Anything, mock.Anything)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestSubmit_WaitsForOtherReviewers() {
+	ctx, _, review := suite.reviewFixture(schema.SubmissionSubmitted)
+	other := schema.PeerReview{ID: uuid.New(), SubmissionID: review.SubmissionID, ReviewerID: uuid.New()}
+
+	suite.repo.On("Save", ctx, review).Return(nil)
+	suite.repo.On("GetBySubmission", ctx, review.SubmissionID).Return([]schema.PeerReview{*review, other}, nil)
+
+	res, err := suite.useCase.Submit(ctx, &SubmitReviewRequest{
+		ID:                     review.ID.String(),
+		GradeSubmissionRequest: submission.GradeSubmissionRequest{Grade: testutil.Ptr(80.0), Feedback: "Clear structure"},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 80.0, *res.Grade)
+	assert.Equal(suite.T(), "Clear structure", res.Comment)
+	assert.NotNil(suite.T(), res.SubmittedAt)
+	suite.grader.AssertNotCalled(suite.T(), "ApplyPeerGrades", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestSubmit_LastReviewGradesSubmission() {
+	ctx, assignmentObj, review := suite.reviewFixture(schema.SubmissionSubmitted)
+	now := time.Now()
+	other := schema.PeerReview{ID: uuid.New(), SubmissionID: review.SubmissionID, ReviewerID: uuid.New(), Grade: testutil.Ptr(70.0), SubmittedAt: &now}
+
+	suite.repo.On("Save", ctx, review).Return(nil)
+	// The review is read back as it was saved
+	suite.repo.On("GetBySubmission", ctx, review.SubmissionID).Return([]schema.PeerReview{{ID: review.ID, Grade: testutil.Ptr(80.0), SubmittedAt: &now}, other}, nil)
+	suite.grader.On("ApplyPeerGrades", ctx, assignmentObj, map[uuid.UUID]float64{review.SubmissionID: 75}).Return(1, nil)
+
+	_, err := suite.useCase.Submit(ctx, &SubmitReviewRequest{
+		ID:                     review.ID.String(),
+		GradeSubmissionRequest: submission.GradeSubmissionRequest{Grade: testutil.Ptr(80.0)},
+	})
+
+	assert.NoError(suite.T(), err)
+	suite.grader.AssertExpectations(suite.T())
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestGetMine_ClosedReviewsAreNotOpen() {
+	reviewerID := uuid.New()
+	ctx := testutil.UserCtx(reviewerID, schema.RoleStudent)
+	assignmentID := uuid.New()
+	attachments := []schema.Attachment{{ID: uuid.New(), URL: "https://bucket/essay.pdf"}}
+	reviews := []schema.PeerReview{
+		{ID: uuid.New(), AssignmentID: assignmentID, ReviewerID: reviewerID,
+			Submission: schema.Submission{ID: uuid.New(), Content: "Essay", Status: schema.SubmissionSubmitted, Attachments: attachments}},
+		{ID: uuid.New(), AssignmentID: assignmentID, ReviewerID: reviewerID,
+			Submission: schema.Submission{ID: uuid.New(), Status: schema.SubmissionGraded}},
+	}
+
+	suite.repo.On("GetByReviewer", ctx, reviewerID).Return(reviews, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignmentID).Return([]schema.RubricCriterion{}, nil).Once()
+	suite.signer.On("SignURLs", mock.Anything).Return()
+
+	res, err := suite.useCase.GetMine(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), res, 2)
+	assert.True(suite.T(), res[0].Open)
+	assert.Equal(suite.T(), "Essay", res[0].Content)
+	assert.Equal(suite.T(), attachments, res[0].Attachments)
+	assert.False(suite.T(), res[1].Open)
+	suite.assignmentRepo.AssertExpectations(suite.T())
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestFinalize_GradesOpenReviewedSubmissions() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 2, PeerReviewsAssignedAt: testutil.Ptr(time.Now())}
+	ctx := suite.instructorOf(assignmentObj)
+	now := time.Now()
+
+	open := schema.Submission{ID: uuid.New(), Status: schema.SubmissionSubmitted}
+	graded := schema.Submission{ID: uuid.New(), Status: schema.SubmissionGraded}
+	unreviewed := schema.Submission{ID: uuid.New(), Status: schema.SubmissionInReview}
+	reviews := []schema.PeerReview{
+		{SubmissionID: open.ID, Submission: open, Grade: testutil.Ptr(60.0), SubmittedAt: &now},
+		{SubmissionID: open.ID, Submission: open},
+		{SubmissionID: graded.ID, Submission: graded, Grade: testutil.Ptr(90.0), SubmittedAt: &now},
+		{SubmissionID: unreviewed.ID, Submission: unreviewed},
+	}
+	suite.repo.On("GetByAssignment", ctx, assignmentObj.ID).Return(reviews, nil)
+	suite.grader.On("ApplyPeerGrades", ctx, assignmentObj, map[uuid.UUID]float64{open.ID: 60}).Return(1, nil)
+
+	res, err := suite.useCase.Finalize(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, res.Graded)
+}
+
+func (suite *PeerReviewUseCaseTestSuite) TestFinalize_NotInstructor() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), PeerReviewCount: 2}
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	suite.assignmentRepo.On("GetByID", ctx, assignmentObj.ID).Return(assignmentObj, nil)
+	suite.courseRepo.On("GetByID", ctx, assignmentObj.CourseID).Return(schema.Course{ID: assignmentObj.CourseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.useCase.Finalize(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "GetByAssignment", mock.Anything, mock.Anything)
+}
+
+func TestPeerReviewUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(PeerReviewUseCaseTestSuite))
+}
@@ -0,0 +1,143 @@
+package peerreview
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	GetDueAssignments(ctx context.Context, now time.Time) ([]schema.Assignment, error)
+	GetLatestSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error)
+	GetReviewers(ctx context.Context, courseID uuid.UUID) ([]uuid.UUID, error)
+	Assign(ctx context.Context, assignmentID uuid.UUID, reviews []schema.PeerReview) (bool, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.PeerReview, error)
+	GetByReviewer(ctx context.Context, reviewerID uuid.UUID) ([]schema.PeerReview, error)
+	GetBySubmission(ctx context.Context, submissionID uuid.UUID) ([]schema.PeerReview, error)
+	GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.PeerReview, error)
+	GetReceived(ctx context.Context, assignmentID, userID uuid.UUID) ([]schema.PeerReview, error)
+	Save(ctx context.Context, review *schema.PeerReview) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetDueAssignments returns the peer reviewed assignments past their due date whose reviews are not handed out yet
+func (r *repository) GetDueAssignments(ctx context.Context, now time.Time) ([]schema.Assignment, error) {
+	var assignments []schema.Assignment
+	err := r.db.WithContext(ctx).
+		Where("peer_review_count > 0 AND due <= ? AND peer_reviews_assigned_at IS NULL", now).
+		Order("due").
+		Find(&assignments).Error
+	return assignments, err
+}
+
+// GetLatestSubmissions returns the latest attempt of every student who handed in the assignment
+func (r *repository) GetLatestSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	var submissions []schema.Submission
+	err := r.db.WithContext(ctx).
+		Where("assignment_id = ?", assignmentID).
+		Where("attempt = (SELECT MAX(s.attempt) FROM submissions s WHERE s.assignment_id = submissions.assignment_id " +
+			"AND s.user_id = submissions.user_id AND s.deleted_at IS NULL)").
+		Order("submitted_at").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+// GetReviewers returns the students currently enrolled in the course
+func (r *repository) GetReviewers(ctx context.Context, courseID uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	err := r.db.WithContext(ctx).Table("course_enrolls").
+		Distinct("user_id").
+		Where("course_id = ? AND (expires_at IS NULL OR expires_at > ?)", courseID, time.Now()).
+		Pluck("user_id", &userIDs).Error
+	return userIDs, err
+}
+
+// Assign stores the reviews and marks the assignment as handed out. It returns false without storing
+// anything when the reviews of the assignment were handed out in the meantime
+func (r *repository) Assign(ctx context.Context, assignmentID uuid.UUID, reviews []schema.PeerReview) (bool, error) {
+	assigned := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&schema.Assignment{}).
+			Where("id = ? AND peer_reviews_assigned_at IS NULL", assignmentID).
+			Update("peer_reviews_assigned_at", time.Now())
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return nil
+		}
+		assigned = true
+
+		if len(reviews) == 0 {
+			return nil
+		}
+		return tx.Omit("Assignment", "Submission", "Reviewer").Create(&reviews).Error
+	})
+	return assigned, err
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.PeerReview, error) {
+	var review schema.PeerReview
+	if err := r.db.WithContext(ctx).Preload("Submission").First(&review, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &review, nil
+}
+
+// GetByReviewer returns the reviews handed to the user with the submission to review and its assignment
+func (r *repository) GetByReviewer(ctx context.Context, reviewerID uuid.UUID) ([]schema.PeerReview, error) {
+	var reviews []schema.PeerReview
+	err := r.db.WithContext(ctx).
+		Preload("Assignment").
+		Preload("Submission.Attachments").
+		Where("reviewer_id = ?", reviewerID).
+		Order("created_at DESC").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *repository) GetBySubmission(ctx context.Context, submissionID uuid.UUID) ([]schema.PeerReview, error) {
+	var reviews []schema.PeerReview
+	err := r.db.WithContext(ctx).
+		Where("submission_id = ?", submissionID).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *repository) GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.PeerReview, error) {
+	var reviews []schema.PeerReview
+	err := r.db.WithContext(ctx).
+		Preload("Submission").
+		Where("assignment_id = ?", assignmentID).
+		Order("submission_id, created_at").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+// GetReceived returns the reviews handed in about the work of the user, only once the grade of the
+// reviewed submission is released to them
+func (r *repository) GetReceived(ctx context.Context, assignmentID, userID uuid.UUID) ([]schema.PeerReview, error) {
+	var reviews []schema.PeerReview
+	err := r.db.WithContext(ctx).
+		Select("peer_reviews.*").
+		Joins("JOIN submissions ON submissions.id = peer_reviews.submission_id").
+		Where("peer_reviews.assignment_id = ? AND submissions.user_id = ?", assignmentID, userID).
+		Where("peer_reviews.submitted_at IS NOT NULL AND submissions.released_at IS NOT NULL AND submissions.deleted_at IS NULL").
+		Order("peer_reviews.submitted_at").
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *repository) Save(ctx context.Context, review *schema.PeerReview) error {
+	return r.db.WithContext(ctx).Omit("Assignment", "Submission", "Reviewer").Save(review).Error
+}
@@ -0,0 +1,140 @@
+package peerreview
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	reviewGroup := engine.Group("/v1/peer-reviews", middleware.Authenticate(), middleware.RequireRole("student"))
+	{
+		reviewGroup.GET("", controller.GetMine())
+		reviewGroup.PUT("/:id", controller.Submit())
+	}
+
+	assignmentGroup := engine.Group("/v1/assignments/:id/peer-reviews", middleware.Authenticate())
+	{
+		assignmentGroup.GET("", controller.GetByAssignment())
+		assignmentGroup.GET("/mine", middleware.RequireRole("student"), controller.GetReceived())
+		assignmentGroup.POST("/assign", controller.Assign())
+		assignmentGroup.POST("/finalize", controller.Finalize())
+	}
+}
+
+func (c *RestController) GetMine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetMine(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_PEER_REVIEWS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Submit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		// The ID is set before binding the body, binding the URI alone would fail on the missing body fields
+		req := SubmitReviewRequest{ID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Submit(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "SUBMIT_PEER_REVIEW_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByAssignment() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByAssignment(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_PEER_REVIEWS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetReceived() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetReceived(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_RECEIVED_PEER_REVIEWS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Assign() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Assign(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "ASSIGN_PEER_REVIEWS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Finalize() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Finalize(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "FINALIZE_PEER_REVIEWS_SUCCESS", res).Send(ctx)
+	}
+}
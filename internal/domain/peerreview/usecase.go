@@ -0,0 +1,451 @@
+package peerreview
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/assignment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/submission"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Grader writes the grade the peers agreed on to the submissions, it is the submission use case
+type Grader interface {
+	ApplyPeerGrades(ctx context.Context, assignmentObj *schema.Assignment, grades map[uuid.UUID]float64) (int, error)
+}
+
+// URLSigner signs the attachments of the submissions handed to reviewers, it is the attachment use case
+type URLSigner interface {
+	SignURLs(attachments []schema.Attachment)
+}
+
+type UseCase struct {
+	repo             Repository
+	assignmentRepo   assignment.Repository
+	courseRepo       course.Repository
+	grader           Grader
+	signer           URLSigner
+	notificationRepo notification.IRepository
+}
+
+func NewUseCase(repo Repository, assignmentRepo assignment.Repository, courseRepo course.Repository,
+	grader Grader, signer URLSigner, notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{
+		repo:             repo,
+		assignmentRepo:   assignmentRepo,
+		courseRepo:       courseRepo,
+		grader:           grader,
+		signer:           signer,
+		notificationRepo: notificationRepo,
+	}
+}
+
+func currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return uuid.Nil, apierror.ErrTokenInvalid.Build()
+	}
+	return userID, nil
+}
+
+func (uc *UseCase) getAssignment(ctx context.Context, id uuid.UUID) (*schema.Assignment, error) {
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentNotFound.Build()
+		}
+		log.Println("Error getting assignment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return assignmentObj, nil
+}
+
+// getManagedAssignment returns the assignment when the current user is the instructor of its course or an admin
+func (uc *UseCase) getManagedAssignment(ctx context.Context, idStr string) (*schema.Assignment, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	assignmentObj, err := uc.getAssignment(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	courseObj, err := uc.courseRepo.GetByID(ctx, assignmentObj.CourseID)
+	if err != nil {
+		log.Println("Error getting course: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if courseObj.InstructorID != userID && ctx.Value("user.role") != string(schema.RoleAdmin) {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	return assignmentObj, nil
+}
+
+// AssignDue hands out the reviews of every peer reviewed assignment that passed its due date and returns
+// how many reviews were handed out
+func (uc *UseCase) AssignDue(ctx context.Context) (int, error) {
+	assignments, err := uc.repo.GetDueAssignments(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	total := 0
+	for i := range assignments {
+		n, err := uc.assign(ctx, &assignments[i])
+		if err != nil {
+			log.Println("Error assigning peer reviews: ", err)
+			continue
+		}
+		total += n
+	}
+	return total, nil
+}
+
+func (uc *UseCase) StartAssignWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := uc.AssignDue(ctx); err != nil {
+			log.Println("Error assigning peer reviews: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Assign hands out the reviews of the assignment right away instead of waiting for the worker
+func (uc *UseCase) Assign(ctx context.Context, req *AssignmentIDRequest) (*AssignResponse, error) {
+	assignmentObj, err := uc.getManagedAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	if assignmentObj.PeerReviewCount == 0 {
+		return nil, ErrPeerReviewDisabled.Build()
+	}
+	if assignmentObj.Due == nil || time.Now().Before(*assignmentObj.Due) {
+		return nil, ErrNotDueYet.Build()
+	}
+	if assignmentObj.PeerReviewsAssignedAt != nil {
+		return nil, ErrAlreadyAssigned.Build()
+	}
+
+	n, err := uc.assign(ctx, assignmentObj)
+	if err != nil {
+		var apiErr *apierror.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		log.Println("Error assigning peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return &AssignResponse{Reviews: n}, nil
+}
+
+// assign pairs the latest attempt of every student with reviewers and lets the reviewers know
+func (uc *UseCase) assign(ctx context.Context, assignmentObj *schema.Assignment) (int, error) {
+	submissions, err := uc.repo.GetLatestSubmissions(ctx, assignmentObj.ID)
+	if err != nil {
+		return 0, err
+	}
+	reviewers, err := uc.repo.GetReviewers(ctx, assignmentObj.CourseID)
+	if err != nil {
+		return 0, err
+	}
+
+	var reviews []schema.PeerReview
+	load := make(map[uuid.UUID]int)
+	for submissionID, picked := range pairReviewers(submissions, reviewers, assignmentObj.PeerReviewCount) {
+		for _, reviewerID := range picked {
+			id, err := uuid.NewV7()
+			if err != nil {
+				return 0, err
+			}
+			reviews = append(reviews, schema.PeerReview{
+				ID:           id,
+				AssignmentID: assignmentObj.ID,
+				SubmissionID: submissionID,
+				ReviewerID:   reviewerID,
+			})
+			load[reviewerID]++
+		}
+	}
+
+	assigned, err := uc.repo.Assign(ctx, assignmentObj.ID, reviews)
+	if err != nil {
+		return 0, err
+	}
+	if !assigned {
+		return 0, ErrAlreadyAssigned.Build()
+	}
+
+	for reviewerID, n := range load {
+		go uc.notify(reviewerID, "Peer Review Assigned",
+			fmt.Sprintf("You have %d submissions of %s to review", n, assignmentObj.Title))
+	}
+	return len(reviews), nil
+}
+
+// GetMine returns the reviews handed to the current student with the work to review and the rubric to
+// review it against
+func (uc *UseCase) GetMine(ctx context.Context) ([]ReviewTask, error) {
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := uc.repo.GetByReviewer(ctx, userID)
+	if err != nil {
+		log.Println("Error getting peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	rubrics := make(map[uuid.UUID][]schema.RubricCriterion)
+	res := make([]ReviewTask, 0, len(reviews))
+	for _, r := range reviews {
+		// The submission was removed since the reviews were handed out
+		if r.Submission.ID == uuid.Nil {
+			continue
+		}
+
+		rubric, ok := rubrics[r.AssignmentID]
+		if !ok {
+			rubric, err = uc.assignmentRepo.GetRubric(ctx, r.AssignmentID)
+			if err != nil {
+				log.Println("Error getting rubric: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			rubrics[r.AssignmentID] = rubric
+		}
+
+		uc.signer.SignURLs(r.Submission.Attachments)
+		res = append(res, ReviewTask{
+			ID:              r.ID,
+			AssignmentID:    r.AssignmentID,
+			AssignmentTitle: r.Assignment.Title,
+			Content:         r.Submission.Content,
+			Attachments:     r.Submission.Attachments,
+			Rubric:          rubric,
+			Grade:           r.Grade,
+			Scores:          r.Scores,
+			Comment:         r.Comment,
+			SubmittedAt:     r.SubmittedAt,
+			Open:            reviewOpen(&r.Submission),
+		})
+	}
+	return res, nil
+}
+
+// Submit hands in the review, it can be changed until the submission is graded. Once every reviewer handed
+// in, the submission is graded with the median of their grades
+func (uc *UseCase) Submit(ctx context.Context, req *SubmitReviewRequest) (*schema.PeerReview, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	review, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrReviewNotFound.Build()
+		}
+		log.Println("Error getting peer review: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if review.ReviewerID != userID {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	if review.Submission.ID == uuid.Nil || !reviewOpen(&review.Submission) {
+		return nil, ErrReviewClosed.Build()
+	}
+
+	assignmentObj, err := uc.getAssignment(ctx, review.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	rubric, err := uc.assignmentRepo.GetRubric(ctx, assignmentObj.ID)
+	if err != nil {
+		log.Println("Error getting rubric: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	grade, scores, err := submission.ScoreByRubric(rubric, &req.GradeSubmissionRequest)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	review.Grade = &grade
+	review.Scores = peerScores(scores)
+	review.Comment = req.Feedback
+	review.SubmittedAt = &now
+	if err := uc.repo.Save(ctx, review); err != nil {
+		log.Println("Error saving peer review: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	reviews, err := uc.repo.GetBySubmission(ctx, review.SubmissionID)
+	if err != nil {
+		log.Println("Error getting peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	for _, r := range reviews {
+		if r.SubmittedAt == nil {
+			return review, nil
+		}
+	}
+	if _, err := uc.grader.ApplyPeerGrades(ctx, assignmentObj, map[uuid.UUID]float64{
+		review.SubmissionID: *peerGrade(reviews),
+	}); err != nil {
+		log.Println("Error applying peer grade: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return review, nil
+}
+
+// GetByAssignment returns every submission handed out for review with who reviewed it, for the instructor
+func (uc *UseCase) GetByAssignment(ctx context.Context, req *AssignmentIDRequest) ([]SubmissionReviews, error) {
+	assignmentObj, err := uc.getManagedAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := uc.repo.GetByAssignment(ctx, assignmentObj.ID)
+	if err != nil {
+		log.Println("Error getting peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := make([]SubmissionReviews, 0)
+	index := make(map[uuid.UUID]int)
+	for _, r := range reviews {
+		i, ok := index[r.SubmissionID]
+		if !ok {
+			i = len(res)
+			index[r.SubmissionID] = i
+			res = append(res, SubmissionReviews{
+				SubmissionID: r.SubmissionID,
+				UserID:       r.Submission.UserID,
+				Attempt:      r.Submission.Attempt,
+				Status:       r.Submission.Status,
+				Grade:        r.Submission.Grade,
+			})
+		}
+		res[i].Reviews = append(res[i].Reviews, r)
+	}
+	for i := range res {
+		res[i].PeerGrade = peerGrade(res[i].Reviews)
+	}
+	return res, nil
+}
+
+// GetReceived returns the reviews of the work of the current student once its grade is released, without
+// telling who wrote them
+func (uc *UseCase) GetReceived(ctx context.Context, req *AssignmentIDRequest) ([]ReceivedReview, error) {
+	assignmentID, err := uuid.Parse(req.AssignmentID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reviews, err := uc.repo.GetReceived(ctx, assignmentID, userID)
+	if err != nil {
+		log.Println("Error getting peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := make([]ReceivedReview, len(reviews))
+	for i, r := range reviews {
+		res[i] = ReceivedReview{
+			Grade:       r.Grade,
+			Scores:      r.Scores,
+			Comment:     r.Comment,
+			SubmittedAt: r.SubmittedAt,
+		}
+	}
+	return res, nil
+}
+
+// Finalize grades the submissions still open with the median of the reviews handed in so far, for reviewers
+// who never hand in. Submissions without any review are left for the instructor
+func (uc *UseCase) Finalize(ctx context.Context, req *AssignmentIDRequest) (*FinalizeResponse, error) {
+	assignmentObj, err := uc.getManagedAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+	if assignmentObj.PeerReviewCount == 0 {
+		return nil, ErrPeerReviewDisabled.Build()
+	}
+	if assignmentObj.PeerReviewsAssignedAt == nil {
+		return nil, ErrNotAssigned.Build()
+	}
+
+	reviews, err := uc.repo.GetByAssignment(ctx, assignmentObj.ID)
+	if err != nil {
+		log.Println("Error getting peer reviews: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	bySubmission := make(map[uuid.UUID][]schema.PeerReview)
+	for _, r := range reviews {
+		if reviewOpen(&r.Submission) {
+			bySubmission[r.SubmissionID] = append(bySubmission[r.SubmissionID], r)
+		}
+	}
+	grades := make(map[uuid.UUID]float64, len(bySubmission))
+	for submissionID, rs := range bySubmission {
+		if grade := peerGrade(rs); grade != nil {
+			grades[submissionID] = *grade
+		}
+	}
+
+	n, err := uc.grader.ApplyPeerGrades(ctx, assignmentObj, grades)
+	if err != nil {
+		log.Println("Error applying peer grades: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return &FinalizeResponse{Graded: n}, nil
+}
+
+func (uc *UseCase) notify(userID uuid.UUID, title, detail string) {
+	notificationID, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+
+	notif := schema.Notification{
+		ID:     notificationID,
+		UserID: userID,
+		Title:  title,
+		Detail: detail,
+	}
+	if err := uc.notificationRepo.Create(&notif); err != nil {
+		log.Println("Error creating notification: ", err)
+	}
+}
@@ -0,0 +1,86 @@
+package quiz
+
+import (
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+type CourseIDRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+}
+
+type QuizIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type AttemptIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type QuestionIDRequest struct {
+	QuizID     string `uri:"id" binding:"required,uuid"`
+	QuestionID string `uri:"questionId" binding:"required,uuid"`
+}
+
+// QuestionInput is a question of the bank. Options are required for multiple choice and multi-select
+// questions and the answer key refers to them by index
+type QuestionInput struct {
+	Type     schema.QuestionType  `json:"type" binding:"required,oneof=multiple_choice multi_select true_false numeric short_answer"`
+	Prompt   string               `json:"prompt" binding:"required,max=2000"`
+	Options  []string             `json:"options" binding:"omitempty,max=20,dive,required,max=500"`
+	Answer   schema.QuizAnswerKey `json:"answer"`
+	Points   *float64             `json:"points" binding:"omitempty,gt=0,max=1000"`
+	Position *int                 `json:"position" binding:"omitempty,min=0"`
+}
+
+type CreateQuizRequest struct {
+	CourseID            string          `uri:"id" binding:"required,uuid"`
+	Title               string          `json:"title" binding:"required,max=150"`
+	Description         string          `json:"description" binding:"max=2000"`
+	TimeLimitSeconds    *int            `json:"time_limit_seconds" binding:"omitempty,min=1"`
+	MaxAttempts         *int            `json:"max_attempts" binding:"omitempty,min=1"`
+	QuestionsPerAttempt *int            `json:"questions_per_attempt" binding:"omitempty,min=1"`
+	ShuffleQuestions    bool            `json:"shuffle_questions"`
+	Questions           []QuestionInput `json:"questions" binding:"omitempty,max=200,dive"`
+}
+
+// UpdateQuizRequest changes the quiz settings, 0 removes a time limit, attempt limit or question draw
+type UpdateQuizRequest struct {
+	ID                  string  `uri:"id" binding:"required,uuid"`
+	Title               *string `json:"title" binding:"omitempty,max=150"`
+	Description         *string `json:"description" binding:"omitempty,max=2000"`
+	TimeLimitSeconds    *int    `json:"time_limit_seconds" binding:"omitempty,min=0"`
+	MaxAttempts         *int    `json:"max_attempts" binding:"omitempty,min=0"`
+	QuestionsPerAttempt *int    `json:"questions_per_attempt" binding:"omitempty,min=0"`
+	ShuffleQuestions    *bool   `json:"shuffle_questions"`
+}
+
+type AddQuestionRequest struct {
+	QuizID string `uri:"id" binding:"required,uuid"`
+	QuestionInput
+}
+
+type UpdateQuestionRequest struct {
+	QuizID     string `uri:"id" binding:"required,uuid"`
+	QuestionID string `uri:"questionId" binding:"required,uuid"`
+	QuestionInput
+}
+
+type SaveAnswersRequest struct {
+	ID      string                            `uri:"id" binding:"required,uuid"`
+	Answers map[uuid.UUID]schema.QuizResponse `json:"answers" binding:"required"`
+}
+
+// SubmitAttemptRequest finishes an attempt, the answers given here are merged over the saved ones
+type SubmitAttemptRequest struct {
+	ID      string                            `uri:"id" binding:"required,uuid"`
+	Answers map[uuid.UUID]schema.QuizResponse `json:"answers"`
+}
+
+// QuizSummary is a quiz as listed to course members, the questions are only shown inside an attempt
+type QuizSummary struct {
+	schema.Quiz
+	QuestionCount int64    `json:"question_count"`
+	AttemptsUsed  int64    `json:"attempts_used"`
+	BestScore     *float64 `json:"best_score"`
+}
@@ -0,0 +1,41 @@
+package quiz
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrQuizNotFound = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusNotFound).
+			WithMessage("QUIZ_NOT_FOUND")
+
+	ErrQuestionNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("QUESTION_NOT_FOUND")
+
+	ErrAttemptNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("ATTEMPT_NOT_FOUND")
+
+	ErrInvalidQuestion = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_QUESTION")
+
+	ErrQuizEmpty = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("QUIZ_HAS_NO_QUESTIONS")
+
+	ErrAttemptLimitReached = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("ATTEMPT_LIMIT_REACHED")
+
+	ErrAttemptClosed = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("ATTEMPT_CLOSED")
+
+	ErrUnknownQuestion = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("QUESTION_NOT_IN_ATTEMPT")
+)
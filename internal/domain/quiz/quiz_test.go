@@ -0,0 +1,669 @@
+package quiz
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, quiz *schema.Quiz) error {
+	args := m.Called(ctx, quiz)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Update(ctx context.Context, quiz *schema.Quiz) error {
+	args := m.Called(ctx, quiz)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Quiz, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Quiz), args.Error(1)
+}
+
+func (m *MockRepository) GetSummaries(ctx context.Context, courseID, userID uuid.UUID) ([]QuizSummary, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).([]QuizSummary), args.Error(1)
+}
+
+func (m *MockRepository) AddQuestion(ctx context.Context, question *schema.QuizQuestion) error {
+	args := m.Called(ctx, question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateQuestion(ctx context.Context, question *schema.QuizQuestion) error {
+	args := m.Called(ctx, question)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteQuestion(ctx context.Context, quizID, questionID uuid.UUID) error {
+	args := m.Called(ctx, quizID, questionID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetQuestion(ctx context.Context, quizID, questionID uuid.UUID) (*schema.QuizQuestion, error) {
+	args := m.Called(ctx, quizID, questionID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.QuizQuestion), args.Error(1)
+}
+
+func (m *MockRepository) GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.QuizQuestion, error) {
+	args := m.Called(ctx, ids)
+	return args.Get(0).([]schema.QuizQuestion), args.Error(1)
+}
+
+func (m *MockRepository) StartAttempt(ctx context.Context, attempt *schema.QuizAttempt, maxAttempts *int) (bool, error) {
+	args := m.Called(ctx, attempt, maxAttempts)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) UpdateAttempt(ctx context.Context, attempt *schema.QuizAttempt) error {
+	args := m.Called(ctx, attempt)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetAttempt(ctx context.Context, id uuid.UUID) (*schema.QuizAttempt, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.QuizAttempt), args.Error(1)
+}
+
+func (m *MockRepository) GetAttempts(ctx context.Context, quizID, userID uuid.UUID) ([]schema.QuizAttempt, error) {
+	args := m.Called(ctx, quizID, userID)
+	return args.Get(0).([]schema.QuizAttempt), args.Error(1)
+}
+
+func (m *MockRepository) GetAllAttempts(ctx context.Context, quizID uuid.UUID) ([]schema.QuizAttempt, error) {
+	args := m.Called(ctx, quizID)
+	return args.Get(0).([]schema.QuizAttempt), args.Error(1)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type QuizUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	enrollRepo *MockEnrollRepository
+	useCase    *UseCase
+}
+
+func (suite *QuizUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, enrollUc)
+}
+
+// enrolled makes the student a member of the course of the quiz
+func (suite *QuizUseCaseTestSuite) enrolled(ctx context.Context, userID uuid.UUID, quiz *schema.Quiz) {
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+	suite.courseRepo.On("GetByID", ctx, quiz.CourseID).Return(schema.Course{ID: quiz.CourseID, InstructorID: uuid.New()}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, quiz.CourseID).Return(&schema.CourseEnroll{}, nil)
+}
+
+func bank(quizID uuid.UUID) []schema.QuizQuestion {
+	return []schema.QuizQuestion{
+		{ID: uuid.New(), QuizID: quizID, Type: schema.QuestionMultipleChoice, Options: []string{"2", "3", "4"},
+			Answer: &schema.QuizAnswerKey{Options: []int{2}}, Points: 1, Position: 0},
+		{ID: uuid.New(), QuizID: quizID, Type: schema.QuestionMultiSelect, Options: []string{"a", "b", "c", "d"},
+			Answer: &schema.QuizAnswerKey{Options: []int{0, 1}}, Points: 2, Position: 1},
+		{ID: uuid.New(), QuizID: quizID, Type: schema.QuestionTrueFalse,
+			Answer: &schema.QuizAnswerKey{Bool: testutil.Ptr(true)}, Points: 1, Position: 2},
+		{ID: uuid.New(), QuizID: quizID, Type: schema.QuestionNumeric,
+			Answer: &schema.QuizAnswerKey{Number: testutil.Ptr(3.14), Tolerance: 0.01}, Points: 1, Position: 3},
+		{ID: uuid.New(), QuizID: quizID, Type: schema.QuestionShortAnswer,
+			Answer: &schema.QuizAnswerKey{Texts: []string{"new york"}}, Points: 1, Position: 4},
+	}
+}
+
+func questionIDs(questions []schema.QuizQuestion) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(questions))
+	for _, q := range questions {
+		ids = append(ids, q.ID)
+	}
+	return ids
+}
+
+func (suite *QuizUseCaseTestSuite) TestCreate_InvalidAnswerKey() {
+	instructorID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", instructorID.String())
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: instructorID}
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateQuizRequest{
+		CourseID: courseObj.ID.String(),
+		Title:    "Week 1",
+		Questions: []QuestionInput{{
+			Type:    schema.QuestionMultipleChoice,
+			Prompt:  "Pick one",
+			Options: []string{"a", "b"},
+			Answer:  schema.QuizAnswerKey{Options: []int{0, 1}},
+		}},
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrInvalidQuestion.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *QuizUseCaseTestSuite) TestCreate_NormalizesShortAnswers() {
+	instructorID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", instructorID.String())
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: instructorID}
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.repo.On("Create", ctx, mock.AnythingOfType("*schema.Quiz")).Return(nil)
+
+	res, err := suite.useCase.Create(ctx, &CreateQuizRequest{
+		CourseID: courseObj.ID.String(),
+		Title:    "Week 1",
+		Questions: []QuestionInput{{
+			Type:    schema.QuestionShortAnswer,
+			Prompt:  "Largest city of the US",
+			Options: []string{"ignored"},
+			Answer:  schema.QuizAnswerKey{Texts: []string{"  New   York ", "new york", "NYC"}},
+		}},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), res.Questions, 1)
+	assert.Nil(suite.T(), res.Questions[0].Options)
+	assert.Equal(suite.T(), []string{"new york", "nyc"}, res.Questions[0].Answer.Texts)
+	assert.Equal(suite.T(), 1.0, res.Questions[0].Points)
+}
+
+func (suite *QuizUseCaseTestSuite) TestStartAttempt_DrawsQuestionsWithoutAnswers() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New(), QuestionsPerAttempt: testutil.Ptr(3), TimeLimitSeconds: testutil.Ptr(600)}
+	quiz.Questions = bank(quiz.ID)
+
+	suite.enrolled(ctx, userID, quiz)
+	suite.repo.On("GetAttempts", ctx, quiz.ID, userID).Return([]schema.QuizAttempt{}, nil)
+	suite.repo.On("StartAttempt", ctx, mock.AnythingOfType("*schema.QuizAttempt"), quiz.MaxAttempts).Return(true, nil)
+
+	attempt, err := suite.useCase.StartAttempt(ctx, &QuizIDRequest{ID: quiz.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), attempt.QuestionIDs, 3)
+	assert.Len(suite.T(), attempt.Questions, 3)
+	for _, q := range attempt.Questions {
+		assert.Nil(suite.T(), q.Answer)
+	}
+	assert.NotNil(suite.T(), attempt.DeadlineAt)
+	assert.WithinDuration(suite.T(), attempt.StartedAt.Add(10*time.Minute), *attempt.DeadlineAt, time.Second)
+	// the bank keeps its answer keys
+	for _, q := range quiz.Questions {
+		assert.NotNil(suite.T(), q.Answer)
+	}
+}
+
+func (suite *QuizUseCaseTestSuite) TestStartAttempt_ResumesAttemptInProgress() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New(), MaxAttempts: testutil.Ptr(1)}
+	quiz.Questions = bank(quiz.ID)
+	open := schema.QuizAttempt{ID: uuid.New(), QuizID: quiz.ID, UserID: userID,
+		QuestionIDs: questionIDs(quiz.Questions), StartedAt: time.Now().Add(-time.Minute)}
+
+	suite.enrolled(ctx, userID, quiz)
+	suite.repo.On("GetAttempts", ctx, quiz.ID, userID).Return([]schema.QuizAttempt{open}, nil)
+	suite.repo.On("GetQuestionsByIDs", ctx, open.QuestionIDs).Return(quiz.Questions, nil)
+
+	attempt, err := suite.useCase.StartAttempt(ctx, &QuizIDRequest{ID: quiz.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), open.ID, attempt.ID)
+	assert.Len(suite.T(), attempt.Questions, len(quiz.Questions))
+	suite.repo.AssertNotCalled(suite.T(), "StartAttempt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *QuizUseCaseTestSuite) TestStartAttempt_LimitReached() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New(), MaxAttempts: testutil.Ptr(1)}
+	quiz.Questions = bank(quiz.ID)
+	submittedAt := time.Now().Add(-time.Hour)
+
+	suite.enrolled(ctx, userID, quiz)
+	suite.repo.On("GetAttempts", ctx, quiz.ID, userID).Return([]schema.QuizAttempt{
+		{ID: uuid.New(), QuizID: quiz.ID, UserID: userID, SubmittedAt: &submittedAt},
+	}, nil)
+
+	attempt, err := suite.useCase.StartAttempt(ctx, &QuizIDRequest{ID: quiz.ID.String()})
+
+	assert.Nil(suite.T(), attempt)
+	assert.Equal(suite.T(), ErrAttemptLimitReached.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "StartAttempt", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *QuizUseCaseTestSuite) TestStartAttempt_NotEnrolled() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+	suite.courseRepo.On("GetByID", ctx, quiz.CourseID).Return(schema.Course{ID: quiz.CourseID, InstructorID: uuid.New()}, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, quiz.CourseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
+
+	attempt, err := suite.useCase.StartAttempt(ctx, &QuizIDRequest{ID: quiz.ID.String()})
+
+	assert.Nil(suite.T(), attempt)
+	assert.Equal(suite.T(), courseenroll.ErrNotEnrolled.Build().Error(), err.Error())
+}
+
+func (suite *QuizUseCaseTestSuite) TestSubmit_ScoresEveryQuestionType() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+	quiz.Questions = bank(quiz.ID)
+	ids := questionIDs(quiz.Questions)
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: quiz.ID, UserID: userID, QuestionIDs: ids,
+		Answers: map[uuid.UUID]schema.QuizResponse{}, StartedAt: time.Now().Add(-time.Minute)}
+
+	suite.repo.On("GetAttempt", ctx, attempt.ID).Return(attempt, nil)
+	suite.repo.On("GetQuestionsByIDs", ctx, ids).Return(quiz.Questions, nil)
+	suite.repo.On("UpdateAttempt", ctx, attempt).Return(nil)
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+
+	res, err := suite.useCase.Submit(ctx, &SubmitAttemptRequest{
+		ID: attempt.ID.String(),
+		Answers: map[uuid.UUID]schema.QuizResponse{
+			ids[0]: {Options: []int{2}},
+			// one right and one wrong option cancel out
+			ids[1]: {Options: []int{0, 2}},
+			ids[2]: {Bool: testutil.Ptr(true)},
+			ids[3]: {Number: testutil.Ptr(3.145)},
+			ids[4]: {Text: testutil.Ptr(" New  YORK")},
+		},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.NotNil(suite.T(), res.SubmittedAt)
+	assert.Equal(suite.T(), 4.0, *res.Points)
+	assert.Equal(suite.T(), 6.0, res.MaxPoints)
+	assert.Equal(suite.T(), 66.7, *res.Score)
+	assert.Len(suite.T(), res.Results, 5)
+	assert.False(suite.T(), res.Results[1].Correct)
+	for _, q := range res.Questions {
+		assert.Nil(suite.T(), q.Answer)
+	}
+}
+
+func (suite *QuizUseCaseTestSuite) TestSubmit_PartialMultiSelectCredit() {
+	question := schema.QuizQuestion{ID: uuid.New(), Type: schema.QuestionMultiSelect, Options: []string{"a", "b", "c", "d"},
+		Answer: &schema.QuizAnswerKey{Options: []int{0, 1, 2}}, Points: 3}
+
+	result := scoreAnswer(&question, &schema.QuizResponse{Options: []int{0, 1}})
+
+	assert.Equal(suite.T(), 2.0, result.Points)
+	assert.False(suite.T(), result.Correct)
+}
+
+func (suite *QuizUseCaseTestSuite) TestSubmit_UnknownQuestion() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: uuid.New(), UserID: userID,
+		QuestionIDs: []uuid.UUID{uuid.New()}, StartedAt: time.Now()}
+
+	suite.repo.On("GetAttempt", ctx, attempt.ID).Return(attempt, nil)
+
+	res, err := suite.useCase.Submit(ctx, &SubmitAttemptRequest{
+		ID:      attempt.ID.String(),
+		Answers: map[uuid.UUID]schema.QuizResponse{uuid.New(): {Bool: testutil.Ptr(true)}},
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrUnknownQuestion.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "UpdateAttempt", mock.Anything, mock.Anything)
+}
+
+func (suite *QuizUseCaseTestSuite) TestSaveAnswers_AfterDeadline() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	deadline := time.Now().Add(-10 * time.Second)
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: uuid.New(), UserID: userID,
+		QuestionIDs: []uuid.UUID{uuid.New()}, StartedAt: time.Now().Add(-time.Minute), DeadlineAt: &deadline}
+
+	suite.repo.On("GetAttempt", ctx, attempt.ID).Return(attempt, nil)
+
+	res, err := suite.useCase.SaveAnswers(ctx, &SaveAnswersRequest{
+		ID:      attempt.ID.String(),
+		Answers: map[uuid.UUID]schema.QuizResponse{attempt.QuestionIDs[0]: {Bool: testutil.Ptr(true)}},
+	})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrAttemptClosed.Build().Error(), err.Error())
+}
+
+func (suite *QuizUseCaseTestSuite) TestGetAttempt_ExpiredAttemptIsSubmitted() {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+	quiz.Questions = bank(quiz.ID)[:1]
+	ids := questionIDs(quiz.Questions)
+	deadline := time.Now().Add(-time.Hour)
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: quiz.ID, UserID: userID, QuestionIDs: ids,
+		Answers:   map[uuid.UUID]schema.QuizResponse{ids[0]: {Options: []int{2}}},
+		StartedAt: deadline.Add(-10 * time.Minute), DeadlineAt: &deadline}
+
+	suite.repo.On("GetAttempt", ctx, attempt.ID).Return(attempt, nil)
+	suite.repo.On("GetQuestionsByIDs", ctx, ids).Return(quiz.Questions, nil)
+	suite.repo.On("UpdateAttempt", ctx, attempt).Return(nil)
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+
+	res, err := suite.useCase.GetAttempt(ctx, &AttemptIDRequest{ID: attempt.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), deadline, *res.SubmittedAt)
+	assert.Equal(suite.T(), 100.0, *res.Score)
+}
+
+func (suite *QuizUseCaseTestSuite) TestGetAttempt_OtherStudent() {
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: quiz.ID, UserID: uuid.New(), StartedAt: time.Now()}
+
+	suite.repo.On("GetAttempt", ctx, attempt.ID).Return(attempt, nil)
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+	suite.courseRepo.On("GetByID", ctx, quiz.CourseID).Return(schema.Course{ID: quiz.CourseID, InstructorID: uuid.New()}, nil)
+
+	res, err := suite.useCase.GetAttempt(ctx, &AttemptIDRequest{ID: attempt.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrAttemptNotFound.Build().Error(), err.Error())
+}
+
+func (suite *QuizUseCaseTestSuite) TestDeleteQuestion_NotFound() {
+	instructorID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", instructorID.String())
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+	questionID := uuid.New()
+
+	suite.repo.On("GetByID", ctx, quiz.ID).Return(quiz, nil)
+	suite.courseRepo.On("GetByID", ctx, quiz.CourseID).Return(schema.Course{ID: quiz.CourseID, InstructorID: instructorID}, nil)
+	suite.repo.On("GetQuestion", ctx, quiz.ID, questionID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := suite.useCase.DeleteQuestion(ctx, &QuestionIDRequest{QuizID: quiz.ID.String(), QuestionID: questionID.String()})
+
+	assert.Equal(suite.T(), ErrQuestionNotFound.Build().Error(), err.Error())
+}
+
+// managedQuiz sets up a quiz of a course taught by the returned instructor
+func (suite *QuizUseCaseTestSuite) managedQuiz() (*schema.Quiz, uuid.UUID) {
+	instructorID := uuid.New()
+	quiz := &schema.Quiz{ID: uuid.New(), CourseID: uuid.New()}
+	suite.repo.On("GetByID", mock.Anything, quiz.ID).Return(quiz, nil)
+	suite.courseRepo.On("GetByID", mock.Anything, quiz.CourseID).Return(schema.Course{ID: quiz.CourseID, InstructorID: instructorID}, nil)
+	return quiz, instructorID
+}
+
+func (suite *QuizUseCaseTestSuite) TestCreateController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	instructorID := uuid.New()
+	courseObj := schema.Course{ID: uuid.New(), InstructorID: instructorID}
+
+	suite.courseRepo.On("GetByID", mock.Anything, courseObj.ID).Return(courseObj, nil)
+	suite.repo.On("Create", mock.Anything, mock.AnythingOfType("*schema.Quiz")).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPost, "/v1/courses/"+courseObj.ID.String()+"/quizzes", `{
+		"title": "Week 1",
+		"time_limit_seconds": 600,
+		"max_attempts": 2,
+		"questions": [
+			{"type": "multiple_choice", "prompt": "2 + 2", "options": ["3", "4"], "answer": {"options": [1]}},
+			{"type": "true_false", "prompt": "Go has generics", "answer": {"bool": true}, "points": 2},
+			{"type": "numeric", "prompt": "Pi", "answer": {"number": 3.14, "tolerance": 0.01}},
+			{"type": "short_answer", "prompt": "Capital of France", "answer": {"texts": ["Paris"]}}
+		]
+	}`)
+	rec := testutil.Serve("/v1/courses/:id/quizzes", controller.Create(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	quiz := suite.repo.Calls[0].Arguments.Get(1).(*schema.Quiz)
+	assert.Equal(suite.T(), courseObj.ID, quiz.CourseID)
+	assert.Len(suite.T(), quiz.Questions, 4)
+}
+
+func (suite *QuizUseCaseTestSuite) TestAddQuestionController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	quiz, instructorID := suite.managedQuiz()
+
+	suite.repo.On("AddQuestion", mock.Anything, mock.AnythingOfType("*schema.QuizQuestion")).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPost, "/v1/quizzes/"+quiz.ID.String()+"/questions",
+		`{"type": "multi_select", "prompt": "Pick the vowels", "options": ["a", "b", "e"], "answer": {"options": [0, 2]}}`)
+	rec := testutil.Serve("/v1/quizzes/:id/questions", controller.AddQuestion(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusCreated, rec.Code, rec.Body.String())
+	suite.repo.AssertCalled(suite.T(), "AddQuestion", mock.Anything, mock.AnythingOfType("*schema.QuizQuestion"))
+}
+
+func (suite *QuizUseCaseTestSuite) TestUpdateQuestionController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	quiz, instructorID := suite.managedQuiz()
+	question := &schema.QuizQuestion{ID: uuid.New(), QuizID: quiz.ID, Type: schema.QuestionTrueFalse,
+		Prompt: "Go has generics", Answer: &schema.QuizAnswerKey{Bool: testutil.Ptr(false)}, Points: 1}
+
+	suite.repo.On("GetQuestion", mock.Anything, quiz.ID, question.ID).Return(question, nil)
+	suite.repo.On("UpdateQuestion", mock.Anything, question).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPut, "/v1/quizzes/"+quiz.ID.String()+"/questions/"+question.ID.String(),
+		`{"type": "true_false", "prompt": "Go has generics", "answer": {"bool": true}, "points": 3}`)
+	rec := testutil.Serve("/v1/quizzes/:id/questions/:questionId", controller.UpdateQuestion(), instructorID, schema.RoleInstructor, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	assert.True(suite.T(), *question.Answer.Bool)
+	assert.Equal(suite.T(), 3.0, question.Points)
+}
+
+func (suite *QuizUseCaseTestSuite) TestSaveAnswersController_ValidBody() {
+	controller := &RestController{uc: suite.useCase}
+	userID := uuid.New()
+	questionID := uuid.New()
+	attempt := &schema.QuizAttempt{ID: uuid.New(), QuizID: uuid.New(), UserID: userID,
+		QuestionIDs: []uuid.UUID{questionID}, StartedAt: time.Now()}
+
+	suite.repo.On("GetAttempt", mock.Anything, attempt.ID).Return(attempt, nil)
+	suite.repo.On("UpdateAttempt", mock.Anything, attempt).Return(nil)
+
+	req := testutil.JSONRequest(http.MethodPut, "/v1/quiz-attempts/"+attempt.ID.String()+"/answers",
+		`{"answers": {"`+questionID.String()+`": {"options": [1]}}}`)
+	rec := testutil.Serve("/v1/quiz-attempts/:id/answers", controller.SaveAnswers(), userID, schema.RoleStudent, req)
+
+	assert.Equal(suite.T(), http.StatusOK, rec.Code, rec.Body.String())
+	assert.Equal(suite.T(), []int{1}, attempt.Answers[questionID].Options)
+}
+
+func TestQuizUseCase(t *testing.T) {
+	suite.Run(t, new(QuizUseCaseTestSuite))
+}
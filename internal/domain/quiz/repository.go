@@ -0,0 +1,175 @@
+package quiz
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	Create(ctx context.Context, quiz *schema.Quiz) error
+	Update(ctx context.Context, quiz *schema.Quiz) error
+	Delete(ctx context.Context, id uuid.UUID) error
+	GetByID(ctx context.Context, id uuid.UUID) (*schema.Quiz, error)
+	GetSummaries(ctx context.Context, courseID, userID uuid.UUID) ([]QuizSummary, error)
+	AddQuestion(ctx context.Context, question *schema.QuizQuestion) error
+	UpdateQuestion(ctx context.Context, question *schema.QuizQuestion) error
+	DeleteQuestion(ctx context.Context, quizID, questionID uuid.UUID) error
+	GetQuestion(ctx context.Context, quizID, questionID uuid.UUID) (*schema.QuizQuestion, error)
+	GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.QuizQuestion, error)
+	StartAttempt(ctx context.Context, attempt *schema.QuizAttempt, maxAttempts *int) (bool, error)
+	UpdateAttempt(ctx context.Context, attempt *schema.QuizAttempt) error
+	GetAttempt(ctx context.Context, id uuid.UUID) (*schema.QuizAttempt, error)
+	GetAttempts(ctx context.Context, quizID, userID uuid.UUID) ([]schema.QuizAttempt, error)
+	GetAllAttempts(ctx context.Context, quizID uuid.UUID) ([]schema.QuizAttempt, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, quiz *schema.Quiz) error {
+	return r.db.WithContext(ctx).Omit("Course").Create(quiz).Error
+}
+
+func (r *repository) Update(ctx context.Context, quiz *schema.Quiz) error {
+	return r.db.WithContext(ctx).Omit("Course", "Questions").Save(quiz).Error
+}
+
+func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
+	return r.db.WithContext(ctx).Delete(&schema.Quiz{}, "id = ?", id).Error
+}
+
+func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Quiz, error) {
+	var quiz schema.Quiz
+	err := r.db.WithContext(ctx).
+		Preload("Questions", func(db *gorm.DB) *gorm.DB {
+			return db.Order("position, created_at")
+		}).
+		First(&quiz, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &quiz, nil
+}
+
+// GetSummaries lists the quizzes of a course with the attempts and best score of the given user
+func (r *repository) GetSummaries(ctx context.Context, courseID, userID uuid.UUID) ([]QuizSummary, error) {
+	var summaries []QuizSummary
+	err := r.db.WithContext(ctx).Model(&schema.Quiz{}).
+		Select(`quizzes.*,
+			(SELECT COUNT(*) FROM quiz_questions q WHERE q.quiz_id = quizzes.id AND q.deleted_at IS NULL) AS question_count,
+			(SELECT COUNT(*) FROM quiz_attempts a WHERE a.quiz_id = quizzes.id AND a.user_id = ?) AS attempts_used,
+			(SELECT MAX(a.score) FROM quiz_attempts a
+				WHERE a.quiz_id = quizzes.id AND a.user_id = ? AND a.submitted_at IS NOT NULL) AS best_score`,
+			userID, userID).
+		Where("quizzes.course_id = ?", courseID).
+		Order("quizzes.created_at").
+		Scan(&summaries).Error
+	return summaries, err
+}
+
+func (r *repository) AddQuestion(ctx context.Context, question *schema.QuizQuestion) error {
+	return r.db.WithContext(ctx).Create(question).Error
+}
+
+func (r *repository) UpdateQuestion(ctx context.Context, question *schema.QuizQuestion) error {
+	return r.db.WithContext(ctx).Save(question).Error
+}
+
+func (r *repository) DeleteQuestion(ctx context.Context, quizID, questionID uuid.UUID) error {
+	res := r.db.WithContext(ctx).Where("id = ? AND quiz_id = ?", questionID, quizID).Delete(&schema.QuizQuestion{})
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetQuestion(ctx context.Context, quizID, questionID uuid.UUID) (*schema.QuizQuestion, error) {
+	var question schema.QuizQuestion
+	err := r.db.WithContext(ctx).First(&question, "id = ? AND quiz_id = ?", questionID, quizID).Error
+	if err != nil {
+		return nil, err
+	}
+	return &question, nil
+}
+
+// GetQuestionsByIDs includes removed questions, an attempt keeps the questions it drew
+func (r *repository) GetQuestionsByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.QuizQuestion, error) {
+	var questions []schema.QuizQuestion
+	err := r.db.WithContext(ctx).Unscoped().Where("id IN ?", ids).Find(&questions).Error
+	return questions, err
+}
+
+// StartAttempt stores the attempt unless the student already used maxAttempts, the quiz row is locked
+// so two attempts started at once cannot both slip under the limit
+func (r *repository) StartAttempt(ctx context.Context, attempt *schema.QuizAttempt, maxAttempts *int) (bool, error) {
+	started := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var quiz schema.Quiz
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&quiz, "id = ?", attempt.QuizID).Error; err != nil {
+			return err
+		}
+
+		if maxAttempts != nil {
+			var used int64
+			err := tx.Model(&schema.QuizAttempt{}).
+				Where("quiz_id = ? AND user_id = ?", attempt.QuizID, attempt.UserID).
+				Count(&used).Error
+			if err != nil {
+				return err
+			}
+			if used >= int64(*maxAttempts) {
+				return nil
+			}
+		}
+
+		if err := tx.Omit("Quiz", "User").Create(attempt).Error; err != nil {
+			return err
+		}
+		started = true
+		return nil
+	})
+	return started, err
+}
+
+func (r *repository) UpdateAttempt(ctx context.Context, attempt *schema.QuizAttempt) error {
+	return r.db.WithContext(ctx).Omit("Quiz", "User").Save(attempt).Error
+}
+
+func (r *repository) GetAttempt(ctx context.Context, id uuid.UUID) (*schema.QuizAttempt, error) {
+	var attempt schema.QuizAttempt
+	if err := r.db.WithContext(ctx).First(&attempt, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &attempt, nil
+}
+
+func (r *repository) GetAttempts(ctx context.Context, quizID, userID uuid.UUID) ([]schema.QuizAttempt, error) {
+	var attempts []schema.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Order("started_at").
+		Find(&attempts).Error
+	return attempts, err
+}
+
+func (r *repository) GetAllAttempts(ctx context.Context, quizID uuid.UUID) ([]schema.QuizAttempt, error) {
+	var attempts []schema.QuizAttempt
+	err := r.db.WithContext(ctx).
+		Preload("User").
+		Where("quiz_id = ?", quizID).
+		Order("started_at").
+		Find(&attempts).Error
+	return attempts, err
+}
@@ -0,0 +1,323 @@
+package quiz
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	courseQuizGroup := engine.Group("/v1/courses/:id/quizzes", middleware.Authenticate())
+	{
+		courseQuizGroup.GET("", controller.GetByCourse())
+		courseQuizGroup.POST("", controller.Create())
+	}
+
+	quizGroup := engine.Group("/v1/quizzes", middleware.Authenticate())
+	{
+		quizGroup.GET("/:id", controller.GetByID())
+		quizGroup.PATCH("/:id", controller.Update())
+		quizGroup.DELETE("/:id", controller.Delete())
+		quizGroup.POST("/:id/questions", controller.AddQuestion())
+		quizGroup.PUT("/:id/questions/:questionId", controller.UpdateQuestion())
+		quizGroup.DELETE("/:id/questions/:questionId", controller.DeleteQuestion())
+		quizGroup.GET("/:id/results", controller.GetResults())
+		quizGroup.GET("/:id/attempts", controller.GetMyAttempts())
+		quizGroup.POST("/:id/attempts", middleware.RequireRole("student"), controller.StartAttempt())
+	}
+
+	attemptGroup := engine.Group("/v1/quiz-attempts", middleware.Authenticate())
+	{
+		attemptGroup.GET("/:id", controller.GetAttempt())
+		attemptGroup.PUT("/:id/answers", middleware.RequireRole("student"), controller.SaveAnswers())
+		attemptGroup.POST("/:id/submit", middleware.RequireRole("student"), controller.Submit())
+	}
+}
+
+func (c *RestController) Create() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := CreateQuizRequest{CourseID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Create(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_QUIZ_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByCourse() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByCourse(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_QUIZZES_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetByID() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuizIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetByID(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_QUIZ_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Update() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateQuizRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Update(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_QUIZ_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Delete() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuizIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Delete(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_QUIZ_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) AddQuestion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := AddQuestionRequest{QuizID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.AddQuestion(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "ADD_QUESTION_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) UpdateQuestion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := UpdateQuestionRequest{QuizID: ctx.Param("id"), QuestionID: ctx.Param("questionId")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.UpdateQuestion(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_QUESTION_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) DeleteQuestion() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuestionIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.DeleteQuestion(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_QUESTION_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) StartAttempt() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuizIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.StartAttempt(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "START_ATTEMPT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetMyAttempts() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuizIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetMyAttempts(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_ATTEMPTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetResults() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req QuizIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetResults(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_QUIZ_RESULTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetAttempt() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AttemptIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetAttempt(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_ATTEMPT_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) SaveAnswers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := SaveAnswersRequest{ID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.SaveAnswers(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "SAVE_ANSWERS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Submit() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SubmitAttemptRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		// the body is optional, the saved answers are submitted as they are
+		if ctx.Request.ContentLength != 0 {
+			if err := ctx.ShouldBindJSON(&req); err != nil {
+				err2 := apierror.ErrValidation.Build()
+				response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+				return
+			}
+		}
+
+		res, err := c.uc.Submit(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "SUBMIT_ATTEMPT_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,181 @@
+package quiz
+
+import (
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+)
+
+// buildQuestion checks that the answer key fits the question type and copies the input onto question
+func buildQuestion(question *schema.QuizQuestion, in *QuestionInput) error {
+	invalid := func(reason string) error {
+		return ErrInvalidQuestion.WithPayload(map[string]any{"reason": reason}).Build()
+	}
+
+	key := in.Answer
+	options := in.Options
+	switch in.Type {
+	case schema.QuestionMultipleChoice, schema.QuestionMultiSelect:
+		if len(options) < 2 {
+			return invalid("at least two options are required")
+		}
+		if in.Type == schema.QuestionMultipleChoice && len(key.Options) != 1 {
+			return invalid("a multiple choice question has exactly one correct option")
+		}
+		if len(key.Options) == 0 {
+			return invalid("at least one correct option is required")
+		}
+		seen := make(map[int]bool, len(key.Options))
+		for _, idx := range key.Options {
+			if idx < 0 || idx >= len(options) || seen[idx] {
+				return invalid("answer options must be distinct option indexes")
+			}
+			seen[idx] = true
+		}
+		sorted := slices.Clone(key.Options)
+		slices.Sort(sorted)
+		key = schema.QuizAnswerKey{Options: sorted}
+	case schema.QuestionTrueFalse:
+		if key.Bool == nil {
+			return invalid("answer.bool is required")
+		}
+		options = nil
+		key = schema.QuizAnswerKey{Bool: key.Bool}
+	case schema.QuestionNumeric:
+		if key.Number == nil {
+			return invalid("answer.number is required")
+		}
+		if key.Tolerance < 0 {
+			return invalid("answer.tolerance cannot be negative")
+		}
+		options = nil
+		key = schema.QuizAnswerKey{Number: key.Number, Tolerance: key.Tolerance}
+	case schema.QuestionShortAnswer:
+		var texts []string
+		for _, text := range key.Texts {
+			if text = normalizeText(text); text != "" && !slices.Contains(texts, text) {
+				texts = append(texts, text)
+			}
+		}
+		if len(texts) == 0 {
+			return invalid("at least one accepted answer is required")
+		}
+		options = nil
+		key = schema.QuizAnswerKey{Texts: texts}
+	default:
+		return invalid("unknown question type")
+	}
+
+	question.Type = in.Type
+	question.Prompt = in.Prompt
+	question.Options = options
+	question.Answer = &key
+	question.Points = 1
+	if in.Points != nil {
+		question.Points = *in.Points
+	}
+	if in.Position != nil {
+		question.Position = *in.Position
+	}
+	return nil
+}
+
+// normalizeText makes short answers comparable regardless of case and spacing
+func normalizeText(text string) string {
+	return strings.ToLower(strings.Join(strings.Fields(text), " "))
+}
+
+// scoreAnswer grades one response. Multi-select questions get partial credit, each wrong option
+// cancels a correct one
+func scoreAnswer(question *schema.QuizQuestion, resp *schema.QuizResponse) schema.QuestionResult {
+	result := schema.QuestionResult{QuestionID: question.ID, MaxPoints: question.Points}
+	key := question.Answer
+	if resp == nil || key == nil {
+		return result
+	}
+
+	ratio := 0.0
+	switch question.Type {
+	case schema.QuestionMultipleChoice:
+		if len(resp.Options) == 1 && len(key.Options) == 1 && resp.Options[0] == key.Options[0] {
+			ratio = 1
+		}
+	case schema.QuestionMultiSelect:
+		if len(key.Options) == 0 {
+			break
+		}
+		picked := make(map[int]bool, len(resp.Options))
+		hits, misses := 0, 0
+		for _, idx := range resp.Options {
+			if picked[idx] {
+				continue
+			}
+			picked[idx] = true
+			if slices.Contains(key.Options, idx) {
+				hits++
+			} else {
+				misses++
+			}
+		}
+		ratio = max(0, float64(hits-misses)/float64(len(key.Options)))
+	case schema.QuestionTrueFalse:
+		if resp.Bool != nil && key.Bool != nil && *resp.Bool == *key.Bool {
+			ratio = 1
+		}
+	case schema.QuestionNumeric:
+		if resp.Number != nil && key.Number != nil && math.Abs(*resp.Number-*key.Number) <= key.Tolerance+1e-9 {
+			ratio = 1
+		}
+	case schema.QuestionShortAnswer:
+		if resp.Text != nil && slices.Contains(key.Texts, normalizeText(*resp.Text)) {
+			ratio = 1
+		}
+	}
+
+	result.Points = roundTo(question.Points*ratio, 2)
+	result.Correct = ratio == 1
+	return result
+}
+
+// grade scores every drawn question of the attempt and sets its points and percentage
+func grade(attempt *schema.QuizAttempt, questions []schema.QuizQuestion) {
+	byID := make(map[string]*schema.QuizQuestion, len(questions))
+	for i := range questions {
+		byID[questions[i].ID.String()] = &questions[i]
+	}
+
+	results := make([]schema.QuestionResult, 0, len(attempt.QuestionIDs))
+	points, maxPoints := 0.0, 0.0
+	for _, id := range attempt.QuestionIDs {
+		question, ok := byID[id.String()]
+		if !ok {
+			continue
+		}
+		var resp *schema.QuizResponse
+		if answer, ok := attempt.Answers[id]; ok {
+			resp = &answer
+		}
+		result := scoreAnswer(question, resp)
+		results = append(results, result)
+		points += result.Points
+		maxPoints += result.MaxPoints
+	}
+
+	score := 0.0
+	if maxPoints > 0 {
+		score = roundTo(points/maxPoints*100, 1)
+	}
+	points = roundTo(points, 2)
+
+	attempt.Results = results
+	attempt.Points = &points
+	attempt.MaxPoints = roundTo(maxPoints, 2)
+	attempt.Score = &score
+}
+
+func roundTo(value float64, places int) float64 {
+	factor := math.Pow(10, float64(places))
+	return math.Round(value*factor) / factor
+}
@@ -0,0 +1,706 @@
+package quiz
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/certificate"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// submitGrace is how long after the deadline a submission is still accepted with its answers,
+// it covers the request of a student who pressed submit at the last second
+const submitGrace = 30 * time.Second
+
+type UseCase struct {
+	repo          Repository
+	courseUc      *course.UseCase
+	enrollUc      *courseenroll.UseCase
+	CertificateUc *certificate.UseCase
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase) *UseCase {
+	return &UseCase{
+		repo:     repo,
+		courseUc: courseUc,
+		enrollUc: enrollUc,
+	}
+}
+
+func currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return uuid.Nil, apierror.ErrTokenInvalid.Build()
+	}
+	return userID, nil
+}
+
+// getMemberCourse returns the course when the current user manages it or has access to it as a student,
+// the flag tells which of the two
+func (uc *UseCase) getMemberCourse(ctx context.Context, courseID uuid.UUID) (*schema.Course, bool, error) {
+	courseObj, err := uc.courseUc.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, false, err
+	}
+	if course.IsManager(ctx, courseObj) {
+		return courseObj, true, nil
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+	enrolled, err := uc.enrollUc.CheckEnrollment(ctx, userID, courseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, false, apierror.ErrInternalServer.Build()
+	}
+	if !enrolled {
+		return nil, false, courseenroll.ErrNotEnrolled.Build()
+	}
+	return courseObj, false, nil
+}
+
+func (uc *UseCase) getQuiz(ctx context.Context, idStr string) (*schema.Quiz, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	quiz, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuizNotFound.Build()
+		}
+		log.Println("Error getting quiz: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return quiz, nil
+}
+
+// getManagedQuiz returns the quiz when the current user manages its course
+func (uc *UseCase) getManagedQuiz(ctx context.Context, idStr string) (*schema.Quiz, error) {
+	quiz, err := uc.getQuiz(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.courseUc.GetManagedCourse(ctx, quiz.CourseID); err != nil {
+		return nil, err
+	}
+	return quiz, nil
+}
+
+func (uc *UseCase) Create(ctx context.Context, req *CreateQuizRequest) (*schema.Quiz, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	courseObj, err := uc.courseUc.GetManagedCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	quiz := &schema.Quiz{
+		ID:                  id,
+		CourseID:            courseObj.ID,
+		Title:               req.Title,
+		Description:         req.Description,
+		TimeLimitSeconds:    req.TimeLimitSeconds,
+		MaxAttempts:         req.MaxAttempts,
+		QuestionsPerAttempt: req.QuestionsPerAttempt,
+		ShuffleQuestions:    req.ShuffleQuestions,
+	}
+
+	for i := range req.Questions {
+		questionID, err := uuid.NewV7()
+		if err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+
+		question := schema.QuizQuestion{ID: questionID, QuizID: id, Position: i}
+		if err := buildQuestion(&question, &req.Questions[i]); err != nil {
+			return nil, err
+		}
+		quiz.Questions = append(quiz.Questions, question)
+	}
+
+	if err := uc.repo.Create(ctx, quiz); err != nil {
+		log.Println("Error creating quiz: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return quiz, nil
+}
+
+// nullable maps the 0 of an update request to no limit
+func nullable(value *int) *int {
+	if *value == 0 {
+		return nil
+	}
+	return value
+}
+
+func (uc *UseCase) Update(ctx context.Context, req *UpdateQuizRequest) (*schema.Quiz, error) {
+	quiz, err := uc.getManagedQuiz(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Title != nil {
+		quiz.Title = *req.Title
+	}
+	if req.Description != nil {
+		quiz.Description = *req.Description
+	}
+	if req.TimeLimitSeconds != nil {
+		quiz.TimeLimitSeconds = nullable(req.TimeLimitSeconds)
+	}
+	if req.MaxAttempts != nil {
+		quiz.MaxAttempts = nullable(req.MaxAttempts)
+	}
+	if req.QuestionsPerAttempt != nil {
+		quiz.QuestionsPerAttempt = nullable(req.QuestionsPerAttempt)
+	}
+	if req.ShuffleQuestions != nil {
+		quiz.ShuffleQuestions = *req.ShuffleQuestions
+	}
+
+	if err := uc.repo.Update(ctx, quiz); err != nil {
+		log.Println("Error updating quiz: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return quiz, nil
+}
+
+func (uc *UseCase) Delete(ctx context.Context, req *QuizIDRequest) error {
+	quiz, err := uc.getManagedQuiz(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.Delete(ctx, quiz.ID); err != nil {
+		log.Println("Error deleting quiz: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+func (uc *UseCase) GetByCourse(ctx context.Context, req *CourseIDRequest) ([]QuizSummary, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	if _, _, err := uc.getMemberCourse(ctx, courseID); err != nil {
+		return nil, err
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := uc.repo.GetSummaries(ctx, courseID, userID)
+	if err != nil {
+		log.Println("Error getting quizzes: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return summaries, nil
+}
+
+// GetByID shows the quiz with its question bank and answer keys to the course staff, students only
+// see the summary
+func (uc *UseCase) GetByID(ctx context.Context, req *QuizIDRequest) (*QuizSummary, error) {
+	quiz, err := uc.getQuiz(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, manager, err := uc.getMemberCourse(ctx, quiz.CourseID)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := uc.repo.GetAttempts(ctx, quiz.ID, userID)
+	if err != nil {
+		log.Println("Error getting quiz attempts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	summary := &QuizSummary{Quiz: *quiz, QuestionCount: int64(len(quiz.Questions)), AttemptsUsed: int64(len(attempts))}
+	for _, attempt := range attempts {
+		if attempt.SubmittedAt != nil && attempt.Score != nil &&
+			(summary.BestScore == nil || *attempt.Score > *summary.BestScore) {
+			summary.BestScore = attempt.Score
+		}
+	}
+	if !manager {
+		summary.Questions = nil
+	}
+	return summary, nil
+}
+
+func (uc *UseCase) AddQuestion(ctx context.Context, req *AddQuestionRequest) (*schema.QuizQuestion, error) {
+	quiz, err := uc.getManagedQuiz(ctx, req.QuizID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	// new questions go to the end of the bank unless a position is given
+	question := &schema.QuizQuestion{ID: id, QuizID: quiz.ID}
+	for _, q := range quiz.Questions {
+		question.Position = max(question.Position, q.Position+1)
+	}
+	if err := buildQuestion(question, &req.QuestionInput); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.AddQuestion(ctx, question); err != nil {
+		log.Println("Error adding quiz question: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return question, nil
+}
+
+func (uc *UseCase) getQuestion(ctx context.Context, quizIDStr, questionIDStr string) (*schema.QuizQuestion, error) {
+	quiz, err := uc.getManagedQuiz(ctx, quizIDStr)
+	if err != nil {
+		return nil, err
+	}
+
+	questionID, err := uuid.Parse(questionIDStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	question, err := uc.repo.GetQuestion(ctx, quiz.ID, questionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrQuestionNotFound.Build()
+		}
+		log.Println("Error getting quiz question: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return question, nil
+}
+
+// UpdateQuestion replaces a question of the bank, attempts already submitted keep their score
+func (uc *UseCase) UpdateQuestion(ctx context.Context, req *UpdateQuestionRequest) (*schema.QuizQuestion, error) {
+	question, err := uc.getQuestion(ctx, req.QuizID, req.QuestionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := buildQuestion(question, &req.QuestionInput); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateQuestion(ctx, question); err != nil {
+		log.Println("Error updating quiz question: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return question, nil
+}
+
+func (uc *UseCase) DeleteQuestion(ctx context.Context, req *QuestionIDRequest) error {
+	question, err := uc.getQuestion(ctx, req.QuizID, req.QuestionID)
+	if err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteQuestion(ctx, question.QuizID, question.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrQuestionNotFound.Build()
+		}
+		log.Println("Error deleting quiz question: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+// drawQuestions picks the questions of a new attempt. A partial draw is always random, the full bank
+// keeps the authored order unless the quiz shuffles it
+func drawQuestions(quiz *schema.Quiz) []schema.QuizQuestion {
+	questions := slices.Clone(quiz.Questions)
+	partial := quiz.QuestionsPerAttempt != nil && *quiz.QuestionsPerAttempt < len(questions)
+	if quiz.ShuffleQuestions || partial {
+		rand.Shuffle(len(questions), func(i, j int) {
+			questions[i], questions[j] = questions[j], questions[i]
+		})
+	}
+	if partial {
+		questions = questions[:*quiz.QuestionsPerAttempt]
+	}
+	return questions
+}
+
+// expired reports whether the time of an in-progress attempt ran out, grace included
+func expired(attempt *schema.QuizAttempt, now time.Time) bool {
+	return attempt.SubmittedAt == nil && attempt.DeadlineAt != nil && now.After(attempt.DeadlineAt.Add(submitGrace))
+}
+
+// loadQuestions fills the questions drawn for the attempt in the order they were drawn
+func (uc *UseCase) loadQuestions(ctx context.Context, attempt *schema.QuizAttempt) error {
+	questions, err := uc.repo.GetQuestionsByIDs(ctx, attempt.QuestionIDs)
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]schema.QuizQuestion, len(questions))
+	for _, q := range questions {
+		byID[q.ID] = q
+	}
+	attempt.Questions = make([]schema.QuizQuestion, 0, len(attempt.QuestionIDs))
+	for _, id := range attempt.QuestionIDs {
+		if q, ok := byID[id]; ok {
+			attempt.Questions = append(attempt.Questions, q)
+		}
+	}
+	return nil
+}
+
+// finish grades the attempt with the answers it holds and closes it
+func (uc *UseCase) finish(ctx context.Context, attempt *schema.QuizAttempt, submittedAt time.Time) error {
+	if attempt.Questions == nil {
+		if err := uc.loadQuestions(ctx, attempt); err != nil {
+			return err
+		}
+	}
+
+	grade(attempt, attempt.Questions)
+	attempt.SubmittedAt = &submittedAt
+	return uc.repo.UpdateAttempt(ctx, attempt)
+}
+
+// closeExpired submits an attempt whose time ran out with the answers saved before the deadline
+func (uc *UseCase) closeExpired(ctx context.Context, attempt *schema.QuizAttempt) error {
+	if !expired(attempt, time.Now()) {
+		return nil
+	}
+	if err := uc.finish(ctx, attempt, *attempt.DeadlineAt); err != nil {
+		return err
+	}
+
+	quiz, err := uc.repo.GetByID(ctx, attempt.QuizID)
+	if err == nil {
+		go uc.issueCertificate(context.Background(), attempt.UserID, quiz.CourseID)
+	}
+	return nil
+}
+
+// present hides the answer keys of the drawn questions from students
+func present(attempt *schema.QuizAttempt, manager bool) *schema.QuizAttempt {
+	if manager {
+		return attempt
+	}
+	for i := range attempt.Questions {
+		attempt.Questions[i].Answer = nil
+	}
+	return attempt
+}
+
+// StartAttempt opens a new attempt at the quiz, or returns the attempt the student already has in progress
+func (uc *UseCase) StartAttempt(ctx context.Context, req *QuizIDRequest) (*schema.QuizAttempt, error) {
+	quiz, err := uc.getQuiz(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := uc.getMemberCourse(ctx, quiz.CourseID); err != nil {
+		return nil, err
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := uc.repo.GetAttempts(ctx, quiz.ID, userID)
+	if err != nil {
+		log.Println("Error getting quiz attempts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	for i := range attempts {
+		attempt := &attempts[i]
+		if attempt.SubmittedAt != nil {
+			continue
+		}
+		if err := uc.closeExpired(ctx, attempt); err != nil {
+			log.Println("Error closing quiz attempt: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		if attempt.SubmittedAt == nil {
+			if err := uc.loadQuestions(ctx, attempt); err != nil {
+				log.Println("Error getting quiz questions: ", err)
+				return nil, apierror.ErrInternalServer.Build()
+			}
+			return present(attempt, false), nil
+		}
+	}
+
+	if len(quiz.Questions) == 0 {
+		return nil, ErrQuizEmpty.Build()
+	}
+	if quiz.MaxAttempts != nil && len(attempts) >= *quiz.MaxAttempts {
+		return nil, ErrAttemptLimitReached.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	questions := drawQuestions(quiz)
+	now := time.Now()
+	attempt := &schema.QuizAttempt{
+		ID:          id,
+		QuizID:      quiz.ID,
+		UserID:      userID,
+		QuestionIDs: make([]uuid.UUID, 0, len(questions)),
+		Answers:     map[uuid.UUID]schema.QuizResponse{},
+		StartedAt:   now,
+		Questions:   questions,
+	}
+	for _, q := range questions {
+		attempt.QuestionIDs = append(attempt.QuestionIDs, q.ID)
+		attempt.MaxPoints += q.Points
+	}
+	if quiz.TimeLimitSeconds != nil {
+		deadline := now.Add(time.Duration(*quiz.TimeLimitSeconds) * time.Second)
+		attempt.DeadlineAt = &deadline
+	}
+
+	started, err := uc.repo.StartAttempt(ctx, attempt, quiz.MaxAttempts)
+	if err != nil {
+		log.Println("Error starting quiz attempt: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if !started {
+		return nil, ErrAttemptLimitReached.Build()
+	}
+
+	return present(attempt, false), nil
+}
+
+// getAttempt returns the attempt when it belongs to the current user or the user manages the quiz course.
+// An attempt whose time ran out is submitted first
+func (uc *UseCase) getAttempt(ctx context.Context, idStr string) (*schema.QuizAttempt, bool, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, false, apierror.ErrInvalidParamId.Build()
+	}
+
+	attempt, err := uc.repo.GetAttempt(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, false, ErrAttemptNotFound.Build()
+		}
+		log.Println("Error getting quiz attempt: ", err)
+		return nil, false, apierror.ErrInternalServer.Build()
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	manager := false
+	if attempt.UserID != userID {
+		quiz, err := uc.repo.GetByID(ctx, attempt.QuizID)
+		if err != nil {
+			return nil, false, ErrAttemptNotFound.Build()
+		}
+		if _, err := uc.courseUc.GetManagedCourse(ctx, quiz.CourseID); err != nil {
+			return nil, false, ErrAttemptNotFound.Build()
+		}
+		manager = true
+	}
+
+	if err := uc.closeExpired(ctx, attempt); err != nil {
+		log.Println("Error closing quiz attempt: ", err)
+		return nil, false, apierror.ErrInternalServer.Build()
+	}
+	return attempt, manager, nil
+}
+
+func (uc *UseCase) GetAttempt(ctx context.Context, req *AttemptIDRequest) (*schema.QuizAttempt, error) {
+	attempt, manager, err := uc.getAttempt(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if attempt.Questions == nil {
+		if err := uc.loadQuestions(ctx, attempt); err != nil {
+			log.Println("Error getting quiz questions: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+	}
+	return present(attempt, manager), nil
+}
+
+// mergeAnswers copies the given answers onto the attempt, answers to questions it did not draw are rejected
+func mergeAnswers(attempt *schema.QuizAttempt, answers map[uuid.UUID]schema.QuizResponse) error {
+	for id, answer := range answers {
+		if !slices.Contains(attempt.QuestionIDs, id) {
+			return ErrUnknownQuestion.WithPayload(map[string]any{"question_id": id}).Build()
+		}
+		if attempt.Answers == nil {
+			attempt.Answers = map[uuid.UUID]schema.QuizResponse{}
+		}
+		attempt.Answers[id] = answer
+	}
+	return nil
+}
+
+// getOwnAttempt returns an in-progress attempt of the current user
+func (uc *UseCase) getOwnAttempt(ctx context.Context, idStr string) (*schema.QuizAttempt, error) {
+	attempt, manager, err := uc.getAttempt(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	if manager {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	if attempt.SubmittedAt != nil {
+		return nil, ErrAttemptClosed.Build()
+	}
+	return attempt, nil
+}
+
+// SaveAnswers stores answers of an attempt in progress without submitting it
+func (uc *UseCase) SaveAnswers(ctx context.Context, req *SaveAnswersRequest) (*schema.QuizAttempt, error) {
+	attempt, err := uc.getOwnAttempt(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if attempt.DeadlineAt != nil && time.Now().After(*attempt.DeadlineAt) {
+		return nil, ErrAttemptClosed.Build()
+	}
+
+	if err := mergeAnswers(attempt, req.Answers); err != nil {
+		return nil, err
+	}
+
+	if err := uc.repo.UpdateAttempt(ctx, attempt); err != nil {
+		log.Println("Error saving quiz answers: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return attempt, nil
+}
+
+// Submit scores the attempt. Answers sent with the submission are taken as long as it arrives within
+// the grace period after the deadline
+func (uc *UseCase) Submit(ctx context.Context, req *SubmitAttemptRequest) (*schema.QuizAttempt, error) {
+	attempt, err := uc.getOwnAttempt(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mergeAnswers(attempt, req.Answers); err != nil {
+		return nil, err
+	}
+
+	if err := uc.finish(ctx, attempt, time.Now()); err != nil {
+		log.Println("Error submitting quiz attempt: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	if quiz, err := uc.repo.GetByID(ctx, attempt.QuizID); err == nil {
+		go uc.issueCertificate(context.Background(), attempt.UserID, quiz.CourseID)
+	}
+
+	return present(attempt, false), nil
+}
+
+// GetMyAttempts lists the attempts of the current user at the quiz
+func (uc *UseCase) GetMyAttempts(ctx context.Context, req *QuizIDRequest) ([]schema.QuizAttempt, error) {
+	quiz, err := uc.getQuiz(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, _, err := uc.getMemberCourse(ctx, quiz.CourseID); err != nil {
+		return nil, err
+	}
+
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := uc.repo.GetAttempts(ctx, quiz.ID, userID)
+	if err != nil {
+		log.Println("Error getting quiz attempts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	for i := range attempts {
+		if err := uc.closeExpired(ctx, &attempts[i]); err != nil {
+			log.Println("Error closing quiz attempt: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		attempts[i].Questions = nil
+	}
+	return attempts, nil
+}
+
+// GetResults lists every attempt at the quiz for the course staff
+func (uc *UseCase) GetResults(ctx context.Context, req *QuizIDRequest) ([]schema.QuizAttempt, error) {
+	quiz, err := uc.getManagedQuiz(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	attempts, err := uc.repo.GetAllAttempts(ctx, quiz.ID)
+	if err != nil {
+		log.Println("Error getting quiz attempts: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	for i := range attempts {
+		if err := uc.closeExpired(ctx, &attempts[i]); err != nil {
+			log.Println("Error closing quiz attempt: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		attempts[i].Questions = nil
+	}
+	return attempts, nil
+}
+
+// issueCertificate generates the course certificate once the student becomes eligible. It runs after the
+// request is done, so it is given a fresh context rather than the request one gin hands to the next request
+func (uc *UseCase) issueCertificate(ctx context.Context, userID, courseID uuid.UUID) {
+	if uc.CertificateUc == nil {
+		return
+	}
+
+	if _, err := uc.CertificateUc.IssueIfEligible(ctx, userID, courseID); err != nil &&
+		apierror.GetHttpStatus(err) == http.StatusInternalServerError {
+		log.Println("Error issuing certificate: ", err)
+	}
+}
@@ -0,0 +1,127 @@
+package release
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetDue(ctx context.Context, since, until time.Time) ([]DueRelease, error) {
+	args := m.Called(ctx, since, until)
+	return args.Get(0).([]DueRelease), args.Error(1)
+}
+
+func (m *MockRepository) MarkNotified(ctx context.Context, notices []schema.ReleaseNotice) error {
+	args := m.Called(ctx, notices)
+	return args.Error(0)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type ReleaseUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *ReleaseUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	suite.useCase = NewUseCase(suite.repo, suite.notificationRepo)
+}
+
+func (suite *ReleaseUseCaseTestSuite) TestNotifyReleases_NotifiesEachStudent() {
+	ctx := context.Background()
+	studentA, studentB := uuid.New(), uuid.New()
+	lessonID, assignmentID := uuid.New(), uuid.New()
+	due := []DueRelease{
+		{UserID: studentA, Kind: KindMaterial, ItemID: lessonID, Title: "Week 2: Channels", CourseTitle: "Go"},
+		{UserID: studentB, Kind: KindAssignment, ItemID: assignmentID, Title: "Build a worker pool", CourseTitle: "Go"},
+	}
+
+	suite.repo.On("GetDue", ctx, mock.Anything, mock.Anything).Return(due, nil)
+	suite.repo.On("MarkNotified", ctx, []schema.ReleaseNotice{
+		{UserID: studentA, ItemID: lessonID},
+		{UserID: studentB, ItemID: assignmentID},
+	}).Return(nil)
+	suite.notificationRepo.On("Create", mock.MatchedBy(func(n *schema.Notification) bool {
+		return n.UserID == studentA && n.Title == "A new lesson is available!" &&
+			n.Detail == "Week 2: Channels is now available in Go"
+	})).Return(nil).Once()
+	suite.notificationRepo.On("Create", mock.MatchedBy(func(n *schema.Notification) bool {
+		return n.UserID == studentB && n.Title == "A new assignment is open!"
+	})).Return(nil).Once()
+
+	sent, err := suite.useCase.NotifyReleases(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, sent)
+	suite.repo.AssertExpectations(suite.T())
+	suite.notificationRepo.AssertExpectations(suite.T())
+}
+
+func (suite *ReleaseUseCaseTestSuite) TestNotifyReleases_LooksBackOneWindow() {
+	ctx := context.Background()
+
+	suite.repo.On("GetDue", ctx, mock.Anything, mock.Anything).Return([]DueRelease{}, nil).Run(func(args mock.Arguments) {
+		since, until := args.Get(1).(time.Time), args.Get(2).(time.Time)
+		assert.Equal(suite.T(), noticeWindow, until.Sub(since))
+	})
+
+	sent, err := suite.useCase.NotifyReleases(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.Zero(suite.T(), sent)
+	suite.repo.AssertNotCalled(suite.T(), "MarkNotified", mock.Anything, mock.Anything)
+}
+
+func (suite *ReleaseUseCaseTestSuite) TestNotifyReleases_NothingSentWhenMarkingFails() {
+	ctx := context.Background()
+	due := []DueRelease{{UserID: uuid.New(), Kind: KindMaterial, ItemID: uuid.New(), Title: "Intro", CourseTitle: "Go"}}
+
+	suite.repo.On("GetDue", ctx, mock.Anything, mock.Anything).Return(due, nil)
+	suite.repo.On("MarkNotified", ctx, mock.Anything).Return(errors.New("db down"))
+
+	_, err := suite.useCase.NotifyReleases(ctx)
+
+	assert.Error(suite.T(), err)
+	suite.notificationRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+}
+
+func TestReleaseUseCase(t *testing.T) {
+	suite.Run(t, new(ReleaseUseCaseTestSuite))
+}
@@ -0,0 +1,82 @@
+package release
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ItemKind string
+
+const (
+	KindMaterial   ItemKind = "material"
+	KindAssignment ItemKind = "assignment"
+)
+
+// DueRelease is a scheduled item that opened for an enrolled student who was not told about it yet
+type DueRelease struct {
+	UserID      uuid.UUID
+	Kind        ItemKind
+	ItemID      uuid.UUID
+	Title       string
+	CourseID    uuid.UUID
+	CourseTitle string
+	AvailableAt time.Time
+}
+
+type Repository interface {
+	GetDue(ctx context.Context, since, until time.Time) ([]DueRelease, error)
+	MarkNotified(ctx context.Context, notices []schema.ReleaseNotice) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetDue lists the items that opened between since and until for students with an active enrollment.
+// Schedules count from the first enrollment in the course, items that were already open when the
+// student enrolled are left out since they were never locked for them
+func (r *repository) GetDue(ctx context.Context, since, until time.Time) ([]DueRelease, error) {
+	var due []DueRelease
+	err := r.db.WithContext(ctx).Raw(`
+		WITH enrolled AS (
+			SELECT user_id, course_id, MIN(created_at) AS enrolled_at
+			FROM course_enrolls
+			GROUP BY user_id, course_id
+			HAVING bool_or(expires_at IS NULL OR expires_at > @until)
+		), items AS (
+			SELECT 'material' AS kind, id, course_id, title, release_at, release_after_days FROM materials
+			WHERE deleted_at IS NULL AND (release_at IS NOT NULL OR release_after_days IS NOT NULL)
+			UNION ALL
+			SELECT 'assignment' AS kind, id, course_id, title, release_at, release_after_days FROM assignments
+			WHERE deleted_at IS NULL AND (release_at IS NOT NULL OR release_after_days IS NOT NULL)
+		), releases AS (
+			SELECT e.user_id, i.kind, i.id AS item_id, i.title, c.id AS course_id, c.title AS course_title, e.enrolled_at,
+				GREATEST(i.release_at, e.enrolled_at + make_interval(days => i.release_after_days)) AS available_at
+			FROM items i
+			JOIN enrolled e ON e.course_id = i.course_id
+			JOIN courses c ON c.id = i.course_id AND c.deleted_at IS NULL
+		)
+		SELECT user_id, kind, item_id, title, course_id, course_title, available_at FROM releases
+		WHERE available_at > @since AND available_at <= @until AND available_at > enrolled_at
+			AND NOT EXISTS (
+				SELECT 1 FROM release_notices n WHERE n.user_id = releases.user_id AND n.item_id = releases.item_id
+			)
+		ORDER BY available_at`,
+		map[string]any{"since": since, "until": until}).
+		Scan(&due).Error
+	return due, err
+}
+
+// MarkNotified records the notices, the ones already recorded by a concurrent run are skipped
+func (r *repository) MarkNotified(ctx context.Context, notices []schema.ReleaseNotice) error {
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{DoNothing: true}).Create(&notices).Error
+}
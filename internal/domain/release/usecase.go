@@ -0,0 +1,92 @@
+package release
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// noticeWindow is how far back a run looks for releases, so items that opened while the
+// worker was down are still announced without going back to releases from long ago
+const noticeWindow = 24 * time.Hour
+
+type UseCase struct {
+	repo             Repository
+	notificationRepo notification.IRepository
+}
+
+func NewUseCase(repo Repository, notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{repo: repo, notificationRepo: notificationRepo}
+}
+
+// NotifyReleases tells enrolled students about the scheduled materials and assignments that opened for them
+// since the last run, each student hears about an item once. It returns how many notifications were sent
+func (uc *UseCase) NotifyReleases(ctx context.Context) (int, error) {
+	now := time.Now()
+	due, err := uc.repo.GetDue(ctx, now.Add(-noticeWindow), now)
+	if err != nil {
+		return 0, err
+	}
+	if len(due) == 0 {
+		return 0, nil
+	}
+
+	// Recording first means a failing notification is skipped rather than repeated on every run
+	notices := make([]schema.ReleaseNotice, len(due))
+	for i, item := range due {
+		notices[i] = schema.ReleaseNotice{UserID: item.UserID, ItemID: item.ItemID}
+	}
+	if err := uc.repo.MarkNotified(ctx, notices); err != nil {
+		return 0, err
+	}
+
+	for _, item := range due {
+		title := "A new lesson is available!"
+		if item.Kind == KindAssignment {
+			title = "A new assignment is open!"
+		}
+		uc.notify(item.UserID, title, fmt.Sprintf("%s is now available in %s", item.Title, item.CourseTitle))
+	}
+
+	return len(due), nil
+}
+
+// StartNotifyWorker runs NotifyReleases each interval until the context is done
+func (uc *UseCase) StartNotifyWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := uc.NotifyReleases(ctx); err != nil {
+			log.Println("Error notifying content releases: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *UseCase) notify(userID uuid.UUID, title, detail string) {
+	notificationID, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+
+	notif := schema.Notification{
+		ID:     notificationID,
+		UserID: userID,
+		Title:  title,
+		Detail: detail,
+	}
+	if err := uc.notificationRepo.Create(&notif); err != nil {
+		log.Println("Error creating notification: ", err)
+	}
+}
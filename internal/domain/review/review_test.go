@@ -3,6 +3,7 @@ package review
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
@@ -103,11 +104,61 @@ func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string,
 	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
 }
 
-func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filterType, filterValue, sort string, page, limit int) ([]schema.Course, int, error) {
-	args := m.Called(ctx, filterType, filterValue, sort, page, limit)
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
 	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
 }
 
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
 type MockEnrollRepository struct {
 	mock.Mock
 }
@@ -132,6 +183,32 @@ func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
 type ReviewUseCaseTestSuite struct {
 	suite.Suite
 	reviewRepo    *MockReviewRepository
@@ -158,7 +235,7 @@ func (suite *ReviewUseCaseTestSuite) TestCreateReview_Success() {
 		Feedback: "Great course!",
 	}
 
-	suite.enrollRepo.On("IsEnrolled", ctx, userID, req.CourseID).Return(true, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, req.CourseID).Return(&schema.CourseEnroll{}, nil)
 	suite.courseRepo.On("GetRating", mock.Anything, req.CourseID).Return(float32(4.0), int64(10), nil)
 	suite.reviewRepo.On("Create", mock.Anything, mock.Anything, mock.Anything).Return(nil)
 
@@ -194,7 +271,8 @@ func (suite *ReviewUseCaseTestSuite) TestCreateReview_NotEnrolled() {
 		Feedback: "Great course!",
 	}
 
-	suite.enrollRepo.On("IsEnrolled", ctx, userID, req.CourseID).Return(false, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, req.CourseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
 
 	res, err := suite.reviewUseCase.Create(ctx, req)
 
@@ -213,7 +291,7 @@ func (suite *ReviewUseCaseTestSuite) TestCreateReview_CourseNotFound() {
 		Feedback: "Great course!",
 	}
 
-	suite.enrollRepo.On("IsEnrolled", ctx, userID, req.CourseID).Return(true, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, req.CourseID).Return(&schema.CourseEnroll{}, nil)
 	suite.courseRepo.On("GetRating", mock.Anything, req.CourseID).Return(float32(0), int64(0), gorm.ErrRecordNotFound)
 
 	res, err := suite.reviewUseCase.Create(ctx, req)
@@ -233,7 +311,7 @@ func (suite *ReviewUseCaseTestSuite) TestCreateReview_AlreadyReviewed() {
 		Feedback: "Great course!",
 	}
 
-	suite.enrollRepo.On("IsEnrolled", ctx, userID, req.CourseID).Return(true, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, req.CourseID).Return(&schema.CourseEnroll{}, nil)
 	suite.courseRepo.On("GetRating", ctx, req.CourseID).Return(float32(4.0), int64(10), nil)
 
 	pgErr := pgconn.PgError{Code: "23505"}
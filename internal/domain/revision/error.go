@@ -0,0 +1,17 @@
+package revision
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrRevisionNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("REVISION_NOT_FOUND")
+
+	ErrRevisionConflict = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("REVISION_CONFLICT")
+)
@@ -0,0 +1,63 @@
+package revision
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Create(ctx context.Context, rev *schema.Revision) error
+	GetLatest(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) (*schema.Revision, error)
+	GetByItem(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) ([]schema.Revision, error)
+	GetVersion(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID, version int) (*schema.Revision, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) Create(ctx context.Context, rev *schema.Revision) error {
+	return r.db.WithContext(ctx).Omit("Editor").Create(rev).Error
+}
+
+func (r *repository) GetLatest(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) (*schema.Revision, error) {
+	var rev schema.Revision
+	err := r.db.WithContext(ctx).
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("version DESC").
+		First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
+
+// GetByItem lists the history of an item, newest version first
+func (r *repository) GetByItem(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) ([]schema.Revision, error) {
+	var revs []schema.Revision
+	err := r.db.WithContext(ctx).
+		Preload("Editor").
+		Where("item_type = ? AND item_id = ?", itemType, itemID).
+		Order("version DESC").
+		Find(&revs).Error
+	return revs, err
+}
+
+func (r *repository) GetVersion(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID, version int) (*schema.Revision, error) {
+	var rev schema.Revision
+	err := r.db.WithContext(ctx).
+		Preload("Editor").
+		Where("item_type = ? AND item_id = ? AND version = ?", itemType, itemID, version).
+		First(&rev).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rev, nil
+}
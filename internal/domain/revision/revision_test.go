@@ -0,0 +1,128 @@
+package revision
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Create(ctx context.Context, rev *schema.Revision) error {
+	args := m.Called(ctx, rev)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetLatest(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) (*schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Revision), args.Error(1)
+}
+
+func (m *MockRepository) GetByItem(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) ([]schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID)
+	return args.Get(0).([]schema.Revision), args.Error(1)
+}
+
+func (m *MockRepository) GetVersion(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID, version int) (*schema.Revision, error) {
+	args := m.Called(ctx, itemType, itemID, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Revision), args.Error(1)
+}
+
+type item struct {
+	Title       string              `json:"title"`
+	Attachments []schema.Attachment `json:"attachments"`
+}
+
+type RevisionUseCaseTestSuite struct {
+	suite.Suite
+	repo    *MockRepository
+	useCase *UseCase
+}
+
+func (suite *RevisionUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.useCase = NewUseCase(suite.repo)
+}
+
+func (suite *RevisionUseCaseTestSuite) TestDiff_ComparesAttachmentsByID() {
+	kept := schema.Attachment{ID: uuid.New(), URL: "https://bucket/kept.pdf"}
+	removed := schema.Attachment{ID: uuid.New(), URL: "https://bucket/removed.pdf"}
+	added := schema.Attachment{ID: uuid.New(), URL: "https://bucket/added.pdf"}
+
+	from, err := Encode(item{Title: "Week 1", Attachments: []schema.Attachment{kept, removed}})
+	suite.Require().NoError(err)
+	to, err := Encode(item{Title: "Week 1", Attachments: []schema.Attachment{added, kept}})
+	suite.Require().NoError(err)
+
+	changes := Diff(from, to)
+
+	suite.Require().Len(changes, 1)
+	assert.Equal(suite.T(), "attachments", changes[0].Field)
+	assert.Len(suite.T(), changes[0].From, 1)
+	assert.Equal(suite.T(), removed.ID.String(), changes[0].From.([]any)[0].(map[string]any)["id"])
+	assert.Equal(suite.T(), added.ID.String(), changes[0].To.([]any)[0].(map[string]any)["id"])
+}
+
+func (suite *RevisionUseCaseTestSuite) TestRecord_StoresBaselineOnFirstEdit() {
+	ctx := context.WithValue(context.Background(), "user.id", uuid.New().String())
+	itemID := uuid.New()
+
+	suite.repo.On("GetLatest", ctx, schema.RevisionMaterial, itemID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("Create", ctx, mock.MatchedBy(func(rev *schema.Revision) bool {
+		return rev.Version == 1 && rev.EditorID == nil && rev.Snapshot["title"] == "Draft"
+	})).Return(nil).Once()
+	suite.repo.On("Create", ctx, mock.MatchedBy(func(rev *schema.Revision) bool {
+		return rev.Version == 2 && rev.EditorID != nil && len(rev.Changes) == 1
+	})).Return(nil).Once()
+
+	rev, err := suite.useCase.Record(ctx, schema.RevisionMaterial, itemID, item{Title: "Draft"}, item{Title: "Final"}, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, rev.Version)
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *RevisionUseCaseTestSuite) TestRecord_SkipsEditWithoutChanges() {
+	ctx := context.Background()
+	itemID := uuid.New()
+	snapshot, err := Encode(item{Title: "Same"})
+	suite.Require().NoError(err)
+	latest := &schema.Revision{Version: 5, Snapshot: snapshot}
+
+	suite.repo.On("GetLatest", ctx, schema.RevisionAssignment, itemID).Return(latest, nil)
+
+	rev, err := suite.useCase.Record(ctx, schema.RevisionAssignment, itemID, item{Title: "Same"}, item{Title: "Same"}, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), latest, rev)
+	suite.repo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *RevisionUseCaseTestSuite) TestGetVersion_NotFound() {
+	ctx := context.Background()
+	itemID := uuid.New()
+
+	suite.repo.On("GetVersion", ctx, schema.RevisionMaterial, itemID, 9).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := suite.useCase.GetVersion(ctx, schema.RevisionMaterial, itemID, 9)
+
+	assert.Equal(suite.T(), ErrRevisionNotFound.Build().Error(), err.Error())
+}
+
+func TestRevisionUseCase(t *testing.T) {
+	suite.Run(t, new(RevisionUseCaseTestSuite))
+}
@@ -0,0 +1,213 @@
+package revision
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"sort"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// attachmentsField is the snapshot field holding the attachment set, it is compared by attachment ID
+const attachmentsField = "attachments"
+
+type UseCase struct {
+	repo Repository
+}
+
+func NewUseCase(repo Repository) *UseCase {
+	return &UseCase{repo: repo}
+}
+
+// Encode turns a snapshot struct into the generic form stored with a revision
+func Encode(snapshot any) (map[string]any, error) {
+	raw, err := json.Marshal(snapshot)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]any
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
+
+// Decode fills a snapshot struct back from a stored revision
+func Decode(fields map[string]any, snapshot any) error {
+	raw, err := json.Marshal(fields)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(raw, snapshot)
+}
+
+// Diff lists the fields that differ between two snapshots in field order
+func Diff(from, to map[string]any) []schema.FieldChange {
+	fields := make(map[string]bool)
+	for field := range from {
+		fields[field] = true
+	}
+	for field := range to {
+		fields[field] = true
+	}
+
+	var names []string
+	for field := range fields {
+		names = append(names, field)
+	}
+	sort.Strings(names)
+
+	changes := []schema.FieldChange{}
+	for _, field := range names {
+		if field == attachmentsField {
+			removed, added := diffAttachments(from[field], to[field])
+			if len(removed) > 0 || len(added) > 0 {
+				changes = append(changes, schema.FieldChange{Field: field, From: removed, To: added})
+			}
+			continue
+		}
+		if !reflect.DeepEqual(from[field], to[field]) {
+			changes = append(changes, schema.FieldChange{Field: field, From: from[field], To: to[field]})
+		}
+	}
+	return changes
+}
+
+// diffAttachments returns the attachments only found in from and the ones only found in to
+func diffAttachments(from, to any) (removed, added []any) {
+	ids := func(list any) map[any]bool {
+		set := make(map[any]bool)
+		items, _ := list.([]any)
+		for _, item := range items {
+			if att, ok := item.(map[string]any); ok {
+				set[att["id"]] = true
+			}
+		}
+		return set
+	}
+	fromIDs, toIDs := ids(from), ids(to)
+
+	removed, added = []any{}, []any{}
+	if items, ok := from.([]any); ok {
+		for _, item := range items {
+			if att, ok := item.(map[string]any); ok && !toIDs[att["id"]] {
+				removed = append(removed, item)
+			}
+		}
+	}
+	if items, ok := to.([]any); ok {
+		for _, item := range items {
+			if att, ok := item.(map[string]any); ok && !fromIDs[att["id"]] {
+				added = append(added, item)
+			}
+		}
+	}
+	return removed, added
+}
+
+func editorOf(ctx context.Context) *uuid.UUID {
+	userIDStr, ok := ctx.Value("user.id").(string)
+	if !ok {
+		return nil
+	}
+	userID, err := uuid.Parse(userIDStr)
+	if err != nil {
+		return nil
+	}
+	return &userID
+}
+
+func (uc *UseCase) create(ctx context.Context, rev *schema.Revision) error {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+	rev.ID = id
+
+	if err := uc.repo.Create(ctx, rev); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrRevisionConflict.Build()
+		}
+		return err
+	}
+	return nil
+}
+
+// Record stores the state of an item after an edit as its next version. Items edited for the first
+// time since history was kept get their state before the edit stored as version 1 so the edit has
+// something to be compared with, before may be nil for items that were just created.
+// Edits that change nothing are not stored unless they restore an older version
+func (uc *UseCase) Record(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID,
+	before, after any, restoredFrom *int) (*schema.Revision, error) {
+	snapshot, err := Encode(after)
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	latest, err := uc.repo.GetLatest(ctx, itemType, itemID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if latest == nil && before != nil {
+		baseline, err := Encode(before)
+		if err != nil {
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		latest = &schema.Revision{
+			ItemType: itemType,
+			ItemID:   itemID,
+			Version:  1,
+			Changes:  []schema.FieldChange{},
+			Snapshot: baseline,
+		}
+		if err := uc.create(ctx, latest); err != nil {
+			return nil, err
+		}
+	}
+
+	rev := &schema.Revision{
+		ItemType:     itemType,
+		ItemID:       itemID,
+		Version:      1,
+		EditorID:     editorOf(ctx),
+		Changes:      []schema.FieldChange{},
+		Snapshot:     snapshot,
+		RestoredFrom: restoredFrom,
+	}
+	if latest != nil {
+		rev.Version = latest.Version + 1
+		rev.Changes = Diff(latest.Snapshot, snapshot)
+		if len(rev.Changes) == 0 && restoredFrom == nil {
+			return latest, nil
+		}
+	}
+
+	if err := uc.create(ctx, rev); err != nil {
+		return nil, err
+	}
+	return rev, nil
+}
+
+func (uc *UseCase) GetHistory(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID) ([]schema.Revision, error) {
+	return uc.repo.GetByItem(ctx, itemType, itemID)
+}
+
+func (uc *UseCase) GetVersion(ctx context.Context, itemType schema.RevisionItemType, itemID uuid.UUID, version int) (*schema.Revision, error) {
+	rev, err := uc.repo.GetVersion(ctx, itemType, itemID, version)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRevisionNotFound.Build()
+		}
+		return nil, err
+	}
+	return rev, nil
+}
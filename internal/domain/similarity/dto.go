@@ -0,0 +1,10 @@
+package similarity
+
+type AssignmentIDRequest struct {
+	AssignmentID string `uri:"id" binding:"required,uuid"`
+}
+
+type RecheckResponse struct {
+	Checked int `json:"checked"`
+	Flagged int `json:"flagged"`
+}
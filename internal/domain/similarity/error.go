@@ -0,0 +1,13 @@
+package similarity
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrAssignmentNotFound = apierror.NewApiErrorBuilder().
+		WithHttpStatus(http.StatusNotFound).
+		WithMessage("ASSIGNMENT_NOT_FOUND")
+)
@@ -0,0 +1,99 @@
+package similarity
+
+import (
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const (
+	// shingleSize is the number of consecutive words hashed together, long enough that common phrases
+	// do not make unrelated texts look alike
+	shingleSize = 5
+	// signatureSize is the number of hash functions of the MinHash, the estimate is off by about 1/sqrt(128)
+	signatureSize = 128
+	// minShingles keeps short texts such as "see attached" from matching every other short text
+	minShingles = 10
+)
+
+// seeds pick the hash functions of the signature. They are fixed as the signatures are stored and compared
+// with the ones of later submissions
+var seeds = func() [signatureSize]uint64 {
+	var s [signatureSize]uint64
+	x := uint64(0x5eed)
+	for i := range s {
+		x = splitmix64(x)
+		s[i] = x
+	}
+	return s
+}()
+
+func splitmix64(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb
+	return x ^ (x >> 31)
+}
+
+// words splits the text into lower case words, leaving out punctuation so reformatting a copied text does
+// not hide it
+func words(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// shingles hashes every run of shingleSize words of the text, a text shorter than that is a single shingle
+func shingles(text string) map[uint64]struct{} {
+	w := words(text)
+	set := make(map[uint64]struct{})
+	if len(w) == 0 {
+		return set
+	}
+
+	n := len(w) - shingleSize + 1
+	if n < 1 {
+		n = 1
+	}
+	for i := 0; i < n; i++ {
+		h := fnv.New64a()
+		h.Write([]byte(strings.Join(w[i:min(i+shingleSize, len(w))], " ")))
+		set[h.Sum64()] = struct{}{}
+	}
+	return set
+}
+
+// signature is the MinHash of the shingles, the share of positions two signatures agree on estimates the
+// Jaccard similarity of their shingles
+func signature(set map[uint64]struct{}) []uint32 {
+	if len(set) == 0 {
+		return nil
+	}
+
+	sig := make([]uint32, signatureSize)
+	for i := range sig {
+		sig[i] = ^uint32(0)
+	}
+	for x := range set {
+		for i, seed := range seeds {
+			if h := uint32(splitmix64(x^seed) >> 32); h < sig[i] {
+				sig[i] = h
+			}
+		}
+	}
+	return sig
+}
+
+// estimate returns the estimated similarity of two signatures from 0 to 1
+func estimate(a, b []uint32) float64 {
+	if len(a) != signatureSize || len(b) != signatureSize {
+		return 0
+	}
+	same := 0
+	for i := range a {
+		if a[i] == b[i] {
+			same++
+		}
+	}
+	return float64(same) / signatureSize
+}
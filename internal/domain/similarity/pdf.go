@@ -0,0 +1,230 @@
+package similarity
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxStreamSize caps how much a single compressed stream of a PDF may inflate to
+const maxStreamSize = 16 << 20
+
+var errNoPDFText = errors.New("no text found in pdf")
+
+// pdfText pulls the text out of the content streams of a PDF. It reads the strings shown by the text
+// operators of uncompressed and Flate compressed streams, which covers what word processors export. Text
+// drawn with embedded font encodings comes out unreadable and scanned pages have no text at all
+func pdfText(data []byte) (string, error) {
+	var out strings.Builder
+	rest := data
+	for {
+		i := bytes.Index(rest, []byte("stream"))
+		if i < 0 {
+			break
+		}
+		// "endstream" holds "stream" too
+		if i >= 3 && string(rest[i-3:i]) == "end" {
+			rest = rest[i+len("stream"):]
+			continue
+		}
+
+		// The dictionary of the stream is what follows the object header
+		dict := rest[max(0, i-1024):i]
+		if d := bytes.LastIndex(dict, []byte("obj")); d >= 0 {
+			dict = dict[d:]
+		}
+
+		start := i + len("stream")
+		if start < len(rest) && rest[start] == '\r' {
+			start++
+		}
+		if start < len(rest) && rest[start] == '\n' {
+			start++
+		}
+		end := bytes.Index(rest[start:], []byte("endstream"))
+		if end < 0 {
+			break
+		}
+		raw := rest[start : start+end]
+		rest = rest[start+end+len("endstream"):]
+
+		content, ok := decodeStream(dict, raw)
+		if !ok || !bytes.Contains(content, []byte("BT")) || !bytes.Contains(content, []byte("ET")) {
+			continue
+		}
+		showText(&out, content)
+	}
+
+	text := strings.TrimSpace(out.String())
+	if text == "" {
+		return "", errNoPDFText
+	}
+	return text, nil
+}
+
+func decodeStream(dict, raw []byte) ([]byte, bool) {
+	if !bytes.Contains(dict, []byte("/Filter")) {
+		return raw, true
+	}
+	if !bytes.Contains(dict, []byte("/FlateDecode")) {
+		return nil, false
+	}
+
+	zr, err := zlib.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, false
+	}
+	defer zr.Close()
+	content, err := io.ReadAll(io.LimitReader(zr, maxStreamSize))
+	// Streams are often padded after the compressed data, what was inflated is still good
+	if err != nil && len(content) == 0 {
+		return nil, false
+	}
+	return content, true
+}
+
+// showText writes the strings of the text operators of a content stream, starting a new line where the
+// stream moves to the next line
+func showText(out *strings.Builder, content []byte) {
+	var pending []string
+	for i := 0; i < len(content); {
+		c := content[i]
+		switch {
+		case c == '(':
+			s, n := literalString(content[i:])
+			pending = append(pending, s)
+			i += n
+		case c == '<' && i+1 < len(content) && content[i+1] != '<':
+			s, n := hexString(content[i:])
+			pending = append(pending, s)
+			i += n
+		case c == '%':
+			for i < len(content) && content[i] != '\n' && content[i] != '\r' {
+				i++
+			}
+		case isDelimiter(c) || isSpace(c):
+			i++
+		default:
+			start := i
+			for i < len(content) && !isDelimiter(content[i]) && !isSpace(content[i]) {
+				i++
+			}
+			token := string(content[start:i])
+			if n, err := strconv.ParseFloat(token, 64); err == nil {
+				// A wide gap between the strings of a TJ array stands for a space
+				if n < -200 && len(pending) > 0 {
+					pending = append(pending, " ")
+				}
+				continue
+			}
+
+			switch token {
+			case "Tj", "TJ":
+				out.WriteString(strings.Join(pending, ""))
+				out.WriteByte(' ')
+			case "'", "\"":
+				out.WriteByte('\n')
+				out.WriteString(strings.Join(pending, ""))
+			case "Td", "TD", "T*", "ET":
+				out.WriteByte('\n')
+			}
+			pending = pending[:0]
+		}
+	}
+}
+
+// literalString reads a (string) with its escapes and balanced parentheses, it returns the text and the
+// number of bytes read
+func literalString(b []byte) (string, int) {
+	var s []rune
+	depth := 0
+	i := 0
+	for ; i < len(b); i++ {
+		c := b[i]
+		switch c {
+		case '(':
+			depth++
+			if depth == 1 {
+				continue
+			}
+		case ')':
+			depth--
+			if depth == 0 {
+				return string(s), i + 1
+			}
+		case '\\':
+			i++
+			if i >= len(b) {
+				return string(s), i
+			}
+			switch e := b[i]; e {
+			case 'n', 'r':
+				s = append(s, '\n')
+			case 't':
+				s = append(s, '\t')
+			case 'b', 'f':
+			case '\r', '\n':
+				// A line break after the backslash continues the string
+				if e == '\r' && i+1 < len(b) && b[i+1] == '\n' {
+					i++
+				}
+			default:
+				if e >= '0' && e <= '7' {
+					v := 0
+					for j := 0; j < 3 && i < len(b) && b[i] >= '0' && b[i] <= '7'; j++ {
+						v = v*8 + int(b[i]-'0')
+						i++
+					}
+					i--
+					s = append(s, rune(v&0xff))
+				} else {
+					s = append(s, rune(e))
+				}
+			}
+			continue
+		}
+		s = append(s, rune(c))
+	}
+	return string(s), i
+}
+
+// hexString reads a <hex string>, strings that do not decode to readable text are left out as they are
+// glyph IDs of an embedded font
+func hexString(b []byte) (string, int) {
+	end := bytes.IndexByte(b, '>')
+	if end < 0 {
+		return "", len(b)
+	}
+	digits := strings.Map(func(r rune) rune {
+		if isSpace(byte(r)) {
+			return -1
+		}
+		return r
+	}, string(b[1:end]))
+	if len(digits)%2 == 1 {
+		digits += "0"
+	}
+
+	decoded, err := hex.DecodeString(digits)
+	if err != nil {
+		return "", end + 1
+	}
+	for _, c := range decoded {
+		if (c < 0x20 || c > 0x7e) && !isSpace(c) {
+			return "", end + 1
+		}
+	}
+	return string(decoded), end + 1
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\r' || c == '\n' || c == '\f' || c == 0
+}
+
+func isDelimiter(c byte) bool {
+	return strings.IndexByte("()<>[]{}/%", c) >= 0
+}
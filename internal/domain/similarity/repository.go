@@ -0,0 +1,87 @@
+package similarity
+
+import (
+	"context"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type Repository interface {
+	GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.SimilarityReport, error)
+	GetBySubmissions(ctx context.Context, submissionIDs []uuid.UUID) ([]schema.SimilarityReport, error)
+	GetSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error)
+	Save(ctx context.Context, reports []*schema.SimilarityReport) error
+	Replace(ctx context.Context, assignmentID uuid.UUID, reports []*schema.SimilarityReport) error
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetByAssignment returns the reports of the submissions to the assignment that were not deleted, the
+// highest scores first
+func (r *repository) GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.SimilarityReport, error) {
+	var reports []schema.SimilarityReport
+	err := r.db.WithContext(ctx).
+		Where("assignment_id = ?", assignmentID).
+		Where("submission_id IN (?)", r.db.Model(&schema.Submission{}).Select("id")).
+		Order("score DESC, checked_at").
+		Find(&reports).Error
+	return reports, err
+}
+
+func (r *repository) GetBySubmissions(ctx context.Context, submissionIDs []uuid.UUID) ([]schema.SimilarityReport, error) {
+	var reports []schema.SimilarityReport
+	if len(submissionIDs) == 0 {
+		return reports, nil
+	}
+	err := r.db.WithContext(ctx).
+		Where("submission_id IN ?", submissionIDs).
+		Find(&reports).Error
+	return reports, err
+}
+
+// GetSubmissions returns every attempt at the assignment with its attachments in the order they were handed in
+func (r *repository) GetSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	var submissions []schema.Submission
+	err := r.db.WithContext(ctx).
+		Preload("Attachments").
+		Where("assignment_id = ?", assignmentID).
+		Order("submitted_at").
+		Find(&submissions).Error
+	return submissions, err
+}
+
+// Save stores the reports, the report of a submission that was checked before is replaced
+func (r *repository) Save(ctx context.Context, reports []*schema.SimilarityReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).
+		Omit("Submission", "Assignment").
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "submission_id"}},
+			DoUpdates: clause.AssignmentColumns([]string{"shingles", "signature", "score", "flagged", "matches", "skipped", "checked_at"}),
+		}).
+		Create(&reports).Error
+}
+
+// Replace drops the reports of the assignment for the ones of a new check
+func (r *repository) Replace(ctx context.Context, assignmentID uuid.UUID, reports []*schema.SimilarityReport) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("assignment_id = ?", assignmentID).Delete(&schema.SimilarityReport{}).Error; err != nil {
+			return err
+		}
+		if len(reports) == 0 {
+			return nil
+		}
+		return tx.Omit("Submission", "Assignment").Create(&reports).Error
+	})
+}
@@ -0,0 +1,62 @@
+package similarity
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	similarityGroup := engine.Group("/v1/assignments/:id/similarity", middleware.Authenticate())
+	{
+		similarityGroup.GET("", controller.GetReports())
+		similarityGroup.POST("/check", controller.Recheck())
+	}
+}
+
+func (c *RestController) GetReports() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetReports(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_SIMILARITY_REPORTS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Recheck() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req AssignmentIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Recheck(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "CHECK_SIMILARITY_SUCCESS", res).Send(ctx)
+	}
+}
@@ -0,0 +1,499 @@
+package similarity
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) GetByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.SimilarityReport, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.SimilarityReport), args.Error(1)
+}
+
+func (m *MockRepository) GetBySubmissions(ctx context.Context, submissionIDs []uuid.UUID) ([]schema.SimilarityReport, error) {
+	args := m.Called(ctx, submissionIDs)
+	return args.Get(0).([]schema.SimilarityReport), args.Error(1)
+}
+
+func (m *MockRepository) GetSubmissions(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.Submission), args.Error(1)
+}
+
+func (m *MockRepository) Save(ctx context.Context, reports []*schema.SimilarityReport) error {
+	args := m.Called(ctx, reports)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Replace(ctx context.Context, assignmentID uuid.UUID, reports []*schema.SimilarityReport) error {
+	args := m.Called(ctx, assignmentID, reports)
+	return args.Error(0)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockAssignmentRepo struct {
+	mock.Mock
+}
+
+// Create mocks the Create method
+func (m *MockAssignmentRepo) Create(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+// Update mocks the Update method
+func (m *MockAssignmentRepo) Update(ctx context.Context, a *schema.Assignment) error {
+	args := m.Called(ctx, a)
+	return args.Error(0)
+}
+
+// Delete mocks the Delete method
+func (m *MockAssignmentRepo) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+// GetByID mocks the GetByID method
+func (m *MockAssignmentRepo) GetByID(ctx context.Context, id uuid.UUID) (*schema.Assignment, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(*schema.Assignment), args.Error(1)
+}
+
+// GetByCourseID mocks the GetByCourseID method
+func (m *MockAssignmentRepo) GetByCourseID(ctx context.Context, courseId uuid.UUID) ([]*schema.Assignment, error) {
+	args := m.Called(ctx, courseId)
+	return args.Get(0).([]*schema.Assignment), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error {
+	args := m.Called(ctx, extension)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, teamSetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error) {
+	args := m.Called(ctx, assignmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schema.RubricCriterion), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error {
+	args := m.Called(ctx, assignmentID, criteria)
+	return args.Error(0)
+}
+
+type MockFileOpener struct {
+	mock.Mock
+}
+
+func (m *MockFileOpener) Open(ctx context.Context, fileURL string) (io.ReadCloser, error) {
+	args := m.Called(ctx, fileURL)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+const essay = `Peer assessment asks students to grade the work of their classmates against the same rubric the
+instructor uses. Reading other submissions shows students how the criteria apply in practice and gives
+them a better sense of the quality expected. Large courses benefit most because the instructor cannot
+give every student detailed feedback within a reasonable time.`
+
+const otherEssay = `The water cycle moves water between the oceans, the atmosphere and the land. Heat from the sun
+evaporates water from the surface, the vapour cools as it rises and condenses into clouds, and it comes
+back down as rain or snow that flows through rivers into the sea again.`
+
+// pdfOf builds a PDF showing the lines of text with a Flate compressed content stream
+func pdfOf(lines ...string) []byte {
+	var content bytes.Buffer
+	content.WriteString("BT /F1 12 Tf 72 712 Td ")
+	for _, line := range lines {
+		line = strings.NewReplacer("(", "\\(", ")", "\\)").Replace(line)
+		fmt.Fprintf(&content, "(%s) Tj T* ", line)
+	}
+	content.WriteString("ET")
+
+	var compressed bytes.Buffer
+	zw := zlib.NewWriter(&compressed)
+	zw.Write(content.Bytes())
+	zw.Close()
+
+	var pdf bytes.Buffer
+	pdf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /Catalog /Pages 2 0 R >>\nendobj\n")
+	fmt.Fprintf(&pdf, "4 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", compressed.Len())
+	pdf.Write(compressed.Bytes())
+	pdf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return pdf.Bytes()
+}
+
+type SimilarityUseCaseTestSuite struct {
+	suite.Suite
+	repo           *MockRepository
+	assignmentRepo *MockAssignmentRepo
+	courseRepo     *MockCourseRepository
+	files          *MockFileOpener
+	useCase        *UseCase
+}
+
+func (suite *SimilarityUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.assignmentRepo = new(MockAssignmentRepo)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.files = new(MockFileOpener)
+	suite.useCase = NewUseCase(suite.repo, suite.assignmentRepo, suite.courseRepo, suite.files)
+}
+
+// reportOf is the report stored for an earlier submission of the text
+func reportOf(assignmentID uuid.UUID, text string) schema.SimilarityReport {
+	set := shingles(text)
+	return schema.SimilarityReport{
+		ID:           uuid.New(),
+		SubmissionID: uuid.New(),
+		AssignmentID: assignmentID,
+		UserID:       uuid.New(),
+		Shingles:     len(set),
+		Signature:    signature(set),
+		CheckedAt:    time.Now(),
+	}
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestEstimate() {
+	sig := signature(shingles(essay))
+
+	assert.Equal(suite.T(), 1.0, estimate(sig, signature(shingles(essay))))
+	assert.Less(suite.T(), estimate(sig, signature(shingles(otherEssay))), matchThreshold)
+	// Punctuation and case do not hide a copy
+	reformatted := strings.ToUpper(strings.NewReplacer(".", "!", ",", ";").Replace(essay))
+	assert.Equal(suite.T(), 1.0, estimate(sig, signature(shingles(reformatted))))
+	// Half of the text copied lands in between
+	half := essay[:len(essay)/2] + " " + otherEssay[len(otherEssay)/2:]
+	score := estimate(sig, signature(shingles(half)))
+	assert.Greater(suite.T(), score, matchThreshold)
+	assert.Less(suite.T(), score, 0.9)
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestPDFText() {
+	text, err := pdfText(pdfOf("Hello (world)", "Peer review"))
+
+	assert.NoError(suite.T(), err)
+	assert.Contains(suite.T(), text, "Hello (world)")
+	assert.Contains(suite.T(), text, "Peer review")
+
+	_, err = pdfText([]byte("%PDF-1.4\n%%EOF"))
+	assert.ErrorIs(suite.T(), err, errNoPDFText)
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestShowText_TJSpacing() {
+	var out strings.Builder
+	showText(&out, []byte("BT [(Peer) -250 (review) 20 (s)] TJ ET"))
+
+	assert.Equal(suite.T(), "Peer reviews", strings.TrimSpace(out.String()))
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestCheck_FlagsCopiedSubmission() {
+	assignmentID := uuid.New()
+	original := reportOf(assignmentID, essay)
+	unrelated := reportOf(assignmentID, otherEssay)
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: assignmentID, UserID: uuid.New(), Content: essay}
+	ctx := context.Background()
+
+	suite.repo.On("GetByAssignment", ctx, assignmentID).Return([]schema.SimilarityReport{original, unrelated}, nil)
+	suite.repo.On("Save", ctx, mock.MatchedBy(func(reports []*schema.SimilarityReport) bool {
+		return len(reports) == 2 && reports[0].SubmissionID == original.SubmissionID && reports[1].SubmissionID == submission.ID
+	})).Return(nil)
+
+	err := suite.useCase.Check(ctx, submission)
+
+	assert.NoError(suite.T(), err)
+	saved := suite.repo.Calls[1].Arguments.Get(1).([]*schema.SimilarityReport)
+	for _, r := range saved {
+		assert.True(suite.T(), r.Flagged)
+		assert.Equal(suite.T(), 1.0, r.Score)
+	}
+	assert.Equal(suite.T(), original.UserID, saved[1].Matches[0].UserID)
+	assert.Equal(suite.T(), submission.UserID, saved[0].Matches[0].UserID)
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestCheck_IgnoresOwnAttempts() {
+	assignmentID := uuid.New()
+	previous := reportOf(assignmentID, essay)
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: assignmentID, UserID: previous.UserID, Content: essay}
+	ctx := context.Background()
+
+	suite.repo.On("GetByAssignment", ctx, assignmentID).Return([]schema.SimilarityReport{previous}, nil)
+	suite.repo.On("Save", ctx, mock.MatchedBy(func(reports []*schema.SimilarityReport) bool {
+		return len(reports) == 1 && !reports[0].Flagged && len(reports[0].Matches) == 0
+	})).Return(nil)
+
+	assert.NoError(suite.T(), suite.useCase.Check(ctx, submission))
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestCheck_IgnoresTeamAttempts() {
+	assignmentID := uuid.New()
+	teamID := uuid.New()
+	previous := reportOf(assignmentID, essay)
+	previous.TeamID = &teamID
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: assignmentID, UserID: uuid.New(), TeamID: &teamID, Content: essay}
+	ctx := context.Background()
+
+	suite.repo.On("GetByAssignment", ctx, assignmentID).Return([]schema.SimilarityReport{previous}, nil)
+	suite.repo.On("Save", ctx, mock.MatchedBy(func(reports []*schema.SimilarityReport) bool {
+		return len(reports) == 1 && !reports[0].Flagged && *reports[0].TeamID == teamID
+	})).Return(nil)
+
+	assert.NoError(suite.T(), suite.useCase.Check(ctx, submission))
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestCheck_ReadsTextOfAttachments() {
+	assignmentID := uuid.New()
+	original := reportOf(assignmentID, essay)
+	submission := &schema.Submission{
+		ID:           uuid.New(),
+		AssignmentID: assignmentID,
+		UserID:       uuid.New(),
+		Content:      "See attached",
+		Attachments: []schema.Attachment{
+			{ID: uuid.New(), URL: "https://bucket/submissions/essay.pdf"},
+			{ID: uuid.New(), URL: "https://bucket/submissions/diagram.png"},
+		},
+	}
+	ctx := context.Background()
+
+	lines := strings.Split(essay, "\n")
+	suite.files.On("Open", ctx, "https://bucket/submissions/essay.pdf").Return(io.NopCloser(bytes.NewReader(pdfOf(lines...))), nil)
+	suite.repo.On("GetByAssignment", ctx, assignmentID).Return([]schema.SimilarityReport{original}, nil)
+	suite.repo.On("Save", ctx, mock.Anything).Return(nil)
+
+	err := suite.useCase.Check(ctx, submission)
+
+	assert.NoError(suite.T(), err)
+	saved := suite.repo.Calls[1].Arguments.Get(1).([]*schema.SimilarityReport)
+	report := saved[len(saved)-1]
+	assert.True(suite.T(), report.Flagged)
+	assert.Equal(suite.T(), []string{"diagram.png"}, report.Skipped)
+	suite.files.AssertNotCalled(suite.T(), "Open", ctx, "https://bucket/submissions/diagram.png")
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestCheck_ShortTextsAreNotCompared() {
+	assignmentID := uuid.New()
+	other := reportOf(assignmentID, "See attached file")
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: assignmentID, UserID: uuid.New(), Content: "See attached file"}
+	ctx := context.Background()
+
+	suite.repo.On("GetByAssignment", ctx, assignmentID).Return([]schema.SimilarityReport{other}, nil)
+	suite.repo.On("Save", ctx, mock.MatchedBy(func(reports []*schema.SimilarityReport) bool {
+		return len(reports) == 1 && reports[0].Score == 0
+	})).Return(nil)
+
+	assert.NoError(suite.T(), suite.useCase.Check(ctx, submission))
+	suite.repo.AssertExpectations(suite.T())
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestRecheck_ComparesInOrder() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+	instructorID := uuid.New()
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+	submissions := []schema.Submission{
+		{ID: uuid.New(), AssignmentID: assignmentObj.ID, UserID: uuid.New(), Content: essay},
+		{ID: uuid.New(), AssignmentID: assignmentObj.ID, UserID: uuid.New(), Content: otherEssay},
+		{ID: uuid.New(), AssignmentID: assignmentObj.ID, UserID: uuid.New(), Content: essay},
+	}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignmentObj.ID).Return(assignmentObj, nil)
+	suite.courseRepo.On("GetByID", ctx, assignmentObj.CourseID).Return(schema.Course{ID: assignmentObj.CourseID, InstructorID: instructorID}, nil)
+	suite.repo.On("GetSubmissions", ctx, assignmentObj.ID).Return(submissions, nil)
+	suite.repo.On("Replace", ctx, assignmentObj.ID, mock.Anything).Return(nil)
+
+	res, err := suite.useCase.Recheck(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &RecheckResponse{Checked: 3, Flagged: 2}, res)
+}
+
+func (suite *SimilarityUseCaseTestSuite) TestRecheck_NotInstructor() {
+	assignmentObj := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	suite.assignmentRepo.On("GetByID", ctx, assignmentObj.ID).Return(assignmentObj, nil)
+	suite.courseRepo.On("GetByID", ctx, assignmentObj.CourseID).Return(schema.Course{ID: assignmentObj.CourseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.useCase.Recheck(ctx, &AssignmentIDRequest{AssignmentID: assignmentObj.ID.String()})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "GetSubmissions", mock.Anything, mock.Anything)
+}
+
+func TestSimilarityUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(SimilarityUseCaseTestSuite))
+}
@@ -0,0 +1,301 @@
+package similarity
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"math"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/assignment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// flagThreshold is the score from which a report is flagged to the instructor. Independent work rarely
+	// shares more than a few percent of its five word runs
+	flagThreshold = 0.5
+	// matchThreshold is the lowest score listed as a match
+	matchThreshold = 0.2
+	// maxMatches is how many of the closest submissions a report lists
+	maxMatches = 5
+	// maxFileSize caps how much of an attachment is read
+	maxFileSize = 32 << 20
+)
+
+var errUnsupportedFile = errors.New("attachment type is not compared")
+
+// FileOpener reads attachments from storage, it is the attachment use case
+type FileOpener interface {
+	Open(ctx context.Context, fileURL string) (io.ReadCloser, error)
+}
+
+type UseCase struct {
+	repo           Repository
+	assignmentRepo assignment.Repository
+	courseRepo     course.Repository
+	files          FileOpener
+}
+
+func NewUseCase(repo Repository, assignmentRepo assignment.Repository, courseRepo course.Repository, files FileOpener) *UseCase {
+	return &UseCase{
+		repo:           repo,
+		assignmentRepo: assignmentRepo,
+		courseRepo:     courseRepo,
+		files:          files,
+	}
+}
+
+// getManagedAssignment returns the assignment when the current user is the instructor of its course or an admin
+func (uc *UseCase) getManagedAssignment(ctx context.Context, idStr string) (*schema.Assignment, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentNotFound.Build()
+		}
+		log.Println("Error getting assignment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	courseObj, err := uc.courseRepo.GetByID(ctx, assignmentObj.CourseID)
+	if err != nil {
+		log.Println("Error getting course: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+	if courseObj.InstructorID != userID && ctx.Value("user.role") != string(schema.RoleAdmin) {
+		return nil, apierror.ErrNotYourResource.Build()
+	}
+	return assignmentObj, nil
+}
+
+// Check compares a new submission with the submissions of the other students to the same assignment and
+// stores its report. The reports it matches get the new submission added to their matches
+func (uc *UseCase) Check(ctx context.Context, submission *schema.Submission) error {
+	report, err := uc.analyze(ctx, submission)
+	if err != nil {
+		return err
+	}
+
+	others, err := uc.repo.GetByAssignment(ctx, submission.AssignmentID)
+	if err != nil {
+		return err
+	}
+	existing := make([]*schema.SimilarityReport, len(others))
+	for i := range others {
+		existing[i] = &others[i]
+	}
+
+	changed := compare(report, existing)
+	return uc.repo.Save(ctx, append(changed, report))
+}
+
+// Recheck checks every submission to the assignment again in the order they were handed in, for the
+// submissions from before the check existed or after attachments could not be read
+func (uc *UseCase) Recheck(ctx context.Context, req *AssignmentIDRequest) (*RecheckResponse, error) {
+	assignmentObj, err := uc.getManagedAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	submissions, err := uc.repo.GetSubmissions(ctx, assignmentObj.ID)
+	if err != nil {
+		log.Println("Error getting submissions: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	reports := make([]*schema.SimilarityReport, 0, len(submissions))
+	for i := range submissions {
+		report, err := uc.analyze(ctx, &submissions[i])
+		if err != nil {
+			log.Println("Error analyzing submission: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		compare(report, reports)
+		reports = append(reports, report)
+	}
+
+	if err := uc.repo.Replace(ctx, assignmentObj.ID, reports); err != nil {
+		log.Println("Error saving similarity reports: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := &RecheckResponse{Checked: len(reports)}
+	for _, r := range reports {
+		if r.Flagged {
+			res.Flagged++
+		}
+	}
+	return res, nil
+}
+
+// GetReports returns the reports of the assignment for the instructor, the closest matches first
+func (uc *UseCase) GetReports(ctx context.Context, req *AssignmentIDRequest) ([]schema.SimilarityReport, error) {
+	assignmentObj, err := uc.getManagedAssignment(ctx, req.AssignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := uc.repo.GetByAssignment(ctx, assignmentObj.ID)
+	if err != nil {
+		log.Println("Error getting similarity reports: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return reports, nil
+}
+
+// GetBySubmissions returns the reports of the submissions by submission, submissions that were not checked
+// have none
+func (uc *UseCase) GetBySubmissions(ctx context.Context, submissionIDs []uuid.UUID) (map[uuid.UUID]*schema.SimilarityReport, error) {
+	reports, err := uc.repo.GetBySubmissions(ctx, submissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[uuid.UUID]*schema.SimilarityReport, len(reports))
+	for i := range reports {
+		res[reports[i].SubmissionID] = &reports[i]
+	}
+	return res, nil
+}
+
+// analyze hashes the content of the submission together with the text of its attachments. Attachments
+// without text that can be read are listed as skipped
+func (uc *UseCase) analyze(ctx context.Context, submission *schema.Submission) (*schema.SimilarityReport, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	texts := []string{submission.Content}
+	var skipped []string
+	for _, att := range submission.Attachments {
+		text, err := uc.attachmentText(ctx, att)
+		if err != nil {
+			if !errors.Is(err, errUnsupportedFile) {
+				log.Println("Error reading attachment text: ", err)
+			}
+			skipped = append(skipped, fileName(att))
+			continue
+		}
+		texts = append(texts, text)
+	}
+
+	set := shingles(strings.Join(texts, "\n"))
+	return &schema.SimilarityReport{
+		ID:           id,
+		SubmissionID: submission.ID,
+		AssignmentID: submission.AssignmentID,
+		UserID:       submission.UserID,
+		TeamID:       submission.TeamID,
+		Shingles:     len(set),
+		Signature:    signature(set),
+		Skipped:      skipped,
+		CheckedAt:    time.Now(),
+	}, nil
+}
+
+func (uc *UseCase) attachmentText(ctx context.Context, att schema.Attachment) (string, error) {
+	ext := strings.ToLower(path.Ext(fileName(att)))
+	if ext != ".txt" && ext != ".md" && ext != ".pdf" {
+		return "", errUnsupportedFile
+	}
+
+	file, err := uc.files.Open(ctx, att.URL)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	data, err := io.ReadAll(io.LimitReader(file, maxFileSize))
+	if err != nil {
+		return "", err
+	}
+	if ext == ".pdf" {
+		return pdfText(data)
+	}
+	return string(data), nil
+}
+
+// compare estimates how much the report overlaps with the reports of the other students and teams and
+// records the matches on both sides. It returns the other reports that got a new match
+func compare(report *schema.SimilarityReport, others []*schema.SimilarityReport) []*schema.SimilarityReport {
+	var changed []*schema.SimilarityReport
+	if report.Shingles < minShingles {
+		return changed
+	}
+
+	for _, other := range others {
+		if other.UserID == report.UserID || other.SubmissionID == report.SubmissionID || other.Shingles < minShingles ||
+			sameTeam(report, other) {
+			continue
+		}
+		score := math.Round(estimate(report.Signature, other.Signature)*10000) / 10000
+		if score < matchThreshold {
+			continue
+		}
+
+		addMatch(report, schema.SimilarityMatch{SubmissionID: other.SubmissionID, UserID: other.UserID, Score: score})
+		addMatch(other, schema.SimilarityMatch{SubmissionID: report.SubmissionID, UserID: report.UserID, Score: score})
+		changed = append(changed, other)
+	}
+	return changed
+}
+
+// sameTeam reports whether both reports are of attempts of the same team, they share their work on purpose
+func sameTeam(a, b *schema.SimilarityReport) bool {
+	return a.TeamID != nil && b.TeamID != nil && *a.TeamID == *b.TeamID
+}
+
+// addMatch keeps the closest submission of every other student among the matches of the report and
+// scores the report by its closest match
+func addMatch(report *schema.SimilarityReport, match schema.SimilarityMatch) {
+	found := false
+	for i, m := range report.Matches {
+		if m.UserID == match.UserID {
+			found = true
+			if match.Score > m.Score {
+				report.Matches[i] = match
+			}
+			break
+		}
+	}
+	if !found {
+		report.Matches = append(report.Matches, match)
+	}
+
+	sort.SliceStable(report.Matches, func(i, j int) bool {
+		return report.Matches[i].Score > report.Matches[j].Score
+	})
+	if len(report.Matches) > maxMatches {
+		report.Matches = report.Matches[:maxMatches]
+	}
+	report.Score = report.Matches[0].Score
+	report.Flagged = report.Score >= flagThreshold
+}
+
+// fileName is the name of the stored file of the attachment
+func fileName(att schema.Attachment) string {
+	if u, err := url.Parse(att.URL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			return base
+		}
+	}
+	return att.ID.String()
+}
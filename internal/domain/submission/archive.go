@@ -0,0 +1,149 @@
+package submission
+
+import (
+	"archive/zip"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// Archive is the ZIP of every submission to an assignment, it is only put together while it is written
+// so the files are streamed from storage one at a time
+type Archive struct {
+	Filename    string
+	uc          *UseCase
+	submissions []schema.Submission
+	students    map[uuid.UUID]schema.User
+}
+
+// DownloadSubmissions prepares the archive of the submissions to the assignment for the instructor of the course
+func (uc *UseCase) DownloadSubmissions(ctx context.Context, assignmentID uuid.UUID, userId string) (*Archive, error) {
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, ErrAssignmentNotFound
+	}
+
+	if _, err := uc.getInstructedCourse(ctx, assignmentObj, userId); err != nil {
+		return nil, err
+	}
+
+	submissions, err := uc.repo.GetAllByAssignment(ctx, assignmentID, "")
+	if err != nil {
+		log.Println("Error getting submissions: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	submitters, err := uc.repo.GetSubmitters(ctx, assignmentID)
+	if err != nil {
+		log.Println("Error getting submitters: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	students := make(map[uuid.UUID]schema.User, len(submitters))
+	for _, s := range submitters {
+		students[s.ID] = s
+	}
+
+	return &Archive{
+		Filename:    "submissions-" + assignmentID.String() + ".zip",
+		uc:          uc,
+		submissions: submissions,
+		students:    students,
+	}, nil
+}
+
+// Write writes the archive with a folder per student and per attempt, holding the text of the attempt
+// in content.txt next to its attachments. Attachments that cannot be downloaded are listed in errors.txt
+func (a *Archive) Write(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	var failed []string
+
+	for _, s := range a.submissions {
+		dir := fmt.Sprintf("%s/attempt-%d/", studentFolder(a.students[s.UserID], s.UserID), s.Attempt)
+
+		if s.Content != "" {
+			f, err := zw.Create(dir + "content.txt")
+			if err != nil {
+				return err
+			}
+			if _, err := io.WriteString(f, s.Content); err != nil {
+				return err
+			}
+		}
+
+		used := make(map[string]bool, len(s.Attachments))
+		for _, att := range s.Attachments {
+			name := attachmentName(att)
+			for i := 2; used[name]; i++ {
+				name = fmt.Sprintf("%d-%s", i, attachmentName(att))
+			}
+			used[name] = true
+
+			if err := a.copyAttachment(ctx, zw, dir+name, att.URL); err != nil {
+				log.Println("Error adding attachment to archive: ", err)
+				failed = append(failed, dir+name)
+			}
+		}
+	}
+
+	if len(failed) > 0 {
+		f, err := zw.Create("errors.txt")
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, "These attachments could not be downloaded:\n"+strings.Join(failed, "\n")+"\n"); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func (a *Archive) copyAttachment(ctx context.Context, zw *zip.Writer, name, fileURL string) error {
+	file, err := a.uc.attachmentUseCase.Open(ctx, fileURL)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(f, file)
+	return err
+}
+
+// studentFolder names the folder of a student after them, their email keeps students with the same name apart
+func studentFolder(student schema.User, userID uuid.UUID) string {
+	if student.Name == "" {
+		return userID.String()
+	}
+	return cleanName(fmt.Sprintf("%s (%s)", student.Name, student.Email))
+}
+
+// attachmentName returns the name the file was uploaded with, stored keys are prefixed with the attachment ID
+func attachmentName(att schema.Attachment) string {
+	name := att.ID.String()
+	if u, err := url.Parse(att.URL); err == nil {
+		if base := path.Base(u.Path); base != "." && base != "/" {
+			name = base
+		}
+	}
+	if id, rest, ok := strings.Cut(name, "."); ok {
+		if _, err := uuid.Parse(id); err == nil {
+			name = rest
+		}
+	}
+	return cleanName(name)
+}
+
+func cleanName(name string) string {
+	return strings.NewReplacer("/", "_", "\\", "_").Replace(strings.TrimSpace(name))
+}
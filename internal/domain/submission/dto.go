@@ -2,6 +2,9 @@ package submission
 
 import (
 	"mime/multipart"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
 )
 
 type CreateSubmissionRequest struct {
@@ -15,6 +18,67 @@ type UpdateSubmissionRequest struct {
 	Attachments []*multipart.FileHeader `form:"attachments,omitempty"`
 }
 
+// GradeSubmissionRequest grades by rubric when the assignment has one, Scores then picks a level for
+// every criterion and the grade is worked out from the points. Without a rubric Grade is given directly.
+// Adjustments of a team submission change the grade of single members, members left out keep theirs
 type GradeSubmissionRequest struct {
-	Grade float64 `json:"grade" binding:"required,min=0,max=100"`
+	Grade       *float64                `json:"grade" binding:"required_without=Scores,omitempty,min=0,max=100"`
+	Scores      []CriterionScoreRequest `json:"scores" binding:"omitempty,max=50,dive"`
+	Feedback    string                  `json:"feedback" binding:"max=5000"`
+	Adjustments []AdjustmentRequest     `json:"adjustments" binding:"omitempty,max=100,dive"`
+}
+
+// AdjustmentRequest adds Points to the grade of the team for one of its members, negative points take off
+type AdjustmentRequest struct {
+	UserID uuid.UUID `json:"user_id" binding:"required"`
+	Points float64   `json:"points" binding:"min=-100,max=100"`
+	Reason string    `json:"reason" binding:"max=500"`
+}
+
+// UpdateStatusRequest moves a submission along while it is being graded, a returned submission may carry
+// feedback telling the student what to change before handing in again
+type UpdateStatusRequest struct {
+	Status   schema.SubmissionStatus `json:"status" binding:"required,oneof=in_review returned"`
+	Feedback *string                 `json:"feedback" binding:"omitempty,max=5000"`
+}
+
+type ListSubmissionsRequest struct {
+	Status schema.SubmissionStatus `form:"status" binding:"omitempty,oneof=submitted in_review graded returned"`
+}
+
+// BulkGradeRequest grades many submissions to one assignment together, every item is graded the same way
+// a single submission is
+type BulkGradeRequest struct {
+	Grades []BulkGradeItem `json:"grades" binding:"required,min=1,max=500,dive"`
+}
+
+type BulkGradeItem struct {
+	SubmissionID uuid.UUID `json:"submission_id" binding:"required"`
+	GradeSubmissionRequest
+}
+
+type CriterionScoreRequest struct {
+	CriterionID uuid.UUID `json:"criterion_id" binding:"required"`
+	LevelID     uuid.UUID `json:"level_id" binding:"required"`
+	Comment     string    `json:"comment" binding:"max=1000"`
+}
+
+// AttemptsResponse lists the attempts of the student, for a group assignment those of their Team
+type AttemptsResponse struct {
+	Team          *schema.Team         `json:"team,omitempty"`
+	Attempts      []schema.Submission  `json:"attempts"`
+	MaxAttempts   int                  `json:"max_attempts"`
+	AttemptsLeft  int                  `json:"attempts_left"`
+	GradingPolicy schema.GradingPolicy `json:"grading_policy"`
+	FinalGrade    *float64             `json:"final_grade"`
+}
+
+type ReleaseResponse struct {
+	Released int   `json:"released"`
+	Ungraded int64 `json:"ungraded"`
+}
+
+type BulkGradeResponse struct {
+	Graded int `json:"graded"`
+	Held   int `json:"held"`
 }
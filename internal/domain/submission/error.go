@@ -27,11 +27,61 @@ var (
 				WithMessage("NOT_ENROLL_ACCESS").
 				Build()
 
+	ErrAssignmentNotReleased = apierror.NewApiErrorBuilder().
+					WithHttpStatus(http.StatusForbidden).
+					WithMessage("ASSIGNMENT_NOT_RELEASED").
+					Build()
+
+	ErrPastDue = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusForbidden).
+			WithMessage("ASSIGNMENT_PAST_DUE").
+			Build()
+
 	ErrSubmissionAlreadyExists = apierror.NewApiErrorBuilder().
 					WithHttpStatus(http.StatusBadRequest).
 					WithMessage("SUBMISSION_ALREADY_EXISTS_ACCESS").
 					Build()
 
+	ErrNoAttemptsLeft = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("NO_ATTEMPTS_LEFT").
+				Build()
+
+	ErrSubmissionLocked = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("SUBMISSION_LOCKED").
+				Build()
+
+	ErrInvalidStatusChange = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_STATUS_CHANGE").
+				Build()
+
+	ErrInvalidBulkGrade = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_BULK_GRADE").
+				Build()
+
+	ErrNoRubric = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("ASSIGNMENT_HAS_NO_RUBRIC").
+			Build()
+
+	ErrGradeRequired = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("GRADE_REQUIRED").
+				Build()
+
+	ErrRubricIncomplete = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("RUBRIC_INCOMPLETE").
+				Build()
+
+	ErrInvalidRubricScore = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_RUBRIC_SCORE").
+				Build()
+
 	ErrNotOwnerSubmission = apierror.NewApiErrorBuilder().
 				WithHttpStatus(http.StatusBadRequest).
 				WithMessage("NOT_OWNER_ACCESS").
@@ -66,4 +116,14 @@ var (
 			WithHttpStatus(http.StatusConflict).
 			WithMessage("EDIT_CONFLICT").
 			Build()
+
+	ErrNotInTeam = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusForbidden).
+			WithMessage("NOT_IN_TEAM").
+			Build()
+
+	ErrInvalidAdjustment = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_GRADE_ADJUSTMENT").
+				Build()
 )
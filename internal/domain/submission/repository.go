@@ -2,19 +2,34 @@ package submission
 
 import (
 	"context"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type Repository interface {
 	Create(ctx context.Context, s *schema.Submission) error
 	Update(ctx context.Context, s *schema.Submission) error
+	SaveGrade(ctx context.Context, s *schema.Submission) error
+	SaveGrades(ctx context.Context, submissions []*schema.Submission) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetByID(ctx context.Context, id uuid.UUID) (*schema.Submission, error)
-	GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error)
-	CheckSubmissionExists(ctx context.Context, userID, assignmentID uuid.UUID) (bool, error)
+	GetByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.Submission, error)
+	GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID, status schema.SubmissionStatus) ([]schema.Submission, error)
+	GetAttempts(ctx context.Context, userID, assignmentID uuid.UUID) ([]schema.Submission, error)
+	CountAttempts(ctx context.Context, userID, assignmentID uuid.UUID) (int64, error)
+	GetLastAttempt(ctx context.Context, userID, assignmentID uuid.UUID) (int, error)
+	GetLatestStatus(ctx context.Context, userID, assignmentID uuid.UUID) (schema.SubmissionStatus, error)
+	ReleaseGrades(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error)
+	CountUngraded(ctx context.Context, assignmentID uuid.UUID) (int64, error)
+	GetFinalGrade(ctx context.Context, userID, assignmentID uuid.UUID) (*float64, error)
+	GetSubmitters(ctx context.Context, assignmentID uuid.UUID) ([]schema.User, error)
+	GetTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error)
+	GetTeamAttempts(ctx context.Context, teamID, assignmentID uuid.UUID) ([]schema.Submission, error)
+	GetTeamLastAttempt(ctx context.Context, teamID, assignmentID uuid.UUID) (int, error)
 }
 
 type repository struct {
@@ -33,30 +48,210 @@ func (r *repository) Update(ctx context.Context, s *schema.Submission) error {
 	return r.db.WithContext(ctx).Save(s).Error
 }
 
+// SaveGrade stores the grade and feedback of the submission, its rubric scores replace the ones of an earlier grading
+func (r *repository) SaveGrade(ctx context.Context, s *schema.Submission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		return saveGrade(tx, s)
+	})
+}
+
+// SaveGrades stores the grades of many submissions at once, none of them is saved when one fails
+func (r *repository) SaveGrades(ctx context.Context, submissions []*schema.Submission) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, s := range submissions {
+			if err := saveGrade(tx, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func saveGrade(tx *gorm.DB, s *schema.Submission) error {
+	if err := tx.Omit("Attachments", "RubricScores", "Members").Save(s).Error; err != nil {
+		return err
+	}
+	for _, m := range s.Members {
+		if err := tx.Model(&schema.SubmissionMember{}).
+			Where("submission_id = ? AND user_id = ?", s.ID, m.UserID).
+			Updates(map[string]any{"adjustment": m.Adjustment, "adjustment_reason": m.AdjustmentReason}).Error; err != nil {
+			return err
+		}
+	}
+	if err := tx.Where("submission_id = ?", s.ID).Delete(&schema.RubricScore{}).Error; err != nil {
+		return err
+	}
+	if len(s.RubricScores) == 0 {
+		return nil
+	}
+	return tx.Omit("RubricCriterion", "RubricLevel").Create(&s.RubricScores).Error
+}
+
 func (r *repository) Delete(ctx context.Context, id uuid.UUID) error {
 	return r.db.WithContext(ctx).Delete(&schema.Submission{}, id).Error
 }
 
 func (r *repository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Submission, error) {
 	var submission schema.Submission
-	if err := r.db.Preload("Attachments").First(&submission, "id = ?", id).Error; err != nil {
+	if err := r.db.Preload("Attachments").Preload("RubricScores").Preload("Members").First(&submission, "id = ?", id).Error; err != nil {
 		return nil, err
 	}
 	return &submission, nil
 }
 
-func (r *repository) GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+func (r *repository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.Submission, error) {
+	var submissions []schema.Submission
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Preload("Members").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// GetAllByAssignment lists the submissions to an assignment, only those in the given status when it is set
+func (r *repository) GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID, status schema.SubmissionStatus) ([]schema.Submission, error) {
+	var submissions []schema.Submission
+	query := r.db.Where("assignment_id = ?", assignmentID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if err := query.Preload("Attachments").Preload("RubricScores").Preload("Members").Order("user_id, attempt").Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// GetAttempts lists the attempts of a student at an assignment, oldest first
+func (r *repository) GetAttempts(ctx context.Context, userID, assignmentID uuid.UUID) ([]schema.Submission, error) {
 	var submissions []schema.Submission
-	if err := r.db.Where("assignment_id = ?", assignmentID).Preload("Attachments").Find(&submissions).Error; err != nil {
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+		Preload("Attachments").
+		Preload("RubricScores").
+		Preload("Members").
+		Order("attempt").
+		Find(&submissions).Error; err != nil {
 		return nil, err
 	}
 	return submissions, nil
 }
 
-func (r *repository) CheckSubmissionExists(ctx context.Context, userID, assignmentID uuid.UUID) (bool, error) {
+// CountAttempts counts the attempts a student has used, including deleted attempts so deleting one never
+// gives the slot back
+func (r *repository) CountAttempts(ctx context.Context, userID, assignmentID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&schema.Submission{}).
-		Where("user_id = ? AND assignment_id = ? AND deleted_at IS NULL", userID, assignmentID).
+	err := r.db.WithContext(ctx).Unscoped().Model(&schema.Submission{}).
+		Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
 		Count(&count).Error
-	return count > 0, err
+	return count, err
+}
+
+// GetLastAttempt returns the highest attempt number handed in so far, including deleted attempts
+// so their number is never reused
+func (r *repository) GetLastAttempt(ctx context.Context, userID, assignmentID uuid.UUID) (int, error) {
+	var last int
+	err := r.db.WithContext(ctx).Unscoped().Model(&schema.Submission{}).
+		Select("COALESCE(MAX(attempt), 0)").
+		Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+		Scan(&last).Error
+	return last, err
+}
+
+// GetLatestStatus returns the status of the latest attempt of the student, empty when nothing was handed in
+func (r *repository) GetLatestStatus(ctx context.Context, userID, assignmentID uuid.UUID) (schema.SubmissionStatus, error) {
+	var statuses []schema.SubmissionStatus
+	err := r.db.WithContext(ctx).Model(&schema.Submission{}).
+		Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+		Order("attempt DESC").
+		Limit(1).
+		Pluck("status", &statuses).Error
+	if err != nil || len(statuses) == 0 {
+		return "", err
+	}
+	return statuses[0], nil
+}
+
+// ReleaseGrades shows every graded submission of the assignment that is still held back to its student and
+// returns the ones it released
+func (r *repository) ReleaseGrades(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	var released []schema.Submission
+	err := r.db.WithContext(ctx).Model(&released).
+		Clauses(clause.Returning{}).
+		Where("assignment_id = ? AND status = ? AND released_at IS NULL", assignmentID, schema.SubmissionGraded).
+		Update("released_at", time.Now()).Error
+	return released, err
+}
+
+// CountUngraded counts the submissions to the assignment that still wait for a grade
+func (r *repository) CountUngraded(ctx context.Context, assignmentID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Submission{}).
+		Where("assignment_id = ? AND status IN ?", assignmentID,
+			[]schema.SubmissionStatus{schema.SubmissionSubmitted, schema.SubmissionInReview}).
+		Count(&count).Error
+	return count, err
+}
+
+// GetFinalGrade returns the released grade of the student for the assignment after its grading policy,
+// nil while nothing is graded and released
+func (r *repository) GetFinalGrade(ctx context.Context, userID, assignmentID uuid.UUID) (*float64, error) {
+	var grades []*float64
+	if err := r.db.WithContext(ctx).Table("assignment_grades").
+		Where("user_id = ? AND assignment_id = ?", userID, assignmentID).
+		Pluck("released_grade", &grades).Error; err != nil {
+		return nil, err
+	}
+	if len(grades) == 0 {
+		return nil, nil
+	}
+	return grades[0], nil
+}
+
+// GetSubmitters returns the students who handed in to the assignment
+func (r *repository) GetSubmitters(ctx context.Context, assignmentID uuid.UUID) ([]schema.User, error) {
+	submitted := r.db.Model(&schema.Submission{}).
+		Select("user_id").
+		Where("assignment_id = ?", assignmentID)
+
+	var users []schema.User
+	err := r.db.WithContext(ctx).Where("id IN (?)", submitted).Order("name, email").Find(&users).Error
+	return users, err
+}
+
+// GetTeam returns the team of the student within the team set with its members, gorm.ErrRecordNotFound while
+// they have none
+func (r *repository) GetTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error) {
+	member := r.db.Model(&schema.TeamMember{}).
+		Select("team_id").
+		Where("team_set_id = ? AND user_id = ?", teamSetID, userID)
+
+	var team schema.Team
+	if err := r.db.WithContext(ctx).Preload("Members").Where("id IN (?)", member).First(&team).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetTeamAttempts lists the attempts of a team at a group assignment, oldest first
+func (r *repository) GetTeamAttempts(ctx context.Context, teamID, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	var submissions []schema.Submission
+	if err := r.db.WithContext(ctx).
+		Where("team_id = ? AND assignment_id = ?", teamID, assignmentID).
+		Preload("Attachments").
+		Preload("RubricScores").
+		Preload("Members").
+		Order("attempt").
+		Find(&submissions).Error; err != nil {
+		return nil, err
+	}
+	return submissions, nil
+}
+
+// GetTeamLastAttempt returns the highest attempt number the team handed in so far, including deleted attempts
+func (r *repository) GetTeamLastAttempt(ctx context.Context, teamID, assignmentID uuid.UUID) (int, error) {
+	var last int
+	err := r.db.WithContext(ctx).Unscoped().Model(&schema.Submission{}).
+		Select("COALESCE(MAX(attempt), 0)").
+		Where("team_id = ? AND assignment_id = ?", teamID, assignmentID).
+		Scan(&last).Error
+	return last, err
 }
@@ -1,6 +1,7 @@
 package submission
 
 import (
+	"log"
 	"net/http"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
@@ -20,10 +21,15 @@ func NewRestController(r *gin.Engine, uc *UseCase) {
 	submissionGroup := r.Group("/v1/submissions")
 	{
 		submissionGroup.POST("", middleware.Authenticate(), middleware.RequireRole("student"), c.createSubmission)
-		submissionGroup.GET("/:id", c.getSubmissionByID)
+		submissionGroup.GET("/:id", middleware.Authenticate(), c.getSubmissionByID)
 		submissionGroup.PUT("/:id", middleware.Authenticate(), middleware.RequireRole("student"), c.updateSubmission)
 		submissionGroup.DELETE("/:id", middleware.Authenticate(), middleware.RequireRole("student"), c.deleteSubmission)
-		submissionGroup.GET("/assignments/:assignmentId", c.getAllSubmissionsByAssignment)
+		submissionGroup.PUT("/:id/status", middleware.Authenticate(), middleware.RequireRole("instructor"), c.updateStatus)
+		submissionGroup.GET("/assignments/:assignmentId", middleware.Authenticate(), middleware.RequireRole("instructor"), c.getAllSubmissionsByAssignment)
+		submissionGroup.POST("/assignments/:assignmentId/release", middleware.Authenticate(), middleware.RequireRole("instructor"), c.releaseGrades)
+		submissionGroup.POST("/assignments/:assignmentId/grades", middleware.Authenticate(), middleware.RequireRole("instructor"), c.bulkGrade)
+		submissionGroup.GET("/assignments/:assignmentId/download", middleware.Authenticate(), middleware.RequireRole("instructor"), c.downloadSubmissions)
+		submissionGroup.GET("/assignments/:assignmentId/mine", middleware.Authenticate(), middleware.RequireRole("student"), c.getMyAttempts)
 		submissionGroup.PUT("/grade/:id", middleware.Authenticate(), middleware.RequireRole("instructor"), c.gradeSubmission)
 	}
 
@@ -56,7 +62,7 @@ func (c *Controller) getSubmissionByID(ctx *gin.Context) {
 		return
 	}
 
-	submission, err := c.useCase.GetSubmissionByID(ctx, id)
+	submission, err := c.useCase.GetSubmissionByID(ctx, id, ctx.GetString("user.id"))
 	if err != nil {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
@@ -83,7 +89,7 @@ func (c *Controller) gradeSubmission(ctx *gin.Context) {
 		return
 	}
 
-	if err := c.useCase.GradeSubmission(ctx, userID.(string), id, req.Grade); err != nil {
+	if err := c.useCase.GradeSubmission(ctx, userID.(string), id, &req); err != nil {
 		response.NewRestResponse(apierror.GetHttpStatus(err), "Failed to grade submission: "+err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
@@ -91,6 +97,26 @@ func (c *Controller) gradeSubmission(ctx *gin.Context) {
 	response.NewRestResponse(http.StatusOK, "Submission graded successfully", nil).Send(ctx)
 }
 
+func (c *Controller) updateStatus(ctx *gin.Context) {
+	id, err := uuid.Parse(ctx.Param("id"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid Submission ID", nil).Send(ctx)
+		return
+	}
+
+	var req UpdateStatusRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid status data: "+err.Error(), nil).Send(ctx)
+		return
+	}
+
+	if err := c.useCase.UpdateStatus(ctx, ctx.GetString("user.id"), id, &req); err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Submission status updated successfully", nil).Send(ctx)
+}
+
 func (c *Controller) updateSubmission(ctx *gin.Context) {
 	id, err := uuid.Parse(ctx.Param("id"))
 	if err != nil {
@@ -114,7 +140,7 @@ func (c *Controller) updateSubmission(ctx *gin.Context) {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
-	response.NewRestResponse(http.StatusOK, "Submission updated successfully", nil).Send(ctx)
+	response.NewRestResponse(http.StatusCreated, "Submission resubmitted successfully", nil).Send(ctx)
 }
 
 func (c *Controller) deleteSubmission(ctx *gin.Context) {
@@ -144,10 +170,89 @@ func (c *Controller) getAllSubmissionsByAssignment(ctx *gin.Context) {
 		return
 	}
 
-	submissions, err := c.useCase.GetAllSubmissionsByAssignment(ctx, assignmentId)
+	var req ListSubmissionsRequest
+	if err := ctx.ShouldBindQuery(&req); err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid filter: "+err.Error(), nil).Send(ctx)
+		return
+	}
+
+	submissions, err := c.useCase.GetAllSubmissionsByAssignment(ctx, assignmentId, ctx.GetString("user.id"), &req)
 	if err != nil {
 		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
 		return
 	}
 	response.NewRestResponse(http.StatusOK, "Submissions retrieved successfully", submissions).Send(ctx)
 }
+
+func (c *Controller) getMyAttempts(ctx *gin.Context) {
+	assignmentId, err := uuid.Parse(ctx.Param("assignmentId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid Assignment ID", nil).Send(ctx)
+		return
+	}
+
+	attempts, err := c.useCase.GetMyAttempts(ctx, assignmentId, ctx.GetString("user.id"))
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Attempts retrieved successfully", attempts).Send(ctx)
+}
+
+func (c *Controller) releaseGrades(ctx *gin.Context) {
+	assignmentId, err := uuid.Parse(ctx.Param("assignmentId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid Assignment ID", nil).Send(ctx)
+		return
+	}
+
+	res, err := c.useCase.ReleaseGrades(ctx, assignmentId, ctx.GetString("user.id"))
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Grades released successfully", res).Send(ctx)
+}
+
+func (c *Controller) bulkGrade(ctx *gin.Context) {
+	assignmentId, err := uuid.Parse(ctx.Param("assignmentId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid Assignment ID", nil).Send(ctx)
+		return
+	}
+
+	var req BulkGradeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid grading data: "+err.Error(), nil).Send(ctx)
+		return
+	}
+
+	res, err := c.useCase.BulkGrade(ctx, assignmentId, ctx.GetString("user.id"), &req)
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), "Failed to grade submissions: "+err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+	response.NewRestResponse(http.StatusOK, "Submissions graded successfully", res).Send(ctx)
+}
+
+func (c *Controller) downloadSubmissions(ctx *gin.Context) {
+	assignmentId, err := uuid.Parse(ctx.Param("assignmentId"))
+	if err != nil {
+		response.NewRestResponse(http.StatusBadRequest, "Invalid Assignment ID", nil).Send(ctx)
+		return
+	}
+
+	archive, err := c.useCase.DownloadSubmissions(ctx, assignmentId, ctx.GetString("user.id"))
+	if err != nil {
+		response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+		return
+	}
+
+	ctx.Header("Content-Type", "application/zip")
+	ctx.Header("Content-Disposition", `attachment; filename="`+archive.Filename+`"`)
+	ctx.Status(http.StatusOK)
+	// the headers are gone by now, a failure can only cut the download short
+	if err := archive.Write(ctx.Request.Context(), ctx.Writer); err != nil {
+		log.Println("Error writing submissions archive: ", err)
+	}
+}
@@ -0,0 +1,91 @@
+package submission
+
+import (
+	"math"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+)
+
+// ScoreByRubric grades the picked levels the same way a submission is graded, for scores that are not
+// stored on the submission such as peer reviews
+func ScoreByRubric(rubric []schema.RubricCriterion, req *GradeSubmissionRequest) (float64, []schema.RubricScore, error) {
+	return gradeByRubric(rubric, req, uuid.Nil)
+}
+
+// gradeByRubric works out the grade out of 100 from the level picked for every criterion of the rubric,
+// rounded to one decimal like the grades given directly. Assignments without a rubric take the grade of
+// the request as it is
+func gradeByRubric(rubric []schema.RubricCriterion, req *GradeSubmissionRequest, submissionID uuid.UUID) (float64, []schema.RubricScore, error) {
+	if len(rubric) == 0 {
+		if len(req.Scores) > 0 {
+			return 0, nil, ErrNoRubric
+		}
+		if req.Grade == nil {
+			return 0, nil, ErrGradeRequired
+		}
+		return *req.Grade, nil, nil
+	}
+
+	picked := make(map[uuid.UUID]CriterionScoreRequest, len(req.Scores))
+	for _, score := range req.Scores {
+		if _, ok := picked[score.CriterionID]; ok {
+			return 0, nil, ErrInvalidRubricScore
+		}
+		picked[score.CriterionID] = score
+	}
+
+	var points, maxPoints float64
+	scores := make([]schema.RubricScore, 0, len(rubric))
+	for _, criterion := range rubric {
+		criterionMax := 0.0
+		for _, level := range criterion.Levels {
+			criterionMax = max(criterionMax, level.Points)
+		}
+		maxPoints += criterionMax
+
+		score, ok := picked[criterion.ID]
+		if !ok {
+			return 0, nil, ErrRubricIncomplete
+		}
+		delete(picked, criterion.ID)
+
+		var level *schema.RubricLevel
+		for i := range criterion.Levels {
+			if criterion.Levels[i].ID == score.LevelID {
+				level = &criterion.Levels[i]
+			}
+		}
+		if level == nil {
+			return 0, nil, ErrInvalidRubricScore
+		}
+		points += level.Points
+
+		id, err := uuid.NewV7()
+		if err != nil {
+			return 0, nil, apierror.ErrInternalServer.Build()
+		}
+		criterionID, levelID := criterion.ID, level.ID
+		scores = append(scores, schema.RubricScore{
+			ID:           id,
+			SubmissionID: submissionID,
+			CriterionID:  &criterionID,
+			LevelID:      &levelID,
+			Criterion:    criterion.Title,
+			Level:        level.Title,
+			Points:       level.Points,
+			MaxPoints:    criterionMax,
+			Comment:      score.Comment,
+		})
+	}
+
+	// scores left over point at criteria of another assignment
+	if len(picked) > 0 {
+		return 0, nil, ErrInvalidRubricScore
+	}
+	if maxPoints == 0 {
+		return 0, nil, ErrInvalidRubricScore
+	}
+	return math.Round(points/maxPoints*1000) / 10, scores, nil
+}
@@ -1,13 +1,20 @@
 package submission
 
 import (
+	"archive/zip"
+	"bytes"
 	"context"
+	"io"
 	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/config"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
@@ -16,6 +23,9 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 	"gopkg.in/gomail.v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
 )
 
 type MockRepository struct {
@@ -32,6 +42,16 @@ func (m *MockRepository) Update(ctx context.Context, s *schema.Submission) error
 	return args.Error(0)
 }
 
+func (m *MockRepository) SaveGrade(ctx context.Context, s *schema.Submission) error {
+	args := m.Called(ctx, s)
+	return args.Error(0)
+}
+
+func (m *MockRepository) SaveGrades(ctx context.Context, submissions []*schema.Submission) error {
+	args := m.Called(ctx, submissions)
+	return args.Error(0)
+}
+
 func (m *MockRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	args := m.Called(ctx, id)
 	return args.Error(0)
@@ -45,7 +65,46 @@ func (m *MockRepository) GetByID(ctx context.Context, id uuid.UUID) (*schema.Sub
 	return nil, args.Error(1)
 }
 
-func (m *MockRepository) GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
+func (m *MockRepository) GetByIDs(ctx context.Context, ids []uuid.UUID) ([]schema.Submission, error) {
+	args := m.Called(ctx, ids)
+	if item := args.Get(0); item != nil {
+		return item.([]schema.Submission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetAllByAssignment(ctx context.Context, assignmentID uuid.UUID, status schema.SubmissionStatus) ([]schema.Submission, error) {
+	args := m.Called(ctx, assignmentID, status)
+	if item := args.Get(0); item != nil {
+		return item.([]schema.Submission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetAttempts(ctx context.Context, userID, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	args := m.Called(ctx, userID, assignmentID)
+	if item := args.Get(0); item != nil {
+		return item.([]schema.Submission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) CountAttempts(ctx context.Context, userID, assignmentID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, userID, assignmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetLastAttempt(ctx context.Context, userID, assignmentID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID, assignmentID)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockRepository) GetLatestStatus(ctx context.Context, userID, assignmentID uuid.UUID) (schema.SubmissionStatus, error) {
+	args := m.Called(ctx, userID, assignmentID)
+	return args.Get(0).(schema.SubmissionStatus), args.Error(1)
+}
+
+func (m *MockRepository) ReleaseGrades(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
 	args := m.Called(ctx, assignmentID)
 	if item := args.Get(0); item != nil {
 		return item.([]schema.Submission), args.Error(1)
@@ -53,9 +112,43 @@ func (m *MockRepository) GetAllByAssignment(ctx context.Context, assignmentID uu
 	return nil, args.Error(1)
 }
 
-func (m *MockRepository) CheckSubmissionExists(ctx context.Context, userID, assignmentID uuid.UUID) (bool, error) {
+func (m *MockRepository) CountUngraded(ctx context.Context, assignmentID uuid.UUID) (int64, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockRepository) GetFinalGrade(ctx context.Context, userID, assignmentID uuid.UUID) (*float64, error) {
 	args := m.Called(ctx, userID, assignmentID)
-	return args.Bool(0), args.Error(1)
+	if item := args.Get(0); item != nil {
+		return item.(*float64), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetSubmitters(ctx context.Context, assignmentID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockRepository) GetTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error) {
+	args := m.Called(ctx, teamSetID, userID)
+	if item := args.Get(0); item != nil {
+		return item.(*schema.Team), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetTeamAttempts(ctx context.Context, teamID, assignmentID uuid.UUID) ([]schema.Submission, error) {
+	args := m.Called(ctx, teamID, assignmentID)
+	if item := args.Get(0); item != nil {
+		return item.([]schema.Submission), args.Error(1)
+	}
+	return nil, args.Error(1)
+}
+
+func (m *MockRepository) GetTeamLastAttempt(ctx context.Context, teamID, assignmentID uuid.UUID) (int, error) {
+	args := m.Called(ctx, teamID, assignmentID)
+	return args.Int(0), args.Error(1)
 }
 
 type MockCourseRepository struct {
@@ -112,11 +205,61 @@ func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string,
 	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
 }
 
-func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filterType, filterValue, sort string, page, limit int) ([]schema.Course, int, error) {
-	args := m.Called(ctx, filterType, filterValue, sort, page, limit)
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
 	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
 }
 
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
 type MockFileUploader struct {
 	mock.Mock
 }
@@ -126,6 +269,16 @@ func (m *MockFileUploader) UploadFile(key string, fileHeader *multipart.FileHead
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	args := m.Called(key, content, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	args := m.Called(fileURL, expiry)
+	return args.String(0), args.Error(1)
+}
+
 type MockEnrollRepository struct {
 	mock.Mock
 }
@@ -150,6 +303,32 @@ func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID
 	return args.Bool(0), args.Error(1)
 }
 
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
 type MockNotificationRepository struct {
 	mock.Mock
 }
@@ -254,6 +433,65 @@ func (m *MockAssignmentRepo) GetByCourseID(ctx context.Context, courseId uuid.UU
 	return args.Get(0).([]*schema.Assignment), args.Error(1)
 }
 
+func (m *MockAssignmentRepo) GetSubmitterIDs(ctx context.Context, id uuid.UUID) ([]uuid.UUID, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).([]uuid.UUID), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) SaveExtension(ctx context.Context, extension *schema.AssignmentExtension) error {
+	args := m.Called(ctx, extension)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) DeleteExtension(ctx context.Context, assignmentID, userID uuid.UUID) error {
+	args := m.Called(ctx, assignmentID, userID)
+	return args.Error(0)
+}
+
+func (m *MockAssignmentRepo) GetExtension(ctx context.Context, assignmentID, userID uuid.UUID) (*schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetExtensions(ctx context.Context, assignmentID uuid.UUID) ([]schema.AssignmentExtension, error) {
+	args := m.Called(ctx, assignmentID)
+	return args.Get(0).([]schema.AssignmentExtension), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetCohortStart(ctx context.Context, courseID, userID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, courseID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) CategoryExists(ctx context.Context, courseID, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) TeamSetExists(ctx context.Context, courseID, teamSetID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, courseID, teamSetID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) GetRubric(ctx context.Context, assignmentID uuid.UUID) ([]schema.RubricCriterion, error) {
+	args := m.Called(ctx, assignmentID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]schema.RubricCriterion), args.Error(1)
+}
+
+func (m *MockAssignmentRepo) ReplaceRubric(ctx context.Context, assignmentID uuid.UUID, criteria []schema.RubricCriterion) error {
+	args := m.Called(ctx, assignmentID, criteria)
+	return args.Error(0)
+}
+
 type MockAttachmentRepo struct {
 	mock.Mock
 }
@@ -282,6 +520,44 @@ func (m *MockAttachmentRepo) Delete(ctx context.Context, id uuid.UUID) error {
 	return args.Error(0)
 }
 
+// sqlRecorder keeps the statements gorm builds, with their arguments inlined
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// dryRunRepository builds the queries of the repository without a database
+func dryRunRepository(t *testing.T) (Repository, *sqlRecorder) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:               true,
+		DisableAutomaticPing: true,
+		Logger:               recorder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRepository(db), recorder
+}
+
+func TestCountAttempts_CountsDeletedAttempts(t *testing.T) {
+	repo, recorder := dryRunRepository(t)
+
+	_, _ = repo.CountAttempts(context.Background(), uuid.New(), uuid.New())
+
+	assert.Len(t, recorder.statements, 1)
+	assert.NotContains(t, recorder.statements[0], "deleted_at")
+}
+
 type SubmissionUseCaseTestSuite struct {
 	suite.Suite
 
@@ -311,7 +587,7 @@ func (suite *SubmissionUseCaseTestSuite) SetupTest() {
 	suite.notificationRepo = new(MockNotificationRepository)
 	suite.enrollUseCase = courseenroll.NewUseCase(suite.enrollRepo)
 	suite.attachmentUseCase = attachment.NewUseCase(suite.attachmentRepo, suite.uploader)
-	suite.submisionUseCase = NewUseCase(suite.submissionRepo, suite.assignmentRepo, *suite.attachmentUseCase, suite.courseRepo, suite.enrollRepo, suite.userRepo, suite.notificationRepo, suite.mailer)
+	suite.submisionUseCase = NewUseCase(suite.submissionRepo, suite.assignmentRepo, *suite.attachmentUseCase, suite.courseRepo, suite.enrollUseCase, suite.userRepo, suite.notificationRepo, suite.mailer)
 
 }
 
@@ -348,17 +624,20 @@ func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_Success() {
 	suite.submissionRepo.On("GetByID", ctx, mock.Anything).Return(submission, nil)
 	suite.assignmentRepo.On("GetByID", ctx, mock.Anything).Return(assignment, nil)
 	suite.courseRepo.On("GetByID", ctx, mock.Anything).Return(*course, nil)
-	suite.submissionRepo.On("Update", ctx, submission).Return(nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignmentId).Return(nil, nil)
+	suite.submissionRepo.On("SaveGrade", ctx, submission).Return(nil)
 	suite.userRepo.On("GetByID", mock.Anything).Return(instructor, nil)
 	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
 	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
 
 	// Call the function under test
-	err := suite.submisionUseCase.GradeSubmission(ctx, userId.String(), submissionId, grade)
+	err := suite.submisionUseCase.GradeSubmission(ctx, userId.String(), submissionId, &GradeSubmissionRequest{Grade: &grade})
 
 	// Assertions
 	assert.NoError(suite.T(), err)
-	assert.Equal(suite.T(), grade, submission.Grade)
+	assert.Equal(suite.T(), grade, *submission.Grade)
+	assert.Equal(suite.T(), schema.SubmissionGraded, submission.Status)
+	assert.NotNil(suite.T(), submission.ReleasedAt)
 	suite.submissionRepo.AssertExpectations(suite.T())
 	suite.assignmentRepo.AssertExpectations(suite.T())
 	suite.courseRepo.AssertExpectations(suite.T())
@@ -392,11 +671,12 @@ func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_Failure_NotCourseIn
 	suite.courseRepo.On("GetByID", ctx, courseId).Return(*course, nil)
 
 	// Call the function under test
-	err := suite.submisionUseCase.GradeSubmission(ctx, userId.String(), submissionId, 90.0)
+	grade := 90.0
+	err := suite.submisionUseCase.GradeSubmission(ctx, userId.String(), submissionId, &GradeSubmissionRequest{Grade: &grade})
 
 	// Assertions
 	assert.Error(suite.T(), err)
-	assert.NotEqual(suite.T(), 90.0, submission.Grade)
+	assert.Nil(suite.T(), submission.Grade)
 	assert.Equal(suite.T(), ErrNotOwnerCourse, err)
 	suite.submissionRepo.AssertExpectations(suite.T())
 	suite.assignmentRepo.AssertExpectations(suite.T())
@@ -421,6 +701,23 @@ func (suite *SubmissionUseCaseTestSuite) TestDeleteSubmission_Success() {
 	suite.submissionRepo.AssertExpectations(suite.T())
 }
 
+func (suite *SubmissionUseCaseTestSuite) TestDeleteSubmission_Locked() {
+	for _, status := range []schema.SubmissionStatus{schema.SubmissionInReview, schema.SubmissionGraded, schema.SubmissionReturned} {
+		suite.Run(string(status), func() {
+			ctx := context.Background()
+			userID := uuid.New()
+			submission := &schema.Submission{ID: uuid.New(), UserID: userID, Status: status}
+
+			suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+
+			err := suite.submisionUseCase.DeleteSubmission(ctx, submission.ID, userID.String())
+
+			assert.Equal(suite.T(), ErrSubmissionLocked, err)
+			suite.submissionRepo.AssertNotCalled(suite.T(), "Delete", ctx, submission.ID)
+		})
+	}
+}
+
 func (suite *SubmissionUseCaseTestSuite) TestDeleteSubmission_NotOwner() {
 	ctx := context.Background()
 	id := uuid.New()
@@ -443,36 +740,99 @@ func (suite *SubmissionUseCaseTestSuite) TestDeleteSubmission_NotOwner() {
 func (suite *SubmissionUseCaseTestSuite) TestGetSubmissionByID_Success() {
 	ctx := context.Background()
 	id := uuid.New()
+	userID := uuid.New()
+	grade := 88.0
+	releasedAt := time.Now()
 
 	submission := &schema.Submission{
-		ID: id,
+		ID:         id,
+		UserID:     userID,
+		Status:     schema.SubmissionGraded,
+		Grade:      &grade,
+		ReleasedAt: &releasedAt,
 	}
 
 	suite.submissionRepo.On("GetByID", ctx, id).Return(submission, nil)
 
-	result, err := suite.submisionUseCase.GetSubmissionByID(ctx, id)
+	result, err := suite.submisionUseCase.GetSubmissionByID(ctx, id, userID.String())
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), submission, result)
+	assert.Equal(suite.T(), 88.0, *result.Grade)
 	suite.submissionRepo.AssertExpectations(suite.T())
 }
 
+func (suite *SubmissionUseCaseTestSuite) TestGetSubmissionByID_HidesHeldGrade() {
+	ctx := context.Background()
+	userID := uuid.New()
+	grade := 55.0
+	submission := &schema.Submission{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Status:       schema.SubmissionGraded,
+		Grade:        &grade,
+		Feedback:     "Needs more sources",
+		RubricScores: []schema.RubricScore{{ID: uuid.New(), Points: 5}},
+	}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+
+	result, err := suite.submisionUseCase.GetSubmissionByID(ctx, submission.ID, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Nil(suite.T(), result.Grade)
+	assert.Empty(suite.T(), result.Feedback)
+	assert.Empty(suite.T(), result.RubricScores)
+	assert.Equal(suite.T(), schema.SubmissionInReview, result.Status)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGetSubmissionByID_OtherStudent() {
+	ctx := context.Background()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.submisionUseCase.GetSubmissionByID(ctx, submission.ID, uuid.New().String())
+
+	assert.Equal(suite.T(), ErrForbiddenOperation, err)
+}
+
 func (suite *SubmissionUseCaseTestSuite) TestGetAllSubmissionsByAssignment_Success() {
 	ctx := context.Background()
-	assignmentID := uuid.New()
+	instructorID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
 
 	submissions := []schema.Submission{
-		{ID: uuid.New()},
-		{ID: uuid.New()},
+		{ID: uuid.New(), Status: schema.SubmissionSubmitted},
+		{ID: uuid.New(), Status: schema.SubmissionSubmitted},
 	}
 
-	suite.submissionRepo.On("GetAllByAssignment", ctx, assignmentID).Return(submissions, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("GetAllByAssignment", ctx, assignment.ID, schema.SubmissionSubmitted).Return(submissions, nil)
 
-	result, err := suite.submisionUseCase.GetAllSubmissionsByAssignment(ctx, assignmentID)
+	result, err := suite.submisionUseCase.GetAllSubmissionsByAssignment(ctx, assignment.ID, instructorID.String(),
+		&ListSubmissionsRequest{Status: schema.SubmissionSubmitted})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), submissions, result)
 	suite.submissionRepo.AssertExpectations(suite.T())
 }
 
+func (suite *SubmissionUseCaseTestSuite) TestGetAllSubmissionsByAssignment_NotInstructor() {
+	ctx := context.Background()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.submisionUseCase.GetAllSubmissionsByAssignment(ctx, assignment.ID, uuid.New().String(), &ListSubmissionsRequest{})
+
+	assert.Equal(suite.T(), ErrNotOwnerCourse, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "GetAllByAssignment", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_NotEnrolled() {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -485,7 +845,8 @@ func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_NotEnrolled() {
 	}
 
 	suite.assignmentRepo.On("GetByID", ctx, assignmentID).Return(assignment, nil)
-	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseID).Return(false, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, courseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(false, nil)
 
 	err := suite.submisionUseCase.VerifyCourseEnroll(ctx, userID, assignmentID)
 	assert.Error(suite.T(), err)
@@ -494,17 +855,82 @@ func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_NotEnrolled() {
 	suite.enrollRepo.AssertExpectations(suite.T())
 }
 
-func (suite *SubmissionUseCaseTestSuite) TestCheckSubmissionExists_Exists() {
+func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_Subscriber() {
+	ctx := context.Background()
+	userID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, assignment.CourseID).Return(nil, gorm.ErrRecordNotFound)
+	suite.enrollRepo.On("HasActiveSubscription", ctx, userID).Return(true, nil)
+
+	err := suite.submisionUseCase.VerifyCourseEnroll(ctx, userID, assignment.ID)
+	assert.NoError(suite.T(), err)
+	suite.enrollRepo.AssertNotCalled(suite.T(), "IsEnrolled", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_NotReleasedYet() {
 	ctx := context.Background()
 	userID := uuid.New()
 	assignmentID := uuid.New()
+	courseID := uuid.New()
+	releaseAfterDays := 7
+	enrolledAt := time.Now().AddDate(0, 0, -2)
 
-	suite.submissionRepo.On("CheckSubmissionExists", ctx, userID, assignmentID).Return(true, nil)
+	assignment := &schema.Assignment{
+		ID:              assignmentID,
+		CourseID:        courseID,
+		ReleaseSchedule: schema.ReleaseSchedule{ReleaseAfterDays: &releaseAfterDays},
+	}
 
-	err := suite.submisionUseCase.CheckSubmissionExists(ctx, userID, assignmentID)
-	assert.Error(suite.T(), err)
-	assert.Equal(suite.T(), ErrSubmissionAlreadyExists, err)
-	suite.submissionRepo.AssertExpectations(suite.T())
+	suite.assignmentRepo.On("GetByID", ctx, assignmentID).Return(assignment, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, courseID).Return(&schema.CourseEnroll{}, nil)
+	suite.enrollRepo.On("GetAccessStart", ctx, userID, courseID).Return(&enrolledAt, nil)
+
+	err := suite.submisionUseCase.VerifyCourseEnroll(ctx, userID, assignmentID)
+	assert.Equal(suite.T(), ErrAssignmentNotReleased, err)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestVerifyCourseEnroll_Released() {
+	ctx := context.Background()
+	userID := uuid.New()
+	assignmentID := uuid.New()
+	courseID := uuid.New()
+	releaseAfterDays := 7
+	releaseAt := time.Now().Add(-time.Hour)
+	enrolledAt := time.Now().AddDate(0, 0, -10)
+
+	assignment := &schema.Assignment{
+		ID:       assignmentID,
+		CourseID: courseID,
+		ReleaseSchedule: schema.ReleaseSchedule{
+			ReleaseAt:        &releaseAt,
+			ReleaseAfterDays: &releaseAfterDays,
+		},
+	}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignmentID).Return(assignment, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, courseID).Return(&schema.CourseEnroll{}, nil)
+	suite.enrollRepo.On("GetAccessStart", ctx, userID, courseID).Return(&enrolledAt, nil)
+
+	err := suite.submisionUseCase.VerifyCourseEnroll(ctx, userID, assignmentID)
+	assert.NoError(suite.T(), err)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestCreateSubmission_NoAttemptsLeft() {
+	ctx := context.Background()
+	userID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), MaxAttempts: 2}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, assignment.CourseID).Return(&schema.CourseEnroll{}, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(2), nil)
+	suite.submissionRepo.On("GetLatestStatus", ctx, userID, assignment.ID).Return(schema.SubmissionGraded, nil)
+
+	err := suite.submisionUseCase.CreateSubmission(ctx, &CreateSubmissionRequest{AssignmentID: assignment.ID.String()}, userID.String())
+
+	assert.Equal(suite.T(), ErrNoAttemptsLeft, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
 }
 
 func (suite *SubmissionUseCaseTestSuite) TestCreateSubmission_Success() {
@@ -537,8 +963,9 @@ func (suite *SubmissionUseCaseTestSuite) TestCreateSubmission_Success() {
 		Email: "jane.instructor@example.com",
 	}
 
-	suite.submissionRepo.On("CheckSubmissionExists", ctx, userUUID, assignmentUUID).Return(false, nil)
-	suite.enrollRepo.On("IsEnrolled", ctx, userUUID, assignment.CourseID).Return(true, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userUUID, assignmentUUID).Return(int64(0), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userUUID, assignmentUUID).Return(0, nil)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userUUID, assignment.CourseID).Return(&schema.CourseEnroll{}, nil)
 	suite.assignmentRepo.On("GetByID", ctx, assignmentUUID).Return(assignment, nil)
 	suite.uploader.On("UploadFile", mock.Anything, mock.Anything).Return("http://example.com/testfile.pdf", nil)
 	suite.attachmentRepo.On("Create", ctx, mock.Anything).Return(nil)
@@ -565,20 +992,28 @@ func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_Success() {
 	userID := uuid.New()
 	submissionID := uuid.New()
 	submission := &schema.Submission{
-		ID:          submissionID,
-		UserID:      userID,
-		Content:     "Original Content",
-		Attachments: []schema.Attachment{{ID: uuid.New(), Description: "Original"}},
+		ID:           submissionID,
+		AssignmentID: uuid.New(),
+		UserID:       userID,
+		Attempt:      1,
+		Content:      "Original Content",
+		Attachments:  []schema.Attachment{{ID: uuid.New(), Description: "Original"}},
 	}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, MaxAttempts: 3}
 
 	newContent := "Updated Content"
 	newFileHeader := multipart.FileHeader{Filename: "new_attachment.pdf", Size: 1024}
 	newAttachmentID := uuid.New()
 	newAttachment := schema.Attachment{ID: newAttachmentID, Description: "Updated", URL: "http://example.com/testfile.pdf"}
 
+	var created *schema.Submission
 	suite.submissionRepo.On("GetByID", ctx, submissionID).Return(submission, nil)
-	suite.submissionRepo.On("Update", ctx, submission).Return(nil)
-	suite.attachmentRepo.On("Delete", ctx, mock.AnythingOfType("uuid.UUID")).Return(nil)
+	suite.assignmentRepo.On("GetByID", ctx, submission.AssignmentID).Return(assignment, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
 	suite.attachmentRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
 		arg := args.Get(1).(*schema.Attachment)
 		*arg = newAttachment // directly modify the argument to simulate the creation
@@ -593,13 +1028,67 @@ func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_Success() {
 	err := suite.submisionUseCase.UpdateSubmission(ctx, submissionID, req, userID.String())
 
 	assert.NoError(suite.T(), err)
-	assert.Equal(suite.T(), newContent, submission.Content)
-	assert.Len(suite.T(), submission.Attachments, 1)
-	assert.Equal(suite.T(), newAttachment, submission.Attachments[0]) // This comparison will now work as expected
-
+	assert.NotEqual(suite.T(), submissionID, created.ID)
+	assert.Equal(suite.T(), 2, created.Attempt)
+	assert.Equal(suite.T(), newContent, created.Content)
+	assert.Len(suite.T(), created.Attachments, 1)
+	assert.Equal(suite.T(), newAttachment, created.Attachments[0])
+
+	// the previous attempt is left as it was handed in
+	assert.Equal(suite.T(), "Original Content", submission.Content)
+	suite.attachmentRepo.AssertNotCalled(suite.T(), "Delete", mock.Anything, mock.Anything)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
 	suite.attachmentRepo.AssertExpectations(suite.T())
 	suite.submissionRepo.AssertExpectations(suite.T())
 }
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_CarriesOverAttachments() {
+	ctx := context.Background()
+	userID := uuid.New()
+	original := schema.Attachment{ID: uuid.New(), URL: "http://example.com/report.pdf", Description: "Report"}
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1,
+		Content: "Original Content", Attachments: []schema.Attachment{original}}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, MaxAttempts: 2}
+	content := "Fixed the typos"
+
+	var created *schema.Submission
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), content, created.Content)
+	assert.Len(suite.T(), created.Attachments, 1)
+	assert.NotEqual(suite.T(), original.ID, created.Attachments[0].ID)
+	assert.Equal(suite.T(), original.URL, created.Attachments[0].URL)
+	assert.Equal(suite.T(), original.Description, created.Attachments[0].Description)
+	suite.uploader.AssertNotCalled(suite.T(), "UploadFile", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_NoAttemptsLeft() {
+	ctx := context.Background()
+	userID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, MaxAttempts: 1}
+	content := "One more try"
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLatestStatus", ctx, userID, assignment.ID).Return(schema.SubmissionSubmitted, nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.Equal(suite.T(), ErrNoAttemptsLeft, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
 func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_Failure_NotOwner() {
 	ctx := context.Background()
 	userID := uuid.New()
@@ -626,6 +1115,670 @@ func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_Failure_NotOwner()
 	suite.submissionRepo.AssertExpectations(suite.T())
 }
 
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_HardDeadlinePassed() {
+	ctx := context.Background()
+	userID := uuid.New()
+	due := time.Now().Add(-time.Hour)
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, Due: &due, LatePolicy: schema.LatePolicyHard, MaxAttempts: 2}
+	content := "Too late"
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.assignmentRepo.On("GetExtension", ctx, assignment.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.Equal(suite.T(), ErrPastDue, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_ExtensionKeepsItOnTime() {
+	ctx := context.Background()
+	userID := uuid.New()
+	due := time.Now().Add(-time.Hour)
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, Due: &due, LatePolicy: schema.LatePolicyHard, MaxAttempts: 2}
+	extension := &schema.AssignmentExtension{AssignmentID: assignment.ID, UserID: userID, Due: time.Now().Add(24 * time.Hour)}
+	content := "Handed in within the extension"
+
+	var created *schema.Submission
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.assignmentRepo.On("GetExtension", ctx, assignment.ID, userID).Return(extension, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), created.Late)
+	assert.Equal(suite.T(), content, created.Content)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_SoftDeadlineFlagsLate() {
+	ctx := context.Background()
+	userID := uuid.New()
+	cohortStart := time.Now().AddDate(0, 0, -10).Add(time.Hour)
+	dueOffsetDays := 7
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New(), DueOffsetDays: &dueOffsetDays,
+		LatePolicy: schema.LatePolicySoft, MaxAttempts: 2}
+	content := "Late but accepted"
+
+	var created *schema.Submission
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.assignmentRepo.On("GetExtension", ctx, assignment.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.assignmentRepo.On("GetCohortStart", ctx, assignment.CourseID, userID).Return(&cohortStart, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), created.Late)
+	assert.Equal(suite.T(), 3, created.DaysLate)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_AppliesLatePenalty() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	due := time.Now().AddDate(0, 0, -3)
+	penaltyPercent := 10.0
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New(),
+		SubmittedAt: due.Add(36 * time.Hour)}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New(), Due: &due,
+		LatePolicy: schema.LatePolicyPenalty, LatePenaltyPercent: &penaltyPercent}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.assignmentRepo.On("GetExtension", ctx, assignment.ID, submission.UserID).Return(nil, gorm.ErrRecordNotFound)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+	suite.submissionRepo.On("SaveGrade", ctx, submission).Return(nil)
+	suite.userRepo.On("GetByID", mock.Anything).Return(&schema.User{}, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+
+	grade := 80.0
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, &GradeSubmissionRequest{Grade: &grade})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, submission.DaysLate)
+	assert.Equal(suite.T(), 16.0, submission.LatePenalty)
+	assert.Equal(suite.T(), 64.0, *submission.Grade)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_ByRubric() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+	content := schema.RubricCriterion{ID: uuid.New(), Title: "Content", Levels: []schema.RubricLevel{
+		{ID: uuid.New(), Title: "Missing", Points: 0},
+		{ID: uuid.New(), Title: "Good", Points: 6},
+		{ID: uuid.New(), Title: "Excellent", Points: 10},
+	}}
+	style := schema.RubricCriterion{ID: uuid.New(), Title: "Style", Levels: []schema.RubricLevel{
+		{ID: uuid.New(), Title: "Poor", Points: 1},
+		{ID: uuid.New(), Title: "Clear", Points: 5},
+	}}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return([]schema.RubricCriterion{content, style}, nil)
+	suite.submissionRepo.On("SaveGrade", ctx, submission).Return(nil)
+	suite.userRepo.On("GetByID", mock.Anything).Return(&schema.User{}, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+
+	req := &GradeSubmissionRequest{
+		Scores: []CriterionScoreRequest{
+			{CriterionID: content.ID, LevelID: content.Levels[1].ID, Comment: "Cover the edge cases too"},
+			{CriterionID: style.ID, LevelID: style.Levels[1].ID},
+		},
+		Feedback: "Solid work overall",
+	}
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, req)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 73.3, *submission.Grade)
+	assert.Equal(suite.T(), "Solid work overall", submission.Feedback)
+	assert.Len(suite.T(), submission.RubricScores, 2)
+	assert.Equal(suite.T(), "Good", submission.RubricScores[0].Level)
+	assert.Equal(suite.T(), 10.0, submission.RubricScores[0].MaxPoints)
+	assert.Equal(suite.T(), "Cover the edge cases too", submission.RubricScores[0].Comment)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_RubricIncomplete() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+	criterion := schema.RubricCriterion{ID: uuid.New(), Title: "Content", Levels: []schema.RubricLevel{{ID: uuid.New(), Points: 10}}}
+	grade := 90.0
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return([]schema.RubricCriterion{criterion}, nil)
+
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, &GradeSubmissionRequest{Grade: &grade})
+
+	assert.Equal(suite.T(), ErrRubricIncomplete, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "SaveGrade", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_ScoresWithoutRubric() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+
+	req := &GradeSubmissionRequest{Scores: []CriterionScoreRequest{{CriterionID: uuid.New(), LevelID: uuid.New()}}}
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, req)
+
+	assert.Equal(suite.T(), ErrNoRubric, err)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_ReturnedGetsOneMoreAttempt() {
+	ctx := context.Background()
+	userID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, Attempt: 1,
+		Status: schema.SubmissionReturned}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, MaxAttempts: 1}
+	content := "Fixed the introduction"
+
+	var created *schema.Submission
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("CountAttempts", ctx, userID, assignment.ID).Return(int64(1), nil)
+	suite.submissionRepo.On("GetLatestStatus", ctx, userID, assignment.ID).Return(schema.SubmissionReturned, nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, userID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, submission.ID, &UpdateSubmissionRequest{Content: &content}, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, created.Attempt)
+	assert.Equal(suite.T(), schema.SubmissionSubmitted, created.Status)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_HoldsGrade() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New(), HoldGrades: true}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+	suite.submissionRepo.On("SaveGrade", ctx, submission).Return(nil)
+
+	grade := 70.0
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, &GradeSubmissionRequest{Grade: &grade})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), schema.SubmissionGraded, submission.Status)
+	assert.Equal(suite.T(), 70.0, *submission.Grade)
+	assert.Nil(suite.T(), submission.ReleasedAt)
+	suite.notificationRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mailer.AssertNotCalled(suite.T(), "DialAndSend", mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestReleaseGrades_Success() {
+	ctx := context.Background()
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+	instructorID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), HoldGrades: true}
+	studentID := uuid.New()
+	first, second := 60.0, 75.0
+	released := []schema.Submission{
+		{ID: uuid.New(), AssignmentID: assignment.ID, UserID: studentID, Attempt: 1, Grade: &first},
+		{ID: uuid.New(), AssignmentID: assignment.ID, UserID: studentID, Attempt: 2, Grade: &second},
+		{ID: uuid.New(), AssignmentID: assignment.ID, UserID: uuid.New(), Attempt: 1, Grade: &first},
+	}
+
+	notified := make(chan *schema.Notification, 3)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("ReleaseGrades", ctx, assignment.ID).Return(released, nil)
+	suite.submissionRepo.On("CountUngraded", ctx, assignment.ID).Return(int64(4), nil)
+	suite.userRepo.On("GetByID", mock.Anything).Return(&schema.User{}, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(*schema.Notification)
+	}).Return(nil)
+
+	res, err := suite.submisionUseCase.ReleaseGrades(ctx, assignment.ID, instructorID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, res.Released)
+	assert.Equal(suite.T(), int64(4), res.Ungraded)
+
+	// each student hears about their latest attempt once
+	details := []string{(<-notified).Detail, (<-notified).Detail}
+	assert.Contains(suite.T(), details, "Your submission for  in course  has been graded: 75.0")
+	assert.Contains(suite.T(), details, "Your submission for  in course  has been graded: 60.0")
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestReleaseGrades_NotInstructor() {
+	ctx := context.Background()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.submisionUseCase.ReleaseGrades(ctx, assignment.ID, uuid.New().String())
+
+	assert.Equal(suite.T(), ErrNotOwnerCourse, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "ReleaseGrades", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateStatus_Returned() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New(),
+		Status: schema.SubmissionInReview}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+	feedback := "The second section is missing"
+
+	notified := make(chan *schema.Notification, 1)
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("Update", ctx, submission).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(*schema.Notification)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateStatus(ctx, instructorID.String(), submission.ID,
+		&UpdateStatusRequest{Status: schema.SubmissionReturned, Feedback: &feedback})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), schema.SubmissionReturned, submission.Status)
+	assert.Equal(suite.T(), feedback, submission.Feedback)
+	notif := <-notified
+	assert.Equal(suite.T(), submission.UserID, notif.UserID)
+	assert.Equal(suite.T(), "Submission Returned", notif.Title)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateStatus_InvalidChange() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New(),
+		Status: schema.SubmissionGraded}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+
+	err := suite.submisionUseCase.UpdateStatus(ctx, instructorID.String(), submission.ID,
+		&UpdateStatusRequest{Status: schema.SubmissionInReview})
+
+	assert.Equal(suite.T(), ErrInvalidStatusChange, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestBulkGrade_Success() {
+	ctx := context.Background()
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+	instructorID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+	studentID := uuid.New()
+	first := schema.Submission{ID: uuid.New(), AssignmentID: assignment.ID, UserID: studentID, Attempt: 1}
+	second := schema.Submission{ID: uuid.New(), AssignmentID: assignment.ID, UserID: studentID, Attempt: 2}
+	other := schema.Submission{ID: uuid.New(), AssignmentID: assignment.ID, UserID: uuid.New(), Attempt: 1}
+	ids := []uuid.UUID{first.ID, second.ID, other.ID}
+
+	var saved []*schema.Submission
+	notified := make(chan *schema.Notification, 3)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("GetByIDs", ctx, ids).Return([]schema.Submission{other, first, second}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+	suite.submissionRepo.On("SaveGrades", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		saved = args.Get(1).([]*schema.Submission)
+	}).Return(nil)
+	suite.userRepo.On("GetByID", mock.Anything).Return(&schema.User{}, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(*schema.Notification)
+	}).Return(nil)
+
+	g1, g2, g3 := 50.0, 80.0, 65.0
+	res, err := suite.submisionUseCase.BulkGrade(ctx, assignment.ID, instructorID.String(), &BulkGradeRequest{Grades: []BulkGradeItem{
+		{SubmissionID: first.ID, GradeSubmissionRequest: GradeSubmissionRequest{Grade: &g1}},
+		{SubmissionID: second.ID, GradeSubmissionRequest: GradeSubmissionRequest{Grade: &g2, Feedback: "Much better"}},
+		{SubmissionID: other.ID, GradeSubmissionRequest: GradeSubmissionRequest{Grade: &g3}},
+	}})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), &BulkGradeResponse{Graded: 3}, res)
+	assert.Len(suite.T(), saved, 3)
+	assert.Equal(suite.T(), second.ID, saved[1].ID)
+	assert.Equal(suite.T(), 80.0, *saved[1].Grade)
+	assert.Equal(suite.T(), "Much better", saved[1].Feedback)
+	assert.Equal(suite.T(), schema.SubmissionGraded, saved[2].Status)
+
+	// one notification per student, about their latest attempt
+	details := []string{(<-notified).Detail, (<-notified).Detail}
+	assert.Contains(suite.T(), details, "Your submission for  in course  has been graded: 80.0\n\nMuch better")
+	assert.Contains(suite.T(), details, "Your submission for  in course  has been graded: 65.0")
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestBulkGrade_OtherAssignment() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+	foreign := schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("GetByIDs", ctx, []uuid.UUID{foreign.ID}).Return([]schema.Submission{foreign}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+
+	grade := 90.0
+	_, err := suite.submisionUseCase.BulkGrade(ctx, assignment.ID, instructorID.String(), &BulkGradeRequest{Grades: []BulkGradeItem{
+		{SubmissionID: foreign.ID, GradeSubmissionRequest: GradeSubmissionRequest{Grade: &grade}},
+	}})
+
+	assert.Equal(suite.T(), ErrInvalidBulkGrade, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "SaveGrades", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestDownloadSubmissions_Archive() {
+	ctx := context.Background()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.pdf" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("file " + r.URL.Path))
+	}))
+	defer server.Close()
+
+	instructorID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New()}
+	ann := schema.User{ID: uuid.New(), Name: "Ann Lee", Email: "ann@example.com"}
+	attID := uuid.New()
+	submissions := []schema.Submission{
+		{ID: uuid.New(), AssignmentID: assignment.ID, UserID: ann.ID, Attempt: 1, Content: "My essay",
+			Attachments: []schema.Attachment{{ID: attID, URL: server.URL + "/attachments%2Fsubmission%2F" + attID.String() + ".essay.pdf"}}},
+		{ID: uuid.New(), AssignmentID: assignment.ID, UserID: ann.ID, Attempt: 2,
+			Attachments: []schema.Attachment{{ID: uuid.New(), URL: server.URL + "/missing.pdf"}}},
+	}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.submissionRepo.On("GetAllByAssignment", ctx, assignment.ID, schema.SubmissionStatus("")).Return(submissions, nil)
+	suite.submissionRepo.On("GetSubmitters", ctx, assignment.ID).Return([]schema.User{ann}, nil)
+	for _, sub := range submissions {
+		url := sub.Attachments[0].URL
+		suite.uploader.On("PresignURL", url, mock.Anything).Return(url, nil)
+	}
+
+	archive, err := suite.submisionUseCase.DownloadSubmissions(ctx, assignment.ID, instructorID.String())
+	assert.NoError(suite.T(), err)
+
+	var buf bytes.Buffer
+	assert.NoError(suite.T(), archive.Write(ctx, &buf))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	assert.NoError(suite.T(), err)
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		r, _ := f.Open()
+		content, _ := io.ReadAll(r)
+		r.Close()
+		files[f.Name] = string(content)
+	}
+
+	assert.Equal(suite.T(), "My essay", files["Ann Lee (ann@example.com)/attempt-1/content.txt"])
+	assert.Contains(suite.T(), files, "Ann Lee (ann@example.com)/attempt-1/essay.pdf")
+	assert.Contains(suite.T(), files["errors.txt"], "Ann Lee (ann@example.com)/attempt-2/missing.pdf")
+	assert.Len(suite.T(), files, 3)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestCreateSubmission_GroupWorkNeedsTeam() {
+	ctx := context.Background()
+	userID := uuid.New()
+	teamSetID := uuid.New()
+	assignment := &schema.Assignment{ID: uuid.New(), CourseID: uuid.New(), MaxAttempts: 1, TeamSetID: &teamSetID}
+
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, assignment.CourseID).Return(&schema.CourseEnroll{}, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, userID).Return(nil, gorm.ErrRecordNotFound)
+
+	err := suite.submisionUseCase.CreateSubmission(ctx, &CreateSubmissionRequest{AssignmentID: assignment.ID.String()}, userID.String())
+
+	assert.Equal(suite.T(), ErrNotInTeam, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_TeamMemberHandsInNextAttempt() {
+	ctx := context.Background()
+	authorID, editorID, newMemberID := uuid.New(), uuid.New(), uuid.New()
+	teamSetID := uuid.New()
+	team := &schema.Team{ID: uuid.New(), TeamSetID: teamSetID, Members: []schema.TeamMember{
+		{UserID: authorID}, {UserID: editorID}, {UserID: newMemberID},
+	}}
+	previous := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: authorID, TeamID: &team.ID,
+		Attempt: 1, Content: "Draft", Status: schema.SubmissionSubmitted,
+		Members: []schema.SubmissionMember{{UserID: authorID}, {UserID: editorID}}}
+	assignment := &schema.Assignment{ID: previous.AssignmentID, MaxAttempts: 2, TeamSetID: &teamSetID}
+	content := "Final version"
+
+	var created *schema.Submission
+	suite.submissionRepo.On("GetByID", ctx, previous.ID).Return(previous, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, editorID).Return(team, nil)
+	suite.submissionRepo.On("GetTeamAttempts", ctx, team.ID, assignment.ID).Return([]schema.Submission{*previous}, nil)
+	suite.submissionRepo.On("GetTeamLastAttempt", ctx, team.ID, assignment.ID).Return(1, nil)
+	suite.submissionRepo.On("GetLastAttempt", ctx, editorID, assignment.ID).Return(0, nil)
+	suite.submissionRepo.On("Create", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		created = args.Get(1).(*schema.Submission)
+	}).Return(nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, previous.ID, &UpdateSubmissionRequest{Content: &content}, editorID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, created.Attempt)
+	assert.Equal(suite.T(), editorID, created.UserID)
+	assert.Equal(suite.T(), team.ID, *created.TeamID)
+	// the attempt counts for the team as it is now
+	assert.ElementsMatch(suite.T(), []uuid.UUID{authorID, editorID, newMemberID}, recipients(created))
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_TeamAttemptsUsedUp() {
+	ctx := context.Background()
+	userID := uuid.New()
+	teamSetID := uuid.New()
+	team := &schema.Team{ID: uuid.New(), TeamSetID: teamSetID, Members: []schema.TeamMember{{UserID: userID}}}
+	previous := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New(), TeamID: &team.ID,
+		Attempt: 1, Status: schema.SubmissionGraded}
+	assignment := &schema.Assignment{ID: previous.AssignmentID, MaxAttempts: 1, TeamSetID: &teamSetID}
+
+	suite.submissionRepo.On("GetByID", ctx, previous.ID).Return(previous, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, userID).Return(team, nil)
+	suite.submissionRepo.On("GetTeamAttempts", ctx, team.ID, assignment.ID).Return([]schema.Submission{*previous}, nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, previous.ID, &UpdateSubmissionRequest{}, userID.String())
+
+	assert.Equal(suite.T(), ErrNoAttemptsLeft, err)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestUpdateSubmission_FormerTeamMember() {
+	ctx := context.Background()
+	userID := uuid.New()
+	teamSetID := uuid.New()
+	oldTeamID := uuid.New()
+	previous := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: userID, TeamID: &oldTeamID, Attempt: 1}
+	assignment := &schema.Assignment{ID: previous.AssignmentID, MaxAttempts: 3, TeamSetID: &teamSetID}
+
+	suite.submissionRepo.On("GetByID", ctx, previous.ID).Return(previous, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, userID).Return(&schema.Team{ID: uuid.New()}, nil)
+
+	err := suite.submisionUseCase.UpdateSubmission(ctx, previous.ID, &UpdateSubmissionRequest{}, userID.String())
+
+	assert.Equal(suite.T(), ErrNotOwnerSubmission, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "Create", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestDeleteSubmission_TeamMember() {
+	ctx := context.Background()
+	userID := uuid.New()
+	teamSetID := uuid.New()
+	team := &schema.Team{ID: uuid.New(), TeamSetID: teamSetID}
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New(), TeamID: &team.ID}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, TeamSetID: &teamSetID}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, userID).Return(team, nil)
+	suite.submissionRepo.On("Delete", ctx, submission.ID).Return(nil)
+
+	err := suite.submisionUseCase.DeleteSubmission(ctx, submission.ID, userID.String())
+
+	assert.NoError(suite.T(), err)
+	suite.submissionRepo.AssertExpectations(suite.T())
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_AdjustsTeamMembers() {
+	ctx := context.Background()
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+	instructorID := uuid.New()
+	leadID, quietID := uuid.New(), uuid.New()
+	teamID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: leadID, TeamID: &teamID,
+		Members: []schema.SubmissionMember{{UserID: leadID}, {UserID: quietID}}}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+
+	notified := make(chan *schema.Notification, 2)
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+	suite.submissionRepo.On("SaveGrade", ctx, submission).Return(nil)
+	suite.userRepo.On("GetByID", mock.Anything).Return(&schema.User{}, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Run(func(args mock.Arguments) {
+		notified <- args.Get(0).(*schema.Notification)
+	}).Return(nil)
+
+	grade := 80.0
+	req := &GradeSubmissionRequest{Grade: &grade, Adjustments: []AdjustmentRequest{
+		{UserID: quietID, Points: -15, Reason: "Missed most meetings"},
+	}}
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, req)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 80.0, *submission.Grade)
+	assert.Equal(suite.T(), 0.0, submission.Members[0].Adjustment)
+	assert.Equal(suite.T(), -15.0, submission.Members[1].Adjustment)
+	assert.Equal(suite.T(), "Missed most meetings", submission.Members[1].AdjustmentReason)
+
+	// every member hears about their own grade
+	byUser := make(map[uuid.UUID]string)
+	for i := 0; i < 2; i++ {
+		n := <-notified
+		byUser[n.UserID] = n.Detail
+	}
+	assert.Equal(suite.T(), "Your submission for  in course  has been graded: 80.0", byUser[leadID])
+	assert.Equal(suite.T(), "Your submission for  in course  has been graded: 65.0", byUser[quietID])
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGradeSubmission_AdjustsNonMember() {
+	ctx := context.Background()
+	instructorID := uuid.New()
+	submission := &schema.Submission{ID: uuid.New(), AssignmentID: uuid.New(), UserID: uuid.New()}
+	assignment := &schema.Assignment{ID: submission.AssignmentID, CourseID: uuid.New()}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.courseRepo.On("GetByID", ctx, assignment.CourseID).Return(schema.Course{ID: assignment.CourseID, InstructorID: instructorID}, nil)
+	suite.assignmentRepo.On("GetRubric", ctx, assignment.ID).Return(nil, nil)
+
+	grade := 80.0
+	req := &GradeSubmissionRequest{Grade: &grade, Adjustments: []AdjustmentRequest{{UserID: uuid.New(), Points: 5}}}
+	err := suite.submisionUseCase.GradeSubmission(ctx, instructorID.String(), submission.ID, req)
+
+	assert.Equal(suite.T(), ErrInvalidAdjustment, err)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "SaveGrade", mock.Anything, mock.Anything)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGetSubmissionByID_TeamMemberSeesOwnAdjustment() {
+	ctx := context.Background()
+	viewerID, otherID := uuid.New(), uuid.New()
+	teamID := uuid.New()
+	grade := 70.0
+	releasedAt := time.Now()
+	submission := &schema.Submission{ID: uuid.New(), UserID: otherID, TeamID: &teamID, Status: schema.SubmissionGraded,
+		Grade: &grade, ReleasedAt: &releasedAt, Members: []schema.SubmissionMember{
+			{UserID: viewerID, Adjustment: 5, AdjustmentReason: "Led the project"},
+			{UserID: otherID, Adjustment: -10, AdjustmentReason: "Late on their part"},
+		}}
+
+	suite.submissionRepo.On("GetByID", ctx, submission.ID).Return(submission, nil)
+
+	result, err := suite.submisionUseCase.GetSubmissionByID(ctx, submission.ID, viewerID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 5.0, result.Members[0].Adjustment)
+	assert.Equal(suite.T(), 0.0, result.Members[1].Adjustment)
+	assert.Empty(suite.T(), result.Members[1].AdjustmentReason)
+}
+
+func (suite *SubmissionUseCaseTestSuite) TestGetMyAttempts_Team() {
+	ctx := context.Background()
+	userID := uuid.New()
+	teamSetID := uuid.New()
+	team := &schema.Team{ID: uuid.New(), TeamSetID: teamSetID, Members: []schema.TeamMember{{UserID: userID}}}
+	assignment := &schema.Assignment{ID: uuid.New(), MaxAttempts: 2, TeamSetID: &teamSetID}
+	attempts := []schema.Submission{{ID: uuid.New(), UserID: uuid.New(), TeamID: &team.ID, Attempt: 1,
+		Status: schema.SubmissionSubmitted}}
+
+	suite.assignmentRepo.On("GetByID", ctx, assignment.ID).Return(assignment, nil)
+	suite.submissionRepo.On("GetTeam", ctx, teamSetID, userID).Return(team, nil)
+	suite.submissionRepo.On("GetTeamAttempts", ctx, team.ID, assignment.ID).Return(attempts, nil)
+	suite.submissionRepo.On("GetFinalGrade", ctx, userID, assignment.ID).Return(nil, nil)
+
+	res, err := suite.submisionUseCase.GetMyAttempts(ctx, assignment.ID, userID.String())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), team, res.Team)
+	assert.Len(suite.T(), res.Attempts, 1)
+	assert.Equal(suite.T(), 1, res.AttemptsLeft)
+	suite.submissionRepo.AssertNotCalled(suite.T(), "GetAttempts", mock.Anything, mock.Anything, mock.Anything)
+}
+
 func TestSubmissionUseCaseTestSuite(t *testing.T) {
 	suite.Run(t, new(SubmissionUseCaseTestSuite))
 }
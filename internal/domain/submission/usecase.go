@@ -3,20 +3,29 @@ package submission
 import (
 	"context"
 	_ "embed"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
+	"slices"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/config"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/assignment"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/attachment"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/certificate"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/similarity"
 	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
 	"github.com/Stefanuswilfrid/course-backend/internal/mailer"
 	"github.com/Stefanuswilfrid/course-backend/internal/schema"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
 )
 
 type UseCase struct {
@@ -24,17 +33,19 @@ type UseCase struct {
 	assignmentRepo    assignment.Repository
 	courseRepo        course.Repository
 	attachmentUseCase attachment.UseCase
-	courseEnrollRepo  courseenroll.Repository
+	courseEnrollUc    *courseenroll.UseCase
 	userRepo          user.IRepository
 	notifRepo         notification.IRepository
 	mailDialer        config.IMailer
+	CertificateUc     *certificate.UseCase
+	SimilarityUc      *similarity.UseCase
 }
 
 // NewUseCase creates a new instance of the submission use case.
 func NewUseCase(repo Repository, aRepo assignment.Repository, auc attachment.UseCase, courseRepo course.Repository,
-	ceRepo courseenroll.Repository, userRepo user.IRepository, notifRepo notification.IRepository, mailDialer config.IMailer) *UseCase {
+	courseEnrollUc *courseenroll.UseCase, userRepo user.IRepository, notifRepo notification.IRepository, mailDialer config.IMailer) *UseCase {
 	return &UseCase{repo: repo, assignmentRepo: aRepo, attachmentUseCase: auc, courseRepo: courseRepo,
-		courseEnrollRepo: ceRepo, userRepo: userRepo, notifRepo: notifRepo, mailDialer: mailDialer}
+		courseEnrollUc: courseEnrollUc, userRepo: userRepo, notifRepo: notifRepo, mailDialer: mailDialer}
 }
 
 //go:embed new_submission_instructor_email_template.html
@@ -53,11 +64,6 @@ func (uc *UseCase) CreateSubmission(ctx context.Context, req *CreateSubmissionRe
 		return apierror.ErrInternalServer.Build()
 	}
 
-	err = uc.CheckSubmissionExists(ctx, userUUID, assignmentUUID)
-	if err != nil {
-		return err
-	}
-
 	err = uc.VerifyCourseEnroll(ctx, userUUID, assignmentUUID)
 	if err != nil {
 		return err
@@ -75,12 +81,34 @@ func (uc *UseCase) CreateSubmission(ctx context.Context, req *CreateSubmissionRe
 		return ErrAssignmentNotFound
 	}
 
+	team, err := uc.teamOf(ctx, assignmentObj, userUUID)
+	if err != nil {
+		return err
+	}
+
+	attempt, err := uc.nextAttempt(ctx, userUUID, team, assignmentObj)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	daysLate, err := uc.lateness(ctx, assignmentObj, userUUID, now)
+	if err != nil {
+		return err
+	}
+
 	submission := &schema.Submission{
 		ID:           id,
 		AssignmentID: assignmentObj.ID,
 		UserID:       userUUID,
+		Attempt:      attempt,
+		Status:       schema.SubmissionSubmitted,
 		Content:      req.Content,
+		SubmittedAt:  now,
+		Late:         daysLate > 0,
+		DaysLate:     daysLate,
 	}
+	handInForTeam(submission, team)
 
 	for _, fileHeader := range req.Attachments {
 		attachmentObj, err := uc.attachmentUseCase.CreateSubmissionAttachment(ctx, fileHeader, "")
@@ -91,9 +119,10 @@ func (uc *UseCase) CreateSubmission(ctx context.Context, req *CreateSubmissionRe
 		submission.Attachments = append(submission.Attachments, attachmentObj)
 	}
 
-	if err := uc.repo.Create(ctx, submission); err != nil {
+	if err := uc.createAttempt(ctx, submission); err != nil {
 		return err
 	}
+	uc.checkSimilarity(submission)
 
 	go func() {
 		studentName := ctx.Value("user.name").(string)
@@ -152,6 +181,8 @@ func (uc *UseCase) CreateSubmission(ctx context.Context, req *CreateSubmissionRe
 				return
 			}
 		}()
+
+		uc.issueCertificate(context.Background(), userUUID, courseObj.ID)
 	}()
 
 	return nil
@@ -160,7 +191,9 @@ func (uc *UseCase) CreateSubmission(ctx context.Context, req *CreateSubmissionRe
 //go:embed submission_graded_student_email_template.html
 var submissionGradedStudentEmailTemplate string
 
-func (uc *UseCase) GradeSubmission(ctx context.Context, userId string, id uuid.UUID, grade float64) error {
+// GradeSubmission grades the submission by rubric or with a plain grade, then takes off the late penalty.
+// The grade and feedback go out to the student right away unless the assignment holds its grades back
+func (uc *UseCase) GradeSubmission(ctx context.Context, userId string, id uuid.UUID, req *GradeSubmissionRequest) error {
 	submission, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
@@ -171,100 +204,513 @@ func (uc *UseCase) GradeSubmission(ctx context.Context, userId string, id uuid.U
 		return err
 	}
 
-	courseId := assignmentObj.CourseID
-	// Get the course to verify the instructor ID
-	courseObj, err := uc.courseRepo.GetByID(ctx, courseId)
+	courseObj, err := uc.getInstructedCourse(ctx, assignmentObj, userId)
 	if err != nil {
 		return err
 	}
 
-	// Check if the current user is the instructor of the course
-	if courseObj.InstructorID.String() != userId {
-		return ErrNotOwnerCourse
+	rubric, err := uc.assignmentRepo.GetRubric(ctx, assignmentObj.ID)
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
 	}
-
-	submission.Grade = grade
-	if err := uc.repo.Update(ctx, submission); err != nil {
+	if err := uc.applyGrade(ctx, assignmentObj, rubric, submission, req, time.Now()); err != nil {
+		return err
+	}
+	if err := uc.repo.SaveGrade(ctx, submission); err != nil {
 		log.Println("Error updating submission: ", err)
 		return err
 	}
 
-	// Send email to student
-	go func() {
-		student, err := uc.userRepo.GetByID(submission.UserID)
-		if err != nil {
-			log.Println("Error getting student: ", err)
-			return
+	if submission.ReleasedAt != nil {
+		uc.notifyGraded(ctx, courseObj, assignmentObj, submission)
+		for _, userID := range recipients(submission) {
+			go uc.issueCertificate(context.Background(), userID, courseObj.ID)
 		}
+	}
+
+	return nil
+}
+
+// BulkGrade grades many submissions to the assignment in one go, either all of them are saved or none.
+// Each student or team hears once, about their latest attempt that was graded
+func (uc *UseCase) BulkGrade(ctx context.Context, assignmentID uuid.UUID, userId string, req *BulkGradeRequest) (*BulkGradeResponse, error) {
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, ErrAssignmentNotFound
+	}
+
+	courseObj, err := uc.getInstructedCourse(ctx, assignmentObj, userId)
+	if err != nil {
+		return nil, err
+	}
 
-		emailData := map[string]any{
-			"student_name":     student.Name,
-			"course_title":     courseObj.Title,
-			"assignment_title": assignmentObj.Title,
-			"grade":            grade,
+	ids := make([]uuid.UUID, 0, len(req.Grades))
+	seen := make(map[uuid.UUID]bool, len(req.Grades))
+	for _, item := range req.Grades {
+		if seen[item.SubmissionID] {
+			return nil, ErrInvalidBulkGrade
 		}
+		seen[item.SubmissionID] = true
+		ids = append(ids, item.SubmissionID)
+	}
 
-		mail, err := mailer.GenerateMail(student.Email, "Assignment Graded",
-			submissionGradedStudentEmailTemplate, emailData)
-		if err != nil {
-			log.Println("Error generating email: ", err)
-			return
+	found, err := uc.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		log.Println("Error getting submissions: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	byID := make(map[uuid.UUID]*schema.Submission, len(found))
+	for i := range found {
+		if found[i].AssignmentID == assignmentID {
+			byID[found[i].ID] = &found[i]
 		}
+	}
 
-		if err = uc.mailDialer.DialAndSend(mail); err != nil {
-			log.Println("Error sending email: ", err)
-			return
+	rubric, err := uc.assignmentRepo.GetRubric(ctx, assignmentID)
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	submissions := make([]*schema.Submission, 0, len(req.Grades))
+	for i := range req.Grades {
+		submission, ok := byID[req.Grades[i].SubmissionID]
+		if !ok {
+			return nil, ErrInvalidBulkGrade
 		}
-	}()
+		if err := uc.applyGrade(ctx, assignmentObj, rubric, submission, &req.Grades[i].GradeSubmissionRequest, now); err != nil {
+			return nil, err
+		}
+		submissions = append(submissions, submission)
+	}
 
-	// Send in-app notification to student
-	go func() {
-		notifID, err := uuid.NewV7()
-		if err != nil {
-			return
+	if err := uc.repo.SaveGrades(ctx, submissions); err != nil {
+		log.Println("Error saving grades: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	res := &BulkGradeResponse{Graded: len(submissions)}
+	latest := make(map[uuid.UUID]*schema.Submission, len(submissions))
+	for _, s := range submissions {
+		if s.ReleasedAt == nil {
+			res.Held++
+			continue
+		}
+		if l, ok := latest[authorOf(s)]; !ok || s.Attempt > l.Attempt {
+			latest[authorOf(s)] = s
+		}
+	}
+	for _, s := range latest {
+		uc.notifyGraded(ctx, courseObj, assignmentObj, s)
+		for _, userID := range recipients(s) {
+			go uc.issueCertificate(context.Background(), userID, courseObj.ID)
 		}
+	}
+
+	return res, nil
+}
+
+// ApplyPeerGrades grades the submissions of the assignment with the grade their peers gave them. Submissions
+// the instructor graded or returned already are left alone, the instructor overrides the peers. It returns how many
+// submissions were graded
+func (uc *UseCase) ApplyPeerGrades(ctx context.Context, assignmentObj *schema.Assignment, grades map[uuid.UUID]float64) (int, error) {
+	if len(grades) == 0 {
+		return 0, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(grades))
+	for id := range grades {
+		ids = append(ids, id)
+	}
+	found, err := uc.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return 0, err
+	}
+
+	courseObj, err := uc.courseRepo.GetByID(ctx, assignmentObj.CourseID)
+	if err != nil {
+		return 0, err
+	}
 
-		notif := schema.Notification{
-			ID:     notifID,
-			UserID: submission.UserID,
-			Title:  "Assignment Graded",
-			Detail: fmt.Sprintf("Your submission for %s in course %s has been graded", assignmentObj.Title, courseObj.Title),
+	now := time.Now()
+	submissions := make([]*schema.Submission, 0, len(found))
+	for i := range found {
+		if found[i].AssignmentID != assignmentObj.ID || found[i].Status == schema.SubmissionGraded ||
+			found[i].Status == schema.SubmissionReturned {
+			continue
+		}
+		grade := grades[found[i].ID]
+		if err := uc.applyGrade(ctx, assignmentObj, nil, &found[i], &GradeSubmissionRequest{Grade: &grade}, now); err != nil {
+			return 0, err
 		}
+		submissions = append(submissions, &found[i])
+	}
+	if len(submissions) == 0 {
+		return 0, nil
+	}
 
-		if err := uc.notifRepo.Create(&notif); err != nil {
-			log.Println("Error creating notification: ", err)
-			return
+	if err := uc.repo.SaveGrades(ctx, submissions); err != nil {
+		return 0, err
+	}
+	for _, s := range submissions {
+		if s.ReleasedAt != nil {
+			uc.notifyGraded(ctx, &courseObj, assignmentObj, s)
+			for _, userID := range recipients(s) {
+				go uc.issueCertificate(context.Background(), userID, courseObj.ID)
+			}
 		}
-	}()
+	}
+	return len(submissions), nil
+}
+
+// applyGrade grades the submission by rubric or with the plain grade of the request and takes off the late
+// penalty. It is released to the student at `now` unless the assignment holds its grades back, a grade that
+// was released already stays visible when it is corrected
+func (uc *UseCase) applyGrade(ctx context.Context, assignmentObj *schema.Assignment, rubric []schema.RubricCriterion,
+	submission *schema.Submission, req *GradeSubmissionRequest, now time.Time) error {
+	grade, scores, err := gradeByRubric(rubric, req, submission.ID)
+	if err != nil {
+		return err
+	}
+	if err := adjustMembers(submission, req.Adjustments); err != nil {
+		return err
+	}
+
+	// The deadline is looked up again so an extension granted after the student handed in still counts
+	due, err := assignment.DueFor(ctx, uc.assignmentRepo, assignmentObj, submission.UserID)
+	if err != nil {
+		return err
+	}
+	submission.DaysLate = assignment.DaysLate(due, submission.SubmittedAt)
+	submission.Late = submission.DaysLate > 0
+	submission.LatePenalty = assignment.LatePenalty(assignmentObj, grade, submission.DaysLate)
+	finalGrade := grade - submission.LatePenalty
+	submission.Grade = &finalGrade
+	submission.Status = schema.SubmissionGraded
+	submission.GradedAt = &now
+	submission.Feedback = req.Feedback
+	submission.RubricScores = scores
 
+	if !assignmentObj.HoldGrades && submission.ReleasedAt == nil {
+		submission.ReleasedAt = &now
+	}
 	return nil
 }
 
-// UpdateSubmission handles the business logic for updating an existing submission.
-func (uc *UseCase) UpdateSubmission(ctx context.Context, id uuid.UUID, req *UpdateSubmissionRequest, userId string) error {
+// UpdateStatus marks a submission as in review or returns it to the student for resubmission, which
+// lets them hand in once more even when they used all their attempts
+func (uc *UseCase) UpdateStatus(ctx context.Context, userId string, id uuid.UUID, req *UpdateStatusRequest) error {
 	submission, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if submission.UserID.String() != userId {
-		return ErrNotOwnerSubmission
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, submission.AssignmentID)
+	if err != nil {
+		return ErrAssignmentNotFound
 	}
 
-	if req.Content != nil {
-		submission.Content = *req.Content
+	courseObj, err := uc.getInstructedCourse(ctx, assignmentObj, userId)
+	if err != nil {
+		return err
 	}
 
-	if req.Attachments != nil {
-		for _, att := range submission.Attachments {
-			err := uc.attachmentUseCase.DeleteAttachment(ctx, att.ID)
+	switch {
+	case req.Status == schema.SubmissionInReview && submission.Status == schema.SubmissionSubmitted:
+	case req.Status == schema.SubmissionReturned && submission.Status != schema.SubmissionReturned:
+	default:
+		return ErrInvalidStatusChange
+	}
+
+	submission.Status = req.Status
+	if req.Feedback != nil {
+		submission.Feedback = *req.Feedback
+	}
+	if err := uc.repo.Update(ctx, submission); err != nil {
+		log.Println("Error updating submission: ", err)
+		return err
+	}
+
+	if submission.Status != schema.SubmissionReturned {
+		return nil
+	}
+
+	for _, userID := range recipients(submission) {
+		go func(userID uuid.UUID) {
+			notifID, err := uuid.NewV7()
 			if err != nil {
-				return err
+				return
+			}
+
+			detail := fmt.Sprintf("Your submission for %s in course %s was returned for resubmission", assignmentObj.Title, courseObj.Title)
+			if submission.Feedback != "" {
+				detail += "\n\n" + submission.Feedback
+			}
+
+			notif := schema.Notification{
+				ID:     notifID,
+				UserID: userID,
+				Title:  "Submission Returned",
+				Detail: detail,
+			}
+
+			if err := uc.notifRepo.Create(&notif); err != nil {
+				log.Println("Error creating notification: ", err)
+				return
 			}
+		}(userID)
+	}
+
+	return nil
+}
+
+// ReleaseGrades shows the held back grades of the assignment to the students at once and tells each of them,
+// the response also counts the submissions that still wait for a grade
+func (uc *UseCase) ReleaseGrades(ctx context.Context, assignmentID uuid.UUID, userId string) (*ReleaseResponse, error) {
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, ErrAssignmentNotFound
+	}
+
+	courseObj, err := uc.getInstructedCourse(ctx, assignmentObj, userId)
+	if err != nil {
+		return nil, err
+	}
+
+	released, err := uc.repo.ReleaseGrades(ctx, assignmentID)
+	if err != nil {
+		log.Println("Error releasing grades: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	ungraded, err := uc.repo.CountUngraded(ctx, assignmentID)
+	if err != nil {
+		log.Println("Error counting ungraded submissions: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	// students and teams hear about their latest released attempt only
+	latest := make(map[uuid.UUID]*schema.Submission, len(released))
+	var teamSubmissions []uuid.UUID
+	for i := range released {
+		if s, ok := latest[authorOf(&released[i])]; !ok || released[i].Attempt > s.Attempt {
+			latest[authorOf(&released[i])] = &released[i]
+		}
+	}
+	for _, s := range latest {
+		if s.TeamID != nil {
+			teamSubmissions = append(teamSubmissions, s.ID)
+		}
+	}
+	// the released rows come without the members of team submissions, who all need to hear
+	if len(teamSubmissions) > 0 {
+		withMembers, err := uc.repo.GetByIDs(ctx, teamSubmissions)
+		if err != nil {
+			log.Println("Error getting team submissions: ", err)
+		}
+		for i := range withMembers {
+			latest[authorOf(&withMembers[i])] = &withMembers[i]
 		}
+	}
+	for _, s := range latest {
+		uc.notifyGraded(ctx, courseObj, assignmentObj, s)
+		for _, userID := range recipients(s) {
+			go uc.issueCertificate(context.Background(), userID, courseObj.ID)
+		}
+	}
+
+	return &ReleaseResponse{Released: len(released), Ungraded: ungraded}, nil
+}
+
+// notifyGraded sends the grade and feedback of the submission to its student by email and in-app notification,
+// every member of a team hears about their own grade
+func (uc *UseCase) notifyGraded(ctx context.Context, courseObj *schema.Course, assignmentObj *schema.Assignment, submission *schema.Submission) {
+	for _, userID := range recipients(submission) {
+		grade := gradeFor(submission, userID)
+
+		// Send email to student
+		go func(userID uuid.UUID) {
+			student, err := uc.userRepo.GetByID(userID)
+			if err != nil {
+				log.Println("Error getting student: ", err)
+				return
+			}
+
+			emailData := map[string]any{
+				"student_name":     student.Name,
+				"course_title":     courseObj.Title,
+				"assignment_title": assignmentObj.Title,
+				"grade":            grade,
+				"late_penalty":     submission.LatePenalty,
+				"feedback":         submission.Feedback,
+				"rubric_scores":    submission.RubricScores,
+			}
+
+			mail, err := mailer.GenerateMail(student.Email, "Assignment Graded",
+				submissionGradedStudentEmailTemplate, emailData)
+			if err != nil {
+				log.Println("Error generating email: ", err)
+				return
+			}
+
+			if err = uc.mailDialer.DialAndSend(mail); err != nil {
+				log.Println("Error sending email: ", err)
+				return
+			}
+		}(userID)
+
+		// Send in-app notification to student
+		go func(userID uuid.UUID) {
+			notifID, err := uuid.NewV7()
+			if err != nil {
+				return
+			}
+
+			detail := fmt.Sprintf("Your submission for %s in course %s has been graded: %.1f", assignmentObj.Title, courseObj.Title, grade)
+			if submission.Feedback != "" {
+				detail += "\n\n" + submission.Feedback
+			}
+
+			notif := schema.Notification{
+				ID:     notifID,
+				UserID: userID,
+				Title:  "Assignment Graded",
+				Detail: detail,
+			}
+
+			if err := uc.notifRepo.Create(&notif); err != nil {
+				log.Println("Error creating notification: ", err)
+				return
+			}
+		}(userID)
+	}
+}
+
+// getInstructedCourse returns the course of the assignment, or ErrNotOwnerCourse when the user does not teach it
+func (uc *UseCase) getInstructedCourse(ctx context.Context, assignmentObj *schema.Assignment, userId string) (*schema.Course, error) {
+	courseObj, err := uc.courseRepo.GetByID(ctx, assignmentObj.CourseID)
+	if err != nil {
+		return nil, err
+	}
 
-		submission.Attachments = []schema.Attachment{}
+	if courseObj.InstructorID.String() != userId {
+		return nil, ErrNotOwnerCourse
+	}
+	return &courseObj, nil
+}
 
+// checkSimilarity compares the new attempt with the work of the other students in the background as
+// reading its attachments takes a while
+func (uc *UseCase) checkSimilarity(submission *schema.Submission) {
+	if uc.SimilarityUc == nil {
+		return
+	}
+	go func() {
+		if err := uc.SimilarityUc.Check(context.Background(), submission); err != nil {
+			log.Println("Error checking submission similarity: ", err)
+		}
+	}()
+}
+
+// attachSimilarity adds the similarity reports to the submissions shown to the instructor, they are shown
+// without them when the reports cannot be read
+func (uc *UseCase) attachSimilarity(ctx context.Context, submissions ...*schema.Submission) {
+	if uc.SimilarityUc == nil || len(submissions) == 0 {
+		return
+	}
+
+	ids := make([]uuid.UUID, len(submissions))
+	for i, s := range submissions {
+		ids[i] = s.ID
+	}
+	reports, err := uc.SimilarityUc.GetBySubmissions(ctx, ids)
+	if err != nil {
+		log.Println("Error getting similarity reports: ", err)
+		return
+	}
+	for _, s := range submissions {
+		s.Similarity = reports[s.ID]
+	}
+}
+
+// issueCertificate generates the course certificate once the student becomes eligible. It runs after the
+// request is done, so it is given a fresh context rather than the request one gin hands to the next request
+func (uc *UseCase) issueCertificate(ctx context.Context, userID, courseID uuid.UUID) {
+	if uc.CertificateUc == nil {
+		return
+	}
+
+	if _, err := uc.CertificateUc.IssueIfEligible(ctx, userID, courseID); err != nil &&
+		apierror.GetHttpStatus(err) == http.StatusInternalServerError {
+		log.Println("Error issuing certificate: ", err)
+	}
+}
+
+// UpdateSubmission hands in a new attempt based on the submission `id`, the content and attachments
+// not given in the request are carried over from it. Earlier attempts are kept as they were graded.
+// Any member of the team may hand in the next attempt of a team submission
+func (uc *UseCase) UpdateSubmission(ctx context.Context, id uuid.UUID, req *UpdateSubmissionRequest, userId string) error {
+	previous, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if previous.TeamID == nil && previous.UserID.String() != userId {
+		return ErrNotOwnerSubmission
+	}
+
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, previous.AssignmentID)
+	if err != nil {
+		return ErrAssignmentNotFound
+	}
+
+	team, err := uc.teamOf(ctx, assignmentObj, userUUID)
+	if err != nil {
+		return err
+	}
+	if previous.TeamID != nil && (team == nil || team.ID != *previous.TeamID) {
+		return ErrNotOwnerSubmission
+	}
+
+	attempt, err := uc.nextAttempt(ctx, userUUID, team, assignmentObj)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	daysLate, err := uc.lateness(ctx, assignmentObj, userUUID, now)
+	if err != nil {
+		return err
+	}
+
+	newID, err := uuid.NewV7()
+	if err != nil {
+		return apierror.ErrInternalServer.Build()
+	}
+
+	submission := &schema.Submission{
+		ID:           newID,
+		AssignmentID: previous.AssignmentID,
+		UserID:       userUUID,
+		Attempt:      attempt,
+		Status:       schema.SubmissionSubmitted,
+		Content:      previous.Content,
+		SubmittedAt:  now,
+		Late:         daysLate > 0,
+		DaysLate:     daysLate,
+	}
+	handInForTeam(submission, team)
+
+	if req.Content != nil {
+		submission.Content = *req.Content
+	}
+
+	if req.Attachments != nil {
 		for _, fileHeader := range req.Attachments {
 			attachment, err := uc.attachmentUseCase.CreateSubmissionAttachment(ctx, fileHeader, "")
 			if err != nil {
@@ -273,33 +719,174 @@ func (uc *UseCase) UpdateSubmission(ctx context.Context, id uuid.UUID, req *Upda
 			}
 			submission.Attachments = append(submission.Attachments, attachment)
 		}
+	} else {
+		// the files stay where they are, the new attempt gets its own rows pointing at them
+		for _, att := range previous.Attachments {
+			attID, err := uuid.NewV7()
+			if err != nil {
+				return apierror.ErrInternalServer.Build()
+			}
+			submission.Attachments = append(submission.Attachments, schema.Attachment{
+				ID:          attID,
+				URL:         att.URL,
+				Description: att.Description,
+			})
+		}
 	}
 
-	return uc.repo.Update(ctx, submission)
+	if err := uc.createAttempt(ctx, submission); err != nil {
+		return err
+	}
+	uc.checkSimilarity(submission)
+	return nil
 }
 
-// DeleteSubmission handles the business logic for deleting a submission.
+// DeleteSubmission handles the business logic for deleting a submission, a team submission may be deleted
+// by any member of the team. Only attempts nobody started grading may be deleted, the others are kept as
+// they were graded and keep counting against the attempt limit
 func (uc *UseCase) DeleteSubmission(ctx context.Context, id uuid.UUID, userId string) error {
 	submission, err := uc.repo.GetByID(ctx, id)
 	if err != nil {
 		return err
 	}
 
-	if submission.UserID.String() != userId {
+	if submission.TeamID == nil && submission.UserID.String() != userId {
 		return ErrNotOwnerSubmission
 	}
 
+	if submission.TeamID != nil {
+		userUUID, err := uuid.Parse(userId)
+		if err != nil {
+			return apierror.ErrInternalServer.Build()
+		}
+		assignmentObj, err := uc.assignmentRepo.GetByID(ctx, submission.AssignmentID)
+		if err != nil {
+			return ErrAssignmentNotFound
+		}
+		team, err := uc.teamOf(ctx, assignmentObj, userUUID)
+		if err != nil && !errors.Is(err, ErrNotInTeam) {
+			return err
+		}
+		if team == nil || team.ID != *submission.TeamID {
+			return ErrNotOwnerSubmission
+		}
+	}
+
+	switch submission.Status {
+	case schema.SubmissionInReview, schema.SubmissionGraded, schema.SubmissionReturned:
+		return ErrSubmissionLocked
+	}
+
 	return uc.repo.Delete(ctx, id)
 }
 
-// GetSubmissionByID handles fetching a submission by its ID.
-func (uc *UseCase) GetSubmissionByID(ctx context.Context, id uuid.UUID) (*schema.Submission, error) {
-	return uc.repo.GetByID(ctx, id)
+// GetSubmissionByID fetches a submission for its student or the members of its team, who only see the grade
+// once it is released, or for the instructor of the course
+func (uc *UseCase) GetSubmissionByID(ctx context.Context, id uuid.UUID, userId string) (*schema.Submission, error) {
+	submission, err := uc.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if countsFor(submission, userId) {
+		hideUnreleased(submission)
+		hideAdjustments(submission, userId)
+	} else {
+		assignmentObj, err := uc.assignmentRepo.GetByID(ctx, submission.AssignmentID)
+		if err != nil {
+			return nil, ErrAssignmentNotFound
+		}
+		if _, err := uc.getInstructedCourse(ctx, assignmentObj, userId); err != nil {
+			if errors.Is(err, ErrNotOwnerCourse) {
+				return nil, ErrForbiddenOperation
+			}
+			return nil, err
+		}
+		uc.attachSimilarity(ctx, submission)
+	}
+
+	uc.attachmentUseCase.SignURLs(submission.Attachments)
+	return submission, nil
+}
+
+// GetAllSubmissionsByAssignment lists the submissions to an assignment for the instructor of the course,
+// filtered by status when one is given
+func (uc *UseCase) GetAllSubmissionsByAssignment(ctx context.Context, assignmentID uuid.UUID, userId string, req *ListSubmissionsRequest) ([]schema.Submission, error) {
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, ErrAssignmentNotFound
+	}
+
+	if _, err := uc.getInstructedCourse(ctx, assignmentObj, userId); err != nil {
+		return nil, err
+	}
+
+	submissions, err := uc.repo.GetAllByAssignment(ctx, assignmentID, req.Status)
+	if err != nil {
+		return nil, err
+	}
+
+	listed := make([]*schema.Submission, len(submissions))
+	for i := range submissions {
+		uc.attachmentUseCase.SignURLs(submissions[i].Attachments)
+		listed[i] = &submissions[i]
+	}
+	uc.attachSimilarity(ctx, listed...)
+	return submissions, nil
 }
 
-// GetAllSubmissionsByAssignment handles fetching all submissions for a given assignment.
-func (uc *UseCase) GetAllSubmissionsByAssignment(ctx context.Context, assignmentID uuid.UUID) ([]schema.Submission, error) {
-	return uc.repo.GetAllByAssignment(ctx, assignmentID)
+// GetMyAttempts lists the attempts of the student at an assignment together with the grade that counts, for
+// a group assignment the attempts of their team
+func (uc *UseCase) GetMyAttempts(ctx context.Context, assignmentID uuid.UUID, userId string) (*AttemptsResponse, error) {
+	userUUID, err := uuid.Parse(userId)
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	assignmentObj, err := uc.assignmentRepo.GetByID(ctx, assignmentID)
+	if err != nil {
+		return nil, ErrAssignmentNotFound
+	}
+
+	team, err := uc.teamOf(ctx, assignmentObj, userUUID)
+	if err != nil && !errors.Is(err, ErrNotInTeam) {
+		return nil, err
+	}
+
+	var attempts []schema.Submission
+	if team != nil {
+		attempts, err = uc.repo.GetTeamAttempts(ctx, team.ID, assignmentID)
+	} else {
+		attempts, err = uc.repo.GetAttempts(ctx, userUUID, assignmentID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	finalGrade, err := uc.repo.GetFinalGrade(ctx, userUUID, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	attemptsLeft := max(assignmentObj.MaxAttempts-len(attempts), 0)
+	if n := len(attempts); n > 0 && attempts[n-1].Status == schema.SubmissionReturned {
+		attemptsLeft = max(attemptsLeft, 1)
+	}
+
+	for i := range attempts {
+		hideUnreleased(&attempts[i])
+		hideAdjustments(&attempts[i], userId)
+		uc.attachmentUseCase.SignURLs(attempts[i].Attachments)
+	}
+
+	return &AttemptsResponse{
+		Team:          team,
+		Attempts:      attempts,
+		MaxAttempts:   assignmentObj.MaxAttempts,
+		AttemptsLeft:  attemptsLeft,
+		GradingPolicy: assignmentObj.GradingPolicy,
+		FinalGrade:    finalGrade,
+	}, nil
 }
 
 func (uc *UseCase) VerifyCourseEnroll(ctx context.Context, userID uuid.UUID, assignmentID uuid.UUID) error {
@@ -308,7 +895,7 @@ func (uc *UseCase) VerifyCourseEnroll(ctx context.Context, userID uuid.UUID, ass
 		return err
 	}
 
-	enroll, err := uc.courseEnrollRepo.IsEnrolled(ctx, userID, ass.CourseID)
+	enroll, err := uc.courseEnrollUc.CheckEnrollment(ctx, userID, ass.CourseID)
 	if err != nil {
 		return apierror.ErrInternalServer.Build()
 	}
@@ -317,16 +904,210 @@ func (uc *UseCase) VerifyCourseEnroll(ctx context.Context, userID uuid.UUID, ass
 		return ErrNotEnrollCourse
 	}
 
+	// Scheduled assignments only take submissions once they are released for the student
+	if ass.ReleaseAt != nil || ass.ReleaseAfterDays != nil {
+		enrolledAt, err := uc.courseEnrollUc.GetAccessStart(ctx, userID, ass.CourseID)
+		if err != nil || enrolledAt == nil {
+			return apierror.ErrInternalServer.Build()
+		}
+		if releaseAt := ass.ReleaseTime(*enrolledAt); releaseAt.After(time.Now()) {
+			return ErrAssignmentNotReleased
+		}
+	}
+
 	return nil
 }
 
-func (uc *UseCase) CheckSubmissionExists(ctx context.Context, userID uuid.UUID, assignmentID uuid.UUID) error {
-	exists, err := uc.repo.CheckSubmissionExists(ctx, userID, assignmentID)
+// lateness counts how many days after the deadline of the student a hand-in at `at` is,
+// assignments with a hard deadline reject it instead
+func (uc *UseCase) lateness(ctx context.Context, ass *schema.Assignment, userID uuid.UUID, at time.Time) (int, error) {
+	due, err := assignment.DueFor(ctx, uc.assignmentRepo, ass, userID)
 	if err != nil {
-		return err
+		return 0, apierror.ErrInternalServer.Build()
+	}
+
+	daysLate := assignment.DaysLate(due, at)
+	if daysLate > 0 && ass.LatePolicy == schema.LatePolicyHard {
+		return 0, ErrPastDue
+	}
+	return daysLate, nil
+}
+
+// nextAttempt returns the number of the next attempt of the student, or ErrNoAttemptsLeft once
+// they handed in as often as the assignment allows and their latest attempt was not returned to them.
+// The attempts of a team are counted for the whole team
+func (uc *UseCase) nextAttempt(ctx context.Context, userID uuid.UUID, team *schema.Team, ass *schema.Assignment) (int, error) {
+	if team != nil {
+		return uc.nextTeamAttempt(ctx, userID, team, ass)
+	}
+
+	used, err := uc.repo.CountAttempts(ctx, userID, ass.ID)
+	if err != nil {
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	if used >= int64(max(ass.MaxAttempts, 1)) {
+		status, err := uc.repo.GetLatestStatus(ctx, userID, ass.ID)
+		if err != nil {
+			return 0, apierror.ErrInternalServer.Build()
+		}
+		if status != schema.SubmissionReturned {
+			return 0, ErrNoAttemptsLeft
+		}
+	}
+
+	last, err := uc.repo.GetLastAttempt(ctx, userID, ass.ID)
+	if err != nil {
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	return last + 1, nil
+}
+
+func (uc *UseCase) nextTeamAttempt(ctx context.Context, userID uuid.UUID, team *schema.Team, ass *schema.Assignment) (int, error) {
+	attempts, err := uc.repo.GetTeamAttempts(ctx, team.ID, ass.ID)
+	if err != nil {
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	if n := len(attempts); n >= max(ass.MaxAttempts, 1) && attempts[n-1].Status != schema.SubmissionReturned {
+		return 0, ErrNoAttemptsLeft
 	}
-	if exists {
-		return ErrSubmissionAlreadyExists
+
+	last, err := uc.repo.GetTeamLastAttempt(ctx, team.ID, ass.ID)
+	if err != nil {
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	// A student who moved over from another team may have used the number already
+	userLast, err := uc.repo.GetLastAttempt(ctx, userID, ass.ID)
+	if err != nil {
+		return 0, apierror.ErrInternalServer.Build()
+	}
+	return max(last, userLast) + 1, nil
+}
+
+// teamOf returns the team the student hands in with at a group assignment, nil for individual work.
+// Students without a team cannot hand in to a group assignment
+func (uc *UseCase) teamOf(ctx context.Context, ass *schema.Assignment, userID uuid.UUID) (*schema.Team, error) {
+	if ass.TeamSetID == nil {
+		return nil, nil
+	}
+	team, err := uc.repo.GetTeam(ctx, *ass.TeamSetID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotInTeam
+		}
+		log.Println("Error getting team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return team, nil
+}
+
+// handInForTeam makes the attempt the one of the team, it counts for everyone in the team at this moment
+func handInForTeam(submission *schema.Submission, team *schema.Team) {
+	if team == nil {
+		return
+	}
+	submission.TeamID = &team.ID
+	for _, m := range team.Members {
+		submission.Members = append(submission.Members, schema.SubmissionMember{
+			SubmissionID: submission.ID,
+			UserID:       m.UserID,
+		})
+	}
+}
+
+// adjustMembers sets the grade adjustments of members of a team submission
+func adjustMembers(submission *schema.Submission, adjustments []AdjustmentRequest) error {
+	for _, a := range adjustments {
+		i := slices.IndexFunc(submission.Members, func(m schema.SubmissionMember) bool {
+			return m.UserID == a.UserID
+		})
+		if i < 0 {
+			return ErrInvalidAdjustment
+		}
+		submission.Members[i].Adjustment = a.Points
+		submission.Members[i].AdjustmentReason = a.Reason
+	}
+	return nil
+}
+
+// authorOf is who handed the submission in, the team for a team submission
+func authorOf(submission *schema.Submission) uuid.UUID {
+	if submission.TeamID != nil {
+		return *submission.TeamID
+	}
+	return submission.UserID
+}
+
+// recipients returns the students the submission counts for, the members of the team for a team submission
+func recipients(submission *schema.Submission) []uuid.UUID {
+	if len(submission.Members) == 0 {
+		return []uuid.UUID{submission.UserID}
+	}
+	ids := make([]uuid.UUID, len(submission.Members))
+	for i, m := range submission.Members {
+		ids[i] = m.UserID
+	}
+	return ids
+}
+
+// countsFor reports whether the student handed the submission in or was in its team
+func countsFor(submission *schema.Submission, userID string) bool {
+	return submission.UserID.String() == userID || slices.ContainsFunc(submission.Members, func(m schema.SubmissionMember) bool {
+		return m.UserID.String() == userID
+	})
+}
+
+// gradeFor is the grade the student gets from the submission, with their adjustment for a team submission
+func gradeFor(submission *schema.Submission, userID uuid.UUID) float64 {
+	var grade float64
+	if submission.Grade != nil {
+		grade = *submission.Grade
+	}
+	for _, m := range submission.Members {
+		if m.UserID == userID {
+			return math.Round(min(max(grade+m.Adjustment, 0), 100)*10) / 10
+		}
+	}
+	return grade
+}
+
+// createAttempt stores a new attempt, two hand-ins racing for the same attempt number end up
+// on the unique index and the later one is rejected
+func (uc *UseCase) createAttempt(ctx context.Context, submission *schema.Submission) error {
+	if err := uc.repo.Create(ctx, submission); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return ErrSubmissionAlreadyExists
+		}
+		return err
 	}
 	return nil
 }
+
+// hideUnreleased strips what the student may not see yet from their own submission, a grade held back
+// by the instructor looks like it is still in review
+func hideUnreleased(submission *schema.Submission) {
+	if submission.ReleasedAt != nil {
+		return
+	}
+	if submission.Status == schema.SubmissionGraded {
+		submission.Status = schema.SubmissionInReview
+	}
+	if submission.Status != schema.SubmissionReturned {
+		submission.Feedback = ""
+	}
+	submission.Grade = nil
+	submission.GradedAt = nil
+	submission.LatePenalty = 0
+	submission.RubricScores = nil
+}
+
+// hideAdjustments keeps the adjustments of the other members of a team from the student, their own shows
+// once the grade is released
+func hideAdjustments(submission *schema.Submission, userID string) {
+	for i := range submission.Members {
+		if submission.Members[i].UserID.String() != userID || submission.ReleasedAt == nil {
+			submission.Members[i].Adjustment = 0
+			submission.Members[i].AdjustmentReason = ""
+		}
+	}
+}
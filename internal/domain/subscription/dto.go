@@ -0,0 +1,20 @@
+package subscription
+
+type CreatePlanRequest struct {
+	Name         string `json:"name" binding:"required,max=100"`
+	Description  string `json:"description" binding:"max=2000"`
+	Price        int64  `json:"price" binding:"gte=0"`
+	IntervalDays int    `json:"interval_days" binding:"required,min=1,max=366"`
+}
+
+type UpdatePlanRequest struct {
+	ID          string  `uri:"id" binding:"required,uuid"`
+	Name        *string `json:"name" binding:"omitempty,max=100"`
+	Description *string `json:"description" binding:"omitempty,max=2000"`
+	Price       *int64  `json:"price" binding:"omitempty,gte=0"`
+	IsActive    *bool   `json:"is_active"`
+}
+
+type SubscribeRequest struct {
+	PlanID string `json:"plan_id" binding:"required,uuid"`
+}
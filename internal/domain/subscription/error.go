@@ -0,0 +1,25 @@
+package subscription
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrPlanNotFound = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusNotFound).
+			WithMessage("SUBSCRIPTION_PLAN_NOT_FOUND")
+
+	ErrPlanInactive = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusBadRequest).
+			WithMessage("SUBSCRIPTION_PLAN_INACTIVE")
+
+	ErrSubscriptionNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("SUBSCRIPTION_NOT_FOUND")
+
+	ErrAlreadySubscribed = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("ALREADY_SUBSCRIBED")
+)
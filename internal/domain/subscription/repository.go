@@ -0,0 +1,113 @@
+package subscription
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/wallet"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error
+	GetPlans(ctx context.Context, activeOnly bool) ([]schema.SubscriptionPlan, error)
+	GetPlanByID(ctx context.Context, id uuid.UUID) (*schema.SubscriptionPlan, error)
+	UpdatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error
+	GetCurrentByUserID(ctx context.Context, userID uuid.UUID) (*schema.Subscription, error)
+	GetDueForRenewal(ctx context.Context, at time.Time, limit int) ([]schema.Subscription, error)
+	Subscribe(ctx context.Context, subscription *schema.Subscription, amount int64) error
+	Renew(ctx context.Context, subscription *schema.Subscription, amount int64) error
+	Update(ctx context.Context, subscription *schema.Subscription) error
+}
+
+type repository struct {
+	db         *gorm.DB
+	walletRepo wallet.IRepository
+}
+
+func NewRepository(db *gorm.DB, walletRepo wallet.IRepository) Repository {
+	return &repository{db: db, walletRepo: walletRepo}
+}
+
+func (r *repository) CreatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error {
+	return r.db.WithContext(ctx).Create(plan).Error
+}
+
+func (r *repository) GetPlans(ctx context.Context, activeOnly bool) ([]schema.SubscriptionPlan, error) {
+	var plans []schema.SubscriptionPlan
+
+	query := r.db.WithContext(ctx).Order("price")
+	if activeOnly {
+		query = query.Where("is_active = ?", true)
+	}
+
+	err := query.Find(&plans).Error
+	return plans, err
+}
+
+func (r *repository) GetPlanByID(ctx context.Context, id uuid.UUID) (*schema.SubscriptionPlan, error) {
+	var plan schema.SubscriptionPlan
+	if err := r.db.WithContext(ctx).First(&plan, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &plan, nil
+}
+
+func (r *repository) UpdatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error {
+	return r.db.WithContext(ctx).Save(plan).Error
+}
+
+// GetCurrentByUserID returns the latest subscription that is not canceled yet
+func (r *repository) GetCurrentByUserID(ctx context.Context, userID uuid.UUID) (*schema.Subscription, error) {
+	var subscription schema.Subscription
+	err := r.db.WithContext(ctx).
+		Preload("Plan").
+		Where("user_id = ? AND status <> ?", userID, schema.SubscriptionCanceled).
+		Order("created_at DESC").
+		First(&subscription).Error
+	if err != nil {
+		return nil, err
+	}
+	return &subscription, nil
+}
+
+// GetDueForRenewal returns active subscriptions before past due ones so retries never hold back a fresh renewal
+func (r *repository) GetDueForRenewal(ctx context.Context, at time.Time, limit int) ([]schema.Subscription, error) {
+	var subscriptions []schema.Subscription
+	err := r.db.WithContext(ctx).
+		Preload("Plan").
+		Where("status <> ? AND current_period_end <= ?", schema.SubscriptionCanceled, at).
+		Order("status").
+		Order("current_period_end").
+		Limit(limit).
+		Find(&subscriptions).Error
+	return subscriptions, err
+}
+
+// Subscribe charges the first period and stores the subscription atomically
+func (r *repository) Subscribe(ctx context.Context, subscription *schema.Subscription, amount int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.walletRepo.DebitByUserID(tx, subscription.UserID, amount); err != nil {
+			return err
+		}
+
+		return tx.Omit("Plan").Create(subscription).Error
+	})
+}
+
+// Renew charges the next period and moves the subscription to it atomically
+func (r *repository) Renew(ctx context.Context, subscription *schema.Subscription, amount int64) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := r.walletRepo.DebitByUserID(tx, subscription.UserID, amount); err != nil {
+			return err
+		}
+
+		return tx.Omit("Plan").Save(subscription).Error
+	})
+}
+
+func (r *repository) Update(ctx context.Context, subscription *schema.Subscription) error {
+	return r.db.WithContext(ctx).Omit("Plan").Save(subscription).Error
+}
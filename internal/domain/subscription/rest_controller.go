@@ -0,0 +1,148 @@
+package subscription
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	planGroup := engine.Group("/v1/subscriptions/plans", middleware.Authenticate())
+	{
+		planGroup.GET("", controller.GetPlans())
+		planGroup.POST("", middleware.RequireRole("admin"), controller.CreatePlan())
+		planGroup.PATCH("/:id", middleware.RequireRole("admin"), controller.UpdatePlan())
+	}
+
+	subscriptionGroup := engine.Group("/v1/subscriptions", middleware.Authenticate())
+	{
+		subscriptionGroup.POST("",
+			middleware.RequireEmailVerified(),
+			middleware.RequireRole("student"),
+			controller.Subscribe(),
+		)
+		subscriptionGroup.GET("/me", controller.GetMine())
+		subscriptionGroup.POST("/me/cancel", controller.Cancel())
+		subscriptionGroup.POST("/me/resume", controller.Resume())
+	}
+}
+
+func (c *RestController) GetPlans() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetPlans(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_SUBSCRIPTION_PLANS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) CreatePlan() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CreatePlanRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.CreatePlan(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_SUBSCRIPTION_PLAN_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) UpdatePlan() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdatePlanRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.UpdatePlan(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_SUBSCRIPTION_PLAN_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Subscribe() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SubscribeRequest
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Subscribe(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "SUBSCRIBE_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) GetMine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.GetMine(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_SUBSCRIPTION_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Cancel() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.Cancel(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "CANCEL_SUBSCRIPTION_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Resume() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		res, err := c.uc.Resume(ctx)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "RESUME_SUBSCRIPTION_SUCCESS", res).Send(ctx)
+	}
+}
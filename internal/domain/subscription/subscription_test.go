@@ -0,0 +1,236 @@
+package subscription
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetPlans(ctx context.Context, activeOnly bool) ([]schema.SubscriptionPlan, error) {
+	args := m.Called(ctx, activeOnly)
+	return args.Get(0).([]schema.SubscriptionPlan), args.Error(1)
+}
+
+func (m *MockRepository) GetPlanByID(ctx context.Context, id uuid.UUID) (*schema.SubscriptionPlan, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.SubscriptionPlan), args.Error(1)
+}
+
+func (m *MockRepository) UpdatePlan(ctx context.Context, plan *schema.SubscriptionPlan) error {
+	args := m.Called(ctx, plan)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetCurrentByUserID(ctx context.Context, userID uuid.UUID) (*schema.Subscription, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) GetDueForRenewal(ctx context.Context, at time.Time, limit int) ([]schema.Subscription, error) {
+	args := m.Called(ctx, at, limit)
+	return args.Get(0).([]schema.Subscription), args.Error(1)
+}
+
+func (m *MockRepository) Subscribe(ctx context.Context, subscription *schema.Subscription, amount int64) error {
+	args := m.Called(ctx, subscription, amount)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Renew(ctx context.Context, subscription *schema.Subscription, amount int64) error {
+	args := m.Called(ctx, subscription, amount)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Update(ctx context.Context, subscription *schema.Subscription) error {
+	args := m.Called(ctx, subscription)
+	return args.Error(0)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type SubscriptionUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	notificationRepo *MockNotificationRepository
+	useCase          *UseCase
+}
+
+func (suite *SubscriptionUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	suite.useCase = NewUseCase(suite.repo, suite.notificationRepo)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) dueSubscription(status schema.SubscriptionStatus, periodEnd time.Time) schema.Subscription {
+	plan := schema.SubscriptionPlan{ID: uuid.New(), Name: "Monthly", Price: 100000, IntervalDays: 30, IsActive: true}
+	return schema.Subscription{
+		ID:                 uuid.New(),
+		UserID:             uuid.New(),
+		PlanID:             plan.ID,
+		Plan:               plan,
+		Status:             status,
+		CurrentPeriodStart: periodEnd.AddDate(0, 0, -30),
+		CurrentPeriodEnd:   periodEnd,
+	}
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestSubscribe_AlreadySubscribed() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	plan := &schema.SubscriptionPlan{ID: uuid.New(), Price: 100000, IntervalDays: 30, IsActive: true}
+
+	suite.repo.On("GetPlanByID", ctx, plan.ID).Return(plan, nil)
+	suite.repo.On("GetCurrentByUserID", ctx, userID).Return(&schema.Subscription{ID: uuid.New()}, nil)
+
+	res, err := suite.useCase.Subscribe(ctx, &SubscribeRequest{PlanID: plan.ID.String()})
+
+	assert.Nil(suite.T(), res)
+	assert.Equal(suite.T(), ErrAlreadySubscribed.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Subscribe", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestSubscribe_Success() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	plan := &schema.SubscriptionPlan{ID: uuid.New(), Price: 100000, IntervalDays: 30, IsActive: true}
+
+	suite.repo.On("GetPlanByID", ctx, plan.ID).Return(plan, nil)
+	suite.repo.On("GetCurrentByUserID", ctx, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("Subscribe", ctx, mock.AnythingOfType("*schema.Subscription"), plan.Price).Return(nil)
+
+	res, err := suite.useCase.Subscribe(ctx, &SubscribeRequest{PlanID: plan.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), schema.SubscriptionActive, res.Status)
+	assert.Equal(suite.T(), res.CurrentPeriodStart.AddDate(0, 0, 30), res.CurrentPeriodEnd)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestRenewDue_AdvancesPeriod() {
+	periodEnd := time.Now().Add(-time.Minute)
+	sub := suite.dueSubscription(schema.SubscriptionActive, periodEnd)
+
+	suite.repo.On("GetDueForRenewal", mock.Anything, mock.Anything, renewalBatchSize).Return([]schema.Subscription{sub}, nil)
+	suite.repo.On("Renew", mock.Anything, mock.MatchedBy(func(s *schema.Subscription) bool {
+		return s.CurrentPeriodStart.Equal(periodEnd) && s.CurrentPeriodEnd.Equal(periodEnd.AddDate(0, 0, 30))
+	}), sub.Plan.Price).Return(nil)
+
+	handled, err := suite.useCase.RenewDue(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, handled)
+	suite.repo.AssertNotCalled(suite.T(), "Update", mock.Anything, mock.Anything)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestRenewDue_InsufficientBalanceMarksPastDue() {
+	sub := suite.dueSubscription(schema.SubscriptionActive, time.Now().Add(-time.Minute))
+
+	suite.repo.On("GetDueForRenewal", mock.Anything, mock.Anything, renewalBatchSize).Return([]schema.Subscription{sub}, nil)
+	suite.repo.On("Renew", mock.Anything, mock.Anything, sub.Plan.Price).Return(apierror.ErrInsufficientBalance.Build())
+	suite.repo.On("Update", mock.Anything, mock.MatchedBy(func(s *schema.Subscription) bool {
+		return s.Status == schema.SubscriptionPastDue
+	})).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+
+	handled, err := suite.useCase.RenewDue(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, handled)
+	suite.notificationRepo.AssertNumberOfCalls(suite.T(), "Create", 1)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestRenewDue_PastDueBeyondGraceIsCanceled() {
+	sub := suite.dueSubscription(schema.SubscriptionPastDue, time.Now().Add(-pastDueGracePeriod-time.Hour))
+
+	suite.repo.On("GetDueForRenewal", mock.Anything, mock.Anything, renewalBatchSize).Return([]schema.Subscription{sub}, nil)
+	suite.repo.On("Renew", mock.Anything, mock.Anything, sub.Plan.Price).Return(apierror.ErrInsufficientBalance.Build())
+	suite.repo.On("Update", mock.Anything, mock.MatchedBy(func(s *schema.Subscription) bool {
+		return s.Status == schema.SubscriptionCanceled
+	})).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+
+	handled, err := suite.useCase.RenewDue(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, handled)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestRenewDue_CancelAtPeriodEnd() {
+	sub := suite.dueSubscription(schema.SubscriptionActive, time.Now().Add(-time.Minute))
+	sub.CancelAtPeriodEnd = true
+
+	suite.repo.On("GetDueForRenewal", mock.Anything, mock.Anything, renewalBatchSize).Return([]schema.Subscription{sub}, nil)
+	suite.repo.On("Update", mock.Anything, mock.MatchedBy(func(s *schema.Subscription) bool {
+		return s.Status == schema.SubscriptionCanceled
+	})).Return(nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+
+	handled, err := suite.useCase.RenewDue(context.Background())
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, handled)
+	suite.repo.AssertNotCalled(suite.T(), "Renew", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *SubscriptionUseCaseTestSuite) TestCancel_KeepsAccessUntilPeriodEnd() {
+	userID := uuid.New()
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	sub := suite.dueSubscription(schema.SubscriptionActive, time.Now().AddDate(0, 0, 10))
+
+	suite.repo.On("GetCurrentByUserID", ctx, userID).Return(&sub, nil)
+	suite.repo.On("Update", ctx, &sub).Return(nil)
+
+	res, err := suite.useCase.Cancel(ctx)
+
+	assert.NoError(suite.T(), err)
+	assert.True(suite.T(), res.CancelAtPeriodEnd)
+	assert.Equal(suite.T(), schema.SubscriptionActive, res.Status)
+}
+
+func TestSubscriptionUseCase(t *testing.T) {
+	suite.Run(t, new(SubscriptionUseCaseTestSuite))
+}
@@ -0,0 +1,340 @@
+package subscription
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const (
+	// pastDueGracePeriod is how long a failed renewal is retried before the subscription is canceled
+	pastDueGracePeriod = 7 * 24 * time.Hour
+	renewalBatchSize   = 100
+)
+
+type UseCase struct {
+	repo             Repository
+	notificationRepo notification.IRepository
+}
+
+func NewUseCase(repo Repository, notificationRepo notification.IRepository) *UseCase {
+	return &UseCase{repo: repo, notificationRepo: notificationRepo}
+}
+
+func isInsufficientBalance(err error) bool {
+	var apiErr *apierror.ApiError
+	return errors.As(err, &apiErr) && apiErr.Message == apierror.ErrInsufficientBalance.Build().Message
+}
+
+func (uc *UseCase) notify(userID uuid.UUID, title, detail string) {
+	notificationID, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+
+	notif := schema.Notification{
+		ID:     notificationID,
+		UserID: userID,
+		Title:  title,
+		Detail: detail,
+	}
+	if err := uc.notificationRepo.Create(&notif); err != nil {
+		log.Println("Error creating notification: ", err)
+	}
+}
+
+func (uc *UseCase) GetPlans(ctx context.Context) ([]schema.SubscriptionPlan, error) {
+	activeOnly := ctx.Value("user.role") != string(schema.RoleAdmin)
+
+	plans, err := uc.repo.GetPlans(ctx, activeOnly)
+	if err != nil {
+		log.Println("Error getting subscription plans: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return plans, nil
+}
+
+func (uc *UseCase) CreatePlan(ctx context.Context, req *CreatePlanRequest) (*schema.SubscriptionPlan, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	plan := &schema.SubscriptionPlan{
+		ID:           id,
+		Name:         req.Name,
+		Description:  req.Description,
+		Price:        req.Price,
+		IntervalDays: req.IntervalDays,
+		IsActive:     true,
+	}
+
+	if err := uc.repo.CreatePlan(ctx, plan); err != nil {
+		log.Println("Error creating subscription plan: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return plan, nil
+}
+
+// UpdatePlan edits a plan, a new price applies to existing subscribers from their next renewal
+func (uc *UseCase) UpdatePlan(ctx context.Context, req *UpdatePlanRequest) (*schema.SubscriptionPlan, error) {
+	id, err := uuid.Parse(req.ID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	plan, err := uc.getPlan(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		plan.Name = *req.Name
+	}
+	if req.Description != nil {
+		plan.Description = *req.Description
+	}
+	if req.Price != nil {
+		plan.Price = *req.Price
+	}
+	if req.IsActive != nil {
+		plan.IsActive = *req.IsActive
+	}
+
+	if err := uc.repo.UpdatePlan(ctx, plan); err != nil {
+		log.Println("Error updating subscription plan: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return plan, nil
+}
+
+func (uc *UseCase) getPlan(ctx context.Context, id uuid.UUID) (*schema.SubscriptionPlan, error) {
+	plan, err := uc.repo.GetPlanByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPlanNotFound.Build()
+		}
+		log.Println("Error getting subscription plan: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return plan, nil
+}
+
+func (uc *UseCase) getCurrent(ctx context.Context, userID uuid.UUID) (*schema.Subscription, error) {
+	subscription, err := uc.repo.GetCurrentByUserID(ctx, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSubscriptionNotFound.Build()
+		}
+		log.Println("Error getting subscription: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return subscription, nil
+}
+
+// Subscribe charges the first period from the wallet and starts the subscription right away
+func (uc *UseCase) Subscribe(ctx context.Context, req *SubscribeRequest) (*schema.Subscription, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	planID, err := uuid.Parse(req.PlanID)
+	if err != nil {
+		return nil, ErrPlanNotFound.Build()
+	}
+
+	plan, err := uc.getPlan(ctx, planID)
+	if err != nil {
+		return nil, err
+	}
+	if !plan.IsActive {
+		return nil, ErrPlanInactive.Build()
+	}
+
+	if _, err := uc.repo.GetCurrentByUserID(ctx, userID); err == nil {
+		return nil, ErrAlreadySubscribed.Build()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("Error getting subscription: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	now := time.Now()
+	subscription := &schema.Subscription{
+		ID:                 id,
+		UserID:             userID,
+		PlanID:             plan.ID,
+		Plan:               *plan,
+		Status:             schema.SubscriptionActive,
+		CurrentPeriodStart: now,
+		CurrentPeriodEnd:   now.AddDate(0, 0, plan.IntervalDays),
+	}
+
+	if err := uc.repo.Subscribe(ctx, subscription, plan.Price); err != nil {
+		var apiErr *apierror.ApiError
+		if errors.As(err, &apiErr) {
+			return nil, err
+		}
+		log.Println("Error creating subscription: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return subscription, nil
+}
+
+func (uc *UseCase) GetMine(ctx context.Context) (*schema.Subscription, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	return uc.getCurrent(ctx, userID)
+}
+
+// Cancel stops the renewals, access is kept until the end of the paid period.
+// A past due subscription has no paid period left so it is canceled right away
+func (uc *UseCase) Cancel(ctx context.Context) (*schema.Subscription, error) {
+	subscription, err := uc.GetMine(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == schema.SubscriptionPastDue {
+		subscription.Status = schema.SubscriptionCanceled
+	} else {
+		subscription.CancelAtPeriodEnd = true
+	}
+
+	if err := uc.repo.Update(ctx, subscription); err != nil {
+		log.Println("Error canceling subscription: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return subscription, nil
+}
+
+// Resume undoes a pending cancellation before the period ends
+func (uc *UseCase) Resume(ctx context.Context) (*schema.Subscription, error) {
+	subscription, err := uc.GetMine(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	subscription.CancelAtPeriodEnd = false
+	if err := uc.repo.Update(ctx, subscription); err != nil {
+		log.Println("Error resuming subscription: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	return subscription, nil
+}
+
+// renew bills the next period of a due subscription. When the wallet cannot cover it the subscription
+// becomes past due, which removes access, and is retried until the grace period runs out
+func (uc *UseCase) renew(ctx context.Context, subscription *schema.Subscription, now time.Time) error {
+	if subscription.CancelAtPeriodEnd {
+		subscription.Status = schema.SubscriptionCanceled
+		if err := uc.repo.Update(ctx, subscription); err != nil {
+			return err
+		}
+		uc.notify(subscription.UserID, "Your subscription has ended",
+			fmt.Sprintf("Your %s subscription was canceled and has now ended", subscription.Plan.Name))
+		return nil
+	}
+
+	wasPastDue := subscription.Status == schema.SubscriptionPastDue
+	start := subscription.CurrentPeriodEnd
+	if wasPastDue {
+		start = now
+	}
+
+	renewed := *subscription
+	renewed.Status = schema.SubscriptionActive
+	renewed.CurrentPeriodStart = start
+	renewed.CurrentPeriodEnd = start.AddDate(0, 0, subscription.Plan.IntervalDays)
+
+	err := uc.repo.Renew(ctx, &renewed, subscription.Plan.Price)
+	if err == nil {
+		*subscription = renewed
+		return nil
+	}
+	if !isInsufficientBalance(err) {
+		return err
+	}
+
+	if wasPastDue {
+		if now.Sub(subscription.CurrentPeriodEnd) < pastDueGracePeriod {
+			return nil
+		}
+		subscription.Status = schema.SubscriptionCanceled
+		if err := uc.repo.Update(ctx, subscription); err != nil {
+			return err
+		}
+		uc.notify(subscription.UserID, "Your subscription has been canceled",
+			fmt.Sprintf("We could not renew your %s subscription, top up your wallet and subscribe again to regain access", subscription.Plan.Name))
+		return nil
+	}
+
+	subscription.Status = schema.SubscriptionPastDue
+	if err := uc.repo.Update(ctx, subscription); err != nil {
+		return err
+	}
+	uc.notify(subscription.UserID, "Your subscription payment failed",
+		fmt.Sprintf("Your wallet balance is not enough to renew %s, top up your wallet to keep access", subscription.Plan.Name))
+	return nil
+}
+
+// RenewDue processes every subscription whose period has ended and returns how many were handled
+func (uc *UseCase) RenewDue(ctx context.Context) (int, error) {
+	now := time.Now()
+
+	subscriptions, err := uc.repo.GetDueForRenewal(ctx, now, renewalBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	handled := 0
+	for i := range subscriptions {
+		if err := uc.renew(ctx, &subscriptions[i], now); err != nil {
+			log.Println("Error renewing subscription ", subscriptions[i].ID, ": ", err)
+			continue
+		}
+		handled++
+	}
+
+	return handled, nil
+}
+
+// StartRenewalWorker runs RenewDue every interval until the context is done
+func (uc *UseCase) StartRenewalWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := uc.RenewDue(ctx); err != nil {
+			log.Println("Error renewing subscriptions: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
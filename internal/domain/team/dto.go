@@ -0,0 +1,44 @@
+package team
+
+type CourseIDRequest struct {
+	CourseID string `uri:"id" binding:"required,uuid"`
+}
+
+type SetIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+type TeamIDRequest struct {
+	ID string `uri:"id" binding:"required,uuid"`
+}
+
+// CreateSetRequest adds a team set to the course, MaxSize only limits the teams students join on their own
+type CreateSetRequest struct {
+	CourseID   string `uri:"id" binding:"required,uuid"`
+	Name       string `json:"name" binding:"required,max=100"`
+	SelfSelect bool   `json:"self_select"`
+	MaxSize    *int   `json:"max_size" binding:"omitempty,min=1,max=100"`
+}
+
+type UpdateSetRequest struct {
+	ID         string  `uri:"id" binding:"required,uuid"`
+	Name       *string `json:"name" binding:"omitempty,min=1,max=100"`
+	SelfSelect *bool   `json:"self_select"`
+	MaxSize    *int    `json:"max_size" binding:"omitempty,min=1,max=100"`
+	// ClearMaxSize lets self-selected teams grow without a limit
+	ClearMaxSize bool `json:"clear_max_size"`
+}
+
+// CreateTeamRequest starts a team in the set. The instructor may put students in it right away, a student
+// starting a team is its first member and the others join it
+type CreateTeamRequest struct {
+	SetID     string   `uri:"id" binding:"required,uuid"`
+	Name      string   `json:"name" binding:"required,max=100"`
+	MemberIDs []string `json:"member_ids" binding:"omitempty,max=100,dive,uuid"`
+}
+
+// SetMembersRequest replaces the members of a team, students in another team of the set are moved over
+type SetMembersRequest struct {
+	ID      string   `uri:"id" binding:"required,uuid"`
+	UserIDs []string `json:"user_ids" binding:"max=100,dive,uuid"`
+}
@@ -0,0 +1,45 @@
+package team
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrTeamSetNotFound = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("TEAM_SET_NOT_FOUND")
+
+	ErrTeamNotFound = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusNotFound).
+			WithMessage("TEAM_NOT_FOUND")
+
+	ErrTeamSetInUse = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusConflict).
+			WithMessage("TEAM_SET_IN_USE")
+
+	ErrSelfSelectDisabled = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("TEAM_SELF_SELECT_DISABLED")
+
+	ErrAlreadyInTeam = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("ALREADY_IN_TEAM")
+
+	ErrNotTeamMember = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusForbidden).
+				WithMessage("NOT_TEAM_MEMBER")
+
+	ErrTeamFull = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusConflict).
+			WithMessage("TEAM_FULL")
+
+	ErrTeamLocked = apierror.NewApiErrorBuilder().
+			WithHttpStatus(http.StatusConflict).
+			WithMessage("TEAM_LOCKED")
+
+	ErrInvalidMembers = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("INVALID_TEAM_MEMBERS")
+)
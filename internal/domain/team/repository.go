@@ -0,0 +1,202 @@
+package team
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	CreateSet(ctx context.Context, set *schema.TeamSet) error
+	UpdateSet(ctx context.Context, set *schema.TeamSet) error
+	DeleteSet(ctx context.Context, id uuid.UUID) error
+	GetSet(ctx context.Context, id uuid.UUID) (*schema.TeamSet, error)
+	GetSets(ctx context.Context, courseID uuid.UUID) ([]schema.TeamSet, error)
+	IsSetInUse(ctx context.Context, id uuid.UUID) (bool, error)
+	CreateTeam(ctx context.Context, team *schema.Team) error
+	DeleteTeam(ctx context.Context, id uuid.UUID) error
+	GetTeam(ctx context.Context, id uuid.UUID) (*schema.Team, error)
+	GetMemberTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error)
+	HasSubmissions(ctx context.Context, teamID uuid.UUID) (bool, error)
+	ReplaceMembers(ctx context.Context, team *schema.Team, userIDs []uuid.UUID) error
+	AddMember(ctx context.Context, member *schema.TeamMember) error
+	RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error
+	CountEnrolled(ctx context.Context, courseID uuid.UUID, userIDs []uuid.UUID) (int64, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+func (r *repository) CreateSet(ctx context.Context, set *schema.TeamSet) error {
+	return r.db.WithContext(ctx).Omit("Course", "Teams").Create(set).Error
+}
+
+func (r *repository) UpdateSet(ctx context.Context, set *schema.TeamSet) error {
+	return r.db.WithContext(ctx).Omit("Course", "Teams").Save(set).Error
+}
+
+// DeleteSet removes the team set together with its teams
+func (r *repository) DeleteSet(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.TeamSet{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetSet(ctx context.Context, id uuid.UUID) (*schema.TeamSet, error) {
+	var set schema.TeamSet
+	if err := r.db.WithContext(ctx).First(&set, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &set, nil
+}
+
+// GetSets returns the team sets of the course with their teams and members, in the order they were made
+func (r *repository) GetSets(ctx context.Context, courseID uuid.UUID) ([]schema.TeamSet, error) {
+	var sets []schema.TeamSet
+	err := r.db.WithContext(ctx).
+		Preload("Teams", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at")
+		}).
+		Preload("Teams.Members", func(db *gorm.DB) *gorm.DB {
+			return db.Order("joined_at")
+		}).
+		Preload("Teams.Members.User").
+		Where("course_id = ?", courseID).
+		Order("created_at").
+		Find(&sets).Error
+	return sets, err
+}
+
+// IsSetInUse reports whether an assignment that was not deleted is handed in by the teams of the set
+func (r *repository) IsSetInUse(ctx context.Context, id uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Assignment{}).
+		Where("team_set_id = ?", id).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// CreateTeam stores the team with its members, members in another team of the set are moved out of it
+func (r *repository) CreateTeam(ctx context.Context, team *schema.Team) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(team.Members) > 0 {
+			userIDs := make([]uuid.UUID, len(team.Members))
+			for i, m := range team.Members {
+				userIDs[i] = m.UserID
+			}
+			if err := tx.Where("team_set_id = ? AND user_id IN ?", team.TeamSetID, userIDs).
+				Delete(&schema.TeamMember{}).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Omit("TeamSet").Create(team).Error
+	})
+}
+
+func (r *repository) DeleteTeam(ctx context.Context, id uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.Team{}, "id = ?", id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetTeam(ctx context.Context, id uuid.UUID) (*schema.Team, error) {
+	var team schema.Team
+	err := r.db.WithContext(ctx).
+		Preload("Members", func(db *gorm.DB) *gorm.DB {
+			return db.Order("joined_at")
+		}).
+		Preload("Members.User").
+		First(&team, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// GetMemberTeam returns the team of the student within the set, gorm.ErrRecordNotFound while they have none
+func (r *repository) GetMemberTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error) {
+	member := r.db.Model(&schema.TeamMember{}).
+		Select("team_id").
+		Where("team_set_id = ? AND user_id = ?", teamSetID, userID)
+
+	var team schema.Team
+	if err := r.db.WithContext(ctx).Where("id IN (?)", member).First(&team).Error; err != nil {
+		return nil, err
+	}
+	return &team, nil
+}
+
+// HasSubmissions reports whether the team handed in to a group assignment
+func (r *repository) HasSubmissions(ctx context.Context, teamID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&schema.Submission{}).
+		Where("team_id = ?", teamID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+// ReplaceMembers makes the students the members of the team, students in another team of the set are moved
+// out of it
+func (r *repository) ReplaceMembers(ctx context.Context, team *schema.Team, userIDs []uuid.UUID) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		query := tx.Where("team_id = ?", team.ID)
+		if len(userIDs) > 0 {
+			query = tx.Where("team_id = ? OR (team_set_id = ? AND user_id IN ?)", team.ID, team.TeamSetID, userIDs)
+		}
+		if err := query.Delete(&schema.TeamMember{}).Error; err != nil {
+			return err
+		}
+		if len(userIDs) == 0 {
+			return nil
+		}
+
+		members := make([]schema.TeamMember, len(userIDs))
+		for i, id := range userIDs {
+			members[i] = schema.TeamMember{TeamID: team.ID, TeamSetID: team.TeamSetID, UserID: id}
+		}
+		return tx.Omit("User", "Team").Create(&members).Error
+	})
+}
+
+func (r *repository) AddMember(ctx context.Context, member *schema.TeamMember) error {
+	return r.db.WithContext(ctx).Omit("User", "Team").Create(member).Error
+}
+
+func (r *repository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Where("team_id = ? AND user_id = ?", teamID, userID).Delete(&schema.TeamMember{})
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// CountEnrolled counts how many of the students have access to the course
+func (r *repository) CountEnrolled(ctx context.Context, courseID uuid.UUID, userIDs []uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Table("course_enrolls").
+		Where("course_id = ? AND user_id IN ? AND (expires_at IS NULL OR expires_at > ?)", courseID, userIDs, time.Now()).
+		Distinct("user_id").
+		Count(&count).Error
+	return count, err
+}
@@ -0,0 +1,239 @@
+package team
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/middleware"
+	"github.com/Stefanuswilfrid/course-backend/internal/response"
+	"github.com/gin-gonic/gin"
+)
+
+type RestController struct {
+	uc *UseCase
+}
+
+func NewRestController(engine *gin.Engine, uc *UseCase) {
+	controller := &RestController{uc: uc}
+
+	courseGroup := engine.Group("/v1/courses/:id/team-sets", middleware.Authenticate())
+	{
+		courseGroup.GET("", controller.GetSets())
+		courseGroup.POST("", controller.CreateSet())
+	}
+
+	setGroup := engine.Group("/v1/team-sets", middleware.Authenticate())
+	{
+		setGroup.PATCH("/:id", controller.UpdateSet())
+		setGroup.DELETE("/:id", controller.DeleteSet())
+		setGroup.GET("/:id/mine", middleware.RequireRole("student"), controller.GetMine())
+		setGroup.POST("/:id/teams", controller.CreateTeam())
+	}
+
+	teamGroup := engine.Group("/v1/teams", middleware.Authenticate())
+	{
+		teamGroup.DELETE("/:id", controller.DeleteTeam())
+		teamGroup.PUT("/:id/members", controller.SetMembers())
+		teamGroup.POST("/:id/join", middleware.RequireRole("student"), controller.Join())
+		teamGroup.POST("/:id/leave", middleware.RequireRole("student"), controller.Leave())
+	}
+}
+
+func (c *RestController) GetSets() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req CourseIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetSets(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_TEAM_SETS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) CreateSet() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := CreateSetRequest{CourseID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.CreateSet(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_TEAM_SET_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) UpdateSet() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req UpdateSetRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.UpdateSet(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "UPDATE_TEAM_SET_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) DeleteSet() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SetIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.DeleteSet(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_TEAM_SET_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) GetMine() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SetIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.GetMine(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "GET_TEAM_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) CreateTeam() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		req := CreateTeamRequest{SetID: ctx.Param("id")}
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.CreateTeam(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "CREATE_TEAM_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) DeleteTeam() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req TeamIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.DeleteTeam(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "DELETE_TEAM_SUCCESS", nil).Send(ctx)
+	}
+}
+
+func (c *RestController) SetMembers() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req SetMembersRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := ctx.ShouldBindJSON(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.SetMembers(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "SET_TEAM_MEMBERS_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Join() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req TeamIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		res, err := c.uc.Join(ctx, &req)
+		if err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusCreated, "JOIN_TEAM_SUCCESS", res).Send(ctx)
+	}
+}
+
+func (c *RestController) Leave() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		var req TeamIDRequest
+		if err := ctx.ShouldBindUri(&req); err != nil {
+			err2 := apierror.ErrValidation.Build()
+			response.NewRestResponse(apierror.GetHttpStatus(err2), err2.Error(), err.Error()).Send(ctx)
+			return
+		}
+
+		if err := c.uc.Leave(ctx, &req); err != nil {
+			response.NewRestResponse(apierror.GetHttpStatus(err), err.Error(), apierror.GetPayload(err)).Send(ctx)
+			return
+		}
+
+		response.NewRestResponse(http.StatusOK, "LEAVE_TEAM_SUCCESS", nil).Send(ctx)
+	}
+}
@@ -0,0 +1,490 @@
+package team
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/Stefanuswilfrid/course-backend/internal/testutil"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gorm.io/gorm"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) CreateSet(ctx context.Context, set *schema.TeamSet) error {
+	args := m.Called(ctx, set)
+	return args.Error(0)
+}
+
+func (m *MockRepository) UpdateSet(ctx context.Context, set *schema.TeamSet) error {
+	args := m.Called(ctx, set)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteSet(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetSet(ctx context.Context, id uuid.UUID) (*schema.TeamSet, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.TeamSet), args.Error(1)
+}
+
+func (m *MockRepository) GetSets(ctx context.Context, courseID uuid.UUID) ([]schema.TeamSet, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.TeamSet), args.Error(1)
+}
+
+func (m *MockRepository) IsSetInUse(ctx context.Context, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) CreateTeam(ctx context.Context, team *schema.Team) error {
+	args := m.Called(ctx, team)
+	return args.Error(0)
+}
+
+func (m *MockRepository) DeleteTeam(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetTeam(ctx context.Context, id uuid.UUID) (*schema.Team, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Team), args.Error(1)
+}
+
+func (m *MockRepository) GetMemberTeam(ctx context.Context, teamSetID, userID uuid.UUID) (*schema.Team, error) {
+	args := m.Called(ctx, teamSetID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.Team), args.Error(1)
+}
+
+func (m *MockRepository) HasSubmissions(ctx context.Context, teamID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, teamID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockRepository) ReplaceMembers(ctx context.Context, team *schema.Team, userIDs []uuid.UUID) error {
+	args := m.Called(ctx, team, userIDs)
+	return args.Error(0)
+}
+
+func (m *MockRepository) AddMember(ctx context.Context, member *schema.TeamMember) error {
+	args := m.Called(ctx, member)
+	return args.Error(0)
+}
+
+func (m *MockRepository) RemoveMember(ctx context.Context, teamID, userID uuid.UUID) error {
+	args := m.Called(ctx, teamID, userID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) CountEnrolled(ctx context.Context, courseID uuid.UUID, userIDs []uuid.UUID) (int64, error) {
+	args := m.Called(ctx, courseID, userIDs)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type TeamUseCaseTestSuite struct {
+	suite.Suite
+	repo       *MockRepository
+	courseRepo *MockCourseRepository
+	enrollRepo *MockEnrollRepository
+	useCase    *UseCase
+}
+
+func (suite *TeamUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, nil, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, enrollUc)
+}
+
+// setOf stores a team set of a course taught by the instructor
+func (suite *TeamUseCaseTestSuite) setOf(instructorID uuid.UUID, selfSelect bool, maxSize *int) *schema.TeamSet {
+	set := &schema.TeamSet{ID: uuid.New(), CourseID: uuid.New(), Name: "Projects", SelfSelect: selfSelect, MaxSize: maxSize}
+	suite.repo.On("GetSet", mock.Anything, set.ID).Return(set, nil)
+	suite.courseRepo.On("GetByID", mock.Anything, set.CourseID).Return(schema.Course{ID: set.CourseID, InstructorID: instructorID}, nil)
+	return set
+}
+
+// studentOf returns the context of a student enrolled in the course
+func (suite *TeamUseCaseTestSuite) studentOf(courseID uuid.UUID) (context.Context, uuid.UUID) {
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	suite.enrollRepo.On("GetActiveEnrollment", ctx, userID, courseID).Return(&schema.CourseEnroll{}, nil)
+	return ctx, userID
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateSet_NotInstructor() {
+	courseID := uuid.New()
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleInstructor)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, InstructorID: uuid.New()}, nil)
+
+	_, err := suite.useCase.CreateSet(ctx, &CreateSetRequest{CourseID: courseID.String(), Name: "Projects"})
+
+	assert.Equal(suite.T(), http.StatusForbidden, apierror.GetHttpStatus(err))
+	suite.repo.AssertNotCalled(suite.T(), "CreateSet", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateTeam_InstructorAddsEnrolledStudents() {
+	instructorID := uuid.New()
+	set := suite.setOf(instructorID, false, nil)
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+	ann, bob := uuid.New(), uuid.New()
+
+	suite.repo.On("CountEnrolled", ctx, set.CourseID, []uuid.UUID{ann, bob}).Return(int64(2), nil)
+	suite.repo.On("CreateTeam", ctx, mock.Anything).Return(nil)
+
+	team, err := suite.useCase.CreateTeam(ctx, &CreateTeamRequest{
+		SetID:     set.ID.String(),
+		Name:      "Team A",
+		MemberIDs: []string{ann.String(), bob.String(), ann.String()},
+	})
+
+	assert.NoError(suite.T(), err)
+	assert.Len(suite.T(), team.Members, 2)
+	assert.Equal(suite.T(), set.ID, team.Members[1].TeamSetID)
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateTeam_MembersNotEnrolled() {
+	instructorID := uuid.New()
+	set := suite.setOf(instructorID, false, nil)
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+	outsider := uuid.New()
+
+	suite.repo.On("CountEnrolled", ctx, set.CourseID, []uuid.UUID{outsider}).Return(int64(0), nil)
+
+	_, err := suite.useCase.CreateTeam(ctx, &CreateTeamRequest{SetID: set.ID.String(), Name: "Team A", MemberIDs: []string{outsider.String()}})
+
+	assert.Equal(suite.T(), ErrInvalidMembers.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "CreateTeam", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateTeam_StudentStartsOwnTeam() {
+	set := suite.setOf(uuid.New(), true, nil)
+	ctx, userID := suite.studentOf(set.CourseID)
+
+	suite.repo.On("GetMemberTeam", ctx, set.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("CreateTeam", ctx, mock.Anything).Return(nil)
+
+	team, err := suite.useCase.CreateTeam(ctx, &CreateTeamRequest{SetID: set.ID.String(), Name: "Night owls"})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, team.CreatedBy)
+	assert.Len(suite.T(), team.Members, 1)
+	assert.Equal(suite.T(), userID, team.Members[0].UserID)
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateTeam_StudentInAssignedSet() {
+	set := suite.setOf(uuid.New(), false, nil)
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+
+	_, err := suite.useCase.CreateTeam(ctx, &CreateTeamRequest{SetID: set.ID.String(), Name: "Night owls"})
+
+	assert.Equal(suite.T(), ErrSelfSelectDisabled.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "CreateTeam", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestCreateTeam_StudentAlreadyInTeam() {
+	set := suite.setOf(uuid.New(), true, nil)
+	ctx, userID := suite.studentOf(set.CourseID)
+
+	suite.repo.On("GetMemberTeam", ctx, set.ID, userID).Return(&schema.Team{ID: uuid.New()}, nil)
+
+	_, err := suite.useCase.CreateTeam(ctx, &CreateTeamRequest{SetID: set.ID.String(), Name: "Night owls"})
+
+	assert.Equal(suite.T(), ErrAlreadyInTeam.Build().Error(), err.Error())
+}
+
+func (suite *TeamUseCaseTestSuite) TestJoin() {
+	set := suite.setOf(uuid.New(), true, testutil.Ptr(3))
+	ctx, userID := suite.studentOf(set.CourseID)
+	team := &schema.Team{ID: uuid.New(), TeamSetID: set.ID, Members: []schema.TeamMember{{UserID: uuid.New()}}}
+
+	suite.repo.On("GetTeam", ctx, team.ID).Return(team, nil)
+	suite.repo.On("GetMemberTeam", ctx, set.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("HasSubmissions", ctx, team.ID).Return(false, nil)
+	suite.repo.On("AddMember", ctx, mock.Anything).Return(nil)
+
+	member, err := suite.useCase.Join(ctx, &TeamIDRequest{ID: team.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, member.UserID)
+	assert.Equal(suite.T(), set.ID, member.TeamSetID)
+}
+
+func (suite *TeamUseCaseTestSuite) TestJoin_TeamFull() {
+	set := suite.setOf(uuid.New(), true, testutil.Ptr(2))
+	ctx, userID := suite.studentOf(set.CourseID)
+	team := &schema.Team{ID: uuid.New(), TeamSetID: set.ID, Members: []schema.TeamMember{{UserID: uuid.New()}, {UserID: uuid.New()}}}
+
+	suite.repo.On("GetTeam", ctx, team.ID).Return(team, nil)
+	suite.repo.On("GetMemberTeam", ctx, set.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := suite.useCase.Join(ctx, &TeamIDRequest{ID: team.ID.String()})
+
+	assert.Equal(suite.T(), ErrTeamFull.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "AddMember", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestJoin_TeamHandedIn() {
+	set := suite.setOf(uuid.New(), true, nil)
+	ctx, userID := suite.studentOf(set.CourseID)
+	team := &schema.Team{ID: uuid.New(), TeamSetID: set.ID, Members: []schema.TeamMember{{UserID: uuid.New()}}}
+
+	suite.repo.On("GetTeam", ctx, team.ID).Return(team, nil)
+	suite.repo.On("GetMemberTeam", ctx, set.ID, userID).Return(nil, gorm.ErrRecordNotFound)
+	suite.repo.On("HasSubmissions", ctx, team.ID).Return(true, nil)
+
+	_, err := suite.useCase.Join(ctx, &TeamIDRequest{ID: team.ID.String()})
+
+	assert.Equal(suite.T(), ErrTeamLocked.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "AddMember", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestLeave_LastMemberRemovesTeam() {
+	set := suite.setOf(uuid.New(), true, nil)
+	userID := uuid.New()
+	ctx := testutil.UserCtx(userID, schema.RoleStudent)
+	team := &schema.Team{ID: uuid.New(), TeamSetID: set.ID, Members: []schema.TeamMember{{UserID: userID}}}
+
+	suite.repo.On("GetTeam", ctx, team.ID).Return(team, nil)
+	suite.repo.On("HasSubmissions", ctx, team.ID).Return(false, nil)
+	suite.repo.On("DeleteTeam", ctx, team.ID).Return(nil)
+
+	err := suite.useCase.Leave(ctx, &TeamIDRequest{ID: team.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	suite.repo.AssertNotCalled(suite.T(), "RemoveMember", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestLeave_NotMember() {
+	set := suite.setOf(uuid.New(), true, nil)
+	ctx := testutil.UserCtx(uuid.New(), schema.RoleStudent)
+	team := &schema.Team{ID: uuid.New(), TeamSetID: set.ID, Members: []schema.TeamMember{{UserID: uuid.New()}}}
+
+	suite.repo.On("GetTeam", ctx, team.ID).Return(team, nil)
+
+	err := suite.useCase.Leave(ctx, &TeamIDRequest{ID: team.ID.String()})
+
+	assert.Equal(suite.T(), ErrNotTeamMember.Build().Error(), err.Error())
+}
+
+func (suite *TeamUseCaseTestSuite) TestDeleteSet_UsedByAssignment() {
+	instructorID := uuid.New()
+	set := suite.setOf(instructorID, false, nil)
+	ctx := testutil.UserCtx(instructorID, schema.RoleInstructor)
+
+	suite.repo.On("IsSetInUse", ctx, set.ID).Return(true, nil)
+
+	err := suite.useCase.DeleteSet(ctx, &SetIDRequest{ID: set.ID.String()})
+
+	assert.Equal(suite.T(), ErrTeamSetInUse.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "DeleteSet", mock.Anything, mock.Anything)
+}
+
+func (suite *TeamUseCaseTestSuite) TestGetSets_StudentsSeeNamesOnly() {
+	courseID := uuid.New()
+	ctx, _ := suite.studentOf(courseID)
+	suite.courseRepo.On("GetByID", ctx, courseID).Return(schema.Course{ID: courseID, InstructorID: uuid.New()}, nil)
+	classmate := &schema.User{ID: uuid.New(), Name: "Ann", Email: "ann@example.com"}
+	sets := []schema.TeamSet{{ID: uuid.New(), CourseID: courseID, Teams: []schema.Team{
+		{ID: uuid.New(), Members: []schema.TeamMember{{UserID: classmate.ID, User: classmate}}},
+	}}}
+
+	suite.repo.On("GetSets", ctx, courseID).Return(sets, nil)
+
+	res, err := suite.useCase.GetSets(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.NoError(suite.T(), err)
+	member := res[0].Teams[0].Members[0].User
+	assert.Equal(suite.T(), "Ann", member.Name)
+	assert.Empty(suite.T(), member.Email)
+}
+
+func TestTeamUseCaseTestSuite(t *testing.T) {
+	suite.Run(t, new(TeamUseCaseTestSuite))
+}
@@ -0,0 +1,500 @@
+package team
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+type UseCase struct {
+	repo     Repository
+	courseUc *course.UseCase
+	enrollUc *courseenroll.UseCase
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase) *UseCase {
+	return &UseCase{
+		repo:     repo,
+		courseUc: courseUc,
+		enrollUc: enrollUc,
+	}
+}
+
+func currentUser(ctx context.Context) (uuid.UUID, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return uuid.Nil, apierror.ErrTokenInvalid.Build()
+	}
+	return userID, nil
+}
+
+func (uc *UseCase) getSet(ctx context.Context, idStr string) (*schema.TeamSet, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	set, err := uc.repo.GetSet(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamSetNotFound.Build()
+		}
+		log.Println("Error getting team set: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return set, nil
+}
+
+func (uc *UseCase) getManagedSet(ctx context.Context, idStr string) (*schema.TeamSet, error) {
+	set, err := uc.getSet(ctx, idStr)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.courseUc.GetManagedCourse(ctx, set.CourseID); err != nil {
+		return nil, err
+	}
+	return set, nil
+}
+
+// getTeam returns the team with its members and the set it belongs to
+func (uc *UseCase) getTeam(ctx context.Context, idStr string) (*schema.Team, *schema.TeamSet, error) {
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, nil, apierror.ErrInvalidParamId.Build()
+	}
+	team, err := uc.repo.GetTeam(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrTeamNotFound.Build()
+		}
+		log.Println("Error getting team: ", err)
+		return nil, nil, apierror.ErrInternalServer.Build()
+	}
+	set, err := uc.getSet(ctx, team.TeamSetID.String())
+	if err != nil {
+		return nil, nil, err
+	}
+	return team, set, nil
+}
+
+func (uc *UseCase) checkEnrolled(ctx context.Context, userID, courseID uuid.UUID) error {
+	enrolled, err := uc.enrollUc.CheckEnrollment(ctx, userID, courseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	if !enrolled {
+		return courseenroll.ErrNotEnrolled.Build()
+	}
+	return nil
+}
+
+// checkLocked keeps students from changing a team once it handed in, their grade would go to whoever joined
+// after the work was done
+func (uc *UseCase) checkLocked(ctx context.Context, teamID uuid.UUID) error {
+	submitted, err := uc.repo.HasSubmissions(ctx, teamID)
+	if err != nil {
+		log.Println("Error checking team submissions: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	if submitted {
+		return ErrTeamLocked.Build()
+	}
+	return nil
+}
+
+// memberIDs parses the students of a request, each of them has to be enrolled in the course
+func (uc *UseCase) memberIDs(ctx context.Context, courseID uuid.UUID, ids []string) ([]uuid.UUID, error) {
+	userIDs := make([]uuid.UUID, 0, len(ids))
+	for _, s := range ids {
+		id, err := uuid.Parse(s)
+		if err != nil {
+			return nil, apierror.ErrInvalidParamId.Build()
+		}
+		if !slices.Contains(userIDs, id) {
+			userIDs = append(userIDs, id)
+		}
+	}
+	if len(userIDs) == 0 {
+		return userIDs, nil
+	}
+
+	enrolled, err := uc.repo.CountEnrolled(ctx, courseID, userIDs)
+	if err != nil {
+		log.Println("Error counting enrolled students: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if enrolled != int64(len(userIDs)) {
+		return nil, ErrInvalidMembers.WithPayload(map[string]any{
+			"reason": "every member has to be enrolled in the course",
+		}).Build()
+	}
+	return userIDs, nil
+}
+
+func (uc *UseCase) CreateSet(ctx context.Context, req *CreateSetRequest) (*schema.TeamSet, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	courseObj, err := uc.courseUc.GetManagedCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	set := &schema.TeamSet{
+		ID:         id,
+		CourseID:   courseObj.ID,
+		Name:       req.Name,
+		SelfSelect: req.SelfSelect,
+		MaxSize:    req.MaxSize,
+		Teams:      []schema.Team{},
+	}
+	if err := uc.repo.CreateSet(ctx, set); err != nil {
+		log.Println("Error creating team set: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return set, nil
+}
+
+// GetSets lists the team sets of the course with their teams for the instructor and the enrolled students.
+// Students only see the names of the other students
+func (uc *UseCase) GetSets(ctx context.Context, req *CourseIDRequest) ([]schema.TeamSet, error) {
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+	courseObj, err := uc.courseUc.GetCourse(ctx, courseID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	instructor := course.IsManager(ctx, courseObj)
+	if !instructor {
+		if err := uc.checkEnrolled(ctx, userID, courseObj.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	sets, err := uc.repo.GetSets(ctx, courseObj.ID)
+	if err != nil {
+		log.Println("Error getting team sets: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if !instructor {
+		for i := range sets {
+			for j := range sets[i].Teams {
+				hideContacts(&sets[i].Teams[j])
+			}
+		}
+	}
+	return sets, nil
+}
+
+func (uc *UseCase) UpdateSet(ctx context.Context, req *UpdateSetRequest) (*schema.TeamSet, error) {
+	set, err := uc.getManagedSet(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		set.Name = *req.Name
+	}
+	if req.SelfSelect != nil {
+		set.SelfSelect = *req.SelfSelect
+	}
+	if req.ClearMaxSize {
+		set.MaxSize = nil
+	}
+	if req.MaxSize != nil {
+		set.MaxSize = req.MaxSize
+	}
+	if err := uc.repo.UpdateSet(ctx, set); err != nil {
+		log.Println("Error updating team set: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return set, nil
+}
+
+// DeleteSet removes the team set with its teams, as long as no assignment is handed in by them
+func (uc *UseCase) DeleteSet(ctx context.Context, req *SetIDRequest) error {
+	set, err := uc.getManagedSet(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+
+	inUse, err := uc.repo.IsSetInUse(ctx, set.ID)
+	if err != nil {
+		log.Println("Error checking team set assignments: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	if inUse {
+		return ErrTeamSetInUse.Build()
+	}
+
+	if err := uc.repo.DeleteSet(ctx, set.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTeamSetNotFound.Build()
+		}
+		log.Println("Error deleting team set: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+// GetMine returns the team of the current student within the set
+func (uc *UseCase) GetMine(ctx context.Context, req *SetIDRequest) (*schema.Team, error) {
+	set, err := uc.getSet(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mine, err := uc.repo.GetMemberTeam(ctx, set.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrTeamNotFound.Build()
+		}
+		log.Println("Error getting team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	team, err := uc.repo.GetTeam(ctx, mine.ID)
+	if err != nil {
+		log.Println("Error getting team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	hideContacts(team)
+	return team, nil
+}
+
+// CreateTeam starts a team in the set. The instructor may fill it with students right away, students can only
+// start teams in self-selected sets and become the first member of their team
+func (uc *UseCase) CreateTeam(ctx context.Context, req *CreateTeamRequest) (*schema.Team, error) {
+	set, err := uc.getSet(ctx, req.SetID)
+	if err != nil {
+		return nil, err
+	}
+	courseObj, err := uc.courseUc.GetCourse(ctx, set.CourseID)
+	if err != nil {
+		return nil, err
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	team := &schema.Team{
+		ID:        id,
+		TeamSetID: set.ID,
+		Name:      req.Name,
+		CreatedBy: userID,
+	}
+
+	var userIDs []uuid.UUID
+	if course.IsManager(ctx, courseObj) {
+		if userIDs, err = uc.memberIDs(ctx, courseObj.ID, req.MemberIDs); err != nil {
+			return nil, err
+		}
+	} else {
+		if !set.SelfSelect {
+			return nil, ErrSelfSelectDisabled.Build()
+		}
+		if len(req.MemberIDs) > 0 {
+			return nil, ErrInvalidMembers.WithPayload(map[string]any{
+				"reason": "students join a team on their own",
+			}).Build()
+		}
+		if err := uc.checkEnrolled(ctx, userID, courseObj.ID); err != nil {
+			return nil, err
+		}
+		if _, err := uc.repo.GetMemberTeam(ctx, set.ID, userID); err == nil {
+			return nil, ErrAlreadyInTeam.Build()
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Println("Error getting team: ", err)
+			return nil, apierror.ErrInternalServer.Build()
+		}
+		userIDs = []uuid.UUID{userID}
+	}
+
+	now := time.Now()
+	for _, memberID := range userIDs {
+		team.Members = append(team.Members, schema.TeamMember{
+			TeamID:    team.ID,
+			TeamSetID: set.ID,
+			UserID:    memberID,
+			JoinedAt:  now,
+		})
+	}
+	if err := uc.repo.CreateTeam(ctx, team); err != nil {
+		log.Println("Error creating team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return team, nil
+}
+
+// DeleteTeam breaks up a team that has not handed in yet
+func (uc *UseCase) DeleteTeam(ctx context.Context, req *TeamIDRequest) error {
+	team, set, err := uc.getTeam(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if _, err := uc.courseUc.GetManagedCourse(ctx, set.CourseID); err != nil {
+		return err
+	}
+	if err := uc.checkLocked(ctx, team.ID); err != nil {
+		return err
+	}
+
+	if err := uc.repo.DeleteTeam(ctx, team.ID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrTeamNotFound.Build()
+		}
+		log.Println("Error deleting team: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+// SetMembers lets the instructor put the team together, also after it handed in. Submissions keep the
+// members they were handed in by, so only later attempts count for the new members
+func (uc *UseCase) SetMembers(ctx context.Context, req *SetMembersRequest) (*schema.Team, error) {
+	team, set, err := uc.getTeam(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := uc.courseUc.GetManagedCourse(ctx, set.CourseID); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := uc.memberIDs(ctx, set.CourseID, req.UserIDs)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.repo.ReplaceMembers(ctx, team, userIDs); err != nil {
+		log.Println("Error replacing team members: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	team, err = uc.repo.GetTeam(ctx, team.ID)
+	if err != nil {
+		log.Println("Error getting team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return team, nil
+}
+
+// Join puts the current student in a team of a self-selected set while it has room and has not handed in
+func (uc *UseCase) Join(ctx context.Context, req *TeamIDRequest) (*schema.TeamMember, error) {
+	team, set, err := uc.getTeam(ctx, req.ID)
+	if err != nil {
+		return nil, err
+	}
+	if !set.SelfSelect {
+		return nil, ErrSelfSelectDisabled.Build()
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := uc.checkEnrolled(ctx, userID, set.CourseID); err != nil {
+		return nil, err
+	}
+
+	if _, err := uc.repo.GetMemberTeam(ctx, set.ID, userID); err == nil {
+		return nil, ErrAlreadyInTeam.Build()
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Println("Error getting team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if set.MaxSize != nil && len(team.Members) >= *set.MaxSize {
+		return nil, ErrTeamFull.Build()
+	}
+	if err := uc.checkLocked(ctx, team.ID); err != nil {
+		return nil, err
+	}
+
+	member := &schema.TeamMember{
+		TeamID:    team.ID,
+		TeamSetID: set.ID,
+		UserID:    userID,
+		JoinedAt:  time.Now(),
+	}
+	if err := uc.repo.AddMember(ctx, member); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrAlreadyInTeam.Build()
+		}
+		log.Println("Error joining team: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	return member, nil
+}
+
+// Leave takes the current student out of their team of a self-selected set, the team is removed once its last
+// member leaves
+func (uc *UseCase) Leave(ctx context.Context, req *TeamIDRequest) error {
+	team, set, err := uc.getTeam(ctx, req.ID)
+	if err != nil {
+		return err
+	}
+	if !set.SelfSelect {
+		return ErrSelfSelectDisabled.Build()
+	}
+	userID, err := currentUser(ctx)
+	if err != nil {
+		return err
+	}
+	if !slices.ContainsFunc(team.Members, func(m schema.TeamMember) bool { return m.UserID == userID }) {
+		return ErrNotTeamMember.Build()
+	}
+	if err := uc.checkLocked(ctx, team.ID); err != nil {
+		return err
+	}
+
+	if len(team.Members) == 1 {
+		err = uc.repo.DeleteTeam(ctx, team.ID)
+	} else {
+		err = uc.repo.RemoveMember(ctx, team.ID, userID)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotTeamMember.Build()
+		}
+		log.Println("Error leaving team: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+	return nil
+}
+
+// hideContacts leaves only the names of the members, students see who is in a team but not how to reach them
+func hideContacts(team *schema.Team) {
+	for i, m := range team.Members {
+		if m.User != nil {
+			team.Members[i].User = &schema.User{ID: m.User.ID, Name: m.User.Name, ImageURL: m.User.ImageURL}
+		}
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"mime/multipart"
 	"testing"
+	"time"
 
 	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
 	"github.com/Stefanuswilfrid/course-backend/internal/fileutil"
@@ -62,6 +63,16 @@ func (m *MockFileUploader) UploadFile(key string, fileHeader *multipart.FileHead
 	return args.String(0), args.Error(1)
 }
 
+func (m *MockFileUploader) UploadBytes(key string, content []byte, contentType string) (string, error) {
+	args := m.Called(key, content, contentType)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockFileUploader) PresignURL(fileURL string, expiry time.Duration) (string, error) {
+	args := m.Called(fileURL, expiry)
+	return args.String(0), args.Error(1)
+}
+
 type UseCaseTestSuite struct {
 	suite.Suite
 	repo     *MockRepository
@@ -21,6 +21,8 @@ type IRepository interface {
 
 	TopUpSuccess(transactionID uuid.UUID) error
 	TransferByUserID(tx *gorm.DB, fromUserID, toUserID uuid.UUID, amount int64) error
+	DebitByUserID(tx *gorm.DB, userID uuid.UUID, amount int64) error
+	CreditByUserID(tx *gorm.DB, userID uuid.UUID, amount int64) error
 }
 
 type Repository struct {
@@ -152,3 +154,39 @@ func (r *Repository) TransferByUserID(tx *gorm.DB, fromUserID, toUserID uuid.UUI
 		return nil
 	})
 }
+
+// DebitByUserID charges the user wallet for platform fees such as subscriptions, nobody is credited
+func (r *Repository) DebitByUserID(tx *gorm.DB, userID uuid.UUID, amount int64) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	return tx.Transaction(func(tx *gorm.DB) error {
+		wallet, err := r.GetByUserID(tx, userID)
+		if err != nil {
+			return err
+		}
+
+		if wallet.Balance < amount {
+			return apierror.ErrInsufficientBalance.Build()
+		}
+		return tx.Model(wallet).Update("balance", gorm.Expr("balance - ?", amount)).Error
+	})
+}
+
+// CreditByUserID pays out to the user wallet from the platform, e.g. when escrowed money is released
+func (r *Repository) CreditByUserID(tx *gorm.DB, userID uuid.UUID, amount int64) error {
+	if tx == nil {
+		tx = r.db
+	}
+
+	res := tx.Model(&schema.Wallet{}).Where("user_id = ?", userID).
+		Update("balance", gorm.Expr("balance + ?", amount))
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
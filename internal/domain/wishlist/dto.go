@@ -0,0 +1,13 @@
+package wishlist
+
+type AddWishlistRequest struct {
+	CourseID string `json:"course_id" binding:"required,uuid"`
+}
+
+type GetWishlistRequest struct {
+	Currency string `form:"currency" binding:"omitempty,len=3"`
+}
+
+type CourseIDRequest struct {
+	CourseID string `uri:"courseId" binding:"required,uuid"`
+}
@@ -0,0 +1,21 @@
+package wishlist
+
+import (
+	"net/http"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+)
+
+var (
+	ErrAlreadyInWishlist = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusConflict).
+				WithMessage("COURSE_ALREADY_IN_WISHLIST")
+
+	ErrNotInWishlist = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusNotFound).
+				WithMessage("COURSE_NOT_IN_WISHLIST")
+
+	ErrAlreadyEnrolled = apierror.NewApiErrorBuilder().
+				WithHttpStatus(http.StatusBadRequest).
+				WithMessage("COURSE_ALREADY_ENROLLED")
+)
@@ -0,0 +1,93 @@
+package wishlist
+
+import (
+	"context"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Repository interface {
+	Add(ctx context.Context, item *schema.WishlistItem) error
+	Remove(ctx context.Context, userID, courseID uuid.UUID) error
+	GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.WishlistItem, error)
+	GetByCourseIDs(ctx context.Context, courseIDs []uuid.UUID, after *schema.WishlistItem, limit int) ([]schema.WishlistItem, error)
+	SetAlertPrice(ctx context.Context, item *schema.WishlistItem, price int64, currency string) (bool, error)
+}
+
+type repository struct {
+	db *gorm.DB
+}
+
+func NewRepository(db *gorm.DB) Repository {
+	return &repository{db: db}
+}
+
+// liveCourses keeps the wishlist items whose course has not been deleted
+func liveCourses(db *gorm.DB) *gorm.DB {
+	return db.Joins("JOIN courses ON courses.id = wishlist_items.course_id AND courses.deleted_at IS NULL")
+}
+
+func (r *repository) Add(ctx context.Context, item *schema.WishlistItem) error {
+	return r.db.WithContext(ctx).Omit("Course").Create(item).Error
+}
+
+func (r *repository) Remove(ctx context.Context, userID, courseID uuid.UUID) error {
+	tx := r.db.WithContext(ctx).Delete(&schema.WishlistItem{}, "user_id = ? AND course_id = ?", userID, courseID)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (r *repository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.WishlistItem, error) {
+	var items []schema.WishlistItem
+	err := r.db.WithContext(ctx).
+		Scopes(liveCourses).
+		Preload("Course.Category").
+		Preload("Course.Tags").
+		Where("wishlist_items.user_id = ?", userID).
+		Order("wishlist_items.created_at DESC").
+		Find(&items).Error
+	return items, err
+}
+
+// GetByCourseIDs returns a page of the wishlist items of the given courses, or of every course when courseIDs is
+// empty, leaving out students who already have access to the course. Items are ordered by course then student and
+// the page starts right after the given item, or at the beginning when after is nil
+func (r *repository) GetByCourseIDs(ctx context.Context, courseIDs []uuid.UUID, after *schema.WishlistItem, limit int) ([]schema.WishlistItem, error) {
+	var items []schema.WishlistItem
+
+	query := r.db.WithContext(ctx).
+		Scopes(liveCourses).
+		Preload("Course").
+		Where(`NOT EXISTS (SELECT 1 FROM course_enrolls ce WHERE ce.user_id = wishlist_items.user_id
+			AND ce.course_id = wishlist_items.course_id AND (ce.expires_at IS NULL OR ce.expires_at > ?))`, time.Now())
+	if len(courseIDs) > 0 {
+		query = query.Where("wishlist_items.course_id IN ?", courseIDs)
+	}
+	if after != nil {
+		query = query.Where("(wishlist_items.course_id, wishlist_items.user_id) > (?, ?)", after.CourseID, after.UserID)
+	}
+
+	err := query.Order("wishlist_items.course_id, wishlist_items.user_id").Limit(limit).Find(&items).Error
+	return items, err
+}
+
+// SetAlertPrice moves the wishlist item to the given price, so the next drop is measured from it. The item is only
+// moved while it still holds the price it was read with, false means another run moved it first
+func (r *repository) SetAlertPrice(ctx context.Context, item *schema.WishlistItem, price int64, currency string) (bool, error) {
+	tx := r.db.WithContext(ctx).Model(&schema.WishlistItem{}).
+		Where("user_id = ? AND course_id = ? AND alert_price = ? AND alert_currency = ?",
+			item.UserID, item.CourseID, item.AlertPrice, item.AlertCurrency).
+		Updates(map[string]any{"alert_price": price, "alert_currency": currency})
+	if tx.Error != nil {
+		return false, tx.Error
+	}
+	return tx.RowsAffected > 0, nil
+}
@@ -0,0 +1,304 @@
+package wishlist
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/apierror"
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/notification"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/user"
+	"github.com/Stefanuswilfrid/course-backend/internal/mailer"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+//go:embed price_drop_email_template.html
+var priceDropEmailTemplate string
+
+type UseCase struct {
+	repo             Repository
+	courseUc         *course.UseCase
+	enrollUc         *courseenroll.UseCase
+	userRepo         user.IRepository
+	notificationRepo notification.IRepository
+	mailDialer       config.IMailer
+}
+
+func NewUseCase(repo Repository, courseUc *course.UseCase, enrollUc *courseenroll.UseCase, userRepo user.IRepository,
+	notificationRepo notification.IRepository, mailDialer config.IMailer) *UseCase {
+	return &UseCase{
+		repo:             repo,
+		courseUc:         courseUc,
+		enrollUc:         enrollUc,
+		userRepo:         userRepo,
+		notificationRepo: notificationRepo,
+		mailDialer:       mailDialer,
+	}
+}
+
+// alertPageSize is how many wishlist items a price drop check loads at once
+const alertPageSize = 500
+
+// priceDrop is one wishlisted course that got cheaper since the student was last told about its price
+type priceDrop struct {
+	course   schema.Course
+	oldPrice int64
+}
+
+func (uc *UseCase) Get(ctx context.Context, req *GetWishlistRequest) ([]schema.WishlistItem, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	items, err := uc.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		log.Println("Error getting wishlist: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	courses := make([]schema.Course, len(items))
+	for i := range items {
+		courses[i] = items[i].Course
+	}
+	if err := uc.courseUc.ApplyPricing(ctx, courses, req.Currency); err != nil {
+		return nil, err
+	}
+	for i := range items {
+		items[i].Course = courses[i]
+	}
+
+	return items, nil
+}
+
+func (uc *UseCase) Add(ctx context.Context, req *AddWishlistRequest) (*schema.WishlistItem, error) {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return nil, apierror.ErrTokenInvalid.Build()
+	}
+
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return nil, apierror.ErrInvalidParamId.Build()
+	}
+
+	courseObj, err := uc.courseUc.GetByID(ctx, courseID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, course.ErrCourseNotFound.Build()
+		}
+		log.Println("Error getting course: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	purchased, err := uc.enrollUc.HasPurchased(ctx, userID, courseID)
+	if err != nil {
+		log.Println("Error checking enrollment: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+	if purchased {
+		return nil, ErrAlreadyEnrolled.Build()
+	}
+
+	courses := []schema.Course{courseObj}
+	if err := uc.courseUc.ApplyPricing(ctx, courses, ""); err != nil {
+		return nil, err
+	}
+
+	item := &schema.WishlistItem{
+		UserID:        userID,
+		CourseID:      courseID,
+		AlertPrice:    courses[0].Pricing.Price,
+		AlertCurrency: courses[0].Pricing.Currency,
+	}
+	if err := uc.repo.Add(ctx, item); err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return nil, ErrAlreadyInWishlist.Build()
+		}
+		log.Println("Error adding to wishlist: ", err)
+		return nil, apierror.ErrInternalServer.Build()
+	}
+
+	item.Course = courses[0]
+	return item, nil
+}
+
+func (uc *UseCase) Remove(ctx context.Context, req *CourseIDRequest) error {
+	userID, err := uuid.Parse(ctx.Value("user.id").(string))
+	if err != nil {
+		return apierror.ErrTokenInvalid.Build()
+	}
+
+	courseID, err := uuid.Parse(req.CourseID)
+	if err != nil {
+		return apierror.ErrInvalidParamId.Build()
+	}
+
+	if err := uc.repo.Remove(ctx, userID, courseID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotInWishlist.Build()
+		}
+		log.Println("Error removing from wishlist: ", err)
+		return apierror.ErrInternalServer.Build()
+	}
+
+	return nil
+}
+
+// CheckPriceDrops compares the current price of the given courses, or of every wishlisted course when courseIDs
+// is empty, with the price each student was last told about. Every student with a cheaper course gets a
+// notification per course and a single digest email, it returns how many students were alerted
+func (uc *UseCase) CheckPriceDrops(ctx context.Context, courseIDs []uuid.UUID) (int, error) {
+	drops := make(map[uuid.UUID][]priceDrop)
+
+	var after *schema.WishlistItem
+	for {
+		items, err := uc.repo.GetByCourseIDs(ctx, courseIDs, after, alertPageSize)
+		if err != nil {
+			return 0, err
+		}
+		if err := uc.collectDrops(ctx, items, drops); err != nil {
+			return 0, err
+		}
+		if len(items) < alertPageSize {
+			break
+		}
+		after = &items[len(items)-1]
+	}
+
+	for userID, userDrops := range drops {
+		for _, drop := range userDrops {
+			uc.notify(userID, "A course on your wishlist is cheaper!",
+				fmt.Sprintf("%s dropped from %s %d to %s %d", drop.course.Title,
+					drop.course.Pricing.Currency, drop.oldPrice, drop.course.Pricing.Currency, drop.course.Pricing.Price))
+		}
+		uc.sendDigest(userID, userDrops)
+	}
+
+	return len(drops), nil
+}
+
+// collectDrops records the current price on every item of the page whose price moved. A drop is only kept for the
+// items this call moved itself, so runs racing over the same course alert each student once
+func (uc *UseCase) collectDrops(ctx context.Context, items []schema.WishlistItem, drops map[uuid.UUID][]priceDrop) error {
+	if len(items) == 0 {
+		return nil
+	}
+
+	var courses []schema.Course
+	courseIndex := make(map[uuid.UUID]int)
+	for _, item := range items {
+		if _, ok := courseIndex[item.CourseID]; !ok {
+			courseIndex[item.CourseID] = len(courses)
+			courses = append(courses, item.Course)
+		}
+	}
+	if err := uc.courseUc.ApplyPricing(ctx, courses, ""); err != nil {
+		return err
+	}
+
+	for i := range items {
+		item := &items[i]
+		current := courses[courseIndex[item.CourseID]]
+		pricing := current.Pricing
+		if item.AlertCurrency == pricing.Currency && item.AlertPrice == pricing.Price {
+			continue
+		}
+
+		// Prices going up are recorded too, so the end of a sale rearms the alert for the next one
+		moved, err := uc.repo.SetAlertPrice(ctx, item, pricing.Price, pricing.Currency)
+		if err != nil {
+			return err
+		}
+		if moved && item.AlertCurrency == pricing.Currency && pricing.Price < item.AlertPrice {
+			drops[item.UserID] = append(drops[item.UserID], priceDrop{course: current, oldPrice: item.AlertPrice})
+		}
+	}
+
+	return nil
+}
+
+// StartAlertWorker runs CheckPriceDrops over every wishlisted course each interval until the context is done,
+// this is what picks up scheduled sales once they start
+func (uc *UseCase) StartAlertWorker(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := uc.CheckPriceDrops(ctx, nil); err != nil {
+			log.Println("Error checking wishlist price drops: ", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (uc *UseCase) notify(userID uuid.UUID, title, detail string) {
+	notificationID, err := uuid.NewV7()
+	if err != nil {
+		return
+	}
+
+	notif := schema.Notification{
+		ID:     notificationID,
+		UserID: userID,
+		Title:  title,
+		Detail: detail,
+	}
+	if err := uc.notificationRepo.Create(&notif); err != nil {
+		log.Println("Error creating notification: ", err)
+	}
+}
+
+func (uc *UseCase) sendDigest(userID uuid.UUID, drops []priceDrop) {
+	student, err := uc.userRepo.GetByID(userID)
+	if err != nil {
+		log.Println("Error getting user by ID: ", err)
+		return
+	}
+
+	courses := make([]map[string]any, len(drops))
+	for i, drop := range drops {
+		saleEndsAt := ""
+		if drop.course.Pricing.SaleEndsAt != nil {
+			saleEndsAt = drop.course.Pricing.SaleEndsAt.Format("2 January 2006")
+		}
+		courses[i] = map[string]any{
+			"title":        drop.course.Title,
+			"url":          config.Env.FrontendUrl + "/courses/" + drop.course.ID.String(),
+			"currency":     drop.course.Pricing.Currency,
+			"old_price":    drop.oldPrice,
+			"new_price":    drop.course.Pricing.Price,
+			"sale_ends_at": saleEndsAt,
+		}
+	}
+
+	emailData := map[string]any{
+		"student_name": student.Name,
+		"courses":      courses,
+	}
+
+	mail, err := mailer.GenerateMail(student.Email, "Prices dropped on your wishlist!", priceDropEmailTemplate, emailData)
+	if err != nil {
+		log.Println("Error generating email: ", err)
+		return
+	}
+
+	if err = uc.mailDialer.DialAndSend(mail); err != nil {
+		log.Println("Error sending email: ", err)
+	}
+}
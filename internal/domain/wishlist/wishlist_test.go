@@ -0,0 +1,537 @@
+package wishlist
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/config"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/course"
+	"github.com/Stefanuswilfrid/course-backend/internal/domain/courseenroll"
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/gomail.v2"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+type MockRepository struct {
+	mock.Mock
+}
+
+func (m *MockRepository) Add(ctx context.Context, item *schema.WishlistItem) error {
+	args := m.Called(ctx, item)
+	return args.Error(0)
+}
+
+func (m *MockRepository) Remove(ctx context.Context, userID, courseID uuid.UUID) error {
+	args := m.Called(ctx, userID, courseID)
+	return args.Error(0)
+}
+
+func (m *MockRepository) GetByUserID(ctx context.Context, userID uuid.UUID) ([]schema.WishlistItem, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.WishlistItem), args.Error(1)
+}
+
+func (m *MockRepository) GetByCourseIDs(ctx context.Context, courseIDs []uuid.UUID, after *schema.WishlistItem, limit int) ([]schema.WishlistItem, error) {
+	args := m.Called(ctx, courseIDs, after, limit)
+	return args.Get(0).([]schema.WishlistItem), args.Error(1)
+}
+
+func (m *MockRepository) SetAlertPrice(ctx context.Context, item *schema.WishlistItem, price int64, currency string) (bool, error) {
+	args := m.Called(ctx, item, price, currency)
+	return args.Bool(0), args.Error(1)
+}
+
+type MockCourseRepository struct {
+	mock.Mock
+}
+
+func (m *MockCourseRepository) GetAll(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetByID(ctx context.Context, id uuid.UUID) (schema.Course, error) {
+	args := m.Called(ctx, id)
+	return args.Get(0).(schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetRating(ctx context.Context, courseID uuid.UUID) (float32, int64, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).(float32), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockCourseRepository) Create(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Update(ctx context.Context, course *schema.Course) error {
+	args := m.Called(ctx, course)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) FindByInstructorID(ctx context.Context, instructorID uuid.UUID, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, instructorID, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) FindByPopularity(ctx context.Context, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetUserCourseProgress(ctx context.Context, courseID, userID uuid.UUID) (float64, error) {
+	args := m.Called(ctx, courseID, userID)
+	return args.Get(0).(float64), args.Error(1)
+}
+
+func (m *MockCourseRepository) SearchByTitle(ctx context.Context, title string, page, pageSize int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, title, page, pageSize)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) DynamicFilterCourses(ctx context.Context, filter course.CourseFilter, page, limit int) ([]schema.Course, int, error) {
+	args := m.Called(ctx, filter, page, limit)
+	return args.Get(0).([]schema.Course), args.Int(1), args.Error(2)
+}
+
+func (m *MockCourseRepository) GetPrerequisites(ctx context.Context, courseID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockCourseRepository) SetPrerequisites(ctx context.Context, courseID uuid.UUID, prerequisiteIDs []uuid.UUID) error {
+	args := m.Called(ctx, courseID, prerequisiteIDs)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CategoryExists(ctx context.Context, categoryID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, categoryID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) SaveTags(ctx context.Context, tags []schema.Tag) ([]schema.Tag, error) {
+	args := m.Called(ctx, tags)
+	return args.Get(0).([]schema.Tag), args.Error(1)
+}
+
+func (m *MockCourseRepository) ReplaceTags(ctx context.Context, courseID uuid.UUID, tags []schema.Tag) error {
+	args := m.Called(ctx, courseID, tags)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) CreateSale(ctx context.Context, sale *schema.CourseSale) error {
+	args := m.Called(ctx, sale)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) GetSales(ctx context.Context, courseID uuid.UUID) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+func (m *MockCourseRepository) DeleteSale(ctx context.Context, courseID, saleID uuid.UUID) error {
+	args := m.Called(ctx, courseID, saleID)
+	return args.Error(0)
+}
+
+func (m *MockCourseRepository) HasOverlappingSale(ctx context.Context, courseID uuid.UUID, startsAt, endsAt time.Time) (bool, error) {
+	args := m.Called(ctx, courseID, startsAt, endsAt)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockCourseRepository) GetActiveSales(ctx context.Context, courseIDs []uuid.UUID, at time.Time) ([]schema.CourseSale, error) {
+	args := m.Called(ctx, courseIDs, at)
+	return args.Get(0).([]schema.CourseSale), args.Error(1)
+}
+
+type MockEnrollRepository struct {
+	mock.Mock
+}
+
+func (m *MockEnrollRepository) Create(ctx context.Context, enroll *schema.CourseEnroll) error {
+	args := m.Called(ctx, enroll)
+	return args.Error(0)
+}
+
+func (m *MockEnrollRepository) GetUsersByCourseID(ctx context.Context, courseID uuid.UUID) ([]schema.User, error) {
+	args := m.Called(ctx, courseID)
+	return args.Get(0).([]schema.User), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetCoursesByUserID(ctx context.Context, userID uuid.UUID) ([]schema.Course, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]schema.Course), args.Error(1)
+}
+
+func (m *MockEnrollRepository) IsEnrolled(ctx context.Context, userID, courseID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, courseID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetActiveEnrollment(ctx context.Context, userID, courseID uuid.UUID) (*schema.CourseEnroll, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*schema.CourseEnroll), args.Error(1)
+}
+
+func (m *MockEnrollRepository) HasActiveSubscription(ctx context.Context, userID uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetAccessStart(ctx context.Context, userID, courseID uuid.UUID) (*time.Time, error) {
+	args := m.Called(ctx, userID, courseID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*time.Time), args.Error(1)
+}
+
+func (m *MockEnrollRepository) GetRoster(ctx context.Context, courseID uuid.UUID, search string, page, limit int) ([]courseenroll.RosterEntry, int64, error) {
+	args := m.Called(ctx, courseID, search, page, limit)
+	return args.Get(0).([]courseenroll.RosterEntry), args.Get(1).(int64), args.Error(2)
+}
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Create(notification *schema.Notification) error {
+	args := m.Called(notification)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) GetByUserID(userID uuid.UUID, limit, offset int) ([]*schema.Notification, int64, error) {
+	args := m.Called(userID, limit, offset)
+	return args.Get(0).([]*schema.Notification), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockNotificationRepository) GetUnreadCount(userID uuid.UUID) (int64, error) {
+	args := m.Called(userID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockNotificationRepository) UpdateRead(notificationID uuid.UUID) error {
+	args := m.Called(notificationID)
+	return args.Error(0)
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(user *schema.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) GetByID(id uuid.UUID) (*schema.User, error) {
+	args := m.Called(id)
+	user, ok := args.Get(0).(*schema.User)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) GetByEmail(email string) (*schema.User, error) {
+	args := m.Called(email)
+	user, ok := args.Get(0).(*schema.User)
+	if !ok {
+		return nil, args.Error(1)
+	}
+	return user, args.Error(1)
+}
+
+func (m *MockUserRepository) Update(user *schema.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) UpdateByEmail(email string, user *schema.User) error {
+	args := m.Called(email, user)
+	return args.Error(0)
+}
+
+type MockMailer struct {
+	mock.Mock
+}
+
+func (m *MockMailer) DialAndSend(msgs ...*gomail.Message) error {
+	args := m.Called(msgs)
+	return args.Error(0)
+}
+
+// sqlRecorder keeps the statements gorm builds, with their arguments inlined
+type sqlRecorder struct {
+	logger.Interface
+	statements []string
+}
+
+func (r *sqlRecorder) LogMode(logger.LogLevel) logger.Interface {
+	return r
+}
+
+func (r *sqlRecorder) Trace(_ context.Context, _ time.Time, fc func() (string, int64), _ error) {
+	sql, _ := fc()
+	r.statements = append(r.statements, sql)
+}
+
+// dryRunRepository builds the queries of the repository without a database, writes skip the default
+// transaction since there is no connection to open it on
+func dryRunRepository(t *testing.T) (Repository, *sqlRecorder) {
+	recorder := &sqlRecorder{Interface: logger.Discard}
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 recorder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewRepository(db), recorder
+}
+
+func TestSetAlertPrice_OnlyMovesTheItemFromThePriceItWasReadWith(t *testing.T) {
+	repo, recorder := dryRunRepository(t)
+	item := &schema.WishlistItem{UserID: uuid.New(), CourseID: uuid.New(), AlertPrice: 150000, AlertCurrency: "IDR"}
+
+	_, _ = repo.SetAlertPrice(context.Background(), item, 100000, "IDR")
+
+	assert.Len(t, recorder.statements, 1)
+	assert.Contains(t, recorder.statements[0], `SET "alert_currency"='IDR',"alert_price"=100000`)
+	assert.Contains(t, recorder.statements[0], "WHERE user_id = '"+item.UserID.String()+"' AND course_id = '"+
+		item.CourseID.String()+"' AND alert_price = 150000 AND alert_currency = 'IDR'")
+}
+
+func TestGetByCourseIDs_Pages(t *testing.T) {
+	repo, recorder := dryRunRepository(t)
+	after := &schema.WishlistItem{UserID: uuid.New(), CourseID: uuid.New()}
+
+	_, _ = repo.GetByCourseIDs(context.Background(), nil, nil, 500)
+	_, _ = repo.GetByCourseIDs(context.Background(), nil, after, 500)
+
+	assert.Len(t, recorder.statements, 2)
+	assert.NotContains(t, recorder.statements[0], "(wishlist_items.course_id, wishlist_items.user_id) >")
+	assert.Contains(t, recorder.statements[1], "(wishlist_items.course_id, wishlist_items.user_id) > ('"+
+		after.CourseID.String()+"', '"+after.UserID.String()+"')")
+	for _, sql := range recorder.statements {
+		assert.Contains(t, sql, "ORDER BY wishlist_items.course_id, wishlist_items.user_id LIMIT 500")
+	}
+}
+
+type WishlistUseCaseTestSuite struct {
+	suite.Suite
+	repo             *MockRepository
+	courseRepo       *MockCourseRepository
+	enrollRepo       *MockEnrollRepository
+	userRepo         *MockUserRepository
+	notificationRepo *MockNotificationRepository
+	mailer           *MockMailer
+	useCase          *UseCase
+}
+
+func (suite *WishlistUseCaseTestSuite) SetupTest() {
+	suite.repo = new(MockRepository)
+	suite.courseRepo = new(MockCourseRepository)
+	suite.enrollRepo = new(MockEnrollRepository)
+	suite.userRepo = new(MockUserRepository)
+	suite.notificationRepo = new(MockNotificationRepository)
+	suite.mailer = new(MockMailer)
+	enrollUc := courseenroll.NewUseCase(suite.enrollRepo)
+	courseUc := course.NewUseCase(suite.courseRepo, nil, *enrollUc, nil, suite.notificationRepo, nil, nil)
+	suite.useCase = NewUseCase(suite.repo, courseUc, enrollUc, suite.userRepo, suite.notificationRepo, suite.mailer)
+	_ = os.Setenv("ENV", "test")
+	_ = os.Setenv("SMTP_EMAIL", "test")
+	config.LoadEnv()
+}
+
+func (suite *WishlistUseCaseTestSuite) userCtx(userID uuid.UUID) context.Context {
+	return context.WithValue(context.Background(), "user.id", userID.String())
+}
+
+func (suite *WishlistUseCaseTestSuite) TestAdd_Success() {
+	userID := uuid.New()
+	ctx := suite.userCtx(userID)
+	courseObj := schema.Course{ID: uuid.New(), Title: "Go", Price: 200000, Currency: "IDR"}
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseObj.ID).Return(false, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseObj.ID}, mock.Anything).Return([]schema.CourseSale{
+		{CourseID: courseObj.ID, SalePrice: 150000, EndsAt: time.Now().Add(time.Hour)},
+	}, nil)
+	suite.repo.On("Add", ctx, mock.AnythingOfType("*schema.WishlistItem")).Return(nil)
+
+	item, err := suite.useCase.Add(ctx, &AddWishlistRequest{CourseID: courseObj.ID.String()})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), userID, item.UserID)
+	assert.Equal(suite.T(), int64(150000), item.AlertPrice)
+	assert.Equal(suite.T(), "IDR", item.AlertCurrency)
+	assert.True(suite.T(), item.Course.Pricing.OnSale)
+}
+
+func (suite *WishlistUseCaseTestSuite) TestAdd_AlreadyPurchased() {
+	userID := uuid.New()
+	ctx := suite.userCtx(userID)
+	courseObj := schema.Course{ID: uuid.New(), Price: 200000, Currency: "IDR"}
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseObj.ID).Return(true, nil)
+
+	item, err := suite.useCase.Add(ctx, &AddWishlistRequest{CourseID: courseObj.ID.String()})
+
+	assert.Nil(suite.T(), item)
+	assert.Equal(suite.T(), ErrAlreadyEnrolled.Build().Error(), err.Error())
+	suite.repo.AssertNotCalled(suite.T(), "Add", mock.Anything, mock.Anything)
+}
+
+func (suite *WishlistUseCaseTestSuite) TestAdd_Duplicate() {
+	userID := uuid.New()
+	ctx := suite.userCtx(userID)
+	courseObj := schema.Course{ID: uuid.New(), Price: 200000, Currency: "IDR"}
+
+	suite.courseRepo.On("GetByID", ctx, courseObj.ID).Return(courseObj, nil)
+	suite.enrollRepo.On("IsEnrolled", ctx, userID, courseObj.ID).Return(false, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseObj.ID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.repo.On("Add", ctx, mock.AnythingOfType("*schema.WishlistItem")).Return(&pgconn.PgError{Code: "23505"})
+
+	item, err := suite.useCase.Add(ctx, &AddWishlistRequest{CourseID: courseObj.ID.String()})
+
+	assert.Nil(suite.T(), item)
+	assert.Equal(suite.T(), ErrAlreadyInWishlist.Build().Error(), err.Error())
+}
+
+func (suite *WishlistUseCaseTestSuite) TestRemove_NotInWishlist() {
+	userID := uuid.New()
+	courseID := uuid.New()
+	ctx := suite.userCtx(userID)
+
+	suite.repo.On("Remove", ctx, userID, courseID).Return(gorm.ErrRecordNotFound)
+
+	err := suite.useCase.Remove(ctx, &CourseIDRequest{CourseID: courseID.String()})
+
+	assert.Equal(suite.T(), ErrNotInWishlist.Build().Error(), err.Error())
+}
+
+func (suite *WishlistUseCaseTestSuite) TestCheckPriceDrops_SendsOneDigestPerStudent() {
+	ctx := context.Background()
+	student := &schema.User{ID: uuid.New(), Name: "Student", Email: "student@example.com"}
+	cheaper := schema.Course{ID: uuid.New(), Title: "Go", Price: 100000, Currency: "IDR"}
+	onSale := schema.Course{ID: uuid.New(), Title: "Rust", Price: 300000, Currency: "IDR"}
+	unchanged := schema.Course{ID: uuid.New(), Title: "SQL", Price: 50000, Currency: "IDR"}
+
+	items := []schema.WishlistItem{
+		{UserID: student.ID, CourseID: cheaper.ID, AlertPrice: 150000, AlertCurrency: "IDR", Course: cheaper},
+		{UserID: student.ID, CourseID: onSale.ID, AlertPrice: 300000, AlertCurrency: "IDR", Course: onSale},
+		{UserID: student.ID, CourseID: unchanged.ID, AlertPrice: 50000, AlertCurrency: "IDR", Course: unchanged},
+	}
+
+	suite.repo.On("GetByCourseIDs", ctx, []uuid.UUID(nil), (*schema.WishlistItem)(nil), alertPageSize).Return(items, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, mock.Anything, mock.Anything).Return([]schema.CourseSale{
+		{CourseID: onSale.ID, SalePrice: 200000, EndsAt: time.Now().Add(time.Hour)},
+	}, nil)
+	suite.repo.On("SetAlertPrice", ctx, &items[0], int64(100000), "IDR").Return(true, nil)
+	suite.repo.On("SetAlertPrice", ctx, &items[1], int64(200000), "IDR").Return(true, nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+	suite.userRepo.On("GetByID", student.ID).Return(student, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+
+	alerted, err := suite.useCase.CheckPriceDrops(ctx, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, alerted)
+	suite.repo.AssertNumberOfCalls(suite.T(), "SetAlertPrice", 2)
+	suite.notificationRepo.AssertNumberOfCalls(suite.T(), "Create", 2)
+	suite.mailer.AssertNumberOfCalls(suite.T(), "DialAndSend", 1)
+}
+
+func (suite *WishlistUseCaseTestSuite) TestCheckPriceDrops_PriceRoseRearmsAlert() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Title: "Go", Price: 200000, Currency: "IDR"}
+	items := []schema.WishlistItem{
+		{UserID: uuid.New(), CourseID: courseObj.ID, AlertPrice: 150000, AlertCurrency: "IDR", Course: courseObj},
+	}
+
+	suite.repo.On("GetByCourseIDs", ctx, []uuid.UUID{courseObj.ID}, (*schema.WishlistItem)(nil), alertPageSize).Return(items, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseObj.ID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.repo.On("SetAlertPrice", ctx, &items[0], int64(200000), "IDR").Return(true, nil)
+
+	alerted, err := suite.useCase.CheckPriceDrops(ctx, []uuid.UUID{courseObj.ID})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, alerted)
+	suite.notificationRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mailer.AssertNotCalled(suite.T(), "DialAndSend", mock.Anything)
+}
+
+func (suite *WishlistUseCaseTestSuite) TestCheckPriceDrops_AlreadyMovedByAnotherRun() {
+	ctx := context.Background()
+	courseObj := schema.Course{ID: uuid.New(), Title: "Go", Price: 100000, Currency: "IDR"}
+	items := []schema.WishlistItem{
+		{UserID: uuid.New(), CourseID: courseObj.ID, AlertPrice: 150000, AlertCurrency: "IDR", Course: courseObj},
+	}
+
+	suite.repo.On("GetByCourseIDs", ctx, []uuid.UUID{courseObj.ID}, (*schema.WishlistItem)(nil), alertPageSize).Return(items, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, []uuid.UUID{courseObj.ID}, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.repo.On("SetAlertPrice", ctx, &items[0], int64(100000), "IDR").Return(false, nil)
+
+	alerted, err := suite.useCase.CheckPriceDrops(ctx, []uuid.UUID{courseObj.ID})
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, alerted)
+	suite.notificationRepo.AssertNotCalled(suite.T(), "Create", mock.Anything)
+	suite.mailer.AssertNotCalled(suite.T(), "DialAndSend", mock.Anything)
+}
+
+func (suite *WishlistUseCaseTestSuite) TestCheckPriceDrops_PagesThroughItems() {
+	ctx := context.Background()
+	student := &schema.User{ID: uuid.New(), Name: "Student", Email: "student@example.com"}
+	unchanged := schema.Course{ID: uuid.New(), Title: "SQL", Price: 50000, Currency: "IDR"}
+	cheaper := schema.Course{ID: uuid.New(), Title: "Go", Price: 100000, Currency: "IDR"}
+
+	firstPage := make([]schema.WishlistItem, alertPageSize)
+	for i := range firstPage {
+		firstPage[i] = schema.WishlistItem{UserID: uuid.New(), CourseID: unchanged.ID, AlertPrice: 50000, AlertCurrency: "IDR", Course: unchanged}
+	}
+	secondPage := []schema.WishlistItem{
+		{UserID: student.ID, CourseID: cheaper.ID, AlertPrice: 150000, AlertCurrency: "IDR", Course: cheaper},
+	}
+
+	suite.repo.On("GetByCourseIDs", ctx, []uuid.UUID(nil), (*schema.WishlistItem)(nil), alertPageSize).Return(firstPage, nil)
+	suite.repo.On("GetByCourseIDs", ctx, []uuid.UUID(nil), &firstPage[alertPageSize-1], alertPageSize).Return(secondPage, nil)
+	suite.courseRepo.On("GetActiveSales", ctx, mock.Anything, mock.Anything).Return([]schema.CourseSale{}, nil)
+	suite.repo.On("SetAlertPrice", ctx, &secondPage[0], int64(100000), "IDR").Return(true, nil)
+	suite.notificationRepo.On("Create", mock.AnythingOfType("*schema.Notification")).Return(nil)
+	suite.userRepo.On("GetByID", student.ID).Return(student, nil)
+	suite.mailer.On("DialAndSend", mock.Anything).Return(nil)
+
+	alerted, err := suite.useCase.CheckPriceDrops(ctx, nil)
+
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, alerted)
+	suite.repo.AssertNumberOfCalls(suite.T(), "GetByCourseIDs", 2)
+	suite.repo.AssertNumberOfCalls(suite.T(), "SetAlertPrice", 1)
+}
+
+func TestWishlistUseCase(t *testing.T) {
+	suite.Run(t, new(WishlistUseCaseTestSuite))
+}
@@ -62,6 +62,19 @@ func Authenticate() gin.HandlerFunc {
 	}
 }
 
+// OptionalAuthenticate identifies the user when a token is sent and lets anonymous requests through,
+// a token that is sent but invalid is still rejected
+func OptionalAuthenticate() gin.HandlerFunc {
+	authenticate := Authenticate()
+	return func(ctx *gin.Context) {
+		if ctx.GetHeader("Authorization") == "" {
+			ctx.Next()
+			return
+		}
+		authenticate(ctx)
+	}
+}
+
 // RequireEmailVerified Dependency: [Authenticate]
 func RequireEmailVerified() gin.HandlerFunc {
 	return func(ctx *gin.Context) {
@@ -0,0 +1,18 @@
+package redeemcode
+
+import "crypto/rand"
+
+// charset leaves out look-alike characters such as 0/O and 1/I so codes can be typed from an email
+const charset = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// Generate returns a random code of the given length for invites, gifts and similar links
+func Generate(length int) (string, error) {
+	b := make([]byte, length)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	for i := range b {
+		b[i] = charset[int(b[i])%len(charset)]
+	}
+	return string(b), nil
+}
@@ -0,0 +1,222 @@
+package sanitize
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// allowedTags lists the elements kept in lesson content together with the attributes each may carry
+var allowedTags = map[string][]string{
+	"p": {}, "br": {}, "hr": {}, "span": {}, "div": {},
+	"h1": {}, "h2": {}, "h3": {}, "h4": {}, "h5": {}, "h6": {},
+	"strong": {}, "b": {}, "em": {}, "i": {}, "u": {}, "s": {}, "del": {}, "sub": {}, "sup": {}, "mark": {},
+	"blockquote": {}, "pre": {}, "code": {"class"},
+	"ul": {}, "ol": {"start"}, "li": {},
+	"a":     {"href", "title"},
+	"img":   {"src", "alt", "title", "width", "height"},
+	"table": {}, "thead": {}, "tbody": {}, "tr": {}, "th": {"colspan", "rowspan"}, "td": {"colspan", "rowspan"},
+}
+
+// droppedWithContent are removed from HTML together with everything inside them,
+// in markdown their content is escaped instead
+var droppedWithContent = map[string]bool{
+	"script": true, "style": true, "iframe": true, "object": true, "noscript": true, "noembed": true,
+	"template": true, "textarea": true, "title": true, "xmp": true, "select": true, "svg": true, "math": true,
+}
+
+var voidTags = map[string]bool{"br": true, "hr": true, "img": true}
+
+// markdownDestination matches what comes right before a link destination in markdown, the "](" of inline links
+// and images and the label of link reference definitions
+var markdownDestination = regexp.MustCompile(`\]\([ \t]*\n?[ \t]*|(?m:^ {0,3}\[[^\]\n]+\]:[ \t]*\n?[ \t]*)`)
+
+// markdownEscape matches the backslash escapes markdown resolves inside link destinations
+var markdownEscape = regexp.MustCompile("\\\\([!-/:-@\\[-`{-~])")
+
+// HTML keeps the allowed elements and attributes of rich-text content and drops everything else
+func HTML(input string) string {
+	return clean(input, false)
+}
+
+// Markdown leaves markdown text byte-for-byte and neutralizes inline HTML that is not allowed by escaping it,
+// so it shows up as text instead of being lost. Link and image destinations that are not safe are replaced by "#"
+func Markdown(input string) string {
+	return cleanDestinations(clean(input, true))
+}
+
+// cleanDestinations replaces the unsafe link and image destinations of markdown. Destinations are checked the way
+// a renderer reads them, after resolving backslash escapes and character references
+func cleanDestinations(input string) string {
+	var b strings.Builder
+	last := 0
+	for _, m := range markdownDestination.FindAllStringIndex(input, -1) {
+		if m[0] < last {
+			continue
+		}
+		end := destinationEnd(input, m[1])
+		dest := html.UnescapeString(markdownEscape.ReplaceAllString(input[m[1]:end], "$1"))
+
+		b.WriteString(input[last:m[1]])
+		if SafeURL(dest) {
+			b.WriteString(input[m[1]:end])
+		} else {
+			b.WriteString("#")
+		}
+		last = end
+	}
+	b.WriteString(input[last:])
+	return b.String()
+}
+
+// destinationEnd returns where the destination starting at start ends, at whitespace or at a closing
+// parenthesis that is not balanced inside it
+func destinationEnd(input string, start int) int {
+	depth := 0
+	for i := start; i < len(input); i++ {
+		switch input[i] {
+		case '\\':
+			i++
+		case ' ', '\t', '\n', '\r':
+			return i
+		case '(':
+			depth++
+		case ')':
+			if depth == 0 {
+				return i
+			}
+			depth--
+		}
+	}
+	return len(input)
+}
+
+func clean(input string, markdown bool) string {
+	var b strings.Builder
+	z := html.NewTokenizer(strings.NewReader(input))
+	skipDepth := 0
+
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			return b.String()
+		}
+
+		raw := string(z.Raw())
+		tok := z.Token()
+		_, allowed := allowedTags[tok.Data]
+
+		switch tt {
+		case html.TextToken:
+			switch {
+			case !markdown && skipDepth == 0:
+				b.WriteString(html.EscapeString(tok.Data))
+			case markdown && skipDepth > 0:
+				b.WriteString(html.EscapeString(raw))
+			case markdown:
+				b.WriteString(raw)
+			}
+		case html.StartTagToken, html.SelfClosingTagToken, html.EndTagToken:
+			if droppedWithContent[tok.Data] {
+				if tt == html.StartTagToken {
+					skipDepth++
+				} else if tt == html.EndTagToken && skipDepth > 0 {
+					skipDepth--
+				}
+				if markdown {
+					b.WriteString(html.EscapeString(raw))
+				}
+				continue
+			}
+			if skipDepth > 0 {
+				if markdown {
+					b.WriteString(html.EscapeString(raw))
+				}
+				continue
+			}
+			if !allowed {
+				if markdown {
+					b.WriteString(html.EscapeString(raw))
+				}
+				continue
+			}
+			if tt == html.EndTagToken {
+				if !voidTags[tok.Data] {
+					b.WriteString("</" + tok.Data + ">")
+				}
+				continue
+			}
+			// In markdown a tag that would lose attributes is most likely prose such as "a<b and c>d"
+			tag, complete := renderStartTag(tok)
+			if markdown && !complete {
+				b.WriteString(html.EscapeString(raw))
+				continue
+			}
+			b.WriteString(tag)
+		default:
+			// Comments and doctypes never make it into lesson content
+			if markdown && skipDepth == 0 {
+				b.WriteString(html.EscapeString(raw))
+			}
+		}
+	}
+}
+
+// renderStartTag rebuilds a start tag with the allowed attributes only, complete is false when any was dropped
+func renderStartTag(tok html.Token) (tag string, complete bool) {
+	var b strings.Builder
+	b.WriteString("<" + tok.Data)
+
+	complete = true
+	for _, attr := range tok.Attr {
+		if attr.Namespace != "" || !containsAttr(allowedTags[tok.Data], attr.Key) {
+			complete = false
+			continue
+		}
+		if (attr.Key == "href" || attr.Key == "src") && !SafeURL(attr.Val) {
+			complete = false
+			continue
+		}
+		b.WriteString(" " + attr.Key + `="` + html.EscapeString(attr.Val) + `"`)
+	}
+	if tok.Data == "a" {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+
+	b.WriteString(">")
+	return b.String(), complete
+}
+
+func containsAttr(attrs []string, key string) bool {
+	for _, attr := range attrs {
+		if attr == key {
+			return true
+		}
+	}
+	return false
+}
+
+// SafeURL reports whether a link target is relative or uses http, https or mailto
+func SafeURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return false
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "", "http", "https", "mailto":
+		return true
+	default:
+		return false
+	}
+}
+
+// CSVCell keeps a spreadsheet from reading a user supplied CSV cell as a formula by prefixing it with a quote
+func CSVCell(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
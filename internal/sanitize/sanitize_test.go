@@ -0,0 +1,126 @@
+package sanitize
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTML(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "allowed markup is kept", input: `<p>Safe <b>bold</b> <code class="go">x</code></p>`, want: `<p>Safe <b>bold</b> <code class="go">x</code></p>`},
+		{name: "script is dropped with its content", input: `<p>a</p><script>alert(1)</script><p>b</p>`, want: `<p>a</p><p>b</p>`},
+		{name: "style is dropped with its content", input: `<style>body{display:none}</style>text`, want: `text`},
+		{name: "nested dropped elements", input: `<svg><script>alert(1)</script><p>hidden</p></svg>shown`, want: `shown`},
+		{name: "event handlers are dropped", input: `<p onclick="steal()" onmouseover=steal()>hi</p>`, want: `<p>hi</p>`},
+		{name: "event handlers on images are dropped", input: `<img src="/a.png" onerror="steal()">`, want: `<img src="/a.png">`},
+		{name: "unknown elements are dropped", input: `<form action="/x"><input value="a"></form>text`, want: `text`},
+		{name: "links get rel", input: `<a href="https://example.com" target="_blank">x</a>`, want: `<a href="https://example.com" rel="nofollow noopener noreferrer">x</a>`},
+		{name: "javascript href", input: `<a href="javascript:alert(1)">x</a>`, want: `<a rel="nofollow noopener noreferrer">x</a>`},
+		{name: "mixed case scheme", input: `<a href=" JaVaScRiPt:alert(1)">x</a>`, want: `<a rel="nofollow noopener noreferrer">x</a>`},
+		{name: "entity encoded scheme", input: `<a href="jav&#x61;script&colon;alert(1)">x</a>`, want: `<a rel="nofollow noopener noreferrer">x</a>`},
+		{name: "tab inside the scheme", input: `<a href="java&#9;script:alert(1)">x</a>`, want: `<a rel="nofollow noopener noreferrer">x</a>`},
+		{name: "data image", input: `<img src="data:text/html;base64,PHNjcmlwdD4=">`, want: `<img>`},
+		{name: "attribute values are escaped", input: `<a title='"><script>' href="/a">x</a>`, want: `<a title="&#34;&gt;&lt;script&gt;" href="/a" rel="nofollow noopener noreferrer">x</a>`},
+		{name: "text is escaped", input: `1 &lt; 2 & 3 > 2`, want: `1 &lt; 2 &amp; 3 &gt; 2`},
+		{name: "unbalanced start tag", input: `<p><b>bold`, want: `<p><b>bold`},
+		{name: "unbalanced end tag", input: `text</b></div>`, want: `text</b></div>`},
+		{name: "unclosed script swallows the rest", input: `<p>a</p><script>alert(1)`, want: `<p>a</p>`},
+		{name: "stray script end tag", input: `</script><p>a</p>`, want: `<p>a</p>`},
+		{name: "comments are dropped", input: `a<!-- <script>alert(1)</script> -->b`, want: `ab`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, HTML(tt.input))
+		})
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{name: "plain markdown is untouched", input: "# Title\n\n*a* < b && c > d\n\n```go\nx := 1\n```", want: "# Title\n\n*a* < b && c > d\n\n```go\nx := 1\n```"},
+		{name: "allowed inline html is kept", input: `Some <b>bold</b> text`, want: `Some <b>bold</b> text`},
+		{name: "script is escaped", input: `<script>alert(1)</script>`, want: `&lt;script&gt;alert(1)&lt;/script&gt;`},
+		{name: "style is escaped", input: `<style>p{}</style>`, want: `&lt;style&gt;p{}&lt;/style&gt;`},
+		{name: "event handlers are escaped", input: `<img src="/a.png" onerror="steal()">`, want: `&lt;img src=&#34;/a.png&#34; onerror=&#34;steal()&#34;&gt;`},
+		{name: "javascript href in html is escaped", input: `<a href="javascript:alert(1)">x</a>`, want: `&lt;a href=&#34;javascript:alert(1)&#34;&gt;x</a>`},
+		{name: "safe link", input: `[docs](https://example.com/a_(b) "Docs")`, want: `[docs](https://example.com/a_(b) "Docs")`},
+		{name: "relative link", input: `[next](../lesson-2)`, want: `[next](../lesson-2)`},
+		{name: "javascript link", input: `[x](javascript:alert(1))`, want: `[x](#)`},
+		{name: "javascript image", input: `![x](javascript:alert(1) "t")`, want: `![x](# "t")`},
+		{name: "data image", input: `![x](data:text/html;base64,PHNjcmlwdD4=)`, want: `![x](#)`},
+		{name: "mixed case scheme", input: `[x](JavaScript:alert(1))`, want: `[x](#)`},
+		{name: "entity encoded scheme", input: `[x](jav&#x61;script&colon;alert(1))`, want: `[x](#)`},
+		{name: "backslash escaped scheme", input: `[x](javascript\:alert\(1\))`, want: `[x](#)`},
+		{name: "destination on the next line", input: "[x](\njavascript:alert(1))", want: "[x](\n#)"},
+		{name: "reference definition", input: "[x]\n\n [x]: javascript:alert(1) \"t\"", want: "[x]\n\n [x]: # \"t\""},
+		{name: "safe reference definition", input: "[x]: https://example.com", want: "[x]: https://example.com"},
+		{name: "several links", input: `[a](/a) [b](vbscript:x) [c](mailto:a@b.c)`, want: `[a](/a) [b](#) [c](mailto:a@b.c)`},
+		{name: "unbalanced tags are escaped", input: `a<b and c>d <p onclick="x">`, want: `a&lt;b and c&gt;d &lt;p onclick=&#34;x&#34;&gt;`},
+		{name: "unclosed script escapes the rest", input: "<script>\nalert(1)", want: "&lt;script&gt;\nalert(1)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Markdown(tt.input))
+		})
+	}
+}
+
+func TestSafeURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://example.com", want: true},
+		{url: "http://example.com", want: true},
+		{url: "mailto:a@b.c", want: true},
+		{url: "/relative/path", want: true},
+		{url: "#anchor", want: true},
+		{url: "", want: true},
+		{url: "javascript:alert(1)", want: false},
+		{url: "  JAVASCRIPT:alert(1)", want: false},
+		{url: "vbscript:x", want: false},
+		{url: "data:text/html,x", want: false},
+		{url: "java\tscript:alert(1)", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			assert.Equal(t, tt.want, SafeURL(tt.url))
+		})
+	}
+}
+
+func TestCSVCell(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{value: "John Doe", want: "John Doe"},
+		{value: "", want: ""},
+		{value: "john@example.com", want: "john@example.com"},
+		{value: `=HYPERLINK("http://evil.example","x")`, want: `'=HYPERLINK("http://evil.example","x")`},
+		{value: "+1+1", want: "'+1+1"},
+		{value: "-2+3", want: "'-2+3"},
+		{value: "@SUM(A1)", want: "'@SUM(A1)"},
+		{value: "\t=1", want: "'\t=1"},
+		{value: "\r=1", want: "'\r=1"},
+		{value: "a=1", want: "a=1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			assert.Equal(t, tt.want, CSVCell(tt.value))
+		})
+	}
+}
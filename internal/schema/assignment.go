@@ -7,14 +7,71 @@ import (
 	"gorm.io/gorm"
 )
 
+type LatePolicy string
+
+const (
+	// LatePolicyHard rejects submissions after the deadline
+	LatePolicyHard LatePolicy = "hard"
+	// LatePolicySoft accepts late submissions and flags them
+	LatePolicySoft LatePolicy = "soft"
+	// LatePolicyPenalty accepts late submissions and takes LatePenaltyPercent of the grade per day late
+	LatePolicyPenalty LatePolicy = "penalty"
+)
+
+type GradingPolicy string
+
+const (
+	GradingPolicyLatest  GradingPolicy = "latest"
+	GradingPolicyHighest GradingPolicy = "highest"
+	GradingPolicyAverage GradingPolicy = "average"
+)
+
+// Assignment is due at Due for self-paced students, DueOffsetDays sets the deadline relative to
+// the cohort start instead and takes precedence inside a cohort. An AssignmentExtension of a student
+// overrides both. Locked is set when the viewer only sees the assignment in the course outline,
+// either without access or before its release. A student may hand in MaxAttempts times, GradingPolicy
+// picks which of the graded attempts makes the grade of the assignment. Weight is the share of the
+// assignment within its grade category, or within the course while it has no categories. HoldGrades keeps
+// new grades from the students until the instructor releases them together. With PeerReviewCount set, every
+// submission goes to that many other students for review once Due passes, at PeerReviewsAssignedAt. A group
+// assignment is handed in by the teams of TeamSetID, one submission for the whole team
 type Assignment struct {
-	ID          uuid.UUID      `json:"id" gorm:"primaryKey"`
-	CourseID    uuid.UUID      `json:"course_id" gorm:"not null"`
-	Title       string         `json:"title" gorm:"type:varchar(150);not null"`
-	Description string         `json:"description" gorm:"type:varchar(2000)"`
-	Due         *time.Time     `json:"due"`
-	Attachments []Attachment   `json:"attachments" gorm:"foreignKey:AssignmentID"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"default:now();not null"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"" gorm:"index"`
+	ID                    uuid.UUID      `json:"id" gorm:"primaryKey"`
+	CourseID              uuid.UUID      `json:"course_id" gorm:"not null"`
+	Title                 string         `json:"title" gorm:"type:varchar(150);not null"`
+	Description           string         `json:"description" gorm:"type:varchar(2000)"`
+	Due                   *time.Time     `json:"due"`
+	DueOffsetDays         *int           `json:"due_offset_days" gorm:"check:due_offset_days >= 0"`
+	LatePolicy            LatePolicy     `json:"late_policy" gorm:"type:late_policy;default:soft;not null"`
+	LatePenaltyPercent    *float64       `json:"late_penalty_percent" gorm:"type:numeric(5,2);check:late_penalty_percent > 0 AND late_penalty_percent <= 100"`
+	MaxAttempts           int            `json:"max_attempts" gorm:"default:1;not null;check:max_attempts > 0"`
+	GradingPolicy         GradingPolicy  `json:"grading_policy" gorm:"type:grading_policy;default:latest;not null"`
+	CategoryID            *uuid.UUID     `json:"category_id" gorm:"index"`
+	Weight                float64        `json:"weight" gorm:"type:numeric(6,2);default:1;not null;check:weight >= 0"`
+	HoldGrades            bool           `json:"hold_grades" gorm:"default:false;not null"`
+	PeerReviewCount       int            `json:"peer_review_count" gorm:"default:0;not null;check:peer_review_count BETWEEN 0 AND 10"`
+	PeerReviewsAssignedAt *time.Time     `json:"peer_reviews_assigned_at"`
+	TeamSetID             *uuid.UUID     `json:"team_set_id" gorm:"index"`
+	TeamSet               *TeamSet       `json:"-" gorm:"foreignKey:TeamSetID;constraint:OnDelete:SET NULL"`
+	Category              *GradeCategory `json:"-" gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+	Attachments           []Attachment   `json:"attachments" gorm:"foreignKey:AssignmentID"`
+	Locked                bool           `json:"locked" gorm:"-"`
+	CreatedAt             time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt             time.Time      `json:"updated_at"`
+	DeletedAt             gorm.DeletedAt `json:"" gorm:"index"`
+
+	ReleaseSchedule
+}
+
+// AssignmentExtension is a personal deadline granted to one student by the instructor
+type AssignmentExtension struct {
+	AssignmentID uuid.UUID  `json:"assignment_id" gorm:"primaryKey"`
+	UserID       uuid.UUID  `json:"user_id" gorm:"primaryKey"`
+	Due          time.Time  `json:"due" gorm:"not null"`
+	Reason       string     `json:"reason" gorm:"type:varchar(500)"`
+	GrantedBy    uuid.UUID  `json:"granted_by" gorm:"not null"`
+	User         *User      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Assignment   Assignment `json:"-" gorm:"foreignKey:AssignmentID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time  `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt    time.Time  `json:"updated_at"`
 }
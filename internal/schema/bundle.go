@@ -0,0 +1,26 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type Bundle struct {
+	ID           uuid.UUID      `json:"id" gorm:"primaryKey"`
+	Title        string         `json:"title" gorm:"type:varchar(100);not null"`
+	Description  string         `json:"description" gorm:"type:varchar(1000)"`
+	InstructorID uuid.UUID      `json:"instructor_id" gorm:"not null;index"`
+	Price        int64          `json:"price" gorm:"not null;check:price >= 0"` // in the base currency
+	Courses      []BundleCourse `json:"courses" gorm:"foreignKey:BundleID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type BundleCourse struct {
+	BundleID uuid.UUID `json:"-" gorm:"primaryKey"`
+	CourseID uuid.UUID `json:"course_id" gorm:"primaryKey"`
+	Course   Course    `json:"course" gorm:"foreignKey:CourseID"`
+}
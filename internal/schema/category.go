@@ -0,0 +1,24 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Category struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	Name      string     `json:"name" gorm:"type:varchar(100);not null"`
+	Slug      string     `json:"slug" gorm:"type:varchar(120);uniqueIndex;not null"`
+	ParentID  *uuid.UUID `json:"parent_id" gorm:"index"`
+	Children  []Category `json:"children,omitempty" gorm:"foreignKey:ParentID;constraint:OnDelete:RESTRICT"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+type Tag struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	Name      string    `json:"name" gorm:"type:varchar(50);not null"`
+	Slug      string    `json:"slug" gorm:"type:varchar(60);uniqueIndex;not null"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:now();not null"`
+}
@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type Certificate struct {
+	ID             uuid.UUID `json:"id" gorm:"primaryKey"`
+	UserID         uuid.UUID `json:"user_id" gorm:"not null;index:idx_certificate_user_course,unique"`
+	CourseID       uuid.UUID `json:"course_id" gorm:"not null;index:idx_certificate_user_course,unique"`
+	StudentName    string    `json:"student_name" gorm:"type:varchar(50);not null"`
+	CourseTitle    string    `json:"course_title" gorm:"type:varchar(100);not null"`
+	InstructorName string    `json:"instructor_name" gorm:"type:varchar(50);not null"`
+	AverageGrade   float64   `json:"average_grade" gorm:"type:numeric(4,1);not null"`
+	URL            string    `json:"url" gorm:"type:text;not null"`
+	IssuedAt       time.Time `json:"issued_at" gorm:"default:now();not null"`
+}
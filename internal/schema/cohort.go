@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Cohort is a scheduled run of a course, students go through it together between StartsAt and EndsAt
+type Cohort struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey"`
+	CourseID  uuid.UUID      `json:"course_id" gorm:"not null;index"`
+	Name      string         `json:"name" gorm:"type:varchar(100);not null"`
+	StartsAt  time.Time      `json:"starts_at" gorm:"not null"`
+	EndsAt    time.Time      `json:"ends_at" gorm:"not null"`
+	Capacity  *int           `json:"capacity" gorm:"check:capacity > 0"`
+	Course    Course         `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type CohortMemberStatus string
+
+const (
+	CohortMemberEnrolled   CohortMemberStatus = "enrolled"
+	CohortMemberWaitlisted CohortMemberStatus = "waitlisted"
+)
+
+// CohortMember holds a seat in a cohort, waitlisted members are promoted in CreatedAt order when a seat frees up
+type CohortMember struct {
+	ID        uuid.UUID          `json:"id" gorm:"primaryKey"`
+	CohortID  uuid.UUID          `json:"cohort_id" gorm:"not null;uniqueIndex:idx_cohort_member"`
+	UserID    uuid.UUID          `json:"user_id" gorm:"not null;uniqueIndex:idx_cohort_member"`
+	Status    CohortMemberStatus `json:"status" gorm:"type:cohort_member_status;not null"`
+	User      *User              `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Cohort    Cohort             `json:"-" gorm:"foreignKey:CohortID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time          `json:"created_at" gorm:"default:now()"`
+	UpdatedAt time.Time          `json:"updated_at"`
+}
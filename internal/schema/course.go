@@ -16,41 +16,69 @@ const (
 	Expert       CourseDifficulty = "expert"
 )
 
-type CourseCategory string
+type PrerequisitePolicy string
 
 const (
-	WebDevelopment           CourseCategory = "Web Development"
-	GameDevelopment          CourseCategory = "Game Development"
-	CloudComputing           CourseCategory = "Cloud Computing"
-	DataScienceAnalytics     CourseCategory = "Data Science & Analytics"
-	ProgrammingLanguages     CourseCategory = "Programming Languages"
-	Cybersecurity            CourseCategory = "Cybersecurity"
-	MobileAppDevelopment     CourseCategory = "Mobile App Development"
-	DatabaseManagement       CourseCategory = "Database Management"
-	SoftwareDevelopment      CourseCategory = "Software Development"
-	DevOpsAutomation         CourseCategory = "DevOps & Automation"
-	Networking               CourseCategory = "Networking"
-	AIMachineLearning        CourseCategory = "AI & Machine Learning"
-	InternetOfThings         CourseCategory = "Internet of Things (IoT)"
-	BlockchainCryptocurrency CourseCategory = "Blockchain & Cryptocurrency"
-	AugmentedVirtualReality  CourseCategory = "Augmented Reality (AR) & Virtual Reality (VR)"
+	PrerequisiteNone  PrerequisitePolicy = "none"
+	PrerequisiteWarn  PrerequisitePolicy = "warn"
+	PrerequisiteBlock PrerequisitePolicy = "block"
 )
 
 type Course struct {
-	ID           uuid.UUID        `json:"id" gorm:"primaryKey"`
-	Title        string           `json:"title" gorm:"type:varchar(100);not null"`
-	Description  string           `json:"description" gorm:"type:varchar(1000)"`
-	Price        int64            `json:"price" gorm:"not null"`
-	Rating       float32          `json:"rating" gorm:"type:numeric(2,1);default:0.0;not null;check:rating >= 0.0 AND rating <= 5.0;index"`
-	ReviewCount  int64            `json:"review_count" gorm:"type:bigint;default:0;not null"`
-	ImageURL     string           `json:"image_url" gorm:"type:text"`
-	SyllabusURL  string           `json:"syllabus_url" gorm:"type:text"`
-	InstructorID uuid.UUID        `json:"instructor_id" gorm:"not null"`
-	Difficulty   CourseDifficulty `json:"difficulty" gorm:"type:course_difficulty;not null"`
-	Category     CourseCategory   `json:"category" gorm:"type:course_category"`
-	Materials    []Material       `json:"materials" gorm:"foreignKey:CourseID"`
-	Assignments  []Assignment     `json:"assignments" gorm:"foreignKey:CourseID"`
-	CreatedAt    time.Time        `json:"created_at" gorm:"default:now();not null"`
-	UpdatedAt    time.Time        `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt   `json:"-" gorm:"index"`
+	ID                  uuid.UUID          `json:"id" gorm:"primaryKey"`
+	Title               string             `json:"title" gorm:"type:varchar(100);not null"`
+	Description         string             `json:"description" gorm:"type:varchar(1000)"`
+	Price               int64              `json:"price" gorm:"not null"`
+	Currency            string             `json:"currency" gorm:"type:char(3);not null"`
+	Pricing             *CoursePricing     `json:"pricing,omitempty" gorm:"-"`
+	Rating              float32            `json:"rating" gorm:"type:numeric(2,1);default:0.0;not null;check:rating >= 0.0 AND rating <= 5.0;index"`
+	ReviewCount         int64              `json:"review_count" gorm:"type:bigint;default:0;not null"`
+	ImageURL            string             `json:"image_url" gorm:"type:text"`
+	SyllabusURL         string             `json:"syllabus_url" gorm:"type:text"`
+	InstructorID        uuid.UUID          `json:"instructor_id" gorm:"not null"`
+	Difficulty          CourseDifficulty   `json:"difficulty" gorm:"type:course_difficulty;not null"`
+	CategoryID          *uuid.UUID         `json:"category_id" gorm:"index"`
+	Category            *Category          `json:"category,omitempty" gorm:"foreignKey:CategoryID;constraint:OnDelete:SET NULL"`
+	Tags                []Tag              `json:"tags" gorm:"many2many:course_tags"`
+	CertificateMinGrade *float64           `json:"certificate_min_grade" gorm:"type:numeric(4,1);check:certificate_min_grade BETWEEN 0 AND 100"`
+	PrerequisitePolicy  PrerequisitePolicy `json:"prerequisite_policy" gorm:"type:prerequisite_policy;default:'warn';not null"`
+	AccessDays          *int               `json:"access_days"`
+	Materials           []Material         `json:"materials" gorm:"foreignKey:CourseID"`
+	Assignments         []Assignment       `json:"assignments" gorm:"foreignKey:CourseID"`
+	CreatedAt           time.Time          `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt           time.Time          `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt     `json:"-" gorm:"index"`
+}
+
+type CoursePrerequisite struct {
+	CourseID       uuid.UUID `json:"course_id" gorm:"primaryKey"`
+	PrerequisiteID uuid.UUID `json:"prerequisite_id" gorm:"primaryKey;index"`
+	CreatedAt      time.Time `json:"created_at" gorm:"default:now()"`
+}
+
+// CourseSale discounts a course to SalePrice, in the course currency, between StartsAt and EndsAt
+type CourseSale struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CourseID  uuid.UUID `json:"course_id" gorm:"not null;index"`
+	SalePrice int64     `json:"sale_price" gorm:"not null;check:sale_price >= 0"`
+	StartsAt  time.Time `json:"starts_at" gorm:"not null;index"`
+	EndsAt    time.Time `json:"ends_at" gorm:"not null;index"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:now()"`
+}
+
+// CoursePricing is the computed price of a course at request time, it is not stored
+type CoursePricing struct {
+	Currency      string        `json:"currency"`
+	OriginalPrice int64         `json:"original_price"`
+	Price         int64         `json:"price"`
+	OnSale        bool          `json:"on_sale"`
+	SaleEndsAt    *time.Time    `json:"sale_ends_at,omitempty"`
+	Display       *DisplayPrice `json:"display,omitempty"`
+}
+
+// DisplayPrice is the course price converted to the currency requested by the client, for display only
+type DisplayPrice struct {
+	Currency      string `json:"currency"`
+	OriginalPrice int64  `json:"original_price"`
+	Price         int64  `json:"price"`
 }
@@ -6,9 +6,12 @@ import (
 	"github.com/google/uuid"
 )
 
+// CourseEnroll grants access to a course, ExpiresAt is nil for lifetime access.
+// Renewing a time-limited course adds a new row so the purchase history is kept
 type CourseEnroll struct {
-	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
-	UserID    uuid.UUID `json:"user_id" gorm:"not null;index"`
-	CourseID  uuid.UUID `json:"course_id" gorm:"not null;index"`
-	CreatedAt time.Time `json:"created_at" gorm:"default:now()"`
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	UserID    uuid.UUID  `json:"user_id" gorm:"not null;index"`
+	CourseID  uuid.UUID  `json:"course_id" gorm:"not null;index"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:now()"`
 }
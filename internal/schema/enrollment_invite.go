@@ -0,0 +1,22 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EnrollmentInvite lets students join a course for free through a shareable code,
+// MaxUses and ExpiresAt are unlimited when nil
+type EnrollmentInvite struct {
+	ID        uuid.UUID  `json:"id" gorm:"primaryKey"`
+	CourseID  uuid.UUID  `json:"course_id" gorm:"not null;index"`
+	Code      string     `json:"code" gorm:"type:varchar(16);not null;uniqueIndex"`
+	CreatedBy uuid.UUID  `json:"created_by" gorm:"not null"`
+	MaxUses   *int       `json:"max_uses"`
+	UsedCount int        `json:"used_count" gorm:"default:0;not null"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	CreatedAt time.Time  `json:"created_at" gorm:"default:now()"`
+
+	Course Course `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+}
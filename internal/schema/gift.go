@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type GiftStatus string
+
+const (
+	GiftPending  GiftStatus = "pending"
+	GiftRedeemed GiftStatus = "redeemed"
+	GiftRefunded GiftStatus = "refunded"
+)
+
+// Gift is a course bought for someone else. Amount is held in escrow until the recipient redeems the code,
+// then it goes to the instructor, or back to the sender when the gift expires unredeemed
+type Gift struct {
+	ID             uuid.UUID  `json:"id" gorm:"primaryKey"`
+	Code           string     `json:"code" gorm:"type:varchar(16);unique;not null"`
+	CourseID       uuid.UUID  `json:"course_id" gorm:"not null;index"`
+	SenderID       uuid.UUID  `json:"sender_id" gorm:"not null;index"`
+	RecipientEmail string     `json:"recipient_email" gorm:"type:varchar(320);not null"`
+	Message        string     `json:"message" gorm:"type:varchar(500)"`
+	Amount         int64      `json:"amount" gorm:"not null;check:amount >= 0"`
+	Status         GiftStatus `json:"status" gorm:"type:gift_status;not null"`
+	ExpiresAt      time.Time  `json:"expires_at" gorm:"not null"`
+	RedeemedBy     *uuid.UUID `json:"redeemed_by"`
+	RedeemedAt     *time.Time `json:"redeemed_at"`
+	RefundedAt     *time.Time `json:"refunded_at"`
+	Course         Course     `json:"course" gorm:"foreignKey:CourseID"`
+	CreatedAt      time.Time  `json:"created_at" gorm:"default:now()"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,28 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GradeCategory groups the assignments of a course, like homework or exams. Weight is the share of the
+// category in the final grade, relative to the other categories of the course
+type GradeCategory struct {
+	ID        uuid.UUID `json:"id" gorm:"primaryKey"`
+	CourseID  uuid.UUID `json:"course_id" gorm:"not null;index"`
+	Name      string    `json:"name" gorm:"type:varchar(100);not null"`
+	Weight    float64   `json:"weight" gorm:"type:numeric(5,2);not null;check:weight > 0 AND weight <= 100"`
+	Position  int       `json:"position" gorm:"not null;default:0"`
+	Course    Course    `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// GradeScaleStep gives the letter for final grades from MinPercent up to the next step of the course
+type GradeScaleStep struct {
+	CourseID   uuid.UUID `json:"-" gorm:"primaryKey"`
+	Letter     string    `json:"letter" gorm:"primaryKey;type:varchar(5)"`
+	MinPercent float64   `json:"min_percent" gorm:"type:numeric(5,2);not null;check:min_percent BETWEEN 0 AND 100"`
+	Course     Course    `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,27 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type LearningPath struct {
+	ID          uuid.UUID            `json:"id" gorm:"primaryKey"`
+	Title       string               `json:"title" gorm:"type:varchar(100);not null"`
+	Description string               `json:"description" gorm:"type:varchar(1000)"`
+	CreatorID   uuid.UUID            `json:"creator_id" gorm:"not null;index"`
+	Price       *int64               `json:"price" gorm:"check:price >= 0"` // in the base currency, nil when the path is not sold as a bundle
+	Courses     []LearningPathCourse `json:"courses" gorm:"foreignKey:LearningPathID;constraint:OnDelete:CASCADE"`
+	CreatedAt   time.Time            `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt   time.Time            `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt       `json:"-" gorm:"index"`
+}
+
+type LearningPathCourse struct {
+	LearningPathID uuid.UUID `json:"-" gorm:"primaryKey"`
+	CourseID       uuid.UUID `json:"course_id" gorm:"primaryKey"`
+	Position       int       `json:"position" gorm:"not null"`
+	Course         Course    `json:"course" gorm:"foreignKey:CourseID"`
+}
@@ -7,13 +7,84 @@ import (
 	"gorm.io/gorm"
 )
 
+type MaterialType string
+
+const (
+	MaterialAttachment MaterialType = "attachment"
+	MaterialText       MaterialType = "text"
+	MaterialVideo      MaterialType = "video"
+	MaterialEmbed      MaterialType = "embed"
+)
+
+type TextFormat string
+
+const (
+	TextMarkdown TextFormat = "markdown"
+	TextHTML     TextFormat = "html"
+)
+
+// Material is a lesson of a course. Type decides which typed fields are used: text lessons keep their
+// sanitized Body, video lessons and embeds keep their source in MediaURL. Content is the typed payload
+// built from those fields whenever a material is read, it is not stored. IsPreview opens the lesson to
+// everyone, Locked is set on the lessons a viewer without access only sees in the course outline,
+// including the ones still waiting for their release
 type Material struct {
-	ID          uuid.UUID      `json:"id" gorm:"primaryKey"`
-	CourseID    uuid.UUID      `json:"course_id" gorm:"not null"`
-	Title       string         `json:"title" gorm:"type:varchar(150);not null"`
-	Description string         `json:"description" gorm:"type:varchar(2000)"`
-	Attachments []Attachment   `json:"attachments" gorm:"foreignKey:MaterialID"`
-	CreatedAt   time.Time      `json:"created_at" gorm:"default:now();not null"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+	ID              uuid.UUID      `json:"id" gorm:"primaryKey"`
+	CourseID        uuid.UUID      `json:"course_id" gorm:"not null"`
+	Title           string         `json:"title" gorm:"type:varchar(150);not null"`
+	Description     string         `json:"description" gorm:"type:varchar(2000)"`
+	Type            MaterialType   `json:"type" gorm:"type:material_type;default:'attachment';not null"`
+	BodyFormat      TextFormat     `json:"-" gorm:"type:varchar(10)"`
+	Body            string         `json:"-" gorm:"type:text"`
+	MediaURL        string         `json:"-" gorm:"type:text"`
+	DurationSeconds *int           `json:"-"`
+	EmbedProvider   string         `json:"-" gorm:"type:varchar(30)"`
+	Content         any            `json:"content,omitempty" gorm:"-"`
+	IsPreview       bool           `json:"is_preview" gorm:"default:false;not null"`
+	Locked          bool           `json:"locked" gorm:"-"`
+	Attachments     []Attachment   `json:"attachments" gorm:"foreignKey:MaterialID"`
+	CreatedAt       time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       gorm.DeletedAt `json:"deleted_at" gorm:"index"`
+
+	ReleaseSchedule
+}
+
+type TextContent struct {
+	Format TextFormat `json:"format"`
+	Body   string     `json:"body"`
+}
+
+type VideoContent struct {
+	URL             string `json:"url"`
+	DurationSeconds int    `json:"duration_seconds"`
+}
+
+type EmbedContent struct {
+	Provider string `json:"provider"`
+	URL      string `json:"url"`
+}
+
+// TypedContent builds the payload matching the material type, attachment materials have none
+func (m *Material) TypedContent() any {
+	switch m.Type {
+	case MaterialText:
+		return &TextContent{Format: m.BodyFormat, Body: m.Body}
+	case MaterialVideo:
+		content := &VideoContent{URL: m.MediaURL}
+		if m.DurationSeconds != nil {
+			content.DurationSeconds = *m.DurationSeconds
+		}
+		return content
+	case MaterialEmbed:
+		return &EmbedContent{Provider: m.EmbedProvider, URL: m.MediaURL}
+	default:
+		return nil
+	}
+}
+
+// AfterFind fills Content on every read, including materials preloaded with their course
+func (m *Material) AfterFind(tx *gorm.DB) error {
+	m.Content = m.TypedContent()
+	return nil
 }
@@ -0,0 +1,37 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PeerReview hands a submission to another student of the course after the due date. Reviews are
+// anonymous both ways, students never see the author of what they review nor who reviewed their work.
+// Grade and Scores stay empty until the reviewer hands the review in at SubmittedAt
+type PeerReview struct {
+	ID           uuid.UUID         `json:"id" gorm:"primaryKey"`
+	AssignmentID uuid.UUID         `json:"assignment_id" gorm:"not null;index"`
+	SubmissionID uuid.UUID         `json:"submission_id" gorm:"not null;uniqueIndex:idx_peer_review"`
+	ReviewerID   uuid.UUID         `json:"reviewer_id" gorm:"not null;uniqueIndex:idx_peer_review;index"`
+	Grade        *float64          `json:"grade" gorm:"type:numeric(4,1);check:grade BETWEEN 0 AND 100"`
+	Scores       []PeerReviewScore `json:"scores" gorm:"type:jsonb;serializer:json"`
+	Comment      string            `json:"comment" gorm:"type:text"`
+	SubmittedAt  *time.Time        `json:"submitted_at"`
+	Assignment   Assignment        `json:"-" gorm:"foreignKey:AssignmentID;constraint:OnDelete:CASCADE"`
+	Submission   Submission        `json:"-" gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE"`
+	Reviewer     User              `json:"-" gorm:"foreignKey:ReviewerID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time         `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+// PeerReviewScore is the level a reviewer picked for a criterion of the rubric, copied like a RubricScore
+type PeerReviewScore struct {
+	CriterionID *uuid.UUID `json:"criterion_id"`
+	LevelID     *uuid.UUID `json:"level_id"`
+	Criterion   string     `json:"criterion"`
+	Level       string     `json:"level"`
+	Points      float64    `json:"points"`
+	MaxPoints   float64    `json:"max_points"`
+	Comment     string     `json:"comment"`
+}
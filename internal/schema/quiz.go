@@ -0,0 +1,100 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type QuestionType string
+
+const (
+	QuestionMultipleChoice QuestionType = "multiple_choice"
+	QuestionMultiSelect    QuestionType = "multi_select"
+	QuestionTrueFalse      QuestionType = "true_false"
+	QuestionNumeric        QuestionType = "numeric"
+	QuestionShortAnswer    QuestionType = "short_answer"
+)
+
+// Quiz is an auto-graded assessment of a course. Each attempt draws QuestionsPerAttempt questions from the
+// bank, all of them when nil, in a random order when ShuffleQuestions is set. TimeLimitSeconds and MaxAttempts
+// are unlimited when nil. The result of a student is the best score of their submitted attempts
+type Quiz struct {
+	ID                  uuid.UUID      `json:"id" gorm:"primaryKey"`
+	CourseID            uuid.UUID      `json:"course_id" gorm:"not null;index"`
+	Title               string         `json:"title" gorm:"type:varchar(150);not null"`
+	Description         string         `json:"description" gorm:"type:varchar(2000)"`
+	TimeLimitSeconds    *int           `json:"time_limit_seconds" gorm:"check:time_limit_seconds > 0"`
+	MaxAttempts         *int           `json:"max_attempts" gorm:"check:max_attempts > 0"`
+	QuestionsPerAttempt *int           `json:"questions_per_attempt" gorm:"check:questions_per_attempt > 0"`
+	ShuffleQuestions    bool           `json:"shuffle_questions" gorm:"default:false;not null"`
+	Questions           []QuizQuestion `json:"questions,omitempty" gorm:"foreignKey:QuizID"`
+	Course              Course         `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	CreatedAt           time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt           time.Time      `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// QuizAnswerKey is the expected answer of a question, the field used depends on the question type.
+// Options are indexes into the question options, Texts are the accepted short answers
+type QuizAnswerKey struct {
+	Options   []int    `json:"options,omitempty"`
+	Bool      *bool    `json:"bool,omitempty"`
+	Number    *float64 `json:"number,omitempty"`
+	Tolerance float64  `json:"tolerance,omitempty"`
+	Texts     []string `json:"texts,omitempty"`
+}
+
+// QuizQuestion is one question of a quiz bank. Answer is only shown to the course staff, removed
+// questions are kept for the attempts that already drew them
+type QuizQuestion struct {
+	ID        uuid.UUID      `json:"id" gorm:"primaryKey"`
+	QuizID    uuid.UUID      `json:"quiz_id" gorm:"not null;index"`
+	Type      QuestionType   `json:"type" gorm:"type:question_type;not null"`
+	Prompt    string         `json:"prompt" gorm:"type:varchar(2000);not null"`
+	Options   []string       `json:"options" gorm:"type:jsonb;serializer:json"`
+	Answer    *QuizAnswerKey `json:"answer,omitempty" gorm:"type:jsonb;serializer:json;not null"`
+	Points    float64        `json:"points" gorm:"type:numeric(6,2);default:1;not null;check:points > 0"`
+	Position  int            `json:"position" gorm:"not null;default:0"`
+	CreatedAt time.Time      `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+// QuizResponse is the answer of a student to one question, the field used depends on the question type
+type QuizResponse struct {
+	Options []int    `json:"options,omitempty"`
+	Bool    *bool    `json:"bool,omitempty"`
+	Number  *float64 `json:"number,omitempty"`
+	Text    *string  `json:"text,omitempty"`
+}
+
+// QuestionResult is how a submitted answer was scored
+type QuestionResult struct {
+	QuestionID uuid.UUID `json:"question_id"`
+	Points     float64   `json:"points"`
+	MaxPoints  float64   `json:"max_points"`
+	Correct    bool      `json:"correct"`
+}
+
+// QuizAttempt is one try of a student at a quiz. QuestionIDs keeps the questions drawn for it in the order
+// they are shown. The attempt is in progress until SubmittedAt is set, Score is the percentage of MaxPoints
+// earned. Questions is filled with the drawn questions when the attempt is shown to the student
+type QuizAttempt struct {
+	ID          uuid.UUID                  `json:"id" gorm:"primaryKey"`
+	QuizID      uuid.UUID                  `json:"quiz_id" gorm:"not null;index"`
+	UserID      uuid.UUID                  `json:"user_id" gorm:"not null;index"`
+	QuestionIDs []uuid.UUID                `json:"question_ids" gorm:"type:jsonb;serializer:json;not null"`
+	Answers     map[uuid.UUID]QuizResponse `json:"answers" gorm:"type:jsonb;serializer:json;not null"`
+	Results     []QuestionResult           `json:"results,omitempty" gorm:"type:jsonb;serializer:json"`
+	StartedAt   time.Time                  `json:"started_at" gorm:"not null"`
+	DeadlineAt  *time.Time                 `json:"deadline_at"`
+	SubmittedAt *time.Time                 `json:"submitted_at"`
+	Points      *float64                   `json:"points" gorm:"type:numeric(8,2)"`
+	MaxPoints   float64                    `json:"max_points" gorm:"type:numeric(8,2);not null"`
+	Score       *float64                   `json:"score" gorm:"type:numeric(5,2);check:score BETWEEN 0 AND 100"`
+	Questions   []QuizQuestion             `json:"questions,omitempty" gorm:"-"`
+	User        *User                      `json:"user,omitempty" gorm:"foreignKey:UserID"`
+	Quiz        Quiz                       `json:"-" gorm:"foreignKey:QuizID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,35 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReleaseSchedule holds when a material or assignment opens to students: on ReleaseAt, ReleaseAfterDays
+// after the student enrolled, or at the later of both when both are set. Without either it opens right away.
+// AvailableAt is set for viewers who still have to wait, the course outline shows those items as upcoming
+type ReleaseSchedule struct {
+	ReleaseAt        *time.Time `json:"release_at"`
+	ReleaseAfterDays *int       `json:"release_after_days" gorm:"check:release_after_days >= 0"`
+	AvailableAt      *time.Time `json:"available_at,omitempty" gorm:"-"`
+}
+
+// ReleaseTime returns when the item opens for a student enrolled at enrolledAt, nil when it is not scheduled
+func (s *ReleaseSchedule) ReleaseTime(enrolledAt time.Time) *time.Time {
+	releaseAt := s.ReleaseAt
+	if s.ReleaseAfterDays != nil {
+		relative := enrolledAt.AddDate(0, 0, *s.ReleaseAfterDays)
+		if releaseAt == nil || relative.After(*releaseAt) {
+			releaseAt = &relative
+		}
+	}
+	return releaseAt
+}
+
+// ReleaseNotice records that a student was told about a scheduled item opening, so it is only announced once
+type ReleaseNotice struct {
+	UserID    uuid.UUID `json:"user_id" gorm:"primaryKey"`
+	ItemID    uuid.UUID `json:"item_id" gorm:"primaryKey"`
+	CreatedAt time.Time `json:"created_at" gorm:"default:now()"`
+}
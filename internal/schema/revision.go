@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type RevisionItemType string
+
+const (
+	RevisionMaterial   RevisionItemType = "material"
+	RevisionAssignment RevisionItemType = "assignment"
+)
+
+// FieldChange is one field that differs from the previous version. For attachments From lists the
+// attachments that were removed and To the ones that were added
+type FieldChange struct {
+	Field string `json:"field"`
+	From  any    `json:"from"`
+	To    any    `json:"to"`
+}
+
+// Revision is one saved version of a material or an assignment. Snapshot holds the item as it was after
+// the edit, Changes what differs from the version before. The first version of an item edited before
+// history was kept has no editor. RestoredFrom is set when the version brought back an older one
+type Revision struct {
+	ID           uuid.UUID        `json:"id" gorm:"primaryKey"`
+	ItemType     RevisionItemType `json:"item_type" gorm:"type:revision_item_type;not null;uniqueIndex:idx_revision_version"`
+	ItemID       uuid.UUID        `json:"item_id" gorm:"not null;uniqueIndex:idx_revision_version"`
+	Version      int              `json:"version" gorm:"not null;uniqueIndex:idx_revision_version"`
+	EditorID     *uuid.UUID       `json:"editor_id"`
+	Editor       *User            `json:"editor,omitempty" gorm:"foreignKey:EditorID"`
+	Changes      []FieldChange    `json:"changes" gorm:"type:jsonb;serializer:json;not null"`
+	Snapshot     map[string]any   `json:"snapshot" gorm:"type:jsonb;serializer:json;not null"`
+	RestoredFrom *int             `json:"restored_from"`
+	CreatedAt    time.Time        `json:"created_at" gorm:"default:now()"`
+}
@@ -0,0 +1,46 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RubricCriterion is one row of the rubric of an assignment, a submission is scored on it by picking
+// one of its levels
+type RubricCriterion struct {
+	ID           uuid.UUID     `json:"id" gorm:"primaryKey"`
+	AssignmentID uuid.UUID     `json:"assignment_id" gorm:"not null;index"`
+	Title        string        `json:"title" gorm:"type:varchar(255);not null"`
+	Description  string        `json:"description" gorm:"type:varchar(1000)"`
+	Position     int           `json:"position" gorm:"not null"`
+	Levels       []RubricLevel `json:"levels" gorm:"foreignKey:CriterionID;constraint:OnDelete:CASCADE"`
+	Assignment   Assignment    `json:"-" gorm:"foreignKey:AssignmentID;constraint:OnDelete:CASCADE"`
+	CreatedAt    time.Time     `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt    time.Time     `json:"updated_at"`
+}
+
+type RubricLevel struct {
+	ID          uuid.UUID `json:"id" gorm:"primaryKey"`
+	CriterionID uuid.UUID `json:"criterion_id" gorm:"not null;index"`
+	Title       string    `json:"title" gorm:"type:varchar(255);not null"`
+	Description string    `json:"description" gorm:"type:varchar(1000)"`
+	Points      float64   `json:"points" gorm:"type:numeric(6,2);not null;check:points >= 0"`
+}
+
+// RubricScore is the level picked for a criterion when grading a submission. The titles and points are
+// copied from the rubric so the score still reads the same after the rubric is changed
+type RubricScore struct {
+	ID           uuid.UUID  `json:"id" gorm:"primaryKey"`
+	SubmissionID uuid.UUID  `json:"submission_id" gorm:"not null;index"`
+	CriterionID  *uuid.UUID `json:"criterion_id"`
+	LevelID      *uuid.UUID `json:"level_id"`
+	Criterion    string     `json:"criterion" gorm:"type:varchar(255);not null"`
+	Level        string     `json:"level" gorm:"type:varchar(255);not null"`
+	Points       float64    `json:"points" gorm:"type:numeric(6,2);not null"`
+	MaxPoints    float64    `json:"max_points" gorm:"type:numeric(6,2);not null"`
+	Comment      string     `json:"comment" gorm:"type:varchar(1000)"`
+
+	RubricCriterion *RubricCriterion `json:"-" gorm:"foreignKey:CriterionID;constraint:OnDelete:SET NULL"`
+	RubricLevel     *RubricLevel     `json:"-" gorm:"foreignKey:LevelID;constraint:OnDelete:SET NULL"`
+}
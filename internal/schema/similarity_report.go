@@ -0,0 +1,36 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SimilarityReport compares the text of a submission, its content and the text of its attachments, with the
+// submissions of the other students to the same assignment. Signature is the MinHash of the text so later
+// submissions are compared without reading the files again. Score is the highest overlap estimated with
+// another student from 0 to 1, Flagged marks the reports the instructor should look at. The attempts of
+// a team are not compared with each other
+type SimilarityReport struct {
+	ID           uuid.UUID         `json:"id" gorm:"primaryKey"`
+	SubmissionID uuid.UUID         `json:"submission_id" gorm:"not null;uniqueIndex"`
+	AssignmentID uuid.UUID         `json:"assignment_id" gorm:"not null;index"`
+	UserID       uuid.UUID         `json:"user_id" gorm:"not null"`
+	TeamID       *uuid.UUID        `json:"team_id"`
+	Shingles     int               `json:"shingles" gorm:"not null;default:0"`
+	Signature    []uint32          `json:"-" gorm:"type:jsonb;serializer:json"`
+	Score        float64           `json:"score" gorm:"type:numeric(5,4);not null;default:0"`
+	Flagged      bool              `json:"flagged" gorm:"not null;default:false;index"`
+	Matches      []SimilarityMatch `json:"matches" gorm:"type:jsonb;serializer:json"`
+	Skipped      []string          `json:"skipped" gorm:"type:jsonb;serializer:json"`
+	CheckedAt    time.Time         `json:"checked_at" gorm:"not null"`
+	Submission   Submission        `json:"-" gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE"`
+	Assignment   Assignment        `json:"-" gorm:"foreignKey:AssignmentID;constraint:OnDelete:CASCADE"`
+}
+
+// SimilarityMatch is a submission of another student that overlaps with the reported one
+type SimilarityMatch struct {
+	SubmissionID uuid.UUID `json:"submission_id"`
+	UserID       uuid.UUID `json:"user_id"`
+	Score        float64   `json:"score"`
+}
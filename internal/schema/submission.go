@@ -7,14 +7,53 @@ import (
 	"gorm.io/gorm"
 )
 
+type SubmissionStatus string
+
+const (
+	SubmissionSubmitted SubmissionStatus = "submitted"
+	SubmissionInReview  SubmissionStatus = "in_review"
+	SubmissionGraded    SubmissionStatus = "graded"
+	// SubmissionReturned sends the attempt back to the student, who may hand in once more even without attempts left
+	SubmissionReturned SubmissionStatus = "returned"
+)
+
+// Submission is one attempt of a student at an assignment, attempts are numbered from 1 and never
+// edited once handed in, a new hand-in is a new attempt. Late and DaysLate are measured against the
+// deadline of the student when it was last handed in at SubmittedAt, LatePenalty is the part of the
+// grade taken off for it. Feedback and RubricScores are left by the instructor when grading. Grade stays
+// empty until the attempt is graded and students only see it once it is released at ReleasedAt. The attempt
+// of a team at a group assignment has TeamID set, it is numbered per team and counts for its Members
 type Submission struct {
-	ID           uuid.UUID      `json:"id" gorm:"primarykey"`
-	AssignmentID uuid.UUID      `json:"assignment_id" gorm:"not null"`
-	UserID       uuid.UUID      `json:"user_id" gorm:"not null"`
-	Content      string         `json:"content" gorm:"type:varchar(1000)"`
-	Grade        float64        `json:"grade" gorm:"type:numeric(4,1);check:grade BETWEEN 0 AND 100"`
-	Attachments  []Attachment   `json:"attachments" gorm:"foreignKey:SubmissionID"`
-	CreatedAt    time.Time      `json:"created_at" gorm:"default:now();not null"`
-	UpdatedAt    time.Time      `json:"updated_at"`
-	DeletedAt    gorm.DeletedAt `json:"" gorm:"index"`
+	ID           uuid.UUID          `json:"id" gorm:"primarykey"`
+	AssignmentID uuid.UUID          `json:"assignment_id" gorm:"not null;uniqueIndex:idx_submission_attempt;uniqueIndex:idx_submission_team_attempt"`
+	UserID       uuid.UUID          `json:"user_id" gorm:"not null;uniqueIndex:idx_submission_attempt"`
+	TeamID       *uuid.UUID         `json:"team_id" gorm:"uniqueIndex:idx_submission_team_attempt"`
+	Attempt      int                `json:"attempt" gorm:"not null;default:1;uniqueIndex:idx_submission_attempt;uniqueIndex:idx_submission_team_attempt"`
+	Content      string             `json:"content" gorm:"type:varchar(1000)"`
+	Status       SubmissionStatus   `json:"status" gorm:"type:submission_status;default:submitted;not null;index"`
+	Grade        *float64           `json:"grade" gorm:"type:numeric(4,1);check:grade BETWEEN 0 AND 100"`
+	GradedAt     *time.Time         `json:"graded_at"`
+	ReleasedAt   *time.Time         `json:"released_at"`
+	SubmittedAt  time.Time          `json:"submitted_at" gorm:"default:now();not null"`
+	Late         bool               `json:"late" gorm:"default:false;not null"`
+	DaysLate     int                `json:"days_late" gorm:"default:0;not null"`
+	LatePenalty  float64            `json:"late_penalty" gorm:"type:numeric(4,1);default:0;not null"`
+	Feedback     string             `json:"feedback" gorm:"type:text"`
+	Attachments  []Attachment       `json:"attachments" gorm:"foreignKey:SubmissionID"`
+	RubricScores []RubricScore      `json:"rubric_scores" gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE"`
+	Members      []SubmissionMember `json:"members,omitempty" gorm:"foreignKey:SubmissionID;constraint:OnDelete:CASCADE"`
+	Team         *Team              `json:"-" gorm:"foreignKey:TeamID;constraint:OnDelete:SET NULL"`
+	Similarity   *SimilarityReport  `json:"similarity,omitempty" gorm:"-"`
+	CreatedAt    time.Time          `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt    time.Time          `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt     `json:"" gorm:"index"`
+}
+
+// SubmissionMember is a student a team submission counts for, the team is copied when the attempt is handed
+// in so later changes to the team leave it alone. Adjustment is added to the grade of the team for this member
+type SubmissionMember struct {
+	SubmissionID     uuid.UUID `json:"submission_id" gorm:"primaryKey"`
+	UserID           uuid.UUID `json:"user_id" gorm:"primaryKey;index"`
+	Adjustment       float64   `json:"adjustment" gorm:"type:numeric(4,1);default:0;not null;check:adjustment BETWEEN -100 AND 100"`
+	AdjustmentReason string    `json:"adjustment_reason" gorm:"type:varchar(500)"`
 }
@@ -0,0 +1,41 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionPlan is an all-access plan billed from the wallet every IntervalDays
+type SubscriptionPlan struct {
+	ID           uuid.UUID `json:"id" gorm:"primaryKey"`
+	Name         string    `json:"name" gorm:"type:varchar(100);not null"`
+	Description  string    `json:"description" gorm:"type:text"`
+	Price        int64     `json:"price" gorm:"not null;check:price >= 0"`
+	IntervalDays int       `json:"interval_days" gorm:"not null;check:interval_days > 0"`
+	IsActive     bool      `json:"is_active" gorm:"default:true;not null"`
+	CreatedAt    time.Time `json:"created_at" gorm:"default:now()"`
+	UpdatedAt    time.Time `json:"updated_at" gorm:"default:now()"`
+}
+
+type SubscriptionStatus string
+
+const (
+	SubscriptionActive   SubscriptionStatus = "active"
+	SubscriptionPastDue  SubscriptionStatus = "past_due"
+	SubscriptionCanceled SubscriptionStatus = "canceled"
+)
+
+// Subscription gives access to every course while it is active and CurrentPeriodEnd has not passed
+type Subscription struct {
+	ID                 uuid.UUID          `json:"id" gorm:"primaryKey"`
+	UserID             uuid.UUID          `json:"user_id" gorm:"not null;index"`
+	PlanID             uuid.UUID          `json:"plan_id" gorm:"not null"`
+	Plan               SubscriptionPlan   `json:"plan" gorm:"foreignKey:PlanID"`
+	Status             SubscriptionStatus `json:"status" gorm:"type:subscription_status;not null;index"`
+	CurrentPeriodStart time.Time          `json:"current_period_start" gorm:"not null"`
+	CurrentPeriodEnd   time.Time          `json:"current_period_end" gorm:"not null;index"`
+	CancelAtPeriodEnd  bool               `json:"cancel_at_period_end" gorm:"default:false;not null"`
+	CreatedAt          time.Time          `json:"created_at" gorm:"default:now()"`
+	UpdatedAt          time.Time          `json:"updated_at" gorm:"default:now()"`
+}
@@ -0,0 +1,45 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TeamSet splits the students of a course into teams, a group assignment points at the set whose teams hand
+// in together. The instructor puts the teams together, with SelfSelect students also form and join teams on
+// their own, up to MaxSize members each
+type TeamSet struct {
+	ID         uuid.UUID `json:"id" gorm:"primaryKey"`
+	CourseID   uuid.UUID `json:"course_id" gorm:"not null;index"`
+	Name       string    `json:"name" gorm:"type:varchar(100);not null"`
+	SelfSelect bool      `json:"self_select" gorm:"default:false;not null"`
+	MaxSize    *int      `json:"max_size" gorm:"check:max_size > 0"`
+	Teams      []Team    `json:"teams" gorm:"foreignKey:TeamSetID"`
+	Course     Course    `json:"-" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+	CreatedAt  time.Time `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// Team is a group of students within a team set, a student is in at most one team of every set
+type Team struct {
+	ID        uuid.UUID    `json:"id" gorm:"primaryKey"`
+	TeamSetID uuid.UUID    `json:"team_set_id" gorm:"not null;index"`
+	Name      string       `json:"name" gorm:"type:varchar(100);not null"`
+	CreatedBy uuid.UUID    `json:"created_by" gorm:"not null"`
+	Members   []TeamMember `json:"members" gorm:"foreignKey:TeamID"`
+	TeamSet   TeamSet      `json:"-" gorm:"foreignKey:TeamSetID;constraint:OnDelete:CASCADE"`
+	CreatedAt time.Time    `json:"created_at" gorm:"default:now();not null"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// TeamMember puts a student in a team, TeamSetID is copied from the team so a student cannot be in two
+// teams of the same set
+type TeamMember struct {
+	TeamID    uuid.UUID `json:"team_id" gorm:"primaryKey"`
+	TeamSetID uuid.UUID `json:"team_set_id" gorm:"not null;uniqueIndex:idx_team_set_member"`
+	UserID    uuid.UUID `json:"user_id" gorm:"primaryKey;uniqueIndex:idx_team_set_member"`
+	JoinedAt  time.Time `json:"joined_at" gorm:"default:now();not null"`
+	User      *User     `json:"user,omitempty" gorm:"foreignKey:UserID;constraint:OnDelete:CASCADE"`
+	Team      Team      `json:"-" gorm:"foreignKey:TeamID;constraint:OnDelete:CASCADE"`
+}
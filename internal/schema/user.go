@@ -12,6 +12,7 @@ type Role string
 const (
 	RoleStudent    Role = "student"
 	RoleInstructor Role = "instructor"
+	RoleAdmin      Role = "admin"
 )
 
 type User struct {
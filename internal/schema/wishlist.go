@@ -0,0 +1,19 @@
+package schema
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WishlistItem is a course a student saved for later, AlertPrice is the price in AlertCurrency the student
+// was last told about and is what a price drop is measured against
+type WishlistItem struct {
+	UserID        uuid.UUID `json:"-" gorm:"primaryKey"`
+	CourseID      uuid.UUID `json:"course_id" gorm:"primaryKey;index"`
+	AlertPrice    int64     `json:"-" gorm:"not null"`
+	AlertCurrency string    `json:"-" gorm:"type:char(3);not null"`
+	CreatedAt     time.Time `json:"created_at" gorm:"default:now()"`
+
+	Course Course `json:"course" gorm:"foreignKey:CourseID;constraint:OnDelete:CASCADE"`
+}
@@ -0,0 +1,27 @@
+package slug
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Make turns a display name into a lowercase, hyphen separated identifier, e.g. "AI & Machine Learning" becomes
+// "ai-machine-learning". Characters other than ASCII letters and digits act as separators.
+func Make(name string) string {
+	var sb strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(name) {
+		if r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)) {
+			if pendingHyphen && sb.Len() > 0 {
+				sb.WriteByte('-')
+			}
+			sb.WriteRune(r)
+			pendingHyphen = false
+		} else {
+			pendingHyphen = true
+		}
+	}
+
+	return sb.String()
+}
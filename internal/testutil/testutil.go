@@ -0,0 +1,45 @@
+package testutil
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/Stefanuswilfrid/course-backend/internal/schema"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserCtx is the context the authentication middleware leaves behind for the user
+func UserCtx(userID uuid.UUID, role schema.Role) context.Context {
+	ctx := context.WithValue(context.Background(), "user.id", userID.String())
+	return context.WithValue(ctx, "user.role", string(role))
+}
+
+func Ptr[T any](v T) *T {
+	return &v
+}
+
+// Serve runs a single request through the handler mounted on route, as if the user had been authenticated
+func Serve(route string, handler gin.HandlerFunc, userID uuid.UUID, role schema.Role, req *http.Request) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Handle(req.Method, route, func(ctx *gin.Context) {
+		ctx.Set("user.id", userID.String())
+		ctx.Set("user.role", string(role))
+		ctx.Set("user.name", "Test User")
+		ctx.Next()
+	}, handler)
+
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+// JSONRequest builds a request carrying the JSON body
+func JSONRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}